@@ -4,14 +4,18 @@ import (
 	"errors"
 	"log"
 	"os"
+	"os/signal"
 	"path"
+	"syscall"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/feedback"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/web"
 	"github.com/pivotal-cf-experimental/warrant"
 	"github.com/pivotal-golang/lager"
@@ -19,6 +23,25 @@ import (
 
 const WorkerCount = 10
 
+// halter is a loop started by Run that has to be stopped on shutdown.
+type halter interface {
+	Halt()
+}
+
+type halters []halter
+
+func (hs halters) Halt() {
+	for _, h := range hs {
+		h.Halt()
+	}
+}
+
+type haltFunc func()
+
+func (f haltFunc) Halt() {
+	f()
+}
+
 type Application struct {
 	env        Environment
 	logger     lager.Logger
@@ -40,8 +63,8 @@ func New(env Environment, dbp *DBProvider) Application {
 	}
 }
 
-func (a Application) mailClient() *mail.Client {
-	return mail.NewClient(mail.Config{
+func (a Application) mailConfig() mail.Config {
+	return mail.Config{
 		User:              a.env.SMTPUser,
 		Pass:              a.env.SMTPPass,
 		Host:              a.env.SMTPHost,
@@ -52,6 +75,19 @@ func (a Application) mailClient() *mail.Client {
 		DisableTLS:        !a.env.SMTPTLS,
 		LoggingEnabled:    a.env.SMTPLoggingEnabled,
 		SMTPAuthMechanism: a.env.SMTPAuthMechanism,
+		DKIMKeys:          a.env.DKIMKeys,
+	}
+}
+
+func (a Application) mailClient() *mail.Client {
+	return mail.NewClient(a.mailConfig())
+}
+
+func (a Application) mailPool() *mail.Pool {
+	return mail.NewPool(a.mailConfig(), mail.PoolConfig{
+		Size:                     WorkerCount,
+		MaxMessagesPerConnection: a.env.SMTPPoolMaxMessages,
+		MaxIdleTime:              time.Duration(a.env.SMTPPoolMaxIdleTime) * time.Second,
 	})
 }
 
@@ -72,11 +108,64 @@ func (a Application) Run() {
 
 	a.migrator.Migrate()
 
-	a.StartQueueGauge()
-	a.StartWorkers(validator)
-	a.StartMessageGC()
-	a.StartKeyRefresher(validator)
-	a.StartServer(a.logger, validator)
+	background := halters{
+		a.StartQueueGauge(),
+		a.StartMessageGC(),
+		a.StartKeyRefresher(validator),
+	}
+	mailPool := a.mailPool()
+	workers := a.StartWorkers(mailPool, validator)
+	feedbackListener := a.StartFeedbackListener()
+
+	server := web.NewServer()
+	stopped := make(chan struct{})
+	go func() {
+		a.WaitForShutdownSignal()
+		a.Shutdown(server, feedbackListener, workers, mailPool, background)
+		close(stopped)
+	}()
+
+	err := a.StartServer(server, a.logger, validator)
+	if err != nil {
+		a.logger.Fatal("listen-and-serve-errored", err)
+	}
+
+	<-stopped
+}
+
+func (a Application) WaitForShutdownSignal() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	sig := <-signals
+	a.logger.Info("received-signal", lager.Data{"signal": sig.String()})
+}
+
+// Shutdown stops the server and the feedback listener from accepting
+// requests, lets the workers finish the jobs they are working on, closes the
+// SMTP connections they leave idle and then stops the background loops. The
+// server and the workers share a deadline of ShutdownTimeout seconds.
+func (a Application) Shutdown(server web.Server, feedbackListener halter, workers postal.Workers, mailPool *mail.Pool, background halter) {
+	deadline := time.Now().Add(time.Duration(a.env.ShutdownTimeout) * time.Second)
+
+	a.logger.Info("shutting-down")
+
+	err := server.Shutdown(deadline)
+	if err != nil {
+		a.logger.Error("server-shutdown-errored", err)
+	}
+
+	feedbackListener.Halt()
+
+	err = workers.Halt(deadline)
+	if err != nil {
+		a.logger.Error("workers-halt-errored", err)
+	}
+
+	mailPool.Close()
+	background.Halt()
+
+	a.logger.Info("shut-down")
 }
 
 func (a Application) VerifySMTPConfiguration() {
@@ -108,19 +197,22 @@ func (a Application) VerifySMTPConfiguration() {
 	}
 }
 
-func (a Application) StartQueueGauge() {
+func (a Application) StartQueueGauge() halter {
 	if a.env.VCAPApplication.InstanceIndex != 0 {
-		return
+		return halters{}
 	}
 
 	queueGauge := gobble.NewQueueGauge(a.dbProvider.Queue(), time.Tick(time.Minute))
 	go queueGauge.Run()
+
+	return queueGauge
 }
 
-func (a Application) StartKeyRefresher(validator *uaa.TokenValidator) {
+func (a Application) StartKeyRefresher(validator *uaa.TokenValidator) halter {
 	duration := time.Duration(a.env.UAAKeyRefreshInterval) * time.Millisecond
 
 	t := time.NewTimer(duration)
+	halt := make(chan struct{})
 
 	go func() {
 		for {
@@ -128,32 +220,44 @@ func (a Application) StartKeyRefresher(validator *uaa.TokenValidator) {
 			case <-t.C:
 				validator.LoadSigningKeys()
 				t.Reset(duration)
-				break
+			case <-halt:
+				t.Stop()
+				return
 			}
 		}
 	}()
+
+	return haltFunc(func() {
+		close(halt)
+	})
 }
 
-func (a Application) StartWorkers(validator *uaa.TokenValidator) {
-	postal.Boot(a.mailClient, a.dbProvider.sqlDB, postal.Config{
-		UAAClientID:          a.env.UAAClientID,
-		UAAClientSecret:      a.env.UAAClientSecret,
-		UAATokenValidator:    validator,
-		UAAHost:              a.env.UAAHost,
-		VerifySSL:            a.env.VerifySSL,
-		InstanceIndex:        a.env.VCAPApplication.InstanceIndex,
-		WorkerCount:          WorkerCount,
-		RootPath:             a.env.RootPath,
-		EncryptionKey:        a.env.EncryptionKey,
-		DBLoggingEnabled:     a.env.DBLoggingEnabled,
-		Sender:               a.env.Sender,
-		Domain:               a.env.Domain,
-		QueueWaitMaxDuration: a.env.GobbleWaitMaxDuration,
-		CCHost:               a.env.CCHost,
+func (a Application) StartWorkers(mailPool *mail.Pool, validator *uaa.TokenValidator) postal.Workers {
+	return postal.Boot(mailPool, a.dbProvider.sqlDB, postal.Config{
+		UAAClientID:                a.env.UAAClientID,
+		UAAClientSecret:            a.env.UAAClientSecret,
+		UAATokenValidator:          validator,
+		UAAHost:                    a.env.UAAHost,
+		VerifySSL:                  a.env.VerifySSL,
+		InstanceIndex:              a.env.VCAPApplication.InstanceIndex,
+		WorkerCount:                WorkerCount,
+		RootPath:                   a.env.RootPath,
+		EncryptionKey:              a.env.EncryptionKey,
+		DBLoggingEnabled:           a.env.DBLoggingEnabled,
+		Sender:                     a.env.Sender,
+		Domain:                     a.env.Domain,
+		NotificationsURL:           a.env.NotificationsURL,
+		QueueWaitMaxDuration:       a.env.GobbleWaitMaxDuration,
+		QueueReserveBatchSize:      a.env.GobbleReserveBatchSize,
+		QueuePriorityAgingInterval: a.env.GobblePriorityAgingInterval,
+		CCHost:                     a.env.CCHost,
+		DefaultUAAScopes:           a.env.DefaultUAAScopes,
+
+		HTMLEscapingCompatibilityMode: a.env.HTMLEscapingCompatibilityMode,
 	})
 }
 
-func (a Application) StartMessageGC() {
+func (a Application) StartMessageGC() halter {
 	messageLifetime := 24 * time.Hour
 	db := a.dbProvider.Database()
 	messagesRepo := a.dbProvider.MessagesRepo()
@@ -162,10 +266,43 @@ func (a Application) StartMessageGC() {
 	logger := log.New(os.Stdout, "", 0)
 	messageGC := postal.NewMessageGC(messageLifetime, db, messagesRepo, pollingInterval, logger)
 	messageGC.Run()
+
+	idempotencyKeyLifetime := time.Duration(a.env.IdempotencyKeyTTL) * time.Hour
+	idempotencyKeyGC := postal.NewNamedGC("IdempotencyKeyGC", idempotencyKeyLifetime, db, models.NewIdempotencyKeysRepo(), pollingInterval, logger)
+	idempotencyKeyGC.Run()
+
+	return halters{messageGC, idempotencyKeyGC}
 }
 
-func (a Application) StartServer(logger lager.Logger, validator *uaa.TokenValidator) {
-	web.NewServer().Run(web.Config{
+func (a Application) StartFeedbackListener() halter {
+	if a.env.FeedbackSMTPAddress == "" {
+		return haltFunc(func() {})
+	}
+
+	database := a.dbProvider.Database()
+	recorder := services.NewFeedbackRecorder(a.dbProvider.MessagesRepo(), models.NewMessageEventsRepo(), models.NewSuppressionsRepo())
+
+	listener := feedback.NewListener(a.env.FeedbackSMTPAddress, a.env.FeedbackSMTPAllowedNetworks, func(report feedback.Report) error {
+		return recorder.Record(database.Connection(), report)
+	}, a.logger)
+
+	go func() {
+		err := listener.ListenAndServe()
+		if err != nil {
+			a.logger.Fatal("feedback-listener-errored", err)
+		}
+	}()
+
+	return haltFunc(func() {
+		err := listener.Close()
+		if err != nil {
+			a.logger.Error("feedback-listener-close-errored", err)
+		}
+	})
+}
+
+func (a Application) StartServer(server web.Server, logger lager.Logger, validator *uaa.TokenValidator) error {
+	return server.Run(web.Config{
 		DBLoggingEnabled:     a.env.DBLoggingEnabled,
 		SkipVerifySSL:        !a.env.VerifySSL,
 		Port:                 a.env.Port,
@@ -181,6 +318,16 @@ func (a Application) StartServer(logger lager.Logger, validator *uaa.TokenValida
 		UAAClientSecret:   a.env.UAAClientSecret,
 		DefaultUAAScopes:  a.env.DefaultUAAScopes,
 		CCHost:            a.env.CCHost,
+
+		EncryptionKey:         a.env.EncryptionKey,
+		UnsubscribeIDLifetime: time.Duration(a.env.UnsubscribeIDLifetime) * time.Hour,
+		SendAtMaxHorizon:      time.Duration(a.env.SendAtMaxHorizon) * time.Hour,
+		IdempotencyKeyTTL:     time.Duration(a.env.IdempotencyKeyTTL) * time.Hour,
+
+		Sender: a.env.Sender,
+		Domain: a.env.Domain,
+
+		HTMLEscapingCompatibilityMode: a.env.HTMLEscapingCompatibilityMode,
 	})
 }
 
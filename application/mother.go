@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"os"
 	"path"
 	"time"
@@ -28,11 +29,15 @@ func NewDBProvider(env Environment) *DBProvider {
 	var err error
 	databaseURL := env.DatabaseURL
 	if env.DatabaseCACertFile != "" {
-		registerTLSConfig(env)
-		databaseURL += "&tls=custom"
+		if env.DatabaseDriver == db.Postgres {
+			databaseURL = postgresTLSURL(env)
+		} else {
+			registerTLSConfig(env)
+			databaseURL += "&tls=custom"
+		}
 	}
 
-	sqlDB, err := sql.Open("mysql", databaseURL)
+	sqlDB, err := sql.Open(env.DatabaseDriver, databaseURL)
 	if err != nil {
 		panic(err)
 	}
@@ -100,6 +105,27 @@ func registerTLSConfig(env Environment) {
 	mysql.RegisterTLSConfig("custom", tlsConfig)
 }
 
+// postgresTLSURL hands the CA certificate to the PostgreSQL driver, which
+// takes its TLS settings from the connection URL rather than a registry.
+func postgresTLSURL(env Environment) string {
+	databaseURL, err := url.Parse(env.DatabaseURL)
+	if err != nil {
+		panic(err)
+	}
+
+	sslMode := "verify-full"
+	if !env.DatabaseEnableIdentityVerification {
+		sslMode = "verify-ca"
+	}
+
+	query := databaseURL.Query()
+	query.Set("sslmode", sslMode)
+	query.Set("sslrootcert", env.DatabaseCACertFile)
+	databaseURL.RawQuery = query.Encode()
+
+	return databaseURL.String()
+}
+
 func VerifyCertificatesIgnoreHostname(rawCerts [][]byte, caCertPool *x509.CertPool) error {
 	certs := make([]*x509.Certificate, len(rawCerts))
 
@@ -1,13 +1,22 @@
 package cf
 
 import (
+	"crypto/tls"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/pivotal-cf-experimental/rainmaker"
 )
 
+// RequestTimeout bounds each request made to the Cloud Controller, so that an
+// unresponsive Cloud Controller cannot hold a worker forever.
+var RequestTimeout = 30 * time.Second
+
 type CloudController struct {
-	client rainmaker.Client
+	client     rainmaker.Client
+	host       string
+	httpClient *http.Client
 }
 
 func NewCloudController(host string, skipVerifySSL bool) CloudController {
@@ -16,6 +25,16 @@ func NewCloudController(host string, skipVerifySSL bool) CloudController {
 			Host:          host,
 			SkipVerifySSL: skipVerifySSL,
 		}),
+		host: host,
+		httpClient: &http.Client{
+			Timeout: RequestTimeout,
+			Transport: &http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: skipVerifySSL,
+				},
+			},
+		},
 	}
 }
 
@@ -0,0 +1,95 @@
+package cf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+type spaceRoleUsersPage struct {
+	NextURL   *string `json:"next_url"`
+	Resources []struct {
+		Metadata struct {
+			GUID string `json:"guid"`
+		} `json:"metadata"`
+	} `json:"resources"`
+}
+
+func (cc CloudController) GetDevelopersBySpaceGuid(guid, token string) ([]CloudControllerUser, error) {
+	return cc.getUsersBySpaceRole(guid, "developers", token)
+}
+
+func (cc CloudController) GetManagersBySpaceGuid(guid, token string) ([]CloudControllerUser, error) {
+	return cc.getUsersBySpaceRole(guid, "managers", token)
+}
+
+func (cc CloudController) GetAuditorsBySpaceGuid(guid, token string) ([]CloudControllerUser, error) {
+	return cc.getUsersBySpaceRole(guid, "auditors", token)
+}
+
+// getUsersBySpaceRole lists the users holding a role in a space, following
+// the pages of the listing until there are none left.
+func (cc CloudController) getUsersBySpaceRole(guid, role, token string) ([]CloudControllerUser, error) {
+	ccUsers := []CloudControllerUser{}
+
+	then := time.Now()
+
+	path := fmt.Sprintf("/v2/spaces/%s/%s", guid, role)
+	for path != "" {
+		page, err := cc.getSpaceRoleUsersPage(path, token)
+		if err != nil {
+			return []CloudControllerUser{}, err
+		}
+
+		for _, resource := range page.Resources {
+			ccUsers = append(ccUsers, CloudControllerUser{
+				GUID: resource.Metadata.GUID,
+			})
+		}
+
+		path = ""
+		if page.NextURL != nil {
+			path = *page.NextURL
+		}
+	}
+
+	metrics.GetOrRegisterTimer(fmt.Sprintf("notifications.external-requests.cc.%s-by-space-guid", role), nil).Update(time.Since(then))
+
+	return ccUsers, nil
+}
+
+func (cc CloudController) getSpaceRoleUsersPage(path, token string) (spaceRoleUsersPage, error) {
+	var page spaceRoleUsersPage
+
+	request, err := http.NewRequest("GET", cc.host+path, nil)
+	if err != nil {
+		return page, NewFailure(0, err.Error())
+	}
+	request.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := cc.httpClient.Do(request)
+	if err != nil {
+		return page, NewFailure(0, err.Error())
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return page, NewFailure(0, err.Error())
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return page, NewFailure(response.StatusCode, string(body))
+	}
+
+	err = json.Unmarshal(body, &page)
+	if err != nil {
+		return page, NewFailure(0, err.Error())
+	}
+
+	return page, nil
+}
@@ -0,0 +1,143 @@
+package cf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Getting users by space role", func() {
+	var (
+		CCServer        *httptest.Server
+		requestedPaths  []string
+		cloudController cf.CloudController
+	)
+
+	BeforeEach(func() {
+		requestedPaths = []string{}
+
+		CCServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			if token != testUAAToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"code":10002,"description":"Authentication error","error_code":"CF-NotAuthenticated"}`))
+				return
+			}
+
+			requestedPaths = append(requestedPaths, req.URL.String())
+
+			parts := strings.Split(req.URL.Path, "/")
+			if parts[3] != testSpaceGuid {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"code":40004,"description":"The app space could not be found","error_code":"CF-SpaceNotFound"}`))
+				return
+			}
+
+			role := parts[4]
+			if req.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`{
+					"total_results": 2,
+					"total_pages": 2,
+					"prev_url": "/v2/spaces/test-space-guid/` + role + `?page=1",
+					"next_url": null,
+					"resources": [{"metadata": {"guid": "` + role + `-2"}, "entity": {}}]
+				}`))
+				return
+			}
+
+			w.Write([]byte(`{
+				"total_results": 2,
+				"total_pages": 2,
+				"prev_url": null,
+				"next_url": "/v2/spaces/test-space-guid/` + role + `?page=2",
+				"resources": [{"metadata": {"guid": "` + role + `-1"}, "entity": {}}]
+			}`))
+		}))
+
+		cloudController = cf.NewCloudController(CCServer.URL, false)
+	})
+
+	AfterEach(func() {
+		CCServer.Close()
+	})
+
+	It("returns every page of developers for the given space guid", func() {
+		users, err := cloudController.GetDevelopersBySpaceGuid(testSpaceGuid, testUAAToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(Equal([]cf.CloudControllerUser{
+			{GUID: "developers-1"},
+			{GUID: "developers-2"},
+		}))
+		Expect(requestedPaths).To(Equal([]string{
+			"/v2/spaces/test-space-guid/developers",
+			"/v2/spaces/test-space-guid/developers?page=2",
+		}))
+	})
+
+	It("returns the managers for the given space guid", func() {
+		users, err := cloudController.GetManagersBySpaceGuid(testSpaceGuid, testUAAToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(Equal([]cf.CloudControllerUser{
+			{GUID: "managers-1"},
+			{GUID: "managers-2"},
+		}))
+	})
+
+	It("returns the auditors for the given space guid", func() {
+		users, err := cloudController.GetAuditorsBySpaceGuid(testSpaceGuid, testUAAToken)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(Equal([]cf.CloudControllerUser{
+			{GUID: "auditors-1"},
+			{GUID: "auditors-2"},
+		}))
+	})
+
+	It("returns an error when the Cloud Controller returns an error status code", func() {
+		_, err := cloudController.GetDevelopersBySpaceGuid(testSpaceGuid, "bad-token")
+		Expect(err).To(BeAssignableToTypeOf(cf.Failure{}))
+		Expect(err.(cf.Failure).Code).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("returns an error when the space does not exist", func() {
+		_, err := cloudController.GetDevelopersBySpaceGuid("missing-space-guid", testUAAToken)
+		Expect(err).To(BeAssignableToTypeOf(cf.Failure{}))
+		Expect(err.(cf.Failure).Code).To(Equal(http.StatusNotFound))
+	})
+
+	Context("when the Cloud Controller does not respond in time", func() {
+		var (
+			slowServer      *httptest.Server
+			originalTimeout time.Duration
+			release         chan struct{}
+		)
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			slowServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				<-release
+			}))
+
+			originalTimeout = cf.RequestTimeout
+			cf.RequestTimeout = 50 * time.Millisecond
+			cloudController = cf.NewCloudController(slowServer.URL, false)
+		})
+
+		AfterEach(func() {
+			cf.RequestTimeout = originalTimeout
+			close(release)
+			slowServer.Close()
+		})
+
+		It("gives up on the request", func() {
+			_, err := cloudController.GetDevelopersBySpaceGuid(testSpaceGuid, testUAAToken)
+			Expect(err).To(BeAssignableToTypeOf(cf.Failure{}))
+			Expect(err.(cf.Failure).Code).To(Equal(0))
+		})
+	})
+})
@@ -29,3 +29,17 @@ func (conn *Connection) Transaction() TransactionInterface {
 func (conn *Connection) GetDbMap() *gorp.DbMap {
 	return conn.DbMap
 }
+
+// Select, SelectOne and Exec accept queries written for MySQL and rebind
+// them for the dialect of the connection.
+func (conn *Connection) Select(holder interface{}, query string, args ...interface{}) ([]interface{}, error) {
+	return conn.DbMap.Select(holder, Rebind(conn.Dialect, query), args...)
+}
+
+func (conn *Connection) SelectOne(holder interface{}, query string, args ...interface{}) error {
+	return conn.DbMap.SelectOne(holder, Rebind(conn.Dialect, query), args...)
+}
+
+func (conn *Connection) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return conn.DbMap.Exec(Rebind(conn.Dialect, query), args...)
+}
@@ -6,6 +6,7 @@ import (
 	"gopkg.in/gorp.v1"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 )
 
 type DB struct {
@@ -22,11 +23,8 @@ type DatabaseInterface interface {
 func NewDatabase(db *sql.DB, config Config) *DB {
 	connection := &Connection{
 		DbMap: &gorp.DbMap{
-			Db: db,
-			Dialect: gorp.MySQLDialect{
-				Engine:   "InnoDB",
-				Encoding: "UTF8",
-			},
+			Db:      db,
+			Dialect: NewDialect(db),
 		},
 	}
 
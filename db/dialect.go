@@ -0,0 +1,91 @@
+package db
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"gopkg.in/gorp.v1"
+)
+
+const (
+	MySQL    = "mysql"
+	Postgres = "postgres"
+)
+
+// DriverName reports which of the supported databases the connection pool
+// talks to. Anything that is not PostgreSQL is treated as MySQL.
+func DriverName(sqlDB *sql.DB) string {
+	if sqlDB != nil {
+		if _, ok := sqlDB.Driver().(*pq.Driver); ok {
+			return Postgres
+		}
+	}
+
+	return MySQL
+}
+
+func NewDialect(sqlDB *sql.DB) gorp.Dialect {
+	if DriverName(sqlDB) == Postgres {
+		return gorp.PostgresDialect{}
+	}
+
+	return gorp.MySQLDialect{
+		Engine:   "InnoDB",
+		Encoding: "UTF8",
+	}
+}
+
+func IsPostgres(dialect gorp.Dialect) bool {
+	_, ok := dialect.(gorp.PostgresDialect)
+	return ok
+}
+
+// Rebind rewrites a query written for MySQL, with `quoted` identifiers and
+// ? placeholders, into the form the dialect expects. String literals are
+// left alone.
+func Rebind(dialect gorp.Dialect, query string) string {
+	if !IsPostgres(dialect) {
+		return query
+	}
+
+	var rebound strings.Builder
+	inLiteral := false
+	placeholder := 0
+
+	for _, r := range query {
+		switch {
+		case r == '\'':
+			inLiteral = !inLiteral
+			rebound.WriteRune(r)
+		case inLiteral:
+			rebound.WriteRune(r)
+		case r == '`':
+			rebound.WriteRune('"')
+		case r == '?':
+			placeholder++
+			rebound.WriteString("$" + strconv.Itoa(placeholder))
+		default:
+			rebound.WriteRune(r)
+		}
+	}
+
+	return rebound.String()
+}
+
+// IsDuplicateError reports whether an insert or update failed because it
+// would have violated a unique key.
+func IsDuplicateError(err error) bool {
+	switch e := err.(type) {
+	case nil:
+		return false
+	case *mysql.MySQLError:
+		return e.Number == 1062
+	case *pq.Error:
+		return e.Code == "23505"
+	default:
+		return strings.Contains(err.Error(), "Duplicate entry")
+	}
+}
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"gopkg.in/gorp.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dialect", func() {
+	Describe("DriverName", func() {
+		It("defaults to mysql", func() {
+			Expect(db.DriverName(nil)).To(Equal(db.MySQL))
+		})
+
+		It("reports the driver of the connection pool", func() {
+			Expect(db.DriverName(sqlDB)).To(Equal(db.MySQL))
+			Expect(db.NewDialect(sqlDB)).To(BeAssignableToTypeOf(gorp.MySQLDialect{}))
+		})
+	})
+
+	Describe("Rebind", func() {
+		It("leaves MySQL queries alone", func() {
+			query := "SELECT * FROM `kinds` WHERE `id` = ? AND `client_id` = ?"
+			Expect(db.Rebind(gorp.MySQLDialect{}, query)).To(Equal(query))
+		})
+
+		It("rewrites identifiers and placeholders for PostgreSQL", func() {
+			query := "SELECT * FROM `kinds` WHERE `id` = ? AND `client_id` = ?"
+			Expect(db.Rebind(gorp.PostgresDialect{}, query)).To(Equal(`SELECT * FROM "kinds" WHERE "id" = $1 AND "client_id" = $2`))
+		})
+
+		It("does not touch string literals", func() {
+			query := "UPDATE `jobs` SET `worker_id` = '' WHERE `payload` = 'what?' AND `id` = ?"
+			Expect(db.Rebind(gorp.PostgresDialect{}, query)).To(Equal(`UPDATE "jobs" SET "worker_id" = '' WHERE "payload" = 'what?' AND "id" = $1`))
+		})
+	})
+
+	Describe("IsDuplicateError", func() {
+		It("recognizes a MySQL duplicate entry", func() {
+			Expect(db.IsDuplicateError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'id'"})).To(BeTrue())
+			Expect(db.IsDuplicateError(&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"})).To(BeFalse())
+		})
+
+		It("recognizes a PostgreSQL unique violation", func() {
+			Expect(db.IsDuplicateError(&pq.Error{Code: "23505"})).To(BeTrue())
+			Expect(db.IsDuplicateError(&pq.Error{Code: "42P01"})).To(BeFalse())
+		})
+
+		It("returns false for other errors", func() {
+			Expect(db.IsDuplicateError(nil)).To(BeFalse())
+			Expect(db.IsDuplicateError(errors.New("banana"))).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE `messages` ADD `recipient` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD `client_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD `kind_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD `vcap_request_id` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `messages` ADD `queued_at` datetime DEFAULT NULL;
+UPDATE `messages` SET `queued_at` = `updated_at`;
+ALTER TABLE `messages` MODIFY `queued_at` datetime NOT NULL;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE `messages` DROP COLUMN `recipient`;
+ALTER TABLE `messages` DROP COLUMN `client_id`;
+ALTER TABLE `messages` DROP COLUMN `kind_id`;
+ALTER TABLE `messages` DROP COLUMN `vcap_request_id`;
+ALTER TABLE `messages` DROP COLUMN `queued_at`;
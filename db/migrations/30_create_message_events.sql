@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `message_events` (
+      `primary` int(11) NOT NULL AUTO_INCREMENT,
+      `message_id` varchar(255) NOT NULL,
+      `type` varchar(255) NOT NULL,
+      `attempt` int(11) NOT NULL DEFAULT 0,
+      `smtp_code` int(11) NOT NULL DEFAULT 0,
+      `description` text NOT NULL,
+      `created_at` datetime NOT NULL,
+      PRIMARY KEY (`primary`),
+      KEY `message_id` (`message_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `message_events`;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS `sends` (
+      `id` varchar(255) NOT NULL,
+      `client_id` varchar(255) NOT NULL DEFAULT '',
+      `kind_id` varchar(255) NOT NULL DEFAULT '',
+      `audience` varchar(255) NOT NULL DEFAULT '',
+      `vcap_request_id` varchar(255) NOT NULL DEFAULT '',
+      `resolved` tinyint(1) NOT NULL DEFAULT 0,
+      `created_at` datetime DEFAULT NULL,
+      PRIMARY KEY (`id`),
+      KEY `sends_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE `sends`;
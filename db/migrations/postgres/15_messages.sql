@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS messages (
+      id varchar(255) NOT NULL PRIMARY KEY,
+      status varchar(255) NOT NULL,
+      updated_at timestamp NOT NULL
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE messages;
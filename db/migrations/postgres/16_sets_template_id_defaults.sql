@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+UPDATE clients SET template_id = 'default' WHERE template_id = '' OR template_id IS NULL;
+ALTER TABLE clients ALTER COLUMN template_id SET DEFAULT 'default';
+ALTER TABLE clients ALTER COLUMN template_id SET NOT NULL;
+
+UPDATE kinds SET template_id = 'default' WHERE template_id = '' OR template_id IS NULL;
+ALTER TABLE kinds ALTER COLUMN template_id SET DEFAULT 'default';
+ALTER TABLE kinds ALTER COLUMN template_id SET NOT NULL;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE clients ALTER COLUMN template_id DROP NOT NULL;
+ALTER TABLE clients ALTER COLUMN template_id SET DEFAULT '';
+ALTER TABLE kinds ALTER COLUMN template_id DROP NOT NULL;
+ALTER TABLE kinds ALTER COLUMN template_id SET DEFAULT '';
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS senders (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      CONSTRAINT senders_name_client_id_key UNIQUE (name, client_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE senders;
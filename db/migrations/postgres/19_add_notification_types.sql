@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS notification_types (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      description varchar(255) DEFAULT NULL,
+      critical boolean DEFAULT FALSE,
+      template_id varchar(255) DEFAULT NULL,
+      sender_id varchar(255) DEFAULT NULL,
+      CONSTRAINT notification_types_name_sender_id_key UNIQUE (name, sender_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE notification_types;
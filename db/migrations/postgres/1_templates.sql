@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS templates (
+      "primary" SERIAL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      text text DEFAULT NULL,
+      html text DEFAULT NULL,
+      overridden boolean DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT templates_name_key UNIQUE (name)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE templates;
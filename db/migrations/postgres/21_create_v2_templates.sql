@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS v2_templates (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      html text DEFAULT NULL,
+      text text DEFAULT NULL,
+      subject varchar(255) DEFAULT NULL,
+      metadata text DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      CONSTRAINT v2_templates_name_client_id_key UNIQUE (name, client_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE v2_templates;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS campaigns (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      campaign_type_id varchar(36) DEFAULT NULL,
+      template_id varchar(36) DEFAULT NULL,
+      sender_id varchar(36) DEFAULT NULL,
+      send_to text DEFAULT NULL,
+      text text DEFAULT NULL,
+      html text DEFAULT NULL,
+      subject varchar(255) DEFAULT NULL,
+      reply_to varchar(255) DEFAULT NULL
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE campaigns;
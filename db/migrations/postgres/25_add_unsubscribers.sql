@@ -0,0 +1,11 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS unsubscribers (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      campaign_type_id varchar(36) DEFAULT NULL,
+      user_guid varchar(255) DEFAULT NULL
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE unsubscribers;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE unsubscribers ADD CONSTRAINT user_guid_campaign_type_id UNIQUE (user_guid, campaign_type_id);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE unsubscribers DROP CONSTRAINT user_guid_campaign_type_id;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE v2_templates DROP CONSTRAINT v2_templates_name_client_id_key;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE v2_templates ADD CONSTRAINT v2_templates_name_client_id_key UNIQUE (name, client_id);
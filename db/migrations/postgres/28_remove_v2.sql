@@ -0,0 +1,70 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+DROP TABLE v2_templates;
+DROP TABLE campaigns;
+ALTER TABLE messages DROP COLUMN campaign_id;
+DROP TABLE unsubscribers;
+DROP TABLE campaign_types;
+DROP TABLE senders;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+CREATE TABLE IF NOT EXISTS senders (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      CONSTRAINT senders_name_client_id_key UNIQUE (name, client_id)
+);
+
+CREATE TABLE IF NOT EXISTS notification_types (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      description varchar(255) DEFAULT NULL,
+      critical boolean DEFAULT FALSE,
+      template_id varchar(255) DEFAULT NULL,
+      sender_id varchar(255) DEFAULT NULL,
+      CONSTRAINT notification_types_name_sender_id_key UNIQUE (name, sender_id)
+);
+
+ALTER TABLE notification_types RENAME TO campaign_types;
+
+CREATE TABLE IF NOT EXISTS v2_templates (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      name varchar(255) DEFAULT NULL,
+      html text DEFAULT NULL,
+      text text DEFAULT NULL,
+      subject varchar(255) DEFAULT NULL,
+      metadata text DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      CONSTRAINT v2_templates_name_client_id_key UNIQUE (name, client_id)
+);
+
+CREATE TABLE IF NOT EXISTS campaigns (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      campaign_type_id varchar(36) DEFAULT NULL,
+      template_id varchar(36) DEFAULT NULL,
+      sender_id varchar(36) DEFAULT NULL,
+      send_to text DEFAULT NULL,
+      text text DEFAULT NULL,
+      html text DEFAULT NULL,
+      subject varchar(255) DEFAULT NULL,
+      reply_to varchar(255) DEFAULT NULL
+);
+
+ALTER TABLE campaigns ADD status varchar(255);
+ALTER TABLE campaigns ADD total_messages integer;
+ALTER TABLE campaigns ADD sent_messages integer;
+ALTER TABLE campaigns ADD retry_messages integer;
+ALTER TABLE campaigns ADD failed_messages integer;
+ALTER TABLE campaigns ADD start_time timestamp DEFAULT NULL;
+ALTER TABLE campaigns ADD completed_time timestamp DEFAULT NULL;
+ALTER TABLE messages ADD campaign_id varchar(255);
+
+CREATE TABLE IF NOT EXISTS unsubscribers (
+      id varchar(36) NOT NULL PRIMARY KEY,
+      campaign_type_id varchar(36) DEFAULT NULL,
+      user_guid varchar(255) DEFAULT NULL
+);
+
+ALTER TABLE unsubscribers ADD CONSTRAINT user_guid_campaign_type_id UNIQUE (user_guid, campaign_type_id);
+ALTER TABLE v2_templates DROP CONSTRAINT v2_templates_name_client_id_key;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS clients (
+      "primary" SERIAL PRIMARY KEY,
+      id varchar(255) DEFAULT NULL,
+      description varchar(255) DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT clients_id_key UNIQUE (id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE clients;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS message_events (
+      "primary" SERIAL PRIMARY KEY,
+      message_id varchar(255) NOT NULL,
+      type varchar(255) NOT NULL,
+      attempt integer NOT NULL DEFAULT 0,
+      smtp_code integer NOT NULL DEFAULT 0,
+      description text NOT NULL,
+      created_at timestamp NOT NULL
+);
+
+CREATE INDEX message_events_message_id ON message_events (message_id);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE message_events;
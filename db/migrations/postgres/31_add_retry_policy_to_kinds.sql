@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE kinds ADD retry_max_attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE kinds ADD retry_base_delay integer NOT NULL DEFAULT 0;
+ALTER TABLE kinds ADD retry_max_delay integer NOT NULL DEFAULT 0;
+ALTER TABLE kinds ADD retry_jitter double precision NOT NULL DEFAULT 0;
+ALTER TABLE kinds ADD retry_give_up_after integer NOT NULL DEFAULT 0;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE kinds DROP COLUMN retry_max_attempts;
+ALTER TABLE kinds DROP COLUMN retry_base_delay;
+ALTER TABLE kinds DROP COLUMN retry_max_delay;
+ALTER TABLE kinds DROP COLUMN retry_jitter;
+ALTER TABLE kinds DROP COLUMN retry_give_up_after;
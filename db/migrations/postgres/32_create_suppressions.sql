@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS suppressions (
+      "primary" SERIAL PRIMARY KEY,
+      email varchar(255) NOT NULL,
+      reason varchar(255) NOT NULL DEFAULT '',
+      message_id varchar(255) NOT NULL DEFAULT '',
+      description text,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT suppressions_email_key UNIQUE (email)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE suppressions;
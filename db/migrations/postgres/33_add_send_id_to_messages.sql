@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE messages ADD send_id varchar(255) NOT NULL DEFAULT '';
+CREATE INDEX messages_send_id ON messages (send_id);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP INDEX messages_send_id;
+ALTER TABLE messages DROP COLUMN send_id;
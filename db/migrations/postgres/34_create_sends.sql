@@ -0,0 +1,16 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS sends (
+      id varchar(255) NOT NULL PRIMARY KEY,
+      client_id varchar(255) NOT NULL DEFAULT '',
+      kind_id varchar(255) NOT NULL DEFAULT '',
+      audience varchar(255) NOT NULL DEFAULT '',
+      vcap_request_id varchar(255) NOT NULL DEFAULT '',
+      resolved boolean NOT NULL DEFAULT false,
+      created_at timestamp DEFAULT NULL
+);
+CREATE INDEX sends_created_at ON sends (created_at);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE sends;
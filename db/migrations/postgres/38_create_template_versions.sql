@@ -0,0 +1,25 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE templates ADD version integer NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS template_versions (
+      "primary" SERIAL PRIMARY KEY,
+      template_id varchar(255) NOT NULL,
+      version integer NOT NULL,
+      name varchar(255) DEFAULT NULL,
+      subject text DEFAULT NULL,
+      text text DEFAULT NULL,
+      html text DEFAULT NULL,
+      metadata text DEFAULT NULL,
+      client_id varchar(255) NOT NULL DEFAULT '',
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT template_versions_template_id_version_key UNIQUE (template_id, version)
+);
+
+INSERT INTO template_versions (template_id, version, name, subject, text, html, metadata, client_id, created_at)
+      SELECT id, version, name, subject, text, html, metadata, '', updated_at FROM templates;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE template_versions;
+ALTER TABLE templates DROP COLUMN version;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE messages ADD template_id varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE messages ADD template_version integer NOT NULL DEFAULT 0;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE messages DROP COLUMN template_id;
+ALTER TABLE messages DROP COLUMN template_version;
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS kinds (
+      "primary" SERIAL PRIMARY KEY,
+      id varchar(255) DEFAULT NULL,
+      description varchar(255) DEFAULT NULL,
+      critical boolean DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT kinds_id_client_id_key UNIQUE (id, client_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE kinds;
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE templates ADD locales text;
+UPDATE templates SET locales = '{}';
+
+ALTER TABLE template_versions ADD locales text;
+UPDATE template_versions SET locales = '{}';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE template_versions DROP COLUMN locales;
+ALTER TABLE templates DROP COLUMN locales;
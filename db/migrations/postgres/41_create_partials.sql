@@ -0,0 +1,17 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS partials (
+      "primary" SERIAL PRIMARY KEY,
+      name varchar(255) NOT NULL,
+      body text DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      updated_at timestamp DEFAULT NULL,
+      CONSTRAINT partials_name_key UNIQUE (name)
+);
+
+ALTER TABLE clients ADD layout varchar(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE clients DROP COLUMN layout;
+DROP TABLE partials;
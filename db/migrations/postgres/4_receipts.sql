@@ -0,0 +1,15 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS receipts (
+      "primary" SERIAL PRIMARY KEY,
+      user_guid varchar(255) DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      kind_id varchar(255) DEFAULT NULL,
+      count integer DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT receipts_user_guid_client_id_kind_id_key UNIQUE (user_guid, client_id, kind_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE receipts;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS unsubscribes (
+      "primary" SERIAL PRIMARY KEY,
+      user_id varchar(255) DEFAULT NULL,
+      client_id varchar(255) DEFAULT NULL,
+      kind_id varchar(255) DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT unsubscribes_user_id_client_id_kind_id_key UNIQUE (user_id, client_id, kind_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE unsubscribes;
@@ -0,0 +1,12 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+CREATE TABLE IF NOT EXISTS global_unsubscribes (
+      "primary" SERIAL PRIMARY KEY,
+      user_id varchar(255) DEFAULT NULL,
+      created_at timestamp DEFAULT NULL,
+      CONSTRAINT global_unsubscribes_user_id_key UNIQUE (user_id)
+);
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+DROP TABLE global_unsubscribes;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- SQL in section 'Up' is executed when this migration is applied
+ALTER TABLE templates ADD id varchar(255) DEFAULT NULL;
+ALTER TABLE templates ADD CONSTRAINT templates_id_key UNIQUE (id);
+ALTER TABLE templates DROP CONSTRAINT templates_name_key;
+ALTER TABLE templates ADD subject varchar(255) DEFAULT NULL;
+
+-- +migrate Down
+-- SQL section 'Down' is executed when this migration is rolled back
+ALTER TABLE templates DROP CONSTRAINT templates_id_key;
+ALTER TABLE templates DROP COLUMN id;
+ALTER TABLE templates ADD CONSTRAINT templates_name_key UNIQUE (name);
+ALTER TABLE templates DROP COLUMN subject;
@@ -47,11 +47,31 @@ func (transaction *Transaction) Delete(v ...interface{}) (int64, error) {
 }
 
 func (transaction *Transaction) Exec(query string, v ...interface{}) (sql.Result, error) {
-	return transaction.txn.Exec(query, v...)
+	return transaction.txn.Exec(transaction.rebind(query), v...)
 }
 
+// Insert guards the insert with a savepoint on PostgreSQL, which aborts the
+// whole transaction when a statement fails. This lets callers recover from a
+// duplicate key the same way they do on MySQL.
 func (transaction *Transaction) Insert(v ...interface{}) error {
-	return transaction.txn.Insert(v...)
+	if !IsPostgres(transaction.conn.Dialect) {
+		return transaction.txn.Insert(v...)
+	}
+
+	err := transaction.txn.Savepoint("before_insert")
+	if err != nil {
+		return err
+	}
+
+	err = transaction.txn.Insert(v...)
+	if err != nil {
+		if rollbackErr := transaction.txn.RollbackToSavepoint("before_insert"); rollbackErr != nil {
+			return rollbackErr
+		}
+		return err
+	}
+
+	return transaction.txn.ReleaseSavepoint("before_insert")
 }
 
 func (transaction *Transaction) Rollback() error {
@@ -59,11 +79,11 @@ func (transaction *Transaction) Rollback() error {
 }
 
 func (transaction *Transaction) Select(holder interface{}, query string, args ...interface{}) ([]interface{}, error) {
-	return transaction.txn.Select(holder, query, args...)
+	return transaction.txn.Select(holder, transaction.rebind(query), args...)
 }
 
 func (transaction *Transaction) SelectOne(holder interface{}, query string, args ...interface{}) error {
-	return transaction.txn.SelectOne(holder, query, args...)
+	return transaction.txn.SelectOne(holder, transaction.rebind(query), args...)
 }
 
 func (transaction *Transaction) Get(i interface{}, keys ...interface{}) (interface{}, error) {
@@ -73,3 +93,7 @@ func (transaction *Transaction) Get(i interface{}, keys ...interface{}) (interfa
 func (transaction *Transaction) Update(v ...interface{}) (int64, error) {
 	return transaction.txn.Update(v...)
 }
+
+func (transaction *Transaction) rebind(query string) string {
+	return Rebind(transaction.conn.Dialect, query)
+}
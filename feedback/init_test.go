@@ -0,0 +1,84 @@
+package feedback_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestFeedbackSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "feedback")
+}
+
+// crlf turns a fixture written with plain newlines into an email.
+func crlf(s string) string {
+	return strings.Replace(s, "\n", "\r\n", -1)
+}
+
+var bounceReport = crlf(`From: MAILER-DAEMON@mail.example.com
+To: no-reply@notifications.example.com
+Subject: Undelivered Mail Returned to Sender
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=delivery-status; boundary="boundary"
+
+--boundary
+Content-Type: text/plain
+
+The mail system could not deliver your message.
+
+--boundary
+Content-Type: message/delivery-status
+
+Reporting-MTA: dns; mail.example.com
+Arrival-Date: Tue, 30 Sep 2014 22:47:50 +0000
+
+Final-Recipient: rfc822; someone@example.com
+Original-Recipient: rfc822; someone@example.com
+Action: failed
+Status: 5.1.1
+Diagnostic-Code: smtp; 550 5.1.1 user unknown
+
+--boundary
+Content-Type: text/rfc822-headers
+
+From: no-reply@notifications.example.com
+To: someone@example.com
+Subject: CF Notification: Downtime
+X-CF-Notification-ID: some-message-id
+
+--boundary--
+`)
+
+var complaintReport = crlf(`From: feedback@isp.example.com
+To: no-reply@notifications.example.com
+Subject: Abuse report
+MIME-Version: 1.0
+Content-Type: multipart/report; report-type=feedback-report; boundary="boundary"
+
+--boundary
+Content-Type: text/plain
+
+This is an email abuse report.
+
+--boundary
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+User-Agent: SomeGenerator/1.0
+Version: 1
+
+--boundary
+Content-Type: message/rfc822
+
+From: no-reply@notifications.example.com
+To: Someone <someone@example.com>
+Subject: CF Notification: Downtime
+X-CF-Notification-ID: some-message-id
+
+The body of the original message.
+
+--boundary--
+`)
@@ -0,0 +1,128 @@
+package feedback
+
+import (
+	"bytes"
+	"net"
+	"sync"
+
+	"github.com/chrj/smtpd"
+	"github.com/pivotal-golang/lager"
+)
+
+// Listener is an SMTP server that receives bounce and complaint reports,
+// for example as the return path of outgoing notifications, and hands them
+// to the given handler. Only the mail servers in the allowed networks may
+// connect, since a report can suppress an address.
+type Listener struct {
+	server          *smtpd.Server
+	allowedNetworks []*net.IPNet
+	handler         func(Report) error
+	logger          lager.Logger
+
+	mutex    sync.Mutex
+	listener net.Listener
+	closed   bool
+}
+
+func NewListener(address string, allowedNetworks []*net.IPNet, handler func(Report) error, logger lager.Logger) *Listener {
+	listener := &Listener{
+		allowedNetworks: allowedNetworks,
+		handler:         handler,
+		logger:          logger.Session("feedback-listener"),
+	}
+
+	listener.server = &smtpd.Server{
+		Addr:              address,
+		ConnectionChecker: listener.checkConnection,
+		Handler:           listener.deliver,
+	}
+
+	return listener
+}
+
+func (l *Listener) ListenAndServe() error {
+	listener, err := net.Listen("tcp", l.server.Addr)
+	if err != nil {
+		return err
+	}
+
+	return l.Serve(listener)
+}
+
+// Serve accepts connections on the given listener until it fails or the
+// Listener is closed, in which case it returns nil.
+func (l *Listener) Serve(listener net.Listener) error {
+	l.mutex.Lock()
+	if l.closed {
+		l.mutex.Unlock()
+		return listener.Close()
+	}
+	l.listener = listener
+	l.mutex.Unlock()
+
+	err := l.server.Serve(listener)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return nil
+	}
+
+	return err
+}
+
+// Close stops accepting connections. Reports that are being received are
+// allowed to finish.
+func (l *Listener) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.closed = true
+	if l.listener == nil {
+		return nil
+	}
+
+	return l.listener.Close()
+}
+
+func (l *Listener) checkConnection(peer smtpd.Peer) error {
+	if addr, ok := peer.Addr.(*net.TCPAddr); ok {
+		for _, network := range l.allowedNetworks {
+			if network.Contains(addr.IP) {
+				return nil
+			}
+		}
+	}
+
+	l.logger.Info("refused", lager.Data{"peer": peer.Addr.String()})
+	return smtpd.Error{Code: 554, Message: "not allowed to submit reports"}
+}
+
+// deliver accepts mail that is not a report without acting on it, since
+// rejecting it would only produce another bounce. Reports that cannot be
+// recorded are turned away with a transient error so that the sender tries
+// again later.
+func (l *Listener) deliver(peer smtpd.Peer, envelope smtpd.Envelope) error {
+	report, err := Parse(bytes.NewReader(envelope.Data))
+	if err != nil {
+		l.logger.Info("ignored", lager.Data{
+			"sender": envelope.Sender,
+			"error":  err.Error(),
+		})
+		return nil
+	}
+
+	err = l.handler(report)
+	if err != nil {
+		l.logger.Error("failed", err, lager.Data{"notification_id": report.NotificationID})
+		return smtpd.Error{Code: 451, Message: "report could not be recorded, try again later"}
+	}
+
+	l.logger.Info("recorded", lager.Data{
+		"type":            report.Type,
+		"notification_id": report.NotificationID,
+	})
+
+	return nil
+}
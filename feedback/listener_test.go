@@ -0,0 +1,132 @@
+package feedback_test
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"net/smtp"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Listener", func() {
+	var (
+		listener    net.Listener
+		reports     chan feedback.Report
+		handlerErr  error
+		logger      lager.Logger
+		sendMessage func(data string) error
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, lager.DEBUG))
+
+		reports = make(chan feedback.Report, 1)
+		handlerErr = nil
+
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+		Expect(err).NotTo(HaveOccurred())
+
+		server := feedback.NewListener(listener.Addr().String(), []*net.IPNet{loopback}, func(report feedback.Report) error {
+			if handlerErr != nil {
+				return handlerErr
+			}
+
+			reports <- report
+			return nil
+		}, logger)
+		go server.Serve(listener)
+
+		sendMessage = func(data string) error {
+			return smtp.SendMail(listener.Addr().String(), nil, "MAILER-DAEMON@mail.example.com", []string{"bounces@notifications.example.com"}, []byte(data))
+		}
+	})
+
+	AfterEach(func() {
+		listener.Close()
+	})
+
+	It("hands the reports it receives to the handler", func() {
+		Expect(sendMessage(bounceReport)).To(Succeed())
+
+		var report feedback.Report
+		Eventually(reports).Should(Receive(&report))
+		Expect(report.Type).To(Equal(feedback.Bounce))
+		Expect(report.NotificationID).To(Equal("some-message-id"))
+		Expect(report.Recipient).To(Equal("someone@example.com"))
+	})
+
+	It("accepts and drops mail that is not a report", func() {
+		Expect(sendMessage(crlf("Subject: hello\n\nhello\n"))).To(Succeed())
+		Consistently(reports).ShouldNot(Receive())
+	})
+
+	It("turns the report away with a transient error when it cannot be recorded", func() {
+		handlerErr = errors.New("db is down")
+
+		err := sendMessage(bounceReport)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("451"))
+	})
+
+	It("stops serving without an error when it is closed", func() {
+		closable, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		server := feedback.NewListener(closable.Addr().String(), nil, func(report feedback.Report) error {
+			return nil
+		}, logger)
+
+		served := make(chan error, 1)
+		go func() {
+			served <- server.Serve(closable)
+		}()
+
+		Eventually(func() error {
+			conn, err := net.Dial("tcp", closable.Addr().String())
+			if err == nil {
+				conn.Close()
+			}
+			return err
+		}).Should(Succeed())
+
+		Expect(server.Close()).To(Succeed())
+
+		var serveErr error
+		Eventually(served).Should(Receive(&serveErr))
+		Expect(serveErr).NotTo(HaveOccurred())
+
+		_, err = net.Dial("tcp", closable.Addr().String())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("refuses connections from outside the allowed networks", func() {
+		restricted, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		defer restricted.Close()
+
+		_, elsewhere, err := net.ParseCIDR("10.0.0.0/8")
+		Expect(err).NotTo(HaveOccurred())
+
+		server := feedback.NewListener(restricted.Addr().String(), []*net.IPNet{elsewhere}, func(report feedback.Report) error {
+			reports <- report
+			return nil
+		}, logger)
+		go server.Serve(restricted)
+
+		err = smtp.SendMail(restricted.Addr().String(), nil, "MAILER-DAEMON@mail.example.com", []string{"bounces@notifications.example.com"}, []byte(bounceReport))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("554"))
+		Consistently(reports).ShouldNot(Receive())
+	})
+})
@@ -0,0 +1,211 @@
+package feedback
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+const (
+	Bounce    = "bounce"
+	Complaint = "complaint"
+
+	NotificationIDHeader = "X-CF-Notification-ID"
+)
+
+// Report is a delivery result fed back after the SMTP server accepted a
+// message: either a delivery status notification (RFC 3464) or an abuse
+// feedback report (RFC 5965).
+type Report struct {
+	Type           string
+	NotificationID string
+	Recipient      string
+
+	// Action and Status come from the recipient fields of a delivery status
+	// notification, e.g. "failed" and "5.1.1".
+	Action     string
+	Status     string
+	Diagnostic string
+
+	// FeedbackType comes from an abuse feedback report, e.g. "abuse".
+	FeedbackType string
+}
+
+// HardBounce reports whether the recipient address permanently failed, so
+// that mail to it should stop.
+func (r Report) HardBounce() bool {
+	return r.Type == Bounce && strings.EqualFold(r.Action, "failed") && strings.HasPrefix(r.Status, "5.")
+}
+
+// Description summarizes the report for the message history.
+func (r Report) Description() string {
+	switch r.Type {
+	case Bounce:
+		description := strings.TrimSpace(fmt.Sprintf("%s %s", r.Action, r.Status))
+		if r.Diagnostic != "" {
+			description = fmt.Sprintf("%s: %s", description, r.Diagnostic)
+		}
+		return description
+	case Complaint:
+		return fmt.Sprintf("%s complaint", r.FeedbackType)
+	default:
+		return ""
+	}
+}
+
+type InvalidReportError struct {
+	Err error
+}
+
+func (e InvalidReportError) Error() string {
+	return fmt.Sprintf("invalid feedback report: %s", e.Err)
+}
+
+// Parse reads a multipart/report email and extracts the delivery result it
+// carries. The notification it refers to is found through the
+// X-CF-Notification-ID header of the returned original message.
+func Parse(r io.Reader) (Report, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return Report{}, InvalidReportError{Err: err}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		return Report{}, InvalidReportError{Err: err}
+	}
+
+	if mediaType != "multipart/report" {
+		return Report{}, InvalidReportError{Err: fmt.Errorf("unsupported content type %q", mediaType)}
+	}
+
+	var report Report
+	switch params["report-type"] {
+	case "delivery-status":
+		report.Type = Bounce
+	case "feedback-report":
+		report.Type = Complaint
+	default:
+		return Report{}, InvalidReportError{Err: fmt.Errorf("unsupported report type %q", params["report-type"])}
+	}
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Report{}, InvalidReportError{Err: err}
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch partType {
+		case "message/delivery-status":
+			err = parseDeliveryStatus(part, &report)
+		case "message/feedback-report":
+			err = parseFeedbackReport(part, &report)
+		case "message/rfc822", "text/rfc822-headers":
+			err = parseOriginalHeaders(part, &report)
+		}
+
+		if err != nil {
+			return Report{}, InvalidReportError{Err: err}
+		}
+	}
+
+	if report.NotificationID == "" && report.Recipient == "" {
+		return Report{}, InvalidReportError{Err: errors.New("report does not identify a notification or a recipient")}
+	}
+
+	return report, nil
+}
+
+// parseDeliveryStatus reads the per-message fields followed by one block of
+// fields per recipient, preferring the first recipient that failed.
+func parseDeliveryStatus(r io.Reader, report *Report) error {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	_, err := readFields(reader)
+	if err != nil {
+		return err
+	}
+
+	for {
+		fields, err := readFields(reader)
+		if err != nil {
+			return err
+		}
+
+		if len(fields) == 0 {
+			return nil
+		}
+
+		if report.Action != "" && strings.EqualFold(report.Action, "failed") {
+			continue
+		}
+
+		report.Recipient = typedField(fields.Get("Final-Recipient"))
+		report.Action = strings.ToLower(fields.Get("Action"))
+		report.Status = fields.Get("Status")
+		report.Diagnostic = typedField(fields.Get("Diagnostic-Code"))
+	}
+}
+
+func parseFeedbackReport(r io.Reader, report *Report) error {
+	fields, err := readFields(textproto.NewReader(bufio.NewReader(r)))
+	if err != nil {
+		return err
+	}
+
+	report.FeedbackType = strings.ToLower(fields.Get("Feedback-Type"))
+	if recipient := fields.Get("Original-Rcpt-To"); recipient != "" {
+		report.Recipient = recipient
+	}
+
+	return nil
+}
+
+func parseOriginalHeaders(r io.Reader, report *Report) error {
+	headers, err := readFields(textproto.NewReader(bufio.NewReader(r)))
+	if err != nil {
+		return err
+	}
+
+	report.NotificationID = headers.Get(NotificationIDHeader)
+
+	if report.Recipient == "" {
+		if address, err := mail.ParseAddress(headers.Get("To")); err == nil {
+			report.Recipient = address.Address
+		}
+	}
+
+	return nil
+}
+
+// readFields reads one block of header-style fields, returning an empty
+// block once the input is exhausted.
+func readFields(reader *textproto.Reader) (textproto.MIMEHeader, error) {
+	fields, err := reader.ReadMIMEHeader()
+	if err == io.EOF {
+		return fields, nil
+	}
+
+	return fields, err
+}
+
+// typedField strips the type prefix from fields such as
+// "rfc822; someone@example.com" or "smtp; 550 5.1.1 unknown user".
+func typedField(value string) string {
+	if index := strings.Index(value, ";"); index >= 0 {
+		return strings.TrimSpace(value[index+1:])
+	}
+
+	return strings.TrimSpace(value)
+}
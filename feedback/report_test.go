@@ -0,0 +1,84 @@
+package feedback_test
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parse", func() {
+	It("parses delivery status notifications", func() {
+		report, err := feedback.Parse(strings.NewReader(bounceReport))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(feedback.Report{
+			Type:           feedback.Bounce,
+			NotificationID: "some-message-id",
+			Recipient:      "someone@example.com",
+			Action:         "failed",
+			Status:         "5.1.1",
+			Diagnostic:     "550 5.1.1 user unknown",
+		}))
+		Expect(report.HardBounce()).To(BeTrue())
+		Expect(report.Description()).To(Equal("failed 5.1.1: 550 5.1.1 user unknown"))
+	})
+
+	It("does not treat delayed deliveries as hard bounces", func() {
+		delayed := strings.Replace(bounceReport, "Action: failed\r\nStatus: 5.1.1", "Action: delayed\r\nStatus: 4.2.2", 1)
+
+		report, err := feedback.Parse(strings.NewReader(delayed))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Action).To(Equal("delayed"))
+		Expect(report.HardBounce()).To(BeFalse())
+	})
+
+	It("parses abuse feedback reports", func() {
+		report, err := feedback.Parse(strings.NewReader(complaintReport))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report).To(Equal(feedback.Report{
+			Type:           feedback.Complaint,
+			NotificationID: "some-message-id",
+			Recipient:      "someone@example.com",
+			FeedbackType:   "abuse",
+		}))
+		Expect(report.HardBounce()).To(BeFalse())
+		Expect(report.Description()).To(Equal("abuse complaint"))
+	})
+
+	Context("failure cases", func() {
+		It("returns an error when the email is not a report", func() {
+			_, err := feedback.Parse(strings.NewReader(crlf("Content-Type: text/plain\n\nhello\n")))
+			Expect(err).To(MatchError(feedback.InvalidReportError{Err: errors.New(`unsupported content type "text/plain"`)}))
+		})
+
+		It("returns an error when the report type is not supported", func() {
+			report := strings.Replace(bounceReport, "report-type=delivery-status", "report-type=disposition-notification", 1)
+
+			_, err := feedback.Parse(strings.NewReader(report))
+			Expect(err).To(MatchError(feedback.InvalidReportError{Err: errors.New(`unsupported report type "disposition-notification"`)}))
+		})
+
+		It("returns an error when the report identifies neither a notification nor a recipient", func() {
+			report := crlf(`Content-Type: multipart/report; report-type=feedback-report; boundary="boundary"
+
+--boundary
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+
+--boundary--
+`)
+
+			_, err := feedback.Parse(strings.NewReader(report))
+			Expect(err).To(BeAssignableToTypeOf(feedback.InvalidReportError{}))
+		})
+
+		It("returns an error when the email cannot be read", func() {
+			_, err := feedback.Parse(strings.NewReader(""))
+			Expect(err).To(BeAssignableToTypeOf(feedback.InvalidReportError{}))
+		})
+	})
+})
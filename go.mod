@@ -11,6 +11,7 @@ require (
 	github.com/go-sql-driver/mysql v1.4.1
 	github.com/gorilla/context v0.0.0-20140306180640-1be7a086a5fd // indirect
 	github.com/gorilla/mux v0.0.0-20131205071822-9ede152210fa
+	github.com/lib/pq v1.9.0
 	github.com/onsi/ginkgo v0.0.0-20141213001613-291f28e66dd2
 	github.com/onsi/gomega v0.0.0-20150530211311-d6c945f9fdbf
 	github.com/pivotal-cf-experimental/rainmaker v0.0.0-20160401052143-d533d01b7c52
@@ -4,4 +4,12 @@ import "time"
 
 type Config struct {
 	WaitMaxDuration time.Duration
+
+	// ReserveBatchSize is the number of jobs a queue claims at once when the
+	// database supports SELECT ... FOR UPDATE SKIP LOCKED.
+	ReserveBatchSize int
+
+	// PriorityAgingInterval is how long a job waits before it is reserved
+	// as if it had one more level of priority.
+	PriorityAgingInterval time.Duration
 }
@@ -3,10 +3,12 @@ package gobble
 import (
 	"database/sql"
 
+	"github.com/cloudfoundry-incubator/notifications/db"
 	sql_migrate "github.com/rubenv/sql-migrate"
 	"gopkg.in/gorp.v1"
 
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 )
 
 type DatabaseInterface interface {
@@ -17,17 +19,18 @@ type ConnectionInterface interface {
 	Insert(...interface{}) error
 }
 
+type ExecutorInterface interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
 type DB struct {
 	Connection *gorp.DbMap
 }
 
-func NewDatabase(db *sql.DB) *DB {
+func NewDatabase(sqlDB *sql.DB) *DB {
 	conn := &gorp.DbMap{
-		Db: db,
-		Dialect: gorp.MySQLDialect{
-			Engine:   "InnoDB",
-			Encoding: "UTF8",
-		},
+		Db:      sqlDB,
+		Dialect: db.NewDialect(sqlDB),
 	}
 
 	Initializer{}.InitializeDBMap(conn)
@@ -39,16 +42,23 @@ type Initializer struct{}
 
 func (Initializer) InitializeDBMap(dbMap *gorp.DbMap) {
 	dbMap.AddTableWithName(Job{}, "jobs").SetKeys(true, "ID").SetVersionCol("Version")
+	dbMap.AddTableWithName(DeadJob{}, "dead_jobs").SetKeys(true, "ID")
+}
+
+// rebind prepares a query written for MySQL for the dialect of the
+// database.
+func (database DB) rebind(query string) string {
+	return db.Rebind(database.Connection.Dialect, query)
 }
 
-func (db DB) Migrate(migrationsPath string) {
+func (database DB) Migrate(migrationsPath string) {
 	sql_migrate.SetTable("gobble_model_migrations")
 
 	migrations := &sql_migrate.FileMigrationSource{
 		Dir: migrationsPath,
 	}
 
-	_, err := sql_migrate.Exec(db.Connection.Db, "mysql", migrations, sql_migrate.Up)
+	_, err := sql_migrate.Exec(database.Connection.Db, db.DriverName(database.Connection.Db), migrations, sql_migrate.Up)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,195 @@
+package gobble
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+type DeadJob struct {
+	ID              int       `db:"id"`
+	JobID           int       `db:"job_id"`
+	Payload         string    `db:"payload"`
+	Attempts        int       `db:"attempts"`
+	Priority        int       `db:"priority"`
+	LastError       string    `db:"last_error"`
+	LastAttemptedAt time.Time `db:"last_attempted_at"`
+	FailedAt        time.Time `db:"failed_at"`
+}
+
+type DeadJobNotFoundError struct {
+	ID int
+}
+
+func (e DeadJobNotFoundError) Error() string {
+	return fmt.Sprintf("Dead job with id %d could not be found", e.ID)
+}
+
+// Bury moves a job that has exhausted its retries into the dead jobs table.
+func (queue *Queue) Bury(job *Job) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		panic(err)
+	}
+
+	err = transaction.Insert(&DeadJob{
+		JobID:           job.ID,
+		Payload:         job.Payload,
+		Attempts:        job.RetryCount + 1,
+		Priority:        job.Priority,
+		LastError:       job.LastError,
+		LastAttemptedAt: job.ActiveAt,
+		FailedAt:        queue.clock.Now(),
+	})
+	if err != nil {
+		transaction.Rollback()
+		panic(err)
+	}
+
+	_, err = transaction.Delete(job)
+	if err != nil {
+		transaction.Rollback()
+		if _, ok := err.(gorp.OptimisticLockError); ok && strings.Contains(err.Error(), "no row found") {
+			return
+		}
+		panic(err)
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// DeadJobs lists the dead jobs without their payloads, oldest first.
+func (queue *Queue) DeadJobs() ([]DeadJob, error) {
+	deadJobs := []DeadJob{}
+	_, err := queue.database.Connection.Select(&deadJobs, queue.database.rebind("SELECT `id`, `job_id`, `attempts`, `priority`, `last_error`, `last_attempted_at`, `failed_at` FROM `dead_jobs` ORDER BY `id`"))
+	if err != nil {
+		return []DeadJob{}, err
+	}
+
+	return deadJobs, nil
+}
+
+func (queue *Queue) FindDeadJob(id int) (DeadJob, error) {
+	deadJob := DeadJob{}
+	err := queue.database.Connection.SelectOne(&deadJob, queue.database.rebind("SELECT * FROM `dead_jobs` WHERE `id` = ?"), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return DeadJob{}, DeadJobNotFoundError{id}
+		}
+		return DeadJob{}, err
+	}
+
+	return deadJob, nil
+}
+
+// Replay re-enqueues a dead job as a new job with a fresh retry count.
+func (queue *Queue) Replay(id int) (*Job, error) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	deadJob := DeadJob{}
+	err = transaction.SelectOne(&deadJob, queue.database.rebind("SELECT * FROM `dead_jobs` WHERE `id` = ? FOR UPDATE"), id)
+	if err != nil {
+		transaction.Rollback()
+		if err == sql.ErrNoRows {
+			return nil, DeadJobNotFoundError{id}
+		}
+		return nil, err
+	}
+
+	job, err := queue.Enqueue(&Job{Payload: deadJob.Payload, Priority: deadJob.Priority}, transaction)
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	_, err = transaction.Delete(&deadJob)
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// ReplayAll re-enqueues every dead job and returns the number replayed.
+func (queue *Queue) ReplayAll() (int, error) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	maxID, err := transaction.SelectInt(queue.database.rebind("SELECT COALESCE(MAX(`id`), 0) FROM `dead_jobs`"))
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	now := queue.clock.Now()
+	result, err := transaction.Exec(queue.database.rebind("INSERT INTO `jobs` (`worker_id`, `payload`, `version`, `retry_count`, `active_at`, `priority`, `claim_rank`) SELECT '', `payload`, 1, 0, ?, `priority`, "+queue.claimRankExpression(now)+" FROM `dead_jobs` WHERE `id` <= ?"), now, maxID)
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	replayed, err := result.RowsAffected()
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	_, err = transaction.Exec(queue.database.rebind("DELETE FROM `dead_jobs` WHERE `id` <= ?"), maxID)
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	err = transaction.Commit()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(replayed), nil
+}
+
+func (queue *Queue) Purge(id int) error {
+	result, err := queue.database.Connection.Exec(queue.database.rebind("DELETE FROM `dead_jobs` WHERE `id` = ?"), id)
+	if err != nil {
+		return err
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if purged == 0 {
+		return DeadJobNotFoundError{id}
+	}
+
+	return nil
+}
+
+// PurgeAll deletes every dead job and returns the number purged.
+func (queue *Queue) PurgeAll() (int, error) {
+	result, err := queue.database.Connection.Exec(queue.database.rebind("DELETE FROM `dead_jobs`"))
+	if err != nil {
+		return 0, err
+	}
+
+	purged, err := result.RowsAffected()
+	return int(purged), err
+}
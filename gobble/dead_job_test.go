@@ -0,0 +1,237 @@
+package gobble_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dead jobs", func() {
+	var (
+		queue    *gobble.Queue
+		database *gobble.DB
+		clock    *mocks.Clock
+	)
+
+	BeforeEach(func() {
+		TruncateTables()
+		database = gobble.NewDatabase(sqlDB)
+		clock = &mocks.Clock{}
+		clock.NowCall.Returns.Time = time.Now().UTC().Truncate(time.Second)
+
+		queue = gobble.NewQueue(database, clock, gobble.Config{
+			WaitMaxDuration: 50 * time.Millisecond,
+		})
+	})
+
+	AfterEach(func() {
+		queue.Close()
+	})
+
+	buryJob := func(payload, reason string) *gobble.Job {
+		job, err := queue.Enqueue(&gobble.Job{
+			Payload:    payload,
+			RetryCount: 10,
+		}, database.Connection)
+		Expect(err).NotTo(HaveOccurred())
+
+		job.Bury(reason)
+		queue.Bury(job)
+
+		return job
+	}
+
+	Describe("Bury", func() {
+		It("moves the job into the dead jobs table", func() {
+			job := buryJob("the-payload", "smtp is down")
+
+			results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(0))
+
+			deadJobs := []gobble.DeadJob{}
+			_, err = database.Connection.Select(&deadJobs, "SELECT * FROM `dead_jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(HaveLen(1))
+
+			deadJob := deadJobs[0]
+			Expect(deadJob.JobID).To(Equal(job.ID))
+			Expect(deadJob.Payload).To(Equal("the-payload"))
+			Expect(deadJob.Attempts).To(Equal(11))
+			Expect(deadJob.LastError).To(Equal("smtp is down"))
+			Expect(deadJob.LastAttemptedAt).To(BeTemporally("~", job.ActiveAt, time.Second))
+			Expect(deadJob.FailedAt).To(BeTemporally("~", clock.NowCall.Returns.Time, time.Second))
+		})
+
+		It("does not record a dead job when the job is already gone", func() {
+			job := buryJob("the-payload", "smtp is down")
+
+			Expect(func() {
+				queue.Bury(job)
+			}).NotTo(Panic())
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(HaveLen(1))
+		})
+	})
+
+	Describe("DeadJobs", func() {
+		It("lists the dead jobs without their payloads", func() {
+			first := buryJob("first-payload", "first error")
+			second := buryJob("second-payload", "second error")
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(HaveLen(2))
+
+			Expect(deadJobs[0].JobID).To(Equal(first.ID))
+			Expect(deadJobs[0].LastError).To(Equal("first error"))
+			Expect(deadJobs[0].Payload).To(BeEmpty())
+			Expect(deadJobs[1].JobID).To(Equal(second.ID))
+		})
+	})
+
+	Describe("FindDeadJob", func() {
+		It("returns the dead job with its payload", func() {
+			buryJob("the-payload", "smtp is down")
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+
+			deadJob, err := queue.FindDeadJob(deadJobs[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJob.Payload).To(Equal("the-payload"))
+		})
+
+		It("returns a not found error when the dead job does not exist", func() {
+			_, err := queue.FindDeadJob(42)
+			Expect(err).To(MatchError(gobble.DeadJobNotFoundError{ID: 42}))
+		})
+	})
+
+	Describe("Replay", func() {
+		It("re-enqueues the dead job with a fresh retry count", func() {
+			buryJob("the-payload", "smtp is down")
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+
+			job, err := queue.Replay(deadJobs[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Payload).To(Equal("the-payload"))
+			Expect(job.RetryCount).To(Equal(0))
+
+			length, err := queue.Len()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(length).To(Equal(1))
+
+			deadJobs, err = queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(BeEmpty())
+		})
+
+		It("keeps the priority of the job", func() {
+			job, err := queue.Enqueue(&gobble.Job{
+				Payload:  "the-payload",
+				Priority: gobble.PriorityHigh,
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			job.Bury("smtp is down")
+			queue.Bury(job)
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs[0].Priority).To(Equal(gobble.PriorityHigh))
+
+			job, err = queue.Replay(deadJobs[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Priority).To(Equal(gobble.PriorityHigh))
+		})
+
+		It("returns a not found error when the dead job does not exist", func() {
+			_, err := queue.Replay(42)
+			Expect(err).To(MatchError(gobble.DeadJobNotFoundError{ID: 42}))
+		})
+	})
+
+	Describe("ReplayAll", func() {
+		It("re-enqueues every dead job", func() {
+			buryJob("first-payload", "smtp is down")
+			buryJob("second-payload", "smtp is down")
+
+			count, err := queue.ReplayAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			jobs := []gobble.Job{}
+			_, err = database.Connection.Select(&jobs, "SELECT * FROM `jobs` ORDER BY `id`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs).To(HaveLen(2))
+			Expect(jobs[0].Payload).To(Equal("first-payload"))
+			Expect(jobs[0].RetryCount).To(Equal(0))
+			Expect(jobs[0].WorkerID).To(BeEmpty())
+			Expect(jobs[1].Payload).To(Equal("second-payload"))
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(BeEmpty())
+		})
+
+		It("ranks the replayed jobs like jobs of their priority that are enqueued now", func() {
+			buryJob("the-payload", "smtp is down")
+
+			_, err := queue.ReplayAll()
+			Expect(err).NotTo(HaveOccurred())
+
+			enqueued, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			jobs := []gobble.Job{}
+			_, err = database.Connection.Select(&jobs, "SELECT * FROM `jobs` ORDER BY `id`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs).To(HaveLen(2))
+			Expect(jobs[0].ClaimRank).To(Equal(enqueued.ClaimRank))
+		})
+	})
+
+	Describe("Purge", func() {
+		It("deletes the dead job", func() {
+			buryJob("the-payload", "smtp is down")
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+
+			err = queue.Purge(deadJobs[0].ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			deadJobs, err = queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(BeEmpty())
+		})
+
+		It("returns a not found error when the dead job does not exist", func() {
+			err := queue.Purge(42)
+			Expect(err).To(MatchError(gobble.DeadJobNotFoundError{ID: 42}))
+		})
+	})
+
+	Describe("PurgeAll", func() {
+		It("deletes every dead job", func() {
+			buryJob("first-payload", "smtp is down")
+			buryJob("second-payload", "smtp is down")
+
+			count, err := queue.PurgeAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(2))
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(BeEmpty())
+		})
+	})
+})
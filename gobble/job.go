@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+// Jobs are reserved highest priority first. A job that has been waiting
+// gains a priority level for every PriorityAgingInterval it waits, so low
+// priority work is not starved by a steady stream of higher priority jobs.
+const (
+	PriorityLow    = 1
+	PriorityNormal = 5
+	PriorityHigh   = 10
+)
+
 type Job struct {
 	ID          int       `db:"id"`
 	WorkerID    string    `db:"worker_id"`
@@ -12,7 +21,11 @@ type Job struct {
 	Version     int64     `db:"version"`
 	RetryCount  int       `db:"retry_count"`
 	ActiveAt    time.Time `db:"active_at"`
+	Priority    int       `db:"priority"`
+	ClaimRank   int64     `db:"claim_rank"`
 	ShouldRetry bool      `db:"-"`
+	ShouldBury  bool      `db:"-"`
+	LastError   string    `db:"-"`
 }
 
 func NewJob(data interface{}) *Job {
@@ -22,7 +35,8 @@ func NewJob(data interface{}) *Job {
 	}
 
 	return &Job{
-		Payload: string(payload),
+		Payload:  string(payload),
+		Priority: PriorityNormal,
 	}
 }
 
@@ -37,6 +51,14 @@ func (job *Job) Retry(duration time.Duration) {
 	job.ShouldRetry = true
 }
 
+// Bury marks a job that will not be retried so that the worker moves it to
+// the dead jobs table instead of discarding it.
+func (job *Job) Bury(reason string) {
+	job.ShouldRetry = false
+	job.ShouldBury = true
+	job.LastError = reason
+}
+
 func (job *Job) State() (int, time.Time) {
 	return job.RetryCount, job.ActiveAt
 }
@@ -21,6 +21,7 @@ var _ = Describe("Job", func() {
 
 			Expect(job).To(BeAssignableToTypeOf(&gobble.Job{}))
 			Expect(job.Payload).To(Equal(`{"example":"another field","test":"testing a new job"}`))
+			Expect(job.Priority).To(Equal(gobble.PriorityNormal))
 		})
 	})
 
@@ -57,6 +58,19 @@ var _ = Describe("Job", func() {
 		})
 	})
 
+	Describe("Bury", func() {
+		It("marks the job to be moved to the dead jobs table", func() {
+			job := gobble.NewJob("the data")
+			job.ShouldRetry = true
+
+			job.Bury("smtp is down")
+
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(job.ShouldRetry).To(BeFalse())
+			Expect(job.LastError).To(Equal("smtp is down"))
+		})
+	})
+
 	Describe("State", func() {
 		It("returns the current retry count and active at values", func() {
 			expectedActiveAt := time.Now().Add(-5 * time.Minute)
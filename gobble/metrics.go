@@ -9,6 +9,7 @@ import (
 type QueueGauge struct {
 	queue queue
 	timer <-chan time.Time
+	halt  chan struct{}
 }
 
 type queue interface {
@@ -19,13 +20,24 @@ func NewQueueGauge(queue queue, timer <-chan time.Time) QueueGauge {
 	return QueueGauge{
 		queue: queue,
 		timer: timer,
+		halt:  make(chan struct{}),
 	}
 }
 
 func (g QueueGauge) Run() {
-	for range g.timer {
-		ql, _ := g.queue.Len()
+	for {
+		select {
+		case <-g.timer:
+			ql, _ := g.queue.Len()
 
-		metrics.GetOrRegisterGauge("notifications.queue.length", nil).Update(int64(ql))
+			metrics.GetOrRegisterGauge("notifications.queue.length", nil).Update(int64(ql))
+		case <-g.halt:
+			return
+		}
 	}
 }
+
+// Halt stops the gauge from reporting the length of the queue.
+func (g QueueGauge) Halt() {
+	close(g.halt)
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE `jobs` ADD `priority` int(11) NOT NULL DEFAULT '5';
+ALTER TABLE `dead_jobs` ADD `priority` int(11) NOT NULL DEFAULT '5';
+
+-- +migrate Down
+ALTER TABLE `jobs` DROP COLUMN `priority`;
+ALTER TABLE `dead_jobs` DROP COLUMN `priority`;
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE `jobs` ADD `claim_rank` bigint(20) NOT NULL DEFAULT '0';
+UPDATE `jobs` SET `claim_rank` = UNIX_TIMESTAMP(`active_at`) * 1000 - (`priority` - 1) * 60000 WHERE `priority` < 10;
+CREATE INDEX `jobs_worker_id_claim_rank_active_at` ON `jobs` (`worker_id`, `claim_rank`, `active_at`);
+
+-- +migrate Down
+DROP INDEX `jobs_worker_id_claim_rank_active_at` ON `jobs`;
+ALTER TABLE `jobs` DROP COLUMN `claim_rank`;
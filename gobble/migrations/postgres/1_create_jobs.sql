@@ -0,0 +1,10 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS jobs (
+  id SERIAL PRIMARY KEY,
+  worker_id varchar(255) DEFAULT NULL,
+  payload text DEFAULT NULL,
+  version bigint DEFAULT NULL
+);
+
+-- +migrate Down
+DROP TABLE jobs;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS retry_count integer NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS active_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- +migrate Down
+ALTER TABLE jobs DROP COLUMN retry_count;
+ALTER TABLE jobs DROP COLUMN active_at;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- PostgreSQL text columns have no length limit, so there is nothing to change.
+SELECT 1;
+
+-- +migrate Down
+SELECT 1;
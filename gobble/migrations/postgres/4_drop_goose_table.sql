@@ -0,0 +1,5 @@
+-- +migrate Up
+DROP TABLE IF EXISTS goose_db_version;
+
+-- +migrate Down
+SELECT 1;
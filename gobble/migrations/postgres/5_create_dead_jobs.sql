@@ -0,0 +1,13 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS dead_jobs (
+  id SERIAL PRIMARY KEY,
+  job_id integer NOT NULL,
+  payload text DEFAULT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  last_error text NOT NULL,
+  last_attempted_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  failed_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +migrate Down
+DROP TABLE dead_jobs;
@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX jobs_worker_id_active_at ON jobs (worker_id, active_at);
+
+-- +migrate Down
+DROP INDEX jobs_worker_id_active_at;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS priority integer NOT NULL DEFAULT 5;
+ALTER TABLE dead_jobs ADD COLUMN IF NOT EXISTS priority integer NOT NULL DEFAULT 5;
+
+-- +migrate Down
+ALTER TABLE jobs DROP COLUMN priority;
+ALTER TABLE dead_jobs DROP COLUMN priority;
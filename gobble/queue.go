@@ -2,20 +2,30 @@ package gobble
 
 import (
 	"database/sql"
+	"fmt"
 	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
 	"gopkg.in/gorp.v1"
 )
 
-var WaitMaxDuration = 5 * time.Second
+var (
+	WaitMaxDuration       = 5 * time.Second
+	PriorityAgingInterval = 1 * time.Minute
+)
+
+const removeBatchSize = 500
 
 type QueueInterface interface {
 	Enqueue(*Job, ConnectionInterface) (*Job, error)
 	Reserve(string) <-chan *Job
 	Dequeue(*Job)
 	Requeue(*Job)
+	Bury(*Job)
 	Len() (int, error)
 }
 
@@ -27,7 +37,11 @@ type Queue struct {
 	config   Config
 	database *DB
 	clock    clock
-	closed   bool
+	done     chan struct{}
+
+	mutex                 sync.Mutex
+	reserved              []*Job
+	skipLockedUnsupported bool
 }
 
 func NewQueue(database DatabaseInterface, clock clock, config Config) *Queue {
@@ -35,10 +49,19 @@ func NewQueue(database DatabaseInterface, clock clock, config Config) *Queue {
 		config.WaitMaxDuration = WaitMaxDuration
 	}
 
+	if config.ReserveBatchSize == 0 {
+		config.ReserveBatchSize = 1
+	}
+
+	if config.PriorityAgingInterval == 0 {
+		config.PriorityAgingInterval = PriorityAgingInterval
+	}
+
 	return &Queue{
 		database: database.(*DB),
 		clock:    clock,
 		config:   config,
+		done:     make(chan struct{}),
 	}
 }
 
@@ -47,6 +70,11 @@ func (queue *Queue) Enqueue(job *Job, connection ConnectionInterface) (*Job, err
 		job.ActiveAt = queue.clock.Now()
 	}
 
+	if job.Priority == 0 {
+		job.Priority = PriorityNormal
+	}
+	job.ClaimRank = queue.claimRank(job.Priority, job.ActiveAt)
+
 	err := connection.Insert(job)
 	if err != nil {
 		return job, err
@@ -56,6 +84,7 @@ func (queue *Queue) Enqueue(job *Job, connection ConnectionInterface) (*Job, err
 }
 
 func (queue *Queue) Requeue(job *Job) {
+	job.ClaimRank = queue.claimRank(job.Priority, job.ActiveAt)
 	_, err := queue.database.Connection.Update(job)
 	if err != nil {
 		panic(err)
@@ -63,12 +92,26 @@ func (queue *Queue) Requeue(job *Job) {
 }
 
 func (queue *Queue) Len() (int, error) {
-	length, err := queue.database.Connection.SelectInt("SELECT COUNT(*) FROM `jobs`")
+	length, err := queue.database.Connection.SelectInt(queue.database.rebind("SELECT COUNT(*) FROM `jobs`"))
 	return int(length), err
 }
 
+// Close stops the queue from reserving jobs. Reserve calls that are still
+// waiting give up, and jobs the queue has claimed but not handed out to a
+// worker are released for other processes to pick up.
 func (queue *Queue) Close() {
-	queue.closed = true
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.isClosed() {
+		return
+	}
+	close(queue.done)
+
+	for _, job := range queue.reserved {
+		queue.updateJob(job, "")
+	}
+	queue.reserved = nil
 }
 
 func (queue *Queue) Reserve(workerID string) <-chan *Job {
@@ -83,8 +126,9 @@ func (queue *Queue) reserve(channel chan *Job, workerID string) {
 	for job == nil {
 		var err error
 
-		job = queue.findJob()
-		if queue.closed {
+		job = queue.findJob(workerID)
+		if queue.isClosed() {
+			queue.updateJob(job, "")
 			return
 		}
 
@@ -99,12 +143,48 @@ func (queue *Queue) reserve(channel chan *Job, workerID string) {
 		}
 	}
 
-	if queue.closed {
+	if queue.isClosed() {
 		queue.updateJob(job, "")
 		return
 	}
 
-	channel <- job
+	select {
+	case channel <- job:
+	case <-queue.done:
+		queue.updateJob(job, "")
+	}
+}
+
+// Remove deletes the given jobs, using a connection that is passed in, unless
+// a worker has already reserved them. It returns the number of jobs removed.
+func (queue *Queue) Remove(ids []int, connection ExecutorInterface) (int, error) {
+	var removed int64
+	for start := 0; start < len(ids); start += removeBatchSize {
+		end := start + removeBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		batch := ids[start:end]
+		args := []interface{}{}
+		for _, id := range batch {
+			args = append(args, id)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(batch)), ", ")
+		result, err := connection.Exec(queue.database.rebind("DELETE FROM `jobs` WHERE `worker_id` = '' AND `id` IN ("+placeholders+")"), args...)
+		if err != nil {
+			return int(removed), err
+		}
+
+		count, err := result.RowsAffected()
+		if err != nil {
+			return int(removed), err
+		}
+		removed += count
+	}
+
+	return int(removed), nil
 }
 
 func (queue *Queue) Dequeue(job *Job) {
@@ -117,23 +197,187 @@ func (queue *Queue) Dequeue(job *Job) {
 	}
 }
 
-func (queue *Queue) findJob() *Job {
-	var job *Job
-	for job == nil {
-		job = &Job{}
-		now := time.Now()
-		expired := now.Add(-2 * time.Minute)
-		err := queue.database.Connection.SelectOne(job, "SELECT * FROM `jobs` WHERE ( `worker_id` = \"\" AND `active_at` <= ? ) OR `active_at` <= ? LIMIT 1", now, expired)
+func (queue *Queue) findJob(workerID string) *Job {
+	for {
+		job, err := queue.nextJob(workerID)
 		if err != nil {
 			if err == sql.ErrNoRows {
-				job = nil
+				if queue.isClosed() {
+					return nil
+				}
+
 				queue.waitUpTo(queue.config.WaitMaxDuration)
 				continue
 			}
 			panic(err)
 		}
+
+		return job
+	}
+}
+
+// nextJob hands out a job that this queue has already claimed, or claims
+// a new batch with SELECT ... FOR UPDATE SKIP LOCKED. Servers without SKIP
+// LOCKED support (before MySQL 8 and MariaDB 10.6) fall back to selecting a
+// single unclaimed job, which concurrent workers then race to update.
+func (queue *Queue) nextJob(workerID string) (*Job, error) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if queue.isClosed() {
+		return nil, sql.ErrNoRows
+	}
+
+	if len(queue.reserved) > 0 {
+		job := queue.reserved[0]
+		queue.reserved = queue.reserved[1:]
+		return job, nil
+	}
+
+	if !queue.skipLockedUnsupported {
+		jobs, err := queue.claimJobs(workerID)
+		switch {
+		case err == nil && len(jobs) == 0:
+			return nil, sql.ErrNoRows
+		case err == nil:
+			queue.reserved = jobs[1:]
+			return jobs[0], nil
+		case isSkipLockedUnsupported(err):
+			queue.skipLockedUnsupported = true
+		default:
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	for _, query := range queue.availableJobQueries(now) {
+		job := &Job{}
+		err := queue.database.Connection.SelectOne(job, queue.database.rebind("SELECT * FROM `jobs` WHERE "+query.condition+" LIMIT 1"), query.args...)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		return job, nil
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// claimJobs locks up to ReserveBatchSize available jobs, skipping the rows
+// other workers have locked, and marks them as taken by the given worker.
+func (queue *Queue) claimJobs(workerID string) ([]*Job, error) {
+	transaction, err := queue.database.Connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*Job
+	now := time.Now()
+	for _, query := range queue.availableJobQueries(now) {
+		remaining := queue.config.ReserveBatchSize - len(jobs)
+		if remaining == 0 {
+			break
+		}
+
+		var claimed []*Job
+		args := append(append([]interface{}{}, query.args...), remaining)
+		_, err = transaction.Select(&claimed, queue.database.rebind("SELECT * FROM `jobs` WHERE "+query.condition+" LIMIT ? FOR UPDATE SKIP LOCKED"), args...)
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+		jobs = append(jobs, claimed...)
+	}
+
+	for _, job := range jobs {
+		job.WorkerID = workerID
+		job.ActiveAt = now
+		job.ClaimRank = queue.claimRank(job.Priority, job.ActiveAt)
+		_, err = transaction.Update(job)
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+	}
+
+	return jobs, transaction.Commit()
+}
+
+type jobQuery struct {
+	condition string
+	args      []interface{}
+}
+
+// availableJobQueries returns the conditions, each with its ORDER BY, that
+// find the jobs a worker may reserve, in the order they are claimed:
+// critical jobs oldest first, then jobs whose worker stopped sending
+// heartbeats, then all other jobs by claim rank. Every query is served by an
+// index, so a claim reads only the rows it returns and those a concurrent
+// claim has locked, instead of sorting every available job: the critical and
+// ranked queries read jobs_worker_id_claim_rank_active_at in index order
+// (EXPLAIN shows a range scan of that index without "Using filesort"), and
+// the expired query reads the few reserved jobs from jobs_worker_id_active_at.
+// The ranked query stops at the rank of a lowest priority job that becomes
+// active now, so the jobs it skips because they are not active yet are
+// bounded to those becoming active within the next PriorityHigh-PriorityLow-1
+// aging intervals.
+func (queue *Queue) availableJobQueries(now time.Time) []jobQuery {
+	return []jobQuery{
+		{
+			condition: "`worker_id` = '' AND `claim_rank` = 0 AND `active_at` <= ? ORDER BY `active_at`",
+			args:      []interface{}{now},
+		},
+		{
+			condition: "`worker_id` <> '' AND `active_at` <= ? ORDER BY `active_at`",
+			args:      []interface{}{now.Add(-2 * time.Minute)},
+		},
+		{
+			condition: "`worker_id` = '' AND `claim_rank` BETWEEN 1 AND ? AND `active_at` <= ? ORDER BY `claim_rank`, `active_at`",
+			args:      []interface{}{queue.claimRank(PriorityLow, now), now},
+		},
+	}
+}
+
+// claimRank returns the rank that orders a job with the given priority and
+// active time among the other jobs that are not critical, lowest first. It is
+// the active time in milliseconds, less one PriorityAgingInterval for every
+// level of priority above PriorityLow, so a job overtakes a job with one more
+// level of priority once it has been active one interval longer, and jobs
+// never overtake critical jobs, which have a rank of 0 and are claimed first.
+// The rank is stored with the job so that claims can be ordered by an index.
+func (queue *Queue) claimRank(priority int, activeAt time.Time) int64 {
+	if priority >= PriorityHigh {
+		return 0
+	}
+
+	headStart := time.Duration(priority-PriorityLow) * queue.config.PriorityAgingInterval
+	return activeAt.Add(-headStart).UnixNano() / int64(time.Millisecond)
+}
+
+// claimRankExpression returns a SQL expression for the claim rank of jobs
+// that become active at the given time, for inserting jobs in bulk.
+func (queue *Queue) claimRankExpression(activeAt time.Time) string {
+	cases := []string{}
+	for priority := PriorityLow; priority <= PriorityHigh; priority++ {
+		cases = append(cases, fmt.Sprintf("WHEN %d THEN %d", priority, queue.claimRank(priority, activeAt)))
+	}
+
+	return fmt.Sprintf("CASE `priority` %s ELSE %d END", strings.Join(cases, " "), queue.claimRank(PriorityNormal, activeAt))
+}
+
+func isSkipLockedUnsupported(err error) bool {
+	switch e := err.(type) {
+	case *mysql.MySQLError:
+		// ER_PARSE_ERROR and ER_NOT_SUPPORTED_YET
+		return e.Number == 1064 || e.Number == 1235
+	case *pq.Error:
+		return e.Code == "42601"
+	default:
+		return false
 	}
-	return job
 }
 
 func (queue *Queue) updateJob(job *Job, workerID string) (*Job, error) {
@@ -143,6 +387,7 @@ func (queue *Queue) updateJob(job *Job, workerID string) (*Job, error) {
 
 	job.WorkerID = workerID
 	job.ActiveAt = time.Now()
+	job.ClaimRank = queue.claimRank(job.Priority, job.ActiveAt)
 	_, err := queue.database.Connection.Update(job)
 	if err != nil {
 		return job, err
@@ -150,8 +395,22 @@ func (queue *Queue) updateJob(job *Job, workerID string) (*Job, error) {
 	return job, nil
 }
 
+// isClosed reports whether Close has been called. The done channel is only
+// ever closed, so it can be checked without holding the mutex.
+func (queue *Queue) isClosed() bool {
+	select {
+	case <-queue.done:
+		return true
+	default:
+		return false
+	}
+}
+
 func (queue *Queue) waitUpTo(max time.Duration) {
 	rand.Seed(time.Now().UnixNano())
 	waitTime := rand.Int63n(int64(max))
-	<-time.After(time.Duration(waitTime))
+	select {
+	case <-time.After(time.Duration(waitTime)):
+	case <-queue.done:
+	}
 }
@@ -50,6 +50,12 @@ var _ = Describe("Queue", func() {
 			Expect(jobs).To(ContainElement(job))
 		})
 
+		It("gives a job without a priority the normal priority", func() {
+			job, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(job.Priority).To(Equal(gobble.PriorityNormal))
+		})
+
 		Context("when the transaction is not commited", func() {
 			It("should not put things in the database", func() {
 				job := gobble.NewJob(map[string]bool{
@@ -170,6 +176,157 @@ var _ = Describe("Queue", func() {
 			Expect(job.ID).To(Equal(job2.ID))
 		})
 
+		It("picks the job with the highest priority", func() {
+			_, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityLow}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			high, err := queue.Enqueue(&gobble.Job{Priority: gobble.PriorityHigh}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(high.ID))
+		})
+
+		It("ages waiting jobs so that low priority work is not starved", func() {
+			queue.Close()
+			queue = gobble.NewQueue(database, clock, gobble.Config{
+				WaitMaxDuration:       50 * time.Millisecond,
+				PriorityAgingInterval: time.Minute,
+			})
+
+			old, err := queue.Enqueue(&gobble.Job{
+				Priority: gobble.PriorityLow,
+				ActiveAt: time.Now().Add(-10 * time.Minute),
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = queue.Enqueue(&gobble.Job{
+				Priority: gobble.PriorityHigh - 1,
+				ActiveAt: time.Now(),
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(old.ID))
+		})
+
+		It("never ages waiting jobs up to the priority of critical jobs", func() {
+			queue.Close()
+			queue = gobble.NewQueue(database, clock, gobble.Config{
+				WaitMaxDuration:       50 * time.Millisecond,
+				PriorityAgingInterval: time.Minute,
+			})
+
+			for i := 0; i < 3; i++ {
+				_, err := queue.Enqueue(&gobble.Job{
+					Priority: gobble.PriorityNormal,
+					ActiveAt: time.Now().Add(-time.Hour),
+				}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+			}
+			critical, err := queue.Enqueue(&gobble.Job{
+				Priority: gobble.PriorityHigh,
+				ActiveAt: time.Now(),
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job := <-queue.Reserve("worker-id")
+
+			Expect(job.ID).To(Equal(critical.ID))
+		})
+
+		It("ranks a requeued job by the time it becomes active again", func() {
+			queue.Close()
+			queue = gobble.NewQueue(database, clock, gobble.Config{
+				WaitMaxDuration:       50 * time.Millisecond,
+				PriorityAgingInterval: time.Minute,
+			})
+
+			old, err := queue.Enqueue(&gobble.Job{
+				Priority: gobble.PriorityLow,
+				ActiveAt: time.Now().Add(-10 * time.Minute),
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			normal, err := queue.Enqueue(&gobble.Job{
+				ActiveAt: time.Now(),
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			job := <-queue.Reserve("worker-id")
+			Expect(job.ID).To(Equal(old.ID))
+
+			job.Retry(0)
+			queue.Requeue(job)
+
+			job = <-queue.Reserve("worker-id")
+			Expect(job.ID).To(Equal(normal.ID))
+		})
+
+		Context("when the queue claims jobs in batches", func() {
+			BeforeEach(func() {
+				queue.Close()
+				queue = gobble.NewQueue(database, clock, gobble.Config{
+					WaitMaxDuration:  50 * time.Millisecond,
+					ReserveBatchSize: 3,
+				})
+			})
+
+			It("claims several jobs at once and hands them out one at a time", func() {
+				for i := 0; i < 4; i++ {
+					_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				first := <-queue.Reserve("worker-1")
+				Expect(first.WorkerID).To(Equal("worker-1"))
+
+				results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = ''")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(1))
+
+				second := <-queue.Reserve("worker-2")
+				Expect(second.ID).NotTo(Equal(first.ID))
+				Expect(second.WorkerID).To(Equal("worker-2"))
+			})
+
+			It("releases the claimed jobs it has not handed out when it is closed", func() {
+				for i := 0; i < 3; i++ {
+					_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				<-queue.Reserve("worker-1")
+				queue.Close()
+
+				results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = ''")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(2))
+			})
+		})
+
+		Context("when the queue is closed", func() {
+			It("stops waiting for a job and leaves new jobs for other processes", func() {
+				jobChannel := queue.Reserve("worker-id")
+				queue.Close()
+
+				_, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+				Expect(err).NotTo(HaveOccurred())
+
+				Consistently(jobChannel, 200*time.Millisecond).ShouldNot(Receive())
+
+				results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs` WHERE `worker_id` = ''")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(results).To(HaveLen(1))
+			})
+
+			It("can be closed more than once", func() {
+				queue.Close()
+				queue.Close()
+			})
+		})
+
 		Context("when the worker id is set", func() {
 			Context("when active_at is in the future", func() {
 				It("should not grab the job", func() {
@@ -262,6 +419,39 @@ var _ = Describe("Queue", func() {
 		})
 	})
 
+	Describe("Remove", func() {
+		It("deletes the jobs that have not been reserved", func() {
+			first, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			second, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			reserved, err := queue.Enqueue(&gobble.Job{WorkerID: "some-worker"}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			kept, err := queue.Enqueue(&gobble.Job{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			removed, err := queue.Remove([]int{first.ID, second.ID, reserved.ID}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removed).To(Equal(2))
+
+			jobs := []*gobble.Job{}
+			_, err = database.Connection.Select(&jobs, "SELECT * FROM `jobs` ORDER BY `id`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(jobs).To(HaveLen(2))
+			Expect(jobs[0].ID).To(Equal(reserved.ID))
+			Expect(jobs[1].ID).To(Equal(kept.ID))
+		})
+
+		It("does nothing without any jobs", func() {
+			removed, err := queue.Remove([]int{}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(removed).To(Equal(0))
+		})
+	})
+
 	Describe("Len", func() {
 		It("returns the length of the queue", func() {
 			job, err := queue.Enqueue(&gobble.Job{}, database.Connection)
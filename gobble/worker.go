@@ -37,6 +37,8 @@ func (worker *Worker) Perform() int {
 
 		if job.ShouldRetry {
 			worker.queue.Requeue(job)
+		} else if job.ShouldBury {
+			worker.queue.Bury(job)
 		} else {
 			worker.queue.Dequeue(job)
 		}
@@ -97,6 +97,32 @@ var _ = Describe("Worker", func() {
 			Expect(retriedJob.ActiveAt).To(BeTemporally("~", time.Now().Add(1*time.Minute), 1*time.Minute))
 		})
 
+		It("moves jobs that are marked for burial to the dead jobs table", func() {
+			callback = func(job *gobble.Job) {
+				job.RetryCount = 10
+				job.Bury("smtp is down")
+			}
+			worker = gobble.NewWorker(1, queue, callback, heartbeater)
+
+			job, err := queue.Enqueue(&gobble.Job{
+				Payload: "the-payload",
+			}, database.Connection)
+			Expect(err).NotTo(HaveOccurred())
+
+			worker.Perform()
+
+			results, err := database.Connection.Select(gobble.Job{}, "SELECT * FROM `jobs`")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(results).To(HaveLen(0))
+
+			deadJobs, err := queue.DeadJobs()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deadJobs).To(HaveLen(1))
+			Expect(deadJobs[0].JobID).To(Equal(job.ID))
+			Expect(deadJobs[0].Attempts).To(Equal(11))
+			Expect(deadJobs[0].LastError).To(Equal("smtp is down"))
+		})
+
 		It("heartbeats for job ownership while the job executes", func() {
 			job, err := queue.Enqueue(&gobble.Job{
 				Payload: "the-payload",
@@ -40,6 +40,7 @@ type Config struct {
 	DisableTLS        bool
 	ConnectTimeout    time.Duration
 	LoggingEnabled    bool
+	DKIMKeys          []DKIMKey
 }
 
 type connection struct {
@@ -117,15 +118,38 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 		return nil
 	}
 
-	err := c.Connect(logger)
+	err := c.open(logger)
+	if err != nil {
+		return c.Error(logger, err)
+	}
+
+	err = c.deliver(msg, logger)
 	if err != nil {
 		return c.Error(logger, err)
 	}
 
+	c.PrintLog(logger, "quiting")
+	err = c.Quit()
+	if err != nil {
+		return c.Error(logger, err)
+	}
+	c.PrintLog(logger, "disconnected")
+
+	return nil
+}
+
+// open connects to the SMTP server and takes the session through HELO,
+// STARTTLS and authentication so that it is ready to accept messages.
+func (c *Client) open(logger lager.Logger) error {
+	err := c.Connect(logger)
+	if err != nil {
+		return err
+	}
+
 	c.PrintLog(logger, "hello-initiating")
 	err = c.Hello()
 	if err != nil {
-		return c.Error(logger, err)
+		return err
 	}
 	c.PrintLog(logger, "hello-complete")
 
@@ -133,44 +157,43 @@ func (c *Client) Send(msg Message, logger lager.Logger) error {
 		c.PrintLog(logger, "tls-starting")
 		err = c.StartTLS()
 		if err != nil {
-			return c.Error(logger, err)
+			return err
 		}
 		c.PrintLog(logger, "tls-connected")
 
 		c.PrintLog(logger, "authentication-starting")
 		err = c.Auth(logger)
 		if err != nil {
-			return c.Error(logger, err)
+			return err
 		}
 		c.PrintLog(logger, "authenticated")
 	}
 
+	return nil
+}
+
+// deliver sends a single message over an open session. Rejections from the
+// server are returned as an SMTPError.
+func (c *Client) deliver(msg Message, logger lager.Logger) error {
 	c.PrintLog(logger, "setting-msg-from", lager.Data{"from": msg.From})
-	err = c.client.Mail(msg.From)
+	err := c.client.Mail(msg.From)
 	if err != nil {
-		return c.Error(logger, err)
+		return newSMTPError(err)
 	}
 
 	c.PrintLog(logger, "setting-msg-to", lager.Data{"to": msg.To})
 	err = c.client.Rcpt(msg.To)
 	if err != nil {
-		return c.Error(logger, err)
+		return newSMTPError(err)
 	}
 
 	c.PrintLog(logger, "setting-msg-data", lager.Data{"message-data": base64.StdEncoding.EncodeToString([]byte(msg.Data()))})
 	err = c.Data(msg)
 	if err != nil {
-		return c.Error(logger, err)
+		return newSMTPError(err)
 	}
 	c.PrintLog(logger, "msg-data-sent")
 
-	c.PrintLog(logger, "quiting")
-	err = c.Quit()
-	if err != nil {
-		return c.Error(logger, err)
-	}
-	c.PrintLog(logger, "disconnected")
-
 	return nil
 }
 
@@ -229,12 +252,21 @@ func (c *Client) AuthMechanism(logger lager.Logger) smtp.Auth {
 }
 
 func (c *Client) Data(msg Message) error {
+	data := msg.Data()
+	if len(c.config.DKIMKeys) > 0 {
+		signed, err := signMessage(data, c.config.DKIMKeys, time.Now())
+		if err != nil {
+			return err
+		}
+		data = signed
+	}
+
 	wc, err := c.client.Data()
 	if err != nil {
 		return err
 	}
 
-	data := strings.Replace(string(msg.Data()), "%", "%%", -1)
+	data = strings.Replace(data, "%", "%%", -1)
 	_, err = fmt.Fprintf(wc, data)
 	if err != nil {
 		return err
@@ -2,6 +2,9 @@ package mail_test
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"net"
@@ -261,6 +264,100 @@ var _ = Describe("Mail", func() {
 			Expect(delivery.Data).To(Equal(strings.Split(secondMsg.Data(), "\n")))
 		})
 
+		Context("when configured with DKIM keys", func() {
+			It("signs the message with every key", func() {
+				rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+				if err != nil {
+					panic(err)
+				}
+
+				_, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+				if err != nil {
+					panic(err)
+				}
+
+				oldKey, err := mail.ParseDKIMKey("example.com", "old", encodePKCS8(rsaKey))
+				Expect(err).NotTo(HaveOccurred())
+
+				newKey, err := mail.ParseDKIMKey("example.com", "new", encodePKCS8(ed25519Key))
+				Expect(err).NotTo(HaveOccurred())
+
+				config.DKIMKeys = []mail.DKIMKey{oldKey, newKey}
+				client = mail.NewClient(config)
+
+				msg := mail.Message{
+					From:    "me@example.com",
+					To:      "you@example.com",
+					Subject: "Urgent! Read now!",
+					Headers: []string{"X-CF-Notification-ID: some-notification"},
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+
+				err = client.Send(msg, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(func() int {
+					return len(mailServer.Deliveries)
+				}).Should(Equal(1))
+
+				data := mailServer.Deliveries[0].Data
+				Expect(data[0]).To(HavePrefix("DKIM-Signature: v=1; a=rsa-sha256;"))
+				Expect(data[0]).To(ContainSubstring("s=old;"))
+				Expect(data[4]).To(HavePrefix("DKIM-Signature: v=1; a=ed25519-sha256;"))
+				Expect(data[4]).To(ContainSubstring("s=new;"))
+				Expect(data[8:]).To(Equal(strings.Split(msg.Data(), "\n")))
+			})
+		})
+
+		Context("when the server rejects the message", func() {
+			var msg mail.Message
+
+			BeforeEach(func() {
+				config.SkipVerifySSL = true
+				client = mail.NewClient(config)
+
+				msg = mail.Message{
+					From:    "me@example.com",
+					To:      "nobody@example.com",
+					Subject: "Urgent! Read now!",
+					Body: []mail.Part{
+						{
+							ContentType: "text/plain",
+							Content:     "This email is the most important thing you will read all day!",
+						},
+					},
+				}
+			})
+
+			It("returns an SMTP error carrying the reply and enhanced status codes", func() {
+				mailServer.RcptToResponses = []string{"550 5.1.1 mailbox does not exist"}
+
+				err := client.Send(msg, logger)
+				Expect(err).To(Equal(mail.SMTPError{
+					Code:         550,
+					EnhancedCode: "5.1.1",
+					Message:      "mailbox does not exist",
+				}))
+				Expect(err.(mail.SMTPError).Permanent()).To(BeTrue())
+			})
+
+			It("returns an SMTP error without an enhanced status code when the server does not send one", func() {
+				mailServer.MailFromResponses = []string{"451 try again later"}
+
+				err := client.Send(msg, logger)
+				Expect(err).To(Equal(mail.SMTPError{
+					Code:    451,
+					Message: "try again later",
+				}))
+				Expect(err.(mail.SMTPError).Transient()).To(BeTrue())
+			})
+		})
+
 		Context("when configured to use TLS", func() {
 			BeforeEach(func() {
 				config.SkipVerifySSL = true
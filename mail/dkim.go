@@ -0,0 +1,252 @@
+package mail
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	DKIMAlgorithmRSA     = "rsa-sha256"
+	DKIMAlgorithmEd25519 = "ed25519-sha256"
+)
+
+// dkimSignedHeaders are signed whenever they appear in a message, along with
+// every X-CF-* header.
+var dkimSignedHeaders = map[string]bool{
+	"from":                      true,
+	"reply-to":                  true,
+	"to":                        true,
+	"subject":                   true,
+	"date":                      true,
+	"mime-version":              true,
+	"content-type":              true,
+	"content-transfer-encoding": true,
+	"list-unsubscribe":          true,
+	"list-unsubscribe-post":     true,
+}
+
+// DKIMKey signs outgoing messages on behalf of a domain (RFC 6376). The
+// public half of the key is published in DNS at
+// <selector>._domainkey.<domain>.
+type DKIMKey struct {
+	Domain   string
+	Selector string
+	Signer   crypto.Signer
+}
+
+// ParseDKIMKey reads a PEM encoded RSA (PKCS #1 or PKCS #8) or Ed25519
+// (PKCS #8) private key.
+func ParseDKIMKey(domain, selector string, privateKey []byte) (DKIMKey, error) {
+	if domain == "" || selector == "" {
+		return DKIMKey{}, errors.New("DKIM key requires a domain and a selector")
+	}
+
+	block, _ := pem.Decode(privateKey)
+	if block == nil {
+		return DKIMKey{}, fmt.Errorf("DKIM key for selector %q is not PEM encoded", selector)
+	}
+
+	var key interface{}
+	var err error
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	} else {
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return DKIMKey{}, fmt.Errorf("DKIM key for selector %q could not be parsed: %s", selector, err)
+	}
+
+	switch signer := key.(type) {
+	case *rsa.PrivateKey:
+		return DKIMKey{Domain: domain, Selector: selector, Signer: signer}, nil
+	case ed25519.PrivateKey:
+		return DKIMKey{Domain: domain, Selector: selector, Signer: signer}, nil
+	default:
+		return DKIMKey{}, fmt.Errorf("DKIM key for selector %q is neither an RSA nor an Ed25519 key", selector)
+	}
+}
+
+func (k DKIMKey) Algorithm() string {
+	if _, ok := k.Signer.Public().(ed25519.PublicKey); ok {
+		return DKIMAlgorithmEd25519
+	}
+
+	return DKIMAlgorithmRSA
+}
+
+// Sign returns the DKIM-Signature header field for the given message data,
+// using relaxed canonicalization for both the header and the body.
+func (k DKIMKey) Sign(data string, now time.Time) (string, error) {
+	fields, body := splitMessage(toCRLF(data))
+
+	var names []string
+	for _, field := range fields {
+		name := fieldName(field)
+		if dkimSignedHeaders[name] || strings.HasPrefix(name, "x-cf-") {
+			names = append(names, name)
+		}
+	}
+
+	bodyHash := sha256.Sum256([]byte(RelaxedBody(body)))
+
+	signature := fmt.Sprintf("DKIM-Signature: v=1; a=%s; c=relaxed/relaxed; d=%s; s=%s; t=%d;\r\n\th=%s;\r\n\tbh=%s;\r\n\tb=",
+		k.Algorithm(), k.Domain, k.Selector, now.Unix(), strings.Join(names, ":"),
+		base64.StdEncoding.EncodeToString(bodyHash[:]))
+
+	hash := sha256.New()
+	for _, field := range selectFields(fields, names) {
+		hash.Write([]byte(RelaxedHeader(field)))
+	}
+	hash.Write([]byte(strings.TrimSuffix(RelaxedHeader(signature), "\r\n")))
+
+	// Ed25519 signs the SHA-256 digest itself (RFC 8463), so the digest is
+	// passed as the message rather than as a prehashed value.
+	opts := crypto.Hash(0)
+	if k.Algorithm() == DKIMAlgorithmRSA {
+		opts = crypto.SHA256
+	}
+
+	b, err := k.Signer.Sign(rand.Reader, hash.Sum(nil), opts)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Replace(signature, "\r\n", "\n", -1) + base64.StdEncoding.EncodeToString(b), nil
+}
+
+// signMessage prepends one signature per key, so that a new key can be
+// published and put into use before the old one is retired.
+func signMessage(data string, keys []DKIMKey, now time.Time) (string, error) {
+	var signatures []string
+	for _, key := range keys {
+		signature, err := key.Sign(data, now)
+		if err != nil {
+			return "", err
+		}
+
+		signatures = append(signatures, signature+"\n")
+	}
+
+	return strings.Join(signatures, "") + data, nil
+}
+
+// RelaxedHeader canonicalizes a header field as described in RFC 6376,
+// section 3.4.2.
+func RelaxedHeader(field string) string {
+	value := field[strings.Index(field, ":")+1:]
+	value = strings.Replace(value, "\r\n", "", -1)
+	value = strings.TrimSpace(collapseWhitespace(value))
+
+	return fieldName(field) + ":" + value + "\r\n"
+}
+
+// RelaxedBody canonicalizes a message body as described in RFC 6376,
+// section 3.4.4.
+func RelaxedBody(body string) string {
+	lines := strings.Split(body, "\r\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(collapseWhitespace(line), " ")
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// splitMessage breaks the message into its header fields, each including
+// any folded continuation lines, and its body.
+func splitMessage(message string) ([]string, string) {
+	header, body := message, ""
+	if index := strings.Index(message, "\r\n\r\n"); index >= 0 {
+		header, body = message[:index+2], message[index+4:]
+	}
+
+	var fields []string
+	for _, line := range strings.SplitAfter(header, "\r\n") {
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(fields) > 0 {
+			fields[len(fields)-1] += line
+			continue
+		}
+
+		if strings.Contains(line, ":") {
+			fields = append(fields, line)
+		}
+	}
+
+	for i, field := range fields {
+		fields[i] = strings.TrimSuffix(field, "\r\n")
+	}
+
+	return fields, body
+}
+
+// selectFields picks the field for each signed name the way a verifier
+// does: a name that appears more than once is matched from the bottom of
+// the header up.
+func selectFields(fields []string, names []string) []string {
+	used := make([]bool, len(fields))
+
+	var selected []string
+	for _, name := range names {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if !used[i] && fieldName(fields[i]) == name {
+				used[i] = true
+				selected = append(selected, fields[i])
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+func fieldName(field string) string {
+	return strings.ToLower(strings.TrimSpace(field[:strings.Index(field, ":")]))
+}
+
+// toCRLF converts the line endings of the message to the CRLF that the
+// message is sent with.
+func toCRLF(data string) string {
+	return strings.Replace(strings.Replace(data, "\r\n", "\n", -1), "\n", "\r\n", -1)
+}
+
+func collapseWhitespace(s string) string {
+	var result strings.Builder
+	inWhitespace := false
+
+	for _, r := range s {
+		if r == ' ' || r == '\t' {
+			if !inWhitespace {
+				result.WriteRune(' ')
+			}
+			inWhitespace = true
+			continue
+		}
+
+		inWhitespace = false
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
@@ -0,0 +1,265 @@
+package mail_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var signatureValuePattern = regexp.MustCompile(`\bb=[^;]*`)
+
+// verifyDKIM checks the first DKIM-Signature of a CRLF message the way a
+// receiving mail server would.
+func verifyDKIM(message string, publicKey crypto.PublicKey) (map[string]string, error) {
+	parts := strings.SplitN(message, "\r\n\r\n", 2)
+	header, body := parts[0], parts[1]
+
+	var fields []string
+	for _, line := range strings.Split(header, "\r\n") {
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			fields[len(fields)-1] += "\r\n" + line
+			continue
+		}
+		fields = append(fields, line)
+	}
+
+	var signatureField string
+	for i, field := range fields {
+		if strings.HasPrefix(field, "DKIM-Signature:") {
+			signatureField = field
+			fields = fields[i+1:]
+			break
+		}
+	}
+
+	tags := map[string]string{}
+	unfolded := strings.Replace(strings.TrimPrefix(signatureField, "DKIM-Signature:"), "\r\n", "", -1)
+	for _, tag := range strings.Split(unfolded, ";") {
+		pair := strings.SplitN(strings.TrimSpace(tag), "=", 2)
+		tags[pair[0]] = strings.Replace(pair[1], "\t", "", -1)
+	}
+
+	bodyHash := sha256.Sum256([]byte(mail.RelaxedBody(body)))
+	if base64.StdEncoding.EncodeToString(bodyHash[:]) != tags["bh"] {
+		return tags, errors.New("body hash does not match")
+	}
+
+	hash := sha256.New()
+	used := map[int]bool{}
+	for _, name := range strings.Split(tags["h"], ":") {
+		for i := len(fields) - 1; i >= 0; i-- {
+			if !used[i] && strings.EqualFold(strings.SplitN(fields[i], ":", 2)[0], name) {
+				used[i] = true
+				hash.Write([]byte(mail.RelaxedHeader(fields[i])))
+				break
+			}
+		}
+	}
+	hash.Write([]byte(strings.TrimSuffix(mail.RelaxedHeader(signatureValuePattern.ReplaceAllString(signatureField, "b=")), "\r\n")))
+
+	signature, err := base64.StdEncoding.DecodeString(tags["b"])
+	if err != nil {
+		return tags, err
+	}
+
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return tags, rsa.VerifyPKCS1v15(key, crypto.SHA256, hash.Sum(nil), signature)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, hash.Sum(nil), signature) {
+			return tags, errors.New("ed25519 signature does not verify")
+		}
+		return tags, nil
+	}
+
+	return tags, errors.New("unsupported key")
+}
+
+func encodePKCS8(key interface{}) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		panic(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+var _ = Describe("DKIM", func() {
+	var (
+		rsaKey     *rsa.PrivateKey
+		ed25519Key ed25519.PrivateKey
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		rsaKey, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			panic(err)
+		}
+
+		_, ed25519Key, err = ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+	})
+
+	Describe("ParseDKIMKey", func() {
+		It("reads a PKCS #1 RSA key", func() {
+			privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+
+			key, err := mail.ParseDKIMKey("example.com", "2026a", privateKey)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.Domain).To(Equal("example.com"))
+			Expect(key.Selector).To(Equal("2026a"))
+			Expect(key.Algorithm()).To(Equal(mail.DKIMAlgorithmRSA))
+		})
+
+		It("reads a PKCS #8 RSA key", func() {
+			key, err := mail.ParseDKIMKey("example.com", "2026a", encodePKCS8(rsaKey))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.Algorithm()).To(Equal(mail.DKIMAlgorithmRSA))
+		})
+
+		It("reads a PKCS #8 Ed25519 key", func() {
+			key, err := mail.ParseDKIMKey("example.com", "2026b", encodePKCS8(ed25519Key))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key.Algorithm()).To(Equal(mail.DKIMAlgorithmEd25519))
+		})
+
+		It("returns an error when the key is not PEM encoded", func() {
+			_, err := mail.ParseDKIMKey("example.com", "2026a", []byte("not a key"))
+			Expect(err).To(MatchError(`DKIM key for selector "2026a" is not PEM encoded`))
+		})
+
+		It("returns an error when the key is of an unsupported type", func() {
+			ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				panic(err)
+			}
+
+			_, err = mail.ParseDKIMKey("example.com", "2026a", encodePKCS8(ecdsaKey))
+			Expect(err).To(MatchError(`DKIM key for selector "2026a" is neither an RSA nor an Ed25519 key`))
+		})
+
+		It("returns an error when the domain or selector is missing", func() {
+			_, err := mail.ParseDKIMKey("", "2026a", encodePKCS8(rsaKey))
+			Expect(err).To(MatchError("DKIM key requires a domain and a selector"))
+
+			_, err = mail.ParseDKIMKey("example.com", "", encodePKCS8(rsaKey))
+			Expect(err).To(MatchError("DKIM key requires a domain and a selector"))
+		})
+	})
+
+	Describe("relaxed canonicalization", func() {
+		It("canonicalizes the example in RFC 6376, section 3.4.5", func() {
+			Expect(mail.RelaxedHeader("A: X")).To(Equal("a:X\r\n"))
+			Expect(mail.RelaxedHeader("B : Y\t\r\n\tZ  ")).To(Equal("b:Y Z\r\n"))
+			Expect(mail.RelaxedBody(" C \r\nD \t E\r\n\r\n\r\n")).To(Equal(" C\r\nD E\r\n"))
+		})
+
+		It("canonicalizes an empty body to nothing", func() {
+			Expect(mail.RelaxedBody("\r\n\r\n")).To(BeEmpty())
+		})
+	})
+
+	Describe("Sign", func() {
+		var msg mail.Message
+
+		BeforeEach(func() {
+			msg = mail.Message{
+				From:    "me@example.com",
+				To:      "you@example.com",
+				Subject: "Urgent! Read now!",
+				Headers: []string{
+					"X-CF-Client-ID: some-client",
+					"X-CF-Notification-ID: some-notification",
+				},
+				Body: []mail.Part{
+					{
+						ContentType: "text/plain",
+						Content:     "This email is the most important thing you will read all day!",
+					},
+				},
+			}
+		})
+
+		signed := func(key mail.DKIMKey) string {
+			data := msg.Data()
+
+			signature, err := key.Sign(data, time.Unix(1791000000, 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			return strings.Replace(signature+"\n"+data, "\n", "\r\n", -1)
+		}
+
+		It("signs with an RSA key", func() {
+			key, err := mail.ParseDKIMKey("example.com", "2026a", encodePKCS8(rsaKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			tags, err := verifyDKIM(signed(key), rsaKey.Public())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tags["v"]).To(Equal("1"))
+			Expect(tags["a"]).To(Equal("rsa-sha256"))
+			Expect(tags["c"]).To(Equal("relaxed/relaxed"))
+			Expect(tags["d"]).To(Equal("example.com"))
+			Expect(tags["s"]).To(Equal("2026a"))
+			Expect(tags["t"]).To(Equal("1791000000"))
+		})
+
+		It("signs with an Ed25519 key", func() {
+			key, err := mail.ParseDKIMKey("example.com", "2026b", encodePKCS8(ed25519Key))
+			Expect(err).NotTo(HaveOccurred())
+
+			tags, err := verifyDKIM(signed(key), ed25519Key.Public())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tags["a"]).To(Equal("ed25519-sha256"))
+		})
+
+		It("signs the message headers, including the X-CF-* headers", func() {
+			key, err := mail.ParseDKIMKey("example.com", "2026a", encodePKCS8(rsaKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			tags, err := verifyDKIM(signed(key), rsaKey.Public())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.Split(tags["h"], ":")).To(Equal([]string{
+				"x-cf-client-id",
+				"x-cf-notification-id",
+				"date",
+				"mime-version",
+				"content-type",
+				"content-transfer-encoding",
+				"from",
+				"to",
+				"subject",
+			}))
+		})
+
+		It("produces a signature that no longer verifies once a signed header is changed", func() {
+			key, err := mail.ParseDKIMKey("example.com", "2026a", encodePKCS8(rsaKey))
+			Expect(err).NotTo(HaveOccurred())
+
+			message := strings.Replace(signed(key), "X-CF-Notification-ID: some-notification", "X-CF-Notification-ID: another-notification", 1)
+
+			_, err = verifyDKIM(message, rsaKey.Public())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
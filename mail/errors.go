@@ -0,0 +1,62 @@
+package mail
+
+import (
+	"fmt"
+	"net/textproto"
+	"regexp"
+)
+
+var enhancedCodePattern = regexp.MustCompile(`^([245]\.\d{1,3}\.\d{1,3})\s+`)
+
+// SMTPError is returned when the SMTP server rejects a message. It carries
+// the reply code and, when the server sends one, the enhanced status code
+// (RFC 3463), e.g. "5.1.1".
+type SMTPError struct {
+	Code         int
+	EnhancedCode string
+	Message      string
+}
+
+func (e SMTPError) Error() string {
+	return fmt.Sprintf("%d %s", e.Code, e.Reason())
+}
+
+// Reason is the server's reply text, including the enhanced status code.
+func (e SMTPError) Reason() string {
+	if e.EnhancedCode == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s %s", e.EnhancedCode, e.Message)
+}
+
+// Permanent reports whether the server rejected the message for good (a 5xx
+// reply), so that sending it again will not help.
+func (e SMTPError) Permanent() bool {
+	return e.Code >= 500
+}
+
+// Transient reports whether the server turned the message away for now (a
+// 4xx reply), so that sending it again later may succeed.
+func (e SMTPError) Transient() bool {
+	return e.Code >= 400 && e.Code < 500
+}
+
+func newSMTPError(err error) error {
+	protocolError, ok := err.(*textproto.Error)
+	if !ok {
+		return err
+	}
+
+	smtpError := SMTPError{
+		Code:    protocolError.Code,
+		Message: protocolError.Msg,
+	}
+
+	if matches := enhancedCodePattern.FindStringSubmatch(protocolError.Msg); matches != nil {
+		smtpError.EnhancedCode = matches[1]
+		smtpError.Message = protocolError.Msg[len(matches[0]):]
+	}
+
+	return smtpError
+}
@@ -0,0 +1,38 @@
+package mail_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/mail"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SMTPError", func() {
+	It("includes the reply code, enhanced status code and message in the error", func() {
+		err := mail.SMTPError{Code: 550, EnhancedCode: "5.1.1", Message: "mailbox does not exist"}
+
+		Expect(err.Error()).To(Equal("550 5.1.1 mailbox does not exist"))
+		Expect(err.Reason()).To(Equal("5.1.1 mailbox does not exist"))
+	})
+
+	It("leaves out the enhanced status code when there is none", func() {
+		err := mail.SMTPError{Code: 451, Message: "try again later"}
+
+		Expect(err.Error()).To(Equal("451 try again later"))
+		Expect(err.Reason()).To(Equal("try again later"))
+	})
+
+	It("treats 5xx replies as permanent", func() {
+		err := mail.SMTPError{Code: 550}
+
+		Expect(err.Permanent()).To(BeTrue())
+		Expect(err.Transient()).To(BeFalse())
+	})
+
+	It("treats 4xx replies as transient", func() {
+		err := mail.SMTPError{Code: 421}
+
+		Expect(err.Permanent()).To(BeFalse())
+		Expect(err.Transient()).To(BeTrue())
+	})
+})
@@ -86,6 +86,11 @@ type SMTPServer struct {
 	halt            chan bool
 	ConnectionState string
 	FailsHello      bool
+	Connections     int
+	Commands        []string
+
+	MailFromResponses []string
+	RcptToResponses   []string
 }
 
 type Delivery struct {
@@ -154,6 +159,7 @@ func (server *SMTPServer) Close() {
 func (server *SMTPServer) Respond(conn net.Conn) {
 	<-time.After(server.ConnectWait)
 	server.ConnectionState = StateConnected
+	server.Connections++
 
 	input := bufio.NewReader(conn)
 	output := bufio.NewWriter(conn)
@@ -161,7 +167,15 @@ func (server *SMTPServer) Respond(conn net.Conn) {
 
 Loop:
 	for {
-		msg, _ := input.ReadString('\n')
+		msg, err := input.ReadString('\n')
+		if err != nil {
+			break Loop
+		}
+
+		if fields := strings.Fields(msg); len(fields) > 0 {
+			server.Commands = append(server.Commands, strings.ToUpper(fields[0]))
+		}
+
 		switch {
 		case strings.Contains(msg, "EHLO"):
 			server.RespondToEHLO(output)
@@ -176,15 +190,32 @@ Loop:
 		case strings.Contains(msg, "DATA"):
 			server.RespondToData(output)
 			server.RecordData(output, input)
+		case strings.Contains(msg, "RSET"), strings.Contains(msg, "NOOP"):
+			server.RespondOK(output)
 		case strings.Contains(msg, "QUIT"):
 			server.RespondToQuit(output)
 			break Loop
 		}
 	}
-	server.Deliveries = append(server.Deliveries, server.CurrentDelivery)
 	server.CurrentDelivery = Delivery{}
 }
 
+func (server *SMTPServer) RespondOK(output *bufio.Writer) {
+	output.WriteString("250 OK\r\n")
+	output.Flush()
+}
+
+func nextResponse(responses *[]string) string {
+	if len(*responses) == 0 {
+		return "250 OK"
+	}
+
+	response := (*responses)[0]
+	*responses = (*responses)[1:]
+
+	return response
+}
+
 func (server *SMTPServer) Broadcast(output *bufio.Writer) {
 	output.WriteString("220 localhost\r\n")
 	output.Flush()
@@ -236,7 +267,7 @@ func (server *SMTPServer) RespondToMailFrom(output *bufio.Writer, msg string) {
 	sender = strings.Trim(sender, "<>")
 	server.CurrentDelivery.Sender = sender
 
-	output.WriteString("250 OK\r\n")
+	output.WriteString(nextResponse(&server.MailFromResponses) + "\r\n")
 	output.Flush()
 }
 
@@ -246,7 +277,7 @@ func (server *SMTPServer) RespondToRcptTo(output *bufio.Writer, msg string) {
 	recipient = strings.Trim(recipient, "<>")
 	server.CurrentDelivery.Recipient = recipient
 
-	output.WriteString("250 OK\r\n")
+	output.WriteString(nextResponse(&server.RcptToResponses) + "\r\n")
 	output.Flush()
 }
 
@@ -269,6 +300,9 @@ func (server *SMTPServer) RecordData(output *bufio.Writer, input *bufio.Reader)
 	}
 	output.WriteString("250 Written safely to disk.\r\n")
 	output.Flush()
+
+	server.Deliveries = append(server.Deliveries, server.CurrentDelivery)
+	server.CurrentDelivery = Delivery{UsedTLS: server.CurrentDelivery.UsedTLS}
 }
 
 func (server *SMTPServer) RespondToQuit(output *bufio.Writer) {
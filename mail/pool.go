@@ -0,0 +1,269 @@
+package mail
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pivotal-golang/lager"
+)
+
+type PoolConfig struct {
+	Size                     int
+	MaxMessagesPerConnection int
+	MaxIdleTime              time.Duration
+}
+
+// Pool keeps authenticated SMTP sessions open between messages so that each
+// delivery does not pay for a new connection, TLS handshake and login. It is
+// safe for concurrent use by many workers.
+type Pool struct {
+	config     Config
+	poolConfig PoolConfig
+
+	mutex sync.Mutex
+	idle  []*session
+}
+
+type session struct {
+	client    *Client
+	sent      int
+	idleSince time.Time
+	reused    bool
+}
+
+func NewPool(config Config, poolConfig PoolConfig) *Pool {
+	if poolConfig.Size == 0 {
+		poolConfig.Size = 1
+	}
+
+	if poolConfig.MaxMessagesPerConnection == 0 {
+		poolConfig.MaxMessagesPerConnection = 100
+	}
+
+	if poolConfig.MaxIdleTime == 0 {
+		poolConfig.MaxIdleTime = 30 * time.Second
+	}
+
+	return &Pool{
+		config:     config,
+		poolConfig: poolConfig,
+	}
+}
+
+// Connect makes sure a session is available, opening one if none is idle.
+// Idle sessions are left for Send to check out and health check, so that a
+// message costs a single checkout.
+func (p *Pool) Connect(logger lager.Logger) error {
+	logger = p.loggerSession(logger)
+
+	if p.config.TestMode {
+		p.printLog(logger, "test-mode-not-connected")
+		return nil
+	}
+
+	if p.hasIdle() {
+		return nil
+	}
+
+	s, err := p.checkout(logger)
+	if err != nil {
+		logger.Error("failed", err)
+		return err
+	}
+
+	p.park(s, logger)
+
+	return nil
+}
+
+func (p *Pool) Send(msg Message, logger lager.Logger) error {
+	logger = p.loggerSession(logger)
+
+	if p.config.TestMode {
+		logger.Info("test-mode")
+		return nil
+	}
+
+	for {
+		s, err := p.checkout(logger)
+		if err != nil {
+			logger.Error("failed", err)
+			return err
+		}
+
+		err = s.client.deliver(msg, logger)
+		if err == nil {
+			p.checkin(s, logger)
+			return nil
+		}
+
+		// A reused session can be dropped by the server between the health
+		// check and the message; try again on a fresh connection.
+		if s.reused && shouldReconnect(err) {
+			p.close(s, "stale-session", logger)
+			continue
+		}
+
+		p.release(s, err, logger)
+		logger.Error("failed", err)
+
+		return err
+	}
+}
+
+// Close quits every idle session.
+func (p *Pool) Close() {
+	p.mutex.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.mutex.Unlock()
+
+	for _, s := range idle {
+		s.quit()
+	}
+}
+
+func (p *Pool) checkout(logger lager.Logger) (*session, error) {
+	for {
+		s := p.pop()
+		if s == nil {
+			break
+		}
+
+		if time.Since(s.idleSince) > p.poolConfig.MaxIdleTime {
+			p.close(s, "idle-timeout", logger)
+			continue
+		}
+
+		// A session that has not sent a message was opened by Connect for
+		// this message; Send retries on a new connection if it went stale.
+		if s.sent > 0 {
+			err := s.client.client.Noop()
+			if err != nil {
+				p.close(s, "health-check-failed", logger)
+				continue
+			}
+		}
+
+		s.reused = true
+		p.printLog(logger, "session-reused", lager.Data{"messages-sent": s.sent})
+
+		return s, nil
+	}
+
+	client := NewClient(p.config)
+	err := client.open(logger)
+	if err != nil {
+		if client.client != nil {
+			(&session{client: client}).quit()
+		}
+		return nil, err
+	}
+	p.printLog(logger, "session-opened")
+
+	return &session{client: client}, nil
+}
+
+// checkin returns a session that delivered a message to the pool, unless it
+// has reached its message cap.
+func (p *Pool) checkin(s *session, logger lager.Logger) {
+	s.sent++
+
+	if s.sent >= p.poolConfig.MaxMessagesPerConnection {
+		p.close(s, "message-limit-reached", logger)
+		return
+	}
+
+	err := s.client.client.Reset()
+	if err != nil {
+		p.close(s, "reset-failed", logger)
+		return
+	}
+
+	p.park(s, logger)
+}
+
+// release decides what to do with a session after a failed delivery.
+// Permanent (5xx) rejections leave the session usable, so it is reset and
+// kept; anything else means the session can no longer be trusted.
+func (p *Pool) release(s *session, err error, logger lager.Logger) {
+	if smtpError, ok := err.(SMTPError); ok && smtpError.Permanent() {
+		p.checkin(s, logger)
+		return
+	}
+
+	p.close(s, "delivery-failed", logger)
+}
+
+func (p *Pool) park(s *session, logger lager.Logger) {
+	s.idleSince = time.Now()
+
+	p.mutex.Lock()
+	if len(p.idle) < p.poolConfig.Size {
+		p.idle = append(p.idle, s)
+		s = nil
+	}
+	p.mutex.Unlock()
+
+	if s != nil {
+		p.close(s, "pool-full", logger)
+	}
+}
+
+func (p *Pool) hasIdle() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.idle) > 0
+}
+
+// pop takes the most recently used idle session, so that quiet periods let
+// the older sessions age out.
+func (p *Pool) pop() *session {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.idle) == 0 {
+		return nil
+	}
+
+	s := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+
+	return s
+}
+
+func (p *Pool) close(s *session, reason string, logger lager.Logger) {
+	p.printLog(logger, "session-closed", lager.Data{"reason": reason})
+	s.quit()
+}
+
+// quit says goodbye to the server, dropping the connection outright when the
+// server is no longer listening.
+func (s *session) quit() {
+	connection := s.client.client
+	if connection.Quit() != nil {
+		connection.Close()
+	}
+}
+
+func (p *Pool) loggerSession(logger lager.Logger) lager.Logger {
+	return Client{}.createLoggerSession(logger)
+}
+
+func (p *Pool) printLog(logger lager.Logger, action string, data ...lager.Data) {
+	if p.config.LoggingEnabled {
+		logger.Info(action, data...)
+	}
+}
+
+// shouldReconnect reports whether an error means the session itself is no
+// good: the connection was dropped, or the server answered with a transient
+// (4xx) reply such as "421 closing connection".
+func shouldReconnect(err error) bool {
+	if smtpError, ok := err.(SMTPError); ok {
+		return smtpError.Transient()
+	}
+
+	return true
+}
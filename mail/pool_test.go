@@ -0,0 +1,201 @@
+package mail_test
+
+import (
+	"bytes"
+	"net"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pool", func() {
+	var (
+		mailServer *SMTPServer
+		pool       *mail.Pool
+		logger     lager.Logger
+		config     mail.Config
+		poolConfig mail.PoolConfig
+		msg        mail.Message
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(&bytes.Buffer{}, 0))
+
+		mailServer = NewSMTPServer("user", "pass")
+		mailServer.SupportsTLS = true
+
+		config = mail.Config{
+			User:          "user",
+			Pass:          "pass",
+			SkipVerifySSL: true,
+		}
+
+		config.Host, config.Port, err = net.SplitHostPort(mailServer.URL.Host)
+		if err != nil {
+			panic(err)
+		}
+
+		poolConfig = mail.PoolConfig{
+			Size:                     2,
+			MaxMessagesPerConnection: 10,
+			MaxIdleTime:              time.Minute,
+		}
+
+		pool = mail.NewPool(config, poolConfig)
+
+		msg = mail.Message{
+			From:    "me@example.com",
+			To:      "you@example.com",
+			Subject: "Urgent! Read now!",
+			Body: []mail.Part{
+				{
+					ContentType: "text/plain",
+					Content:     "This email is the most important thing you will read all day!",
+				},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		pool.Close()
+		mailServer.Close()
+	})
+
+	It("reuses one authenticated connection for many messages", func() {
+		for i := 0; i < 3; i++ {
+			Expect(pool.Send(msg, logger)).To(Succeed())
+		}
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(3))
+
+		Expect(mailServer.Connections).To(Equal(1))
+		Expect(mailServer.Deliveries[2].UsedTLS).To(BeTrue())
+		Expect(mailServer.Commands).To(ContainElement("RSET"))
+		Expect(mailServer.Commands).To(ContainElement("NOOP"))
+	})
+
+	It("opens a session when connecting and uses it for the next message", func() {
+		Expect(pool.Connect(logger)).To(Succeed())
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(1))
+
+		Expect(mailServer.Connections).To(Equal(1))
+		Expect(mailServer.Commands).NotTo(ContainElement("NOOP"))
+	})
+
+	It("checks an idle session once per message when connecting before sending", func() {
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		Expect(pool.Connect(logger)).To(Succeed())
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(2))
+
+		noops := 0
+		for _, command := range mailServer.Commands {
+			if command == "NOOP" {
+				noops++
+			}
+		}
+		Expect(noops).To(Equal(1))
+		Expect(mailServer.Connections).To(Equal(1))
+	})
+
+	It("opens a new connection once a connection has sent its maximum number of messages", func() {
+		poolConfig.MaxMessagesPerConnection = 2
+		pool = mail.NewPool(config, poolConfig)
+
+		for i := 0; i < 3; i++ {
+			Expect(pool.Send(msg, logger)).To(Succeed())
+		}
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(3))
+
+		Expect(mailServer.Connections).To(Equal(2))
+	})
+
+	It("opens a new connection when the idle connection has been idle for too long", func() {
+		poolConfig.MaxIdleTime = 10 * time.Millisecond
+		pool = mail.NewPool(config, poolConfig)
+
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		time.Sleep(50 * time.Millisecond)
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(2))
+
+		Expect(mailServer.Connections).To(Equal(2))
+	})
+
+	It("reconnects and delivers the message when a reused connection is closed by the server", func() {
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		mailServer.MailFromResponses = []string{"421 closing connection"}
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		Eventually(func() int {
+			return len(mailServer.Deliveries)
+		}).Should(Equal(2))
+
+		Expect(mailServer.Connections).To(Equal(2))
+	})
+
+	It("returns transient errors from a fresh connection and drops the connection", func() {
+		mailServer.MailFromResponses = []string{"451 try again later"}
+
+		err := pool.Send(msg, logger)
+		Expect(err).To(Equal(mail.SMTPError{Code: 451, Message: "try again later"}))
+
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		Expect(mailServer.Connections).To(Equal(2))
+	})
+
+	It("returns permanent errors and keeps the connection", func() {
+		Expect(pool.Send(msg, logger)).To(Succeed())
+
+		mailServer.RcptToResponses = []string{"550 no such user"}
+		err := pool.Send(msg, logger)
+		Expect(err).To(Equal(mail.SMTPError{Code: 550, Message: "no such user"}))
+
+		Expect(pool.Send(msg, logger)).To(Succeed())
+		Expect(mailServer.Connections).To(Equal(1))
+	})
+
+	It("returns an error when it cannot connect", func() {
+		mailServer.ConnectWait = 5 * time.Second
+		config.ConnectTimeout = 100 * time.Millisecond
+		pool = mail.NewPool(config, poolConfig)
+
+		err := pool.Send(msg, logger)
+		Expect(err).To(MatchError("server timeout"))
+	})
+
+	Context("when in test mode", func() {
+		It("does not connect to the smtp server", func() {
+			config.TestMode = true
+			pool = mail.NewPool(config, poolConfig)
+
+			Expect(pool.Connect(logger)).To(Succeed())
+			Expect(pool.Send(msg, logger)).To(Succeed())
+			Expect(mailServer.Connections).To(Equal(0))
+		})
+	})
+})
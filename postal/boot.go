@@ -8,6 +8,7 @@ import (
 	"path"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/mail"
@@ -16,27 +17,37 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	v1models "github.com/cloudfoundry-incubator/notifications/v1/models"
-	"github.com/pivotal-golang/conceal"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/pivotal-golang/lager"
 )
 
 type Config struct {
-	UAAClientID          string
-	UAAClientSecret      string
-	UAATokenValidator    *uaa.TokenValidator
-	UAAHost              string
-	VerifySSL            bool
-	InstanceIndex        int
-	WorkerCount          int
-	EncryptionKey        []byte
-	DBLoggingEnabled     bool
-	RootPath             string
-	Sender               string
-	Domain               string
-	QueueWaitMaxDuration int
-	CCHost               string
+	UAAClientID                string
+	UAAClientSecret            string
+	UAATokenValidator          *uaa.TokenValidator
+	UAAHost                    string
+	VerifySSL                  bool
+	InstanceIndex              int
+	WorkerCount                int
+	EncryptionKey              []byte
+	DBLoggingEnabled           bool
+	RootPath                   string
+	Sender                     string
+	Domain                     string
+	NotificationsURL           string
+	QueueWaitMaxDuration       int
+	QueueReserveBatchSize      int
+	QueuePriorityAgingInterval int
+	CCHost                     string
+	DefaultUAAScopes           []string
+
+	HTMLEscapingCompatibilityMode bool
 }
 
+// fanOutChunkSize is the number of deliveries a fan-out job enqueues in each
+// transaction.
+const fanOutChunkSize = 500
+
 func database(db *sql.DB, dbLoggingEnabled bool, rootPath string) db.DatabaseInterface {
 	database := v1models.NewDatabase(db, v1models.Config{
 		DefaultTemplatePath: path.Join(rootPath, "templates", "default.json"),
@@ -49,7 +60,9 @@ func database(db *sql.DB, dbLoggingEnabled bool, rootPath string) db.DatabaseInt
 	return database
 }
 
-func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
+// Boot starts the delivery workers of this process and returns them so that
+// they can be halted on shutdown.
+func Boot(mailPool *mail.Pool, db *sql.DB, config Config) Workers {
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 
 	logger := lager.NewLogger("notifications")
@@ -61,10 +74,12 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 
 	gobbleDatabase := gobble.NewDatabase(db)
 	gobbleQueue := gobble.NewQueue(gobbleDatabase, clock, gobble.Config{
-		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
+		WaitMaxDuration:       time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
+		ReserveBatchSize:      config.QueueReserveBatchSize,
+		PriorityAgingInterval: time.Duration(config.QueuePriorityAgingInterval) * time.Second,
 	})
 
-	cloak, err := conceal.NewCloak(config.EncryptionKey)
+	cloak, err := common.NewAuthenticatedCloak(config.EncryptionKey)
 	if err != nil {
 		panic(err)
 	}
@@ -76,17 +91,42 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 	unsubscribesRepo := v1models.NewUnsubscribesRepo()
 	globalUnsubscribesRepo := v1models.NewGlobalUnsubscribesRepo()
 	messagesRepo := v1models.NewMessagesRepo(guidGenerator.Generate)
+	messageEventsRepo := v1models.NewMessageEventsRepo()
+	sendsRepo := v1models.NewSendsRepo(guidGenerator.Generate)
+	suppressionsRepo := v1models.NewSuppressionsRepo()
 	clientsRepo := v1models.NewClientsRepo()
 	kindsRepo := v1models.NewKindsRepo()
 	templatesRepo := v1models.NewTemplatesRepo()
-	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+	partialsRepo := v1models.NewPartialsRepo()
+	v1TemplateLoader := v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, partialsRepo)
 	deliveryFailureHandler := common.NewDeliveryFailureHandler()
 	messageStatusUpdater := v1.NewMessageStatusUpdater(messagesRepo)
+	messageEventRecorder := v1.NewMessageEventRecorder(messageEventsRepo)
 	userLoader := common.NewUserLoader(uaaClient)
-	tokenLoader := uaa.NewTokenLoader(uaaClient)
-	packager := common.NewPackager(v1TemplateLoader, cloak)
+	tokenLoader := uaa.NewTokenLoader(uaaClient, clock)
+	packager := common.NewPackager(v1TemplateLoader, cloak, config.NotificationsURL, config.HTMLEscapingCompatibilityMode, logger)
+
+	cloudController := cf.NewCloudController(config.CCHost, !config.VerifySSL)
+	spaceLoader := services.NewSpaceLoader(cloudController)
+	organizationLoader := services.NewOrganizationLoader(cloudController)
+	findsUserIDs := services.NewFindsUserIDs(cloudController, uaaClient)
+	allUsers := services.NewAllUsers(uaaClient)
+	emailResolver := services.NewEmailResolver(tokenLoader, userLoader)
+	fanOutEnqueuer := services.NewChunkedEnqueuer(services.NewEnqueuer(gobbleQueue, messagesRepo, messageEventsRepo, gobble.Initializer{}), messagesRepo, emailResolver, fanOutChunkSize)
+
+	fanOutJobProcessor := v1.NewFanOutJobProcessor(v1.FanOutJobProcessorConfig{
+		Database: database,
+		Strategies: map[string]v1.Dispatcher{
+			services.AudienceOrganization: services.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserIDs, fanOutEnqueuer),
+			services.AudienceSpace:        services.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserIDs, fanOutEnqueuer),
+			services.AudienceUAAScope:     services.NewUAAScopeStrategy(tokenLoader, findsUserIDs, fanOutEnqueuer, config.DefaultUAAScopes),
+			services.AudienceEveryone:     services.NewEveryoneStrategy(tokenLoader, allUsers, fanOutEnqueuer),
+		},
+		SendsRepo:              sendsRepo,
+		DeliveryFailureHandler: deliveryFailureHandler,
+	})
 
-	WorkerGenerator{
+	workers := WorkerGenerator{
 		InstanceIndex: config.InstanceIndex,
 		Count:         config.WorkerCount,
 	}.Work(func(index int) Worker {
@@ -98,7 +138,7 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			Domain:  config.Domain,
 
 			Packager:    packager,
-			MailClient:  mailClient(),
+			MailClient:  mailPool,
 			Database:    database,
 			TokenLoader: tokenLoader,
 			UserLoader:  userLoader,
@@ -107,7 +147,11 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			SuppressionsRepo:       suppressionsRepo,
+			MessagesRepo:           messagesRepo,
+			SendsRepo:              sendsRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
+			MessageEventRecorder:   messageEventRecorder,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
 
@@ -117,6 +161,7 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 			DBTrace: config.DBLoggingEnabled,
 
 			DeliveryFailureHandler: deliveryFailureHandler,
+			FanOutJobProcessor:     fanOutJobProcessor,
 
 			Logger: logger.Session("worker", lager.Data{"worker_id": index}),
 			Queue:  gobbleQueue,
@@ -124,4 +169,6 @@ func Boot(mailClient func() *mail.Client, db *sql.DB, config Config) {
 
 		return &worker
 	})
+
+	return NewWorkers(workers, gobbleQueue)
 }
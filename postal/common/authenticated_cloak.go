@@ -0,0 +1,74 @@
+package common
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/pivotal-golang/conceal"
+)
+
+const macDelimiter = "."
+
+// ErrUnauthenticatedCipherText is returned when a token has no MAC or its MAC
+// does not match its cipher text.
+var ErrUnauthenticatedCipherText = errors.New("cipher text failed authentication")
+
+// AuthenticatedCloak encrypts with the AES cloak and then appends an
+// HMAC-SHA256 of the cipher text, so that a token cannot be altered without
+// being rejected. Tokens are "<cipher text>.<mac>", both URL-safe base64.
+type AuthenticatedCloak struct {
+	cloak  conceal.CloakInterface
+	macKey []byte
+}
+
+// NewAuthenticatedCloak derives the MAC key from the encryption key so that
+// the two keys are never the same.
+func NewAuthenticatedCloak(key []byte) (AuthenticatedCloak, error) {
+	cloak, err := conceal.NewCloak(key)
+	if err != nil {
+		return AuthenticatedCloak{}, err
+	}
+
+	derivation := hmac.New(sha256.New, key)
+	derivation.Write([]byte("notifications-mac-key"))
+
+	return AuthenticatedCloak{
+		cloak:  cloak,
+		macKey: derivation.Sum(nil),
+	}, nil
+}
+
+func (c AuthenticatedCloak) Veil(plainText []byte) ([]byte, error) {
+	cipherText, err := c.cloak.Veil(plainText)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := base64.RawURLEncoding.EncodeToString(c.mac(cipherText))
+
+	return []byte(string(cipherText) + macDelimiter + mac), nil
+}
+
+func (c AuthenticatedCloak) Unveil(token []byte) ([]byte, error) {
+	index := strings.LastIndex(string(token), macDelimiter)
+	if index < 0 {
+		return nil, ErrUnauthenticatedCipherText
+	}
+
+	cipherText := token[:index]
+	mac, err := base64.RawURLEncoding.DecodeString(string(token[index+1:]))
+	if err != nil || !hmac.Equal(mac, c.mac(cipherText)) {
+		return nil, ErrUnauthenticatedCipherText
+	}
+
+	return c.cloak.Unveil(cipherText)
+}
+
+func (c AuthenticatedCloak) mac(cipherText []byte) []byte {
+	mac := hmac.New(sha256.New, c.macKey)
+	mac.Write(cipherText)
+	return mac.Sum(nil)
+}
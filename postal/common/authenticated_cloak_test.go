@@ -0,0 +1,65 @@
+package common_test
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AuthenticatedCloak", func() {
+	var cloak common.AuthenticatedCloak
+
+	BeforeEach(func() {
+		var err error
+		cloak, err = common.NewAuthenticatedCloak([]byte("the-encryption-key"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("round trips the plain text", func() {
+		token, err := cloak.Veil([]byte("some-user|some-client|some-kind"))
+		Expect(err).NotTo(HaveOccurred())
+
+		plainText, err := cloak.Unveil(token)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(plainText)).To(Equal("some-user|some-client|some-kind"))
+	})
+
+	It("rejects a token whose cipher text was altered", func() {
+		token, err := cloak.Veil([]byte("some-user|some-client|some-kind"))
+		Expect(err).NotTo(HaveOccurred())
+
+		altered := []byte(string(token))
+		if altered[20] == 'A' {
+			altered[20] = 'B'
+		} else {
+			altered[20] = 'A'
+		}
+
+		_, err = cloak.Unveil(altered)
+		Expect(err).To(Equal(common.ErrUnauthenticatedCipherText))
+	})
+
+	It("rejects a token without a MAC", func() {
+		token, err := cloak.Veil([]byte("some-user|some-client|some-kind"))
+		Expect(err).NotTo(HaveOccurred())
+
+		cipherText := token[:strings.LastIndex(string(token), ".")]
+
+		_, err = cloak.Unveil(cipherText)
+		Expect(err).To(Equal(common.ErrUnauthenticatedCipherText))
+	})
+
+	It("rejects a token veiled with another key", func() {
+		otherCloak, err := common.NewAuthenticatedCloak([]byte("another-key"))
+		Expect(err).NotTo(HaveOccurred())
+
+		token, err := otherCloak.Veil([]byte("some-user|some-client|some-kind"))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = cloak.Unveil(token)
+		Expect(err).To(Equal(common.ErrUnauthenticatedCipherText))
+	})
+})
@@ -2,30 +2,92 @@ package common
 
 import (
 	"math"
+	"math/rand"
 	"time"
 
 	"github.com/pivotal-golang/lager"
 	"github.com/rcrowley/go-metrics"
 )
 
+const (
+	DefaultMaxAttempts = 11
+	DefaultBaseDelay   = 1 * time.Minute
+)
+
 type Retryable interface {
 	Retry(duration time.Duration)
 	State() (retryCount int, activeAt time.Time)
+	Bury(reason string)
+}
+
+// RetryPolicy describes how a failed delivery is retried. The delay doubles
+// with every retry, starting at BaseDelay and capped at MaxDelay. Jitter is
+// the fraction of each delay that is randomly taken off so that retries of
+// many deliveries that failed together spread out. Zero values take the
+// defaults: 11 attempts, a one minute base delay, no cap, no jitter and no
+// deadline.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      float64
+	Deadline    time.Time
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = DefaultMaxAttempts
+	}
+
+	if p.BaseDelay == 0 {
+		p.BaseDelay = DefaultBaseDelay
+	}
+
+	return p
 }
 
-type DeliveryFailureHandler struct{}
+func (p RetryPolicy) backoff(retryCount int, random float64) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(retryCount))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if delay > math.MaxInt64 {
+		delay = math.MaxInt64
+	}
+
+	delay -= delay * p.Jitter * random
+
+	return time.Duration(delay)
+}
+
+type DeliveryFailureHandler struct {
+	random func() float64
+}
 
 func NewDeliveryFailureHandler() DeliveryFailureHandler {
-	return DeliveryFailureHandler{}
+	return DeliveryFailureHandler{
+		random: rand.Float64,
+	}
 }
 
-func (h DeliveryFailureHandler) Handle(job Retryable, logger lager.Logger) {
+// Handle retries the job according to the policy, or buries it when the
+// policy gives up on it. It returns whether the job will be retried.
+func (h DeliveryFailureHandler) Handle(job Retryable, err error, policy RetryPolicy, logger lager.Logger) bool {
+	policy = policy.withDefaults()
+
 	retryCount, _ := job.State()
-	if retryCount > 9 {
-		return
+	if retryCount+1 >= policy.MaxAttempts {
+		h.bury(job, retryCount, "max-attempts-reached", err, logger)
+		return false
+	}
+
+	duration := policy.backoff(retryCount, h.random())
+	if !policy.Deadline.IsZero() && time.Now().Add(duration).After(policy.Deadline) {
+		h.bury(job, retryCount, "deadline-exceeded", err, logger)
+		return false
 	}
 
-	duration := time.Duration(int64(math.Pow(2, float64(retryCount)))) * time.Minute
 	job.Retry(duration)
 
 	retryCount, activeAt := job.State()
@@ -35,4 +97,25 @@ func (h DeliveryFailureHandler) Handle(job Retryable, logger lager.Logger) {
 	})
 
 	metrics.GetOrRegisterCounter("notifications.worker.retry", nil).Inc(1)
+
+	return true
+}
+
+// bury records the last error on the job, or the reason it was buried when
+// there is no error, such as a job given up on for policy reasons alone.
+func (h DeliveryFailureHandler) bury(job Retryable, retryCount int, reason string, err error, logger lager.Logger) {
+	lastError := reason
+	if err != nil {
+		lastError = err.Error()
+	}
+
+	job.Bury(lastError)
+
+	logger.Info("delivery-failed-burying", lager.Data{
+		"retry_count": retryCount,
+		"reason":      reason,
+		"error":       lastError,
+	})
+
+	metrics.GetOrRegisterCounter("notifications.worker.dead", nil).Inc(1)
 }
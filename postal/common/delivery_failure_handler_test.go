@@ -2,6 +2,7 @@ package common_test
 
 import (
 	"bytes"
+	"errors"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
@@ -46,7 +47,7 @@ var _ = Describe("DeliveryFailureHandler", func() {
 		for retryCount, duration := range backoffDurations {
 			job.StateCall.Returns.Count = retryCount
 
-			handler.Handle(job, logger)
+			handler.Handle(job, errors.New("smtp is down"), common.RetryPolicy{}, logger)
 
 			Expect(job.RetryCall.Receives.Duration).To(Equal(duration))
 		}
@@ -55,17 +56,52 @@ var _ = Describe("DeliveryFailureHandler", func() {
 	It("gives up after 9 retries", func() {
 		job.StateCall.Returns.Count = 10
 
-		handler.Handle(job, logger)
+		retried := handler.Handle(job, errors.New("smtp is down"), common.RetryPolicy{}, logger)
 
+		Expect(retried).To(BeFalse())
 		Expect(job.RetryCall.WasCalled).To(BeFalse())
 	})
 
+	It("buries the job with the last error once it gives up", func() {
+		job.StateCall.Returns.Count = 10
+
+		handler.Handle(job, errors.New("smtp is down"), common.RetryPolicy{}, logger)
+
+		Expect(job.BuryCall.WasCalled).To(BeTrue())
+		Expect(job.BuryCall.Receives.Reason).To(Equal("smtp is down"))
+
+		lines, err := parseLogLines(buffer.Bytes())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0].Message).To(Equal("notifications.delivery-failed-burying"))
+		Expect(lines[0].Data).To(HaveKeyWithValue("retry_count", float64(10)))
+		Expect(lines[0].Data).To(HaveKeyWithValue("error", "smtp is down"))
+	})
+
+	It("buries the job with the reason when there is no error", func() {
+		job.StateCall.Returns.Count = 10
+
+		handler.Handle(job, nil, common.RetryPolicy{}, logger)
+
+		Expect(job.BuryCall.WasCalled).To(BeTrue())
+		Expect(job.BuryCall.Receives.Reason).To(Equal("max-attempts-reached"))
+	})
+
+	It("does not bury jobs that will be retried", func() {
+		job.StateCall.Returns.Count = 9
+
+		retried := handler.Handle(job, errors.New("smtp is down"), common.RetryPolicy{}, logger)
+
+		Expect(retried).To(BeTrue())
+		Expect(job.BuryCall.WasCalled).To(BeFalse())
+	})
+
 	It("logs the retry attempt", func() {
 		expectedActiveAt := time.Now().Truncate(time.Second)
 		job.StateCall.Returns.Time = expectedActiveAt
 		job.StateCall.Returns.Count = 4
 
-		handler.Handle(job, logger)
+		handler.Handle(job, errors.New("smtp is down"), common.RetryPolicy{}, logger)
 
 		lines, err := parseLogLines(buffer.Bytes())
 		Expect(err).NotTo(HaveOccurred())
@@ -82,4 +118,86 @@ var _ = Describe("DeliveryFailureHandler", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(activeAt.UTC()).To(Equal(expectedActiveAt.UTC()))
 	})
+
+	Context("when the kind has its own retry policy", func() {
+		It("backs off from the base delay up to the max delay", func() {
+			policy := common.RetryPolicy{
+				BaseDelay: 10 * time.Second,
+				MaxDelay:  1 * time.Minute,
+			}
+
+			backoffDurations := map[int]time.Duration{
+				0: 10 * time.Second,
+				1: 20 * time.Second,
+				2: 40 * time.Second,
+				3: 1 * time.Minute,
+				9: 1 * time.Minute,
+			}
+
+			for retryCount, duration := range backoffDurations {
+				job.StateCall.Returns.Count = retryCount
+
+				handler.Handle(job, errors.New("smtp is down"), policy, logger)
+
+				Expect(job.RetryCall.Receives.Duration).To(Equal(duration))
+			}
+		})
+
+		It("takes a random part of the delay off when there is jitter", func() {
+			policy := common.RetryPolicy{
+				BaseDelay: 1 * time.Minute,
+				Jitter:    0.5,
+			}
+
+			for i := 0; i < 20; i++ {
+				handler.Handle(job, errors.New("smtp is down"), policy, logger)
+
+				Expect(job.RetryCall.Receives.Duration).To(BeNumerically(">=", 30*time.Second))
+				Expect(job.RetryCall.Receives.Duration).To(BeNumerically("<=", 1*time.Minute))
+			}
+		})
+
+		It("gives up once the job has been attempted the max number of times", func() {
+			policy := common.RetryPolicy{MaxAttempts: 3}
+
+			job.StateCall.Returns.Count = 1
+			handler.Handle(job, errors.New("smtp is down"), policy, logger)
+			Expect(job.BuryCall.WasCalled).To(BeFalse())
+
+			job.StateCall.Returns.Count = 2
+			handler.Handle(job, errors.New("smtp is down"), policy, logger)
+			Expect(job.BuryCall.WasCalled).To(BeTrue())
+		})
+
+		It("gives up when the next retry would start after the deadline", func() {
+			policy := common.RetryPolicy{
+				BaseDelay: 1 * time.Hour,
+				Deadline:  time.Now().Add(30 * time.Minute),
+			}
+
+			handler.Handle(job, errors.New("smtp is down"), policy, logger)
+
+			Expect(job.RetryCall.WasCalled).To(BeFalse())
+			Expect(job.BuryCall.WasCalled).To(BeTrue())
+			Expect(job.BuryCall.Receives.Reason).To(Equal("smtp is down"))
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0].Message).To(Equal("notifications.delivery-failed-burying"))
+			Expect(lines[0].Data).To(HaveKeyWithValue("reason", "deadline-exceeded"))
+		})
+
+		It("retries when the next retry starts before the deadline", func() {
+			policy := common.RetryPolicy{
+				BaseDelay: 1 * time.Minute,
+				Deadline:  time.Now().Add(30 * time.Minute),
+			}
+
+			handler.Handle(job, errors.New("smtp is down"), policy, logger)
+
+			Expect(job.RetryCall.Receives.Duration).To(Equal(1 * time.Minute))
+			Expect(job.BuryCall.WasCalled).To(BeFalse())
+		})
+	})
 })
@@ -20,6 +20,7 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	Locale            string
 }
 
 type Delivery struct {
@@ -35,13 +36,31 @@ type Delivery struct {
 	VCAPRequestID   string
 	RequestReceived time.Time
 	CampaignID      string
+	Locale          string
 }
 
+// RecipientLocale is the locale the message is written in: the one the
+// notification asked for, or else the locale of the recipient.
+func (delivery Delivery) RecipientLocale() string {
+	if delivery.Options.Locale != "" {
+		return delivery.Options.Locale
+	}
+
+	return delivery.Locale
+}
+
+// Templates holds the templates a message is compiled from. Partials are
+// the named templates the others may include, and Layout, when set, replaces
+// the default HTML wrapper.
 type Templates struct {
-	Name    string
-	Subject string
-	Text    string
-	HTML    string
+	ID       string
+	Version  int
+	Name     string
+	Subject  string
+	Text     string
+	HTML     string
+	Partials map[string]string
+	Layout   string
 }
 
 type HTML struct {
@@ -62,6 +81,10 @@ type MessageContext struct {
 	TextTemplate      string
 	HTMLTemplate      string
 	SubjectTemplate   string
+	LayoutTemplate    string
+	Partials          map[string]string
+	TemplateID        string
+	TemplateVersion   int
 	KindDescription   string
 	SourceDescription string
 	UserGUID          string
@@ -75,6 +98,7 @@ type MessageContext struct {
 	Scope             string
 	Endorsement       string
 	OrganizationRole  string
+	SpaceRole         string
 	RequestReceived   time.Time
 	Domain            string
 }
@@ -107,6 +131,10 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		TextTemplate:      templates.Text,
 		HTMLTemplate:      templates.HTML,
 		SubjectTemplate:   templates.Subject,
+		LayoutTemplate:    templates.Layout,
+		Partials:          templates.Partials,
+		TemplateID:        templates.ID,
+		TemplateVersion:   templates.Version,
 		KindDescription:   kindDescription,
 		SourceDescription: sourceDescription,
 		UserGUID:          delivery.UserGUID,
@@ -118,7 +146,6 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		OrganizationGUID:  delivery.Organization.GUID,
 		Scope:             delivery.Scope,
 		Endorsement:       options.Endorsement,
-		OrganizationRole:  options.Role,
 		RequestReceived:   delivery.RequestReceived,
 		Domain:            domain,
 	}
@@ -127,15 +154,31 @@ func NewMessageContext(delivery Delivery, sender, domain string, cloak conceal.C
 		messageContext.Subject = "[no subject]"
 	}
 
-	unsubscribeID, err := cloak.Veil([]byte(delivery.UserGUID + "|" + delivery.ClientID + "|" + options.KindID))
+	// The role belongs to the audience of the send: a space role for a space
+	// send, which also carries its organization, and an organization role
+	// for an organization send.
+	if delivery.Space.GUID != "" {
+		messageContext.SpaceRole = options.Role
+	} else if delivery.Organization.GUID != "" {
+		messageContext.OrganizationRole = options.Role
+	}
+
+	unsubscribeID, err := UnsubscribeID{
+		UserGUID: delivery.UserGUID,
+		ClientID: delivery.ClientID,
+		KindID:   options.KindID,
+		IssuedAt: delivery.RequestReceived,
+	}.Veil(cloak)
 	if err != nil {
 		panic(err)
 	}
 
-	messageContext.UnsubscribeID = string(unsubscribeID)
+	messageContext.UnsubscribeID = unsubscribeID
 	return messageContext
 }
 
+// Escape html-escapes the fields that HTML templates were written against
+// before contextual escaping. It is only used in compatibility mode.
 func (context *MessageContext) Escape() {
 	context.From = html.EscapeString(context.From)
 	context.To = html.EscapeString(context.To)
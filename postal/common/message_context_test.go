@@ -26,6 +26,8 @@ var _ = Describe("MessageContext", func() {
 		domain = "http://www.example.com"
 
 		templates = common.Templates{
+			ID:      "some-template-id",
+			Version: 2,
 			Text:    "the plainText email < template",
 			HTML:    "the html <h1> email < template</h1>",
 			Subject: "the subject < template",
@@ -44,7 +46,7 @@ var _ = Describe("MessageContext", func() {
 			HTML:              html,
 			KindID:            "the-kind-id",
 			Endorsement:       "this is the endorsement",
-			Role:              "OrgRole",
+			Role:              "SpaceDeveloper",
 		}
 
 		reqReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:40:12.207187819-07:00")
@@ -75,7 +77,7 @@ var _ = Describe("MessageContext", func() {
 		It("returns the appropriate MessageContext when all options are specified", func() {
 			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
 
-			Expect(cloak.VeilCall.Receives.PlainText).To(Equal([]byte("the-user|the-client-id|the-kind-id")))
+			Expect(cloak.VeilCall.Receives.PlainText).To(Equal([]byte("the-user|the-client-id|the-kind-id|1433799612")))
 
 			Expect(context.From).To(Equal(sender))
 			Expect(context.ReplyTo).To(Equal(options.ReplyTo))
@@ -87,6 +89,8 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.TextTemplate).To(Equal(templates.Text))
 			Expect(context.HTMLTemplate).To(Equal(templates.HTML))
 			Expect(context.SubjectTemplate).To(Equal(templates.Subject))
+			Expect(context.TemplateID).To(Equal("some-template-id"))
+			Expect(context.TemplateVersion).To(Equal(2))
 			Expect(context.KindDescription).To(Equal(options.KindDescription))
 			Expect(context.SourceDescription).To(Equal(options.SourceDescription))
 			Expect(context.UserGUID).To(Equal("the-user"))
@@ -99,11 +103,21 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.UnsubscribeID).To(Equal("the-encoded-result"))
 			Expect(context.Scope).To(Equal("this.scope"))
 			Expect(context.Endorsement).To(Equal("this is the endorsement"))
-			Expect(context.OrganizationRole).To(Equal("OrgRole"))
+			Expect(context.OrganizationRole).To(BeEmpty())
+			Expect(context.SpaceRole).To(Equal("SpaceDeveloper"))
 			Expect(context.RequestReceived).To(Equal(reqReceived))
 			Expect(context.Domain).To(Equal(domain))
 		})
 
+		It("sets the role of an organization send as the organization role", func() {
+			delivery.Space = cf.CloudControllerSpace{}
+			delivery.Options.Role = "OrgManager"
+			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
+
+			Expect(context.OrganizationRole).To(Equal("OrgManager"))
+			Expect(context.SpaceRole).To(BeEmpty())
+		})
+
 		It("falls back to Kind if KindDescription is missing", func() {
 			delivery.Options.KindDescription = ""
 			context := common.NewMessageContext(delivery, sender, domain, cloak, templates)
@@ -136,7 +150,7 @@ var _ = Describe("MessageContext", func() {
 				HTML:              common.HTML{BodyContent: "user & supplied html"},
 				KindID:            "the & kind",
 				Endorsement:       "this & is the endorsement",
-				Role:              "OrgRole",
+				Role:              "SpaceDeveloper",
 			}
 
 			delivery.Options = options
@@ -169,7 +183,7 @@ var _ = Describe("MessageContext", func() {
 			Expect(context.Organization).To(Equal("the&gt;org"))
 			Expect(context.Scope).To(Equal(""))
 			Expect(context.Endorsement).To(Equal("this &amp; is the endorsement"))
-			Expect(context.OrganizationRole).To(Equal("OrgRole"))
+			Expect(context.SpaceRole).To(Equal("SpaceDeveloper"))
 		})
 	})
 })
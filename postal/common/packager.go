@@ -3,12 +3,18 @@ package common
 import (
 	"bytes"
 	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"regexp"
 	"strings"
+	"sync"
 	"text/template"
+	"text/template/parse"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/pivotal-golang/conceal"
+	"github.com/pivotal-golang/lager"
 )
 
 const HTMLWrapperTemplate = `{{.HTMLComponents.Doctype}}
@@ -20,23 +26,163 @@ const HTMLWrapperTemplate = `{{.HTMLComponents.Doctype}}
 </html>`
 
 type templatesLoader interface {
-	LoadTemplates(clientID, kindID, templateID string) (Templates, error)
+	LoadTemplates(clientID, kindID, templateID, locale string) (Templates, error)
 }
 
+// EscapingChangeError flags an HTML part that contextual escaping would
+// render differently, or not at all, than the escaping it was delivered with.
+// Offset is the first byte at which the two renderings differ once their
+// character references are normalized, and Delivered and Contextual are the
+// snippets of each that start around it.
+type EscapingChangeError struct {
+	Err        error
+	Offset     int
+	Delivered  string
+	Contextual string
+}
+
+func (e EscapingChangeError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("Contextual escaping would reject the HTML part: %s", e.Err)
+	}
+
+	return fmt.Sprintf("Contextual escaping would change the output of the HTML part at offset %d: %q would become %q", e.Offset, e.Delivered, e.Contextual)
+}
+
+const escapingChangeSnippetLength = 40
+
+var characterReference = regexp.MustCompile(`&(#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);`)
+
+// canonicalReferences are the characters that stay escaped when the
+// character references of an HTML part are normalized.
+var canonicalReferences = map[string]string{
+	"<": "&lt;",
+	">": "&gt;",
+	"&": "&amp;",
+	`"`: "&#34;",
+	"'": "&#39;",
+}
+
+// normalizeReferences rewrites the character references of the HTML so that
+// two renderings that only spell them differently, such as "+" and "&#43;"
+// or "&quot;" and "&#34;", compare equal. Characters that are not references
+// are left as they are, so markup that one rendering escapes and the other
+// does not still differs.
+func normalizeReferences(s string) string {
+	return characterReference.ReplaceAllStringFunc(s, func(reference string) string {
+		character := html.UnescapeString(reference)
+		if character == reference {
+			return reference
+		}
+
+		if canonical, ok := canonicalReferences[character]; ok {
+			return canonical
+		}
+
+		return character
+	})
+}
+
+// escapingChange compares the HTML part as delivered with the one contextual
+// escaping renders, and returns nil when they only differ in how their
+// character references are spelled.
+func escapingChange(delivered, contextual string) *EscapingChangeError {
+	delivered = normalizeReferences(delivered)
+	contextual = normalizeReferences(contextual)
+	if delivered == contextual {
+		return nil
+	}
+
+	offset := 0
+	for offset < len(delivered) && offset < len(contextual) && delivered[offset] == contextual[offset] {
+		offset++
+	}
+
+	start := offset - escapingChangeSnippetLength/2
+	if start < 0 {
+		start = 0
+	}
+
+	return &EscapingChangeError{
+		Offset:     offset,
+		Delivered:  snippet(delivered, start),
+		Contextual: snippet(contextual, start),
+	}
+}
+
+func snippet(s string, start int) string {
+	end := start + escapingChangeSnippetLength
+	if end > len(s) {
+		end = len(s)
+	}
+
+	return s[start:end]
+}
+
+// Packager compiles the HTML part with html/template, which escapes the
+// fields of the context for where they appear in the markup. In
+// compatibility mode it delivers the HTML part escaped the way it was before
+// and logs the messages whose output contextual escaping would change. Each
+// template is logged once; after that its messages are no longer compiled a
+// second time to compare them.
+//
+// The one-click unsubscribe headers point at the notifications service under
+// notificationsURL, and are left out when it is not set.
 type Packager struct {
-	templates templatesLoader
-	cloak     conceal.CloakInterface
+	templates          templatesLoader
+	cloak              conceal.CloakInterface
+	notificationsURL   string
+	compatibleEscaping bool
+	flagged            *flaggedTemplates
+	logger             lager.Logger
 }
 
-func NewPackager(templates templatesLoader, cloak conceal.CloakInterface) Packager {
+func NewPackager(templates templatesLoader, cloak conceal.CloakInterface, notificationsURL string, compatibleEscaping bool, logger lager.Logger) Packager {
 	return Packager{
-		templates: templates,
-		cloak:     cloak,
+		templates:          templates,
+		cloak:              cloak,
+		notificationsURL:   notificationsURL,
+		compatibleEscaping: compatibleEscaping,
+		flagged:            &flaggedTemplates{keys: map[string]bool{}},
+		logger:             logger,
 	}
 }
 
+// flaggedTemplates remembers the template versions whose HTML part has been
+// logged as changed by contextual escaping.
+type flaggedTemplates struct {
+	mutex sync.Mutex
+	keys  map[string]bool
+}
+
+func flaggedTemplateKey(context MessageContext) string {
+	return fmt.Sprintf("%s/%s/%d", context.ClientID, context.TemplateID, context.TemplateVersion)
+}
+
+func (f *flaggedTemplates) has(context MessageContext) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.keys[flaggedTemplateKey(context)]
+}
+
+// add returns false when the template was already flagged.
+func (f *flaggedTemplates) add(context MessageContext) bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	key := flaggedTemplateKey(context)
+	if f.keys[key] {
+		return false
+	}
+
+	f.keys[key] = true
+
+	return true
+}
+
 func (packager Packager) PrepareContext(delivery Delivery, sender, domain string) (MessageContext, error) {
-	templates, err := packager.templates.LoadTemplates(delivery.ClientID, delivery.Options.KindID, delivery.Options.TemplateID)
+	templates, err := packager.templates.LoadTemplates(delivery.ClientID, delivery.Options.KindID, delivery.Options.TemplateID, delivery.RecipientLocale())
 	if err != nil {
 		return MessageContext{}, err
 	}
@@ -45,42 +191,101 @@ func (packager Packager) PrepareContext(delivery Delivery, sender, domain string
 }
 
 func (packager Packager) Pack(context MessageContext) (mail.Message, error) {
-	parts, err := packager.CompileParts(context)
+	c := &compilation{checkEscaping: !packager.flagged.has(context)}
+
+	message, err := packager.pack(context, c)
+	if c.escapingChange != nil && packager.flagged.add(context) {
+		packager.logger.Info("html-escaping-would-change-output", lager.Data{
+			"client_id":   context.ClientID,
+			"template_id": context.TemplateID,
+			"message_id":  context.MessageID,
+			"change":      c.escapingChange.Error(),
+			"offset":      c.escapingChange.Offset,
+			"delivered":   c.escapingChange.Delivered,
+			"contextual":  c.escapingChange.Contextual,
+		})
+	}
+
+	return message, err
+}
+
+// Preview packs the message like Pack and also returns the errors hit while
+// executing its templates, followed in compatibility mode by an
+// EscapingChangeError when contextual escaping would change the HTML part.
+// Pack delivers whatever a template rendered before its execution failed.
+func (packager Packager) Preview(context MessageContext) (mail.Message, []error, error) {
+	c := &compilation{checkEscaping: true}
+
+	message, err := packager.pack(context, c)
+
+	errs := c.executionErrors
+	if c.escapingChange != nil {
+		errs = append(errs, *c.escapingChange)
+	}
+
+	return message, errs, err
+}
+
+func (packager Packager) pack(context MessageContext, c *compilation) (mail.Message, error) {
+	parts, err := packager.compileParts(context, c)
 	if err != nil {
 		return mail.Message{}, err
 	}
 
-	compiledSubject, err := packager.compileTemplate(context, context.SubjectTemplate, false)
+	compiledSubject, err := c.compileTemplate(context, "subject", context.SubjectTemplate, false)
 	if err != nil {
 		return mail.Message{}, err
 	}
 
+	headers := []string{
+		fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
+		fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
+		fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
+		fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
+	}
+
+	if context.UnsubscribeID != "" && packager.notificationsURL != "" {
+		headers = append(headers,
+			fmt.Sprintf("List-Unsubscribe: <%s>", unsubscribeURL(packager.notificationsURL, context.UnsubscribeID)),
+			"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+		)
+	}
+
 	return mail.Message{
 		From:    context.From,
 		ReplyTo: context.ReplyTo,
 		To:      context.To,
 		Subject: compiledSubject,
 		Body:    parts,
-		Headers: []string{
-			fmt.Sprintf("X-CF-Client-ID: %s", context.ClientID),
-			fmt.Sprintf("X-CF-Notification-ID: %s", context.MessageID),
-			fmt.Sprintf("X-CF-Notification-Timestamp: %s", time.Now().Format(time.RFC3339Nano)),
-			fmt.Sprintf("X-CF-Notification-Request-Received: %s", context.RequestReceived.Format(time.RFC3339Nano)),
-		},
+		Headers: headers,
 	}, nil
 }
 
+// unsubscribeURL is the one-click unsubscribe endpoint (RFC 8058) for the
+// unsubscribe ID. A URL without a scheme is served over https.
+func unsubscribeURL(notificationsURL, unsubscribeID string) string {
+	if !strings.Contains(notificationsURL, "://") {
+		notificationsURL = "https://" + notificationsURL
+	}
+
+	return fmt.Sprintf("%s/unsubscribe/%s", strings.TrimSuffix(notificationsURL, "/"), unsubscribeID)
+}
+
 func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, error) {
+	return packager.compileParts(context, &compilation{})
+}
+
+func (packager Packager) compileParts(context MessageContext, c *compilation) ([]mail.Part, error) {
 	var parts []mail.Part
 	var err error
 
-	context.Endorsement, err = packager.compileTemplate(context, context.Endorsement, false)
+	context.Endorsement, err = c.compileTemplate(context, "endorsement", context.Endorsement, false)
 	if err != nil {
 		return parts, err
 	}
 
 	if context.Text != "" {
-		plainText, err := packager.compileTemplate(context, context.TextTemplate, false)
+		plainText, err := c.compileTemplate(context, "text", context.TextTemplate, false)
 		if err != nil {
 			return parts, err
 		}
@@ -93,16 +298,28 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	}
 
 	if context.HTML != "" {
-		var err error
+		var htmlPart string
 
-		context.HTMLComponents.BodyContent, err = packager.compileTemplate(context, context.HTMLTemplate, true)
-		if err != nil {
-			return parts, err
-		}
+		if packager.compatibleEscaping {
+			htmlPart, err = c.compileHTMLPart(context, false)
+			if err != nil {
+				return parts, err
+			}
 
-		htmlPart, err := packager.compileTemplate(context, HTMLWrapperTemplate, true)
-		if err != nil {
-			return parts, err
+			if c.checkEscaping {
+				shadow := &compilation{partials: c.partialsOf(context)}
+				contextualPart, err := shadow.compileHTMLPart(context, true)
+				if err != nil {
+					c.escapingChange = &EscapingChangeError{Err: err}
+				} else {
+					c.escapingChange = escapingChange(htmlPart, contextualPart)
+				}
+			}
+		} else {
+			htmlPart, err = c.compileHTMLPart(context, true)
+			if err != nil {
+				return parts, err
+			}
 		}
 
 		parts = append(parts, mail.Part{
@@ -114,10 +331,113 @@ func (packager Packager) CompileParts(context MessageContext) ([]mail.Part, erro
 	return parts, nil
 }
 
-func (packager Packager) compileTemplate(context MessageContext, theTemplate string, escapeContext bool) (string, error) {
+// compilation collects the errors hit while executing the templates of a
+// message. A template that fails to parse fails the whole message instead.
+// In compatibility mode, checkEscaping compiles the HTML part a second time
+// with contextual escaping to compare the two.
+type compilation struct {
+	executionErrors []error
+	checkEscaping   bool
+	escapingChange  *EscapingChangeError
+	partials        *partialSet
+}
+
+func (c *compilation) partialsOf(context MessageContext) *partialSet {
+	if c.partials == nil {
+		c.partials = &partialSet{bodies: context.Partials}
+	}
+
+	return c.partials
+}
+
+// partialSet parses the partials of a message once, the first time a part of
+// the message is compiled as text or as HTML. Every part is then compiled
+// against a clone of the parsed partials.
+type partialSet struct {
+	bodies map[string]string
+	text   *template.Template
+	html   *htmltemplate.Template
+}
+
+func (p *partialSet) textTemplate(name string) (*template.Template, error) {
+	if p.text == nil {
+		base := template.New("")
+		for partialName, body := range p.bodies {
+			_, err := base.New(partialName).Parse(body)
+			if err != nil {
+				return nil, err
+			}
+		}
+		p.text = base
+	}
+
+	clone, err := p.text.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return clone.New(name), nil
+}
+
+func (p *partialSet) htmlTemplate(name string) (*htmltemplate.Template, error) {
+	if p.html == nil {
+		base := htmltemplate.New("")
+		for partialName, body := range p.bodies {
+			_, err := base.New(partialName).Parse(body)
+			if err != nil {
+				return nil, err
+			}
+		}
+		p.html = base
+	}
+
+	clone, err := p.html.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return clone.New(name), nil
+}
+
+// compileHTMLPart compiles the HTML template and wraps it in the layout of
+// the client, or the default wrapper. Without contextual escaping both are
+// compiled as text templates against the escaped context.
+func (c *compilation) compileHTMLPart(context MessageContext, contextual bool) (string, error) {
+	var err error
+
+	wrapper := HTMLWrapperTemplate
+	if context.LayoutTemplate != "" {
+		wrapper = context.LayoutTemplate
+	}
+
+	if !contextual {
+		context.HTMLComponents.BodyContent, err = c.compileTemplate(context, "html", context.HTMLTemplate, true)
+		if err != nil {
+			return "", err
+		}
+
+		return c.compileTemplate(context, "html-wrapper", wrapper, true)
+	}
+
+	context.HTMLComponents.BodyContent, err = c.compileHTMLTemplate(context, "html", context.HTMLTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	return c.compileHTMLTemplate(context, "html-wrapper", wrapper)
+}
+
+// compileTemplate executes the template with the partials of the context
+// defined alongside it, so it can include them by name.
+func (c *compilation) compileTemplate(context MessageContext, name, theTemplate string, escapeContext bool) (string, error) {
 	buffer := bytes.NewBuffer([]byte{})
 
-	source, err := template.New("compileTemplate").Parse(theTemplate)
+	source, err := c.partialsOf(context).textTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = source.Parse(theTemplate)
 	if err != nil {
 		return "", err
 	}
@@ -126,8 +446,129 @@ func (packager Packager) compileTemplate(context MessageContext, theTemplate str
 		context.Escape()
 	}
 
-	source.Execute(buffer, context)
+	err = source.Execute(buffer, context)
+	if err != nil {
+		c.executionErrors = append(c.executionErrors, err)
+	}
+
+	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
+
+	return compiledTemplate, nil
+}
+
+// htmlContext is the context an HTML template is executed with. The HTML of
+// the notification and the compiled body are markup the sender supplied, so
+// they are inserted as they are; every other field is escaped for where it
+// appears.
+type htmlContext struct {
+	MessageContext
+	HTML           htmltemplate.HTML
+	HTMLComponents htmlComponents
+}
+
+type htmlComponents struct {
+	BodyContent    htmltemplate.HTML
+	BodyAttributes htmltemplate.HTMLAttr
+	Head           htmltemplate.HTML
+	Doctype        htmltemplate.HTML
+}
+
+// compileHTMLTemplate executes the template with html/template and the
+// partials of the context defined alongside it. A template that cannot be
+// escaped, such as one that leaves a branch inside an attribute, fails like
+// one that cannot be parsed. A partial that is missing is rendered as empty
+// and reported as an execution error, as it is for text templates, since
+// html/template would otherwise fail to escape the template at all.
+func (c *compilation) compileHTMLTemplate(context MessageContext, name, theTemplate string) (string, error) {
+	buffer := bytes.NewBuffer([]byte{})
+
+	source, err := c.partialsOf(context).htmlTemplate(name)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = source.Parse(theTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	for _, missing := range missingTemplates(source) {
+		c.executionErrors = append(c.executionErrors, fmt.Errorf("html/template:%s: no such template %q", name, missing))
+
+		_, err = source.New(missing).Parse("")
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = source.Execute(buffer, htmlContext{
+		MessageContext: context,
+		HTML:           htmltemplate.HTML(context.HTML),
+		HTMLComponents: htmlComponents{
+			BodyContent:    htmltemplate.HTML(context.HTMLComponents.BodyContent),
+			BodyAttributes: htmltemplate.HTMLAttr(context.HTMLComponents.BodyAttributes),
+			Head:           htmltemplate.HTML(context.HTMLComponents.Head),
+			Doctype:        htmltemplate.HTML(context.HTMLComponents.Doctype),
+		},
+	})
+	if err != nil {
+		if _, ok := err.(*htmltemplate.Error); ok {
+			return "", err
+		}
+
+		c.executionErrors = append(c.executionErrors, err)
+	}
+
 	compiledTemplate := strings.TrimSuffix(buffer.String(), "\n")
 
 	return compiledTemplate, nil
 }
+
+// missingTemplates returns the names of the templates that are included in
+// the set of the source but not defined in it.
+func missingTemplates(source *htmltemplate.Template) []string {
+	var missing []string
+	seen := map[string]bool{}
+
+	for _, defined := range source.Templates() {
+		if defined.Tree == nil {
+			continue
+		}
+
+		for _, name := range templateReferences(defined.Tree.Root, nil) {
+			if seen[name] || source.Lookup(name) != nil {
+				continue
+			}
+
+			seen[name] = true
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
+func templateReferences(node parse.Node, names []string) []string {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return names
+		}
+		for _, child := range node.Nodes {
+			names = templateReferences(child, names)
+		}
+	case *parse.IfNode:
+		names = templateReferences(node.List, names)
+		names = templateReferences(node.ElseList, names)
+	case *parse.RangeNode:
+		names = templateReferences(node.List, names)
+		names = templateReferences(node.ElseList, names)
+	case *parse.WithNode:
+		names = templateReferences(node.List, names)
+		names = templateReferences(node.ElseList, names)
+	case *parse.TemplateNode:
+		names = append(names, node.Name)
+	}
+
+	return names
+}
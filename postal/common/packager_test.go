@@ -1,6 +1,7 @@
 package common_test
 
 import (
+	"bytes"
 	"errors"
 	"strings"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/mail"
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/lager"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,6 +22,8 @@ var _ = Describe("Packager", func() {
 		templatesLoader *mocks.TemplatesLoader
 		delivery        common.Delivery
 		cloak           *mocks.Cloak
+		buffer          *bytes.Buffer
+		logger          lager.Logger
 	)
 
 	BeforeEach(func() {
@@ -43,7 +47,11 @@ var _ = Describe("Packager", func() {
 			},
 		}
 
-		packager = common.NewPackager(templatesLoader, cloak)
+		buffer = bytes.NewBuffer([]byte{})
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+		packager = common.NewPackager(templatesLoader, cloak, "", false, logger)
 
 		requestReceivedTime, _ := time.Parse(time.RFC3339Nano, "2015-06-08T14:38:03.180764129-07:00")
 
@@ -119,6 +127,33 @@ var _ = Describe("Packager", func() {
 			}))
 		})
 
+		It("carries the partials and the layout into the context", func() {
+			templatesLoader.LoadTemplatesCall.Returns.Templates.Partials = map[string]string{"footer": "the footer"}
+			templatesLoader.LoadTemplatesCall.Returns.Templates.Layout = "<main>{{.HTMLComponents.BodyContent}}</main>"
+
+			context, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(context.Partials).To(Equal(map[string]string{"footer": "the footer"}))
+			Expect(context.LayoutTemplate).To(Equal("<main>{{.HTMLComponents.BodyContent}}</main>"))
+		})
+
+		It("loads the template for the locale of the recipient", func() {
+			delivery.Locale = "de-DE"
+
+			_, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templatesLoader.LoadTemplatesCall.Receives.Locale).To(Equal("de-DE"))
+		})
+
+		It("prefers the locale the notification asked for", func() {
+			delivery.Locale = "de-DE"
+			delivery.Options.Locale = "ja"
+
+			_, err := packager.PrepareContext(delivery, "some-sender", "some-domain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(templatesLoader.LoadTemplatesCall.Receives.Locale).To(Equal("ja"))
+		})
+
 		Context("when the template cannot be loaded", func() {
 			It("returns an error", func() {
 				templatesLoader.LoadTemplatesCall.Returns.Error = errors.New("some error")
@@ -165,6 +200,79 @@ var _ = Describe("Packager", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(timestamp).To(BeTemporally("~", time.Now(), 2*time.Second))
 		})
+
+		It("adds one-click unsubscribe headers", func() {
+			packager = common.NewPackager(templatesLoader, cloak, "notifications.example.com", false, logger)
+			context.UnsubscribeID = "some-unsubscribe-id"
+			context.Domain = "system.example.com"
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("List-Unsubscribe: <https://notifications.example.com/unsubscribe/some-unsubscribe-id>"))
+			Expect(msg.Headers).To(ContainElement("List-Unsubscribe-Post: List-Unsubscribe=One-Click"))
+		})
+
+		It("keeps the scheme of the notifications URL", func() {
+			packager = common.NewPackager(templatesLoader, cloak, "http://notifications.example.com/", false, logger)
+			context.UnsubscribeID = "some-unsubscribe-id"
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Headers).To(ContainElement("List-Unsubscribe: <http://notifications.example.com/unsubscribe/some-unsubscribe-id>"))
+		})
+
+		It("omits the unsubscribe headers without an unsubscribe ID", func() {
+			packager = common.NewPackager(templatesLoader, cloak, "notifications.example.com", false, logger)
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			for _, header := range msg.Headers {
+				Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+			}
+		})
+
+		It("omits the unsubscribe headers without a notifications URL", func() {
+			context.UnsubscribeID = "some-unsubscribe-id"
+			context.Domain = "system.example.com"
+
+			msg, err := packager.Pack(context)
+			Expect(err).NotTo(HaveOccurred())
+			for _, header := range msg.Headers {
+				Expect(header).NotTo(HavePrefix("List-Unsubscribe"))
+			}
+		})
+	})
+
+	Describe("Preview", func() {
+		It("packs the message like Pack does", func() {
+			msg, executionErrors, err := packager.Preview(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(executionErrors).To(BeEmpty())
+			Expect(msg.Subject).To(Equal("The Subject: we will be eaten"))
+			Expect(msg.Body).To(HaveLen(2))
+		})
+
+		It("returns the errors hit while executing the templates", func() {
+			context.SubjectTemplate = "The Subject: {{.Subject}} {{.Missing}}"
+			context.TextTemplate = "{{.Text}} {{.Unknown}}"
+
+			msg, executionErrors, err := packager.Preview(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg.Subject).To(Equal("The Subject: we will be eaten "))
+
+			Expect(executionErrors).To(HaveLen(2))
+			Expect(executionErrors[0].Error()).To(ContainSubstring(`template: text:1:12: executing "text" at <.Unknown>`))
+			Expect(executionErrors[1].Error()).To(ContainSubstring(`template: subject:1:28: executing "subject" at <.Missing>`))
+		})
+
+		Context("when a template cannot be parsed", func() {
+			It("returns the error", func() {
+				context.TextTemplate = "{{.Text"
+
+				_, _, err := packager.Preview(context)
+				Expect(err).To(HaveOccurred())
+			})
+		})
 	})
 
 	Describe("CompileParts", func() {
@@ -240,5 +348,218 @@ Banana preamble <p>user supplied banana html</p>  3&amp;3 4&#39;4 user-123
 				}))
 			})
 		})
+
+		Context("when there are partials", func() {
+			BeforeEach(func() {
+				context.Partials = map[string]string{
+					"header": "<header>{{.Organization}}</header>",
+					"footer": "Sent to {{.To}}",
+				}
+				context.SubjectTemplate = `{{template "subject-prefix"}} {{.Subject}}`
+				context.Partials["subject-prefix"] = "[CF]"
+				context.TextTemplate = `{{.Text}}
+{{template "footer" .}}`
+				context.HTMLTemplate = `{{template "header" .}}{{.HTML}}<footer>{{template "footer" .}}</footer>`
+			})
+
+			It("lets the templates include them", func() {
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg.Subject).To(Equal("[CF] we will be eaten"))
+
+				Expect(msg.Body).To(ContainElement(mail.Part{
+					ContentType: "text/plain",
+					Content: `User <supplied> "banana" text
+Sent to endless monkeys`,
+				}))
+				Expect(msg.Body).To(ContainElement(mail.Part{
+					ContentType: "text/html",
+					Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<header>banana</header><p>user supplied banana html</p><footer>Sent to endless monkeys</footer>
+	</body>
+</html>`,
+				}))
+			})
+
+			It("wraps the html in the layout instead of the default wrapper", func() {
+				context.LayoutTemplate = `<html>{{template "header" .}}<main>{{.HTMLComponents.BodyContent}}</main></html>`
+				context.HTMLTemplate = "{{.HTML}}"
+
+				parts, err := packager.CompileParts(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parts).To(ContainElement(mail.Part{
+					ContentType: "text/html",
+					Content:     "<html><header>banana</header><main><p>user supplied banana html</p></main></html>",
+				}))
+			})
+
+			It("reports a partial that is missing from the text as an execution error", func() {
+				context.TextTemplate = `{{template "missing" .}}`
+
+				_, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executionErrors).To(HaveLen(1))
+				Expect(executionErrors[0].Error()).To(ContainSubstring(`template "missing" not defined`))
+			})
+
+			It("reports a partial that is missing from the html as an execution error", func() {
+				context.HTMLTemplate = `before{{template "missing" .}}after`
+
+				msg, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executionErrors).To(HaveLen(1))
+				Expect(executionErrors[0].Error()).To(ContainSubstring(`no such template "missing"`))
+				Expect(msg.Body[len(msg.Body)-1].Content).To(ContainSubstring("beforeafter"))
+			})
+
+			It("reports a partial that is missing from the layout as an execution error", func() {
+				context.LayoutTemplate = `<html>{{template "missing" .}}{{.HTMLComponents.BodyContent}}</html>`
+
+				_, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executionErrors).To(HaveLen(1))
+				Expect(executionErrors[0].Error()).To(ContainSubstring(`no such template "missing"`))
+			})
+
+			It("returns an error when a partial cannot be parsed", func() {
+				context.Partials["footer"] = "{{.To"
+
+				_, err := packager.Pack(context)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	Describe("escaping the html", func() {
+		BeforeEach(func() {
+			context.Text = ""
+			context.Space = `"><script>alert(1)</script>`
+			context.UserGUID = "user<123>"
+			context.Domain = "javascript:alert(1)//"
+			context.UnsubscribeID = "some id"
+			context.HTMLTemplate = `<a title="{{.Space}}" href="{{.Domain}}/unsubscribe?id={{.UnsubscribeID}}">{{.Space}}</a> {{.UserGUID}}{{.HTML}}`
+		})
+
+		It("escapes the fields for where they appear in the template", func() {
+			parts, err := packager.CompileParts(context)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parts).To(ConsistOf(mail.Part{
+				ContentType: "text/html",
+				Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<a title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" href="#ZgotmplZ/unsubscribe?id=some%20id">&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;</a> user&lt;123&gt;<p>user supplied banana html</p>
+	</body>
+</html>`,
+			}))
+		})
+
+		It("returns an error when the template cannot be escaped", func() {
+			context.HTMLTemplate = `<a title="{{.Space}}`
+
+			_, err := packager.Pack(context)
+			Expect(err).To(HaveOccurred())
+		})
+
+		Context("in compatibility mode", func() {
+			BeforeEach(func() {
+				packager = common.NewPackager(templatesLoader, cloak, "", true, logger)
+			})
+
+			It("delivers the html escaped the way it was before", func() {
+				msg, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg.Body).To(ConsistOf(mail.Part{
+					ContentType: "text/html",
+					Content: `<!DOCTYPE html>
+<head><title>The title</title></head>
+<html>
+	<body class="bananaBody">
+		<a title="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;" href="javascript:alert(1)///unsubscribe?id=some id">&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;</a> user<123><p>user supplied banana html</p>
+	</body>
+</html>`,
+				}))
+			})
+
+			It("logs the messages whose html contextual escaping would change", func() {
+				context.TemplateID = "some-template-id"
+
+				_, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(ContainSubstring(`"message":"notifications.html-escaping-would-change-output"`))
+				Expect(buffer.String()).To(ContainSubstring(`"template_id":"some-template-id"`))
+				Expect(buffer.String()).To(ContainSubstring(`"client_id":"3\u00263"`))
+				Expect(buffer.String()).To(ContainSubstring(`"offset":154`))
+				Expect(buffer.String()).To(ContainSubstring(`"delivered":";/script\u0026gt;\" href=\"javascript:alert(1)/"`))
+				Expect(buffer.String()).To(ContainSubstring(`"contextual":";/script\u0026gt;\" href=\"#ZgotmplZ/unsubscrib"`))
+			})
+
+			It("logs each template version once", func() {
+				context.TemplateID = "some-template-id"
+
+				_, err := packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(strings.Count(buffer.String(), "html-escaping-would-change-output")).To(Equal(1))
+
+				context.TemplateVersion = 2
+
+				_, err = packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(strings.Count(buffer.String(), "html-escaping-would-change-output")).To(Equal(2))
+			})
+
+			It("does not flag html whose character references are only spelled differently", func() {
+				context.Space = "a+b 'quoted'"
+				context.UserGUID = "user-123"
+				context.Domain = "http://example.com"
+				context.UnsubscribeID = "some-id"
+
+				_, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executionErrors).To(BeEmpty())
+			})
+
+			It("reports the change in the preview", func() {
+				_, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executionErrors).To(Equal([]error{common.EscapingChangeError{
+					Offset:     154,
+					Delivered:  `;/script&gt;" href="javascript:alert(1)/`,
+					Contextual: `;/script&gt;" href="#ZgotmplZ/unsubscrib`,
+				}}))
+			})
+
+			It("reports a template that contextual escaping would reject", func() {
+				context.HTMLTemplate = `<a title="{{.Space}}`
+
+				msg, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg.Body).To(HaveLen(1))
+				Expect(executionErrors).To(HaveLen(1))
+				Expect(executionErrors[0].Error()).To(HavePrefix("Contextual escaping would reject the HTML part: "))
+			})
+
+			It("does not flag html whose output would stay the same", func() {
+				context.Space = "development"
+				context.UserGUID = "user-123"
+				context.Domain = "http://example.com"
+				context.UnsubscribeID = "some-id"
+
+				_, executionErrors, err := packager.Preview(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(executionErrors).To(BeEmpty())
+
+				_, err = packager.Pack(context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(BeEmpty())
+			})
+		})
 	})
 })
@@ -6,4 +6,5 @@ const (
 	StatusDelivered     = "delivered"
 	StatusQueued        = "queued"
 	StatusUndeliverable = "undeliverable"
+	StatusCancelled     = "cancelled"
 )
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pivotal-golang/conceal"
+)
+
+const unsubscribeIDDelimiter = "|"
+
+type UnsubscribeID struct {
+	UserGUID string
+	ClientID string
+	KindID   string
+	IssuedAt time.Time
+}
+
+// Veil encrypts the unsubscribe ID. IDs carrying an IssuedAt time are encoded
+// as "user_guid|client_id|kind_id|issued_at" so that they can be expired.
+func (id UnsubscribeID) Veil(cloak conceal.CloakInterface) (string, error) {
+	parts := []string{id.UserGUID, id.ClientID, id.KindID}
+	if !id.IssuedAt.IsZero() {
+		parts = append(parts, strconv.FormatInt(id.IssuedAt.Unix(), 10))
+	}
+
+	cipherText, err := cloak.Veil([]byte(strings.Join(parts, unsubscribeIDDelimiter)))
+	if err != nil {
+		return "", err
+	}
+
+	return string(cipherText), nil
+}
+
+// UnveilUnsubscribeID decrypts an unsubscribe ID. Legacy IDs of the form
+// "user_guid|client_id|kind_id" are returned with a zero IssuedAt time.
+func UnveilUnsubscribeID(cloak conceal.CloakInterface, token string) (UnsubscribeID, error) {
+	plainText, err := cloak.Unveil([]byte(token))
+	if err != nil {
+		return UnsubscribeID{}, err
+	}
+
+	parts := strings.Split(string(plainText), unsubscribeIDDelimiter)
+	if len(parts) != 3 && len(parts) != 4 {
+		return UnsubscribeID{}, errors.New("unsubscribe ID is malformed")
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return UnsubscribeID{}, errors.New("unsubscribe ID is malformed")
+		}
+	}
+
+	id := UnsubscribeID{
+		UserGUID: parts[0],
+		ClientID: parts[1],
+		KindID:   parts[2],
+	}
+
+	if len(parts) == 4 {
+		issuedAt, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil {
+			return UnsubscribeID{}, errors.New("unsubscribe ID has a malformed timestamp")
+		}
+
+		id.IssuedAt = time.Unix(issuedAt, 0).UTC()
+	}
+
+	return id, nil
+}
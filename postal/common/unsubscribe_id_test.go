@@ -0,0 +1,122 @@
+package common_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/pivotal-golang/conceal"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("UnsubscribeID", func() {
+	var cloak conceal.CloakInterface
+
+	BeforeEach(func() {
+		var err error
+		cloak, err = conceal.NewCloak([]byte("the-encryption-key"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Veil", func() {
+		It("includes the issued at time when it is set", func() {
+			fakeCloak := mocks.NewCloak()
+			fakeCloak.VeilCall.Returns.CipherText = []byte("the-cipher-text")
+
+			token, err := common.UnsubscribeID{
+				UserGUID: "some-user",
+				ClientID: "some-client",
+				KindID:   "some-kind",
+				IssuedAt: time.Unix(1433799612, 0),
+			}.Veil(fakeCloak)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token).To(Equal("the-cipher-text"))
+			Expect(fakeCloak.VeilCall.Receives.PlainText).To(Equal([]byte("some-user|some-client|some-kind|1433799612")))
+		})
+
+		It("omits the issued at time when it is not set", func() {
+			fakeCloak := mocks.NewCloak()
+
+			_, err := common.UnsubscribeID{
+				UserGUID: "some-user",
+				ClientID: "some-client",
+				KindID:   "some-kind",
+			}.Veil(fakeCloak)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeCloak.VeilCall.Receives.PlainText).To(Equal([]byte("some-user|some-client|some-kind")))
+		})
+
+		It("returns an error when the cloak fails", func() {
+			fakeCloak := mocks.NewCloak()
+			fakeCloak.VeilCall.Returns.Error = errors.New("veil failed")
+
+			_, err := common.UnsubscribeID{}.Veil(fakeCloak)
+			Expect(err).To(MatchError(errors.New("veil failed")))
+		})
+	})
+
+	Describe("UnveilUnsubscribeID", func() {
+		It("round trips a veiled unsubscribe ID", func() {
+			token, err := common.UnsubscribeID{
+				UserGUID: "some-user",
+				ClientID: "some-client",
+				KindID:   "some-kind",
+				IssuedAt: time.Unix(1433799612, 0),
+			}.Veil(cloak)
+			Expect(err).NotTo(HaveOccurred())
+
+			id, err := common.UnveilUnsubscribeID(cloak, token)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id).To(Equal(common.UnsubscribeID{
+				UserGUID: "some-user",
+				ClientID: "some-client",
+				KindID:   "some-kind",
+				IssuedAt: time.Unix(1433799612, 0).UTC(),
+			}))
+		})
+
+		It("accepts legacy unsubscribe IDs without an issued at time", func() {
+			token, err := cloak.Veil([]byte("some-user|some-client|some-kind"))
+			Expect(err).NotTo(HaveOccurred())
+
+			id, err := common.UnveilUnsubscribeID(cloak, string(token))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(id.UserGUID).To(Equal("some-user"))
+			Expect(id.IssuedAt.IsZero()).To(BeTrue())
+		})
+
+		Context("when the unsubscribe ID is invalid", func() {
+			It("returns an error when the token cannot be unveiled", func() {
+				_, err := common.UnveilUnsubscribeID(cloak, "%%%not-base64%%%")
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("returns an error when the token has the wrong number of parts", func() {
+				token, err := cloak.Veil([]byte("some-user|some-client"))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = common.UnveilUnsubscribeID(cloak, string(token))
+				Expect(err).To(MatchError(errors.New("unsubscribe ID is malformed")))
+			})
+
+			It("returns an error when a part is empty", func() {
+				token, err := cloak.Veil([]byte("|some-client|some-kind"))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = common.UnveilUnsubscribeID(cloak, string(token))
+				Expect(err).To(MatchError(errors.New("unsubscribe ID is malformed")))
+			})
+
+			It("returns an error when the timestamp is not a number", func() {
+				token, err := cloak.Veil([]byte("some-user|some-client|some-kind|yesterday"))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = common.UnveilUnsubscribeID(cloak, string(token))
+				Expect(err).To(MatchError(errors.New("unsubscribe ID has a malformed timestamp")))
+			})
+		})
+	})
+})
@@ -28,7 +28,7 @@ type messageStatusUpdater interface {
 }
 
 type deliveryFailureHandler interface {
-	Handle(job common.Retryable, logger lager.Logger)
+	Handle(job common.Retryable, err error, policy common.RetryPolicy, logger lager.Logger) bool
 }
 
 type DeliveryWorkerConfig struct {
@@ -38,6 +38,7 @@ type DeliveryWorkerConfig struct {
 	Queue                  gobble.QueueInterface
 	DBTrace                bool
 	Database               db.DatabaseInterface
+	FanOutJobProcessor     DeliveryJobProcessor
 	CampaignJobProcessor   campaignJobProcessor
 	DeliveryFailureHandler deliveryFailureHandler
 	MessageStatusUpdater   messageStatusUpdater
@@ -49,6 +50,7 @@ type DeliveryWorker struct {
 	uaaHost                string
 	DeliveryJobProcessor   DeliveryJobProcessor
 	V2DeliveryJobProcessor v2DeliveryJobProcessor
+	FanOutJobProcessor     DeliveryJobProcessor
 	logger                 lager.Logger
 	database               db.DatabaseInterface
 	campaignJobProcessor   campaignJobProcessor
@@ -59,6 +61,7 @@ type DeliveryWorker struct {
 func NewDeliveryWorker(v1DeliveryJobProcessor DeliveryJobProcessor, config DeliveryWorkerConfig) DeliveryWorker {
 	worker := DeliveryWorker{
 		DeliveryJobProcessor:   v1DeliveryJobProcessor,
+		FanOutJobProcessor:     config.FanOutJobProcessor,
 		uaaHost:                config.UAAHost,
 		logger:                 config.Logger,
 		database:               config.Database,
@@ -82,9 +85,14 @@ func (worker DeliveryWorker) Deliver(job *gobble.Job) {
 	if err != nil {
 		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
 
-		worker.deliveryFailureHandler.Handle(job, worker.logger)
+		worker.deliveryFailureHandler.Handle(job, err, common.RetryPolicy{}, worker.logger)
 		return
 	}
 
-	worker.DeliveryJobProcessor.Process(job, worker.logger)
+	switch typedJob.JobType {
+	case services.FanOutJobType:
+		worker.FanOutJobProcessor.Process(job, worker.logger)
+	default:
+		worker.DeliveryJobProcessor.Process(job, worker.logger)
+	}
 }
@@ -12,23 +12,34 @@ type messagesDeleter interface {
 	DeleteBefore(models.ConnectionInterface, time.Time) (int, error)
 }
 
+// MessageGC periodically deletes the records of a repo that are older than
+// their lifetime. Besides messages it collects any other records that expire,
+// such as idempotency keys; the name tells the collectors apart in the logs.
 type MessageGC struct {
+	name            string
 	messages        messagesDeleter
 	db              db.DatabaseInterface
 	lifetime        time.Duration
 	logger          *log.Logger
 	timer           <-chan time.Time
 	pollingInterval time.Duration
+	halt            chan struct{}
 }
 
 func NewMessageGC(lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
+	return NewNamedGC("MessageGC", lifetime, db, messages, pollingInterval, logger)
+}
+
+func NewNamedGC(name string, lifetime time.Duration, db db.DatabaseInterface, messages messagesDeleter, pollingInterval time.Duration, logger *log.Logger) MessageGC {
 	return MessageGC{
+		name:            name,
 		messages:        messages,
 		db:              db,
 		lifetime:        lifetime,
 		logger:          logger,
 		pollingInterval: pollingInterval,
 		timer:           time.After(0),
+		halt:            make(chan struct{}),
 	}
 }
 
@@ -36,16 +47,26 @@ func (gc MessageGC) Collect() {
 	threshold := time.Now().Add(-1 * gc.lifetime)
 	_, err := gc.messages.DeleteBefore(gc.db.Connection(), threshold)
 	if err != nil {
-		gc.logger.Printf("MessageGC.Collect() failed: " + err.Error())
+		gc.logger.Printf("%s.Collect() failed: %s", gc.name, err)
 	}
 }
 
 func (gc MessageGC) Run() {
 	go func() {
 		for {
-			<-gc.timer
-			gc.Collect()
-			gc.timer = time.After(gc.pollingInterval)
+			select {
+			case <-gc.timer:
+				gc.Collect()
+				gc.timer = time.After(gc.pollingInterval)
+			case <-gc.halt:
+				return
+			}
 		}
 	}()
 }
+
+// Halt stops the collector from running again. A collection that is under
+// way is allowed to finish.
+func (gc MessageGC) Halt() {
+	close(gc.halt)
+}
@@ -56,6 +56,22 @@ var _ = Describe("MessageGC", func() {
 		})
 	})
 
+	Describe("Halt", func() {
+		It("stops the collector from running again", func() {
+			messageGC.Run()
+
+			Eventually(func() int {
+				return repo.DeleteBeforeCall.CallCount
+			}).Should(Equal(1))
+
+			messageGC.Halt()
+
+			Consistently(func() int {
+				return repo.DeleteBeforeCall.CallCount
+			}, 2*pollingInterval).Should(Equal(1))
+		})
+	})
+
 	Describe("Collect", func() {
 		It("Deletes message statuses older than the specified time", func() {
 			messageGC.Collect()
@@ -70,7 +86,16 @@ var _ = Describe("MessageGC", func() {
 
 				messageGC.Collect()
 
-				Expect(loggerBuffer.String()).To(ContainSubstring("messages table is totally corrupt"))
+				Expect(loggerBuffer.String()).To(ContainSubstring("MessageGC.Collect() failed: messages table is totally corrupt"))
+			})
+
+			It("logs the error with the name of the collector", func() {
+				repo.DeleteBeforeCall.Returns.Error = errors.New("idempotency keys table is corrupt")
+
+				keyGC := postal.NewNamedGC("IdempotencyKeyGC", lifetime, database, repo, pollingInterval, log.New(loggerBuffer, "", 0))
+				keyGC.Collect()
+
+				Expect(loggerBuffer.String()).To(ContainSubstring("IdempotencyKeyGC.Collect() failed: idempotency keys table is corrupt"))
 			})
 		})
 
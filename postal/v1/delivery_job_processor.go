@@ -1,7 +1,9 @@
 package v1
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/db"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
@@ -30,8 +32,12 @@ type messageStatusUpdater interface {
 	Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger)
 }
 
+type messageEventRecorder interface {
+	Record(conn db.ConnectionInterface, event models.MessageEvent, logger lager.Logger)
+}
+
 type deliveryFailureHandler interface {
-	Handle(job common.Retryable, logger lager.Logger)
+	Handle(job common.Retryable, err error, policy common.RetryPolicy, logger lager.Logger) bool
 }
 
 type kindsFinder interface {
@@ -50,6 +56,20 @@ type globalUnsubscribesGetter interface {
 	Get(connection models.ConnectionInterface, userGUID string) (bool, error)
 }
 
+type suppressionsChecker interface {
+	IsSuppressed(connection models.ConnectionInterface, email string) (bool, error)
+}
+
+type messagesRepository interface {
+	FindByID(conn models.ConnectionInterface, messageID string) (models.Message, error)
+	SetTemplateVersion(conn models.ConnectionInterface, messageID, templateID string, version int) error
+	SetDeliveredTo(conn models.ConnectionInterface, messageID, email string) error
+}
+
+type sendFinder interface {
+	FindByID(conn models.ConnectionInterface, sendID string) (models.Send, error)
+}
+
 type DeliveryJobProcessorConfig struct {
 	DBTrace bool
 	UAAHost string
@@ -66,7 +86,11 @@ type DeliveryJobProcessorConfig struct {
 	ReceiptsRepo           receiptsCreator
 	UnsubscribesRepo       unsubscribesGetter
 	GlobalUnsubscribesRepo globalUnsubscribesGetter
+	SuppressionsRepo       suppressionsChecker
+	MessagesRepo           messagesRepository
+	SendsRepo              sendFinder
 	MessageStatusUpdater   messageStatusUpdater
+	MessageEventRecorder   messageEventRecorder
 	DeliveryFailureHandler deliveryFailureHandler
 }
 
@@ -86,7 +110,11 @@ type DeliveryJobProcessor struct {
 	receiptsRepo           receiptsCreator
 	unsubscribesRepo       unsubscribesGetter
 	globalUnsubscribesRepo globalUnsubscribesGetter
+	suppressionsRepo       suppressionsChecker
+	messagesRepo           messagesRepository
+	sendsRepo              sendFinder
 	messageStatusUpdater   messageStatusUpdater
+	messageEventRecorder   messageEventRecorder
 	deliveryFailureHandler deliveryFailureHandler
 }
 
@@ -107,7 +135,11 @@ func NewDeliveryJobProcessor(config DeliveryJobProcessorConfig) DeliveryJobProce
 		receiptsRepo:           config.ReceiptsRepo,
 		unsubscribesRepo:       config.UnsubscribesRepo,
 		globalUnsubscribesRepo: config.GlobalUnsubscribesRepo,
+		suppressionsRepo:       config.SuppressionsRepo,
+		messagesRepo:           config.MessagesRepo,
+		sendsRepo:              config.SendsRepo,
 		messageStatusUpdater:   config.MessageStatusUpdater,
+		messageEventRecorder:   config.MessageEventRecorder,
 		deliveryFailureHandler: config.DeliveryFailureHandler,
 	}
 }
@@ -118,7 +150,7 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	if err != nil {
 		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
 
-		p.deliveryFailureHandler.Handle(job, logger)
+		p.deliveryFailureHandler.Handle(job, err, common.RetryPolicy{}, logger)
 		return nil
 	}
 
@@ -131,9 +163,14 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 		p.database.TraceOn("", gorpCompatibleLogger{logger})
 	}
 
+	attempt := job.RetryCount + 1
+	kind := p.findKind(p.database.Connection(), delivery.Options.KindID, delivery.ClientID)
+	policy := retryPolicy(kind, delivery)
+
 	err = p.receiptsRepo.CreateReceipts(p.database.Connection(), []string{delivery.UserGUID}, delivery.ClientID, delivery.Options.KindID)
 	if err != nil {
-		p.deliveryFailureHandler.Handle(job, logger)
+		p.recordFailure(delivery, attempt, err, logger)
+		p.retryOrFail(job, delivery, err, policy, logger)
 		return nil
 	}
 
@@ -142,19 +179,29 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 
 		token, err = p.tokenLoader.Load(p.uaaHost)
 		if err != nil {
-			p.deliveryFailureHandler.Handle(job, logger)
+			p.recordFailure(delivery, attempt, err, logger)
+			p.retryOrFail(job, delivery, err, policy, logger)
 			return nil
 		}
 
 		users, err := p.userLoader.Load([]string{delivery.UserGUID}, token)
-		if err != nil || len(users) < 1 {
-			p.deliveryFailureHandler.Handle(job, logger)
+		if err == nil && len(users) < 1 {
+			err = fmt.Errorf("user %q could not be found", delivery.UserGUID)
+		}
+
+		if err != nil {
+			p.recordFailure(delivery, attempt, err, logger)
+			p.retryOrFail(job, delivery, err, policy, logger)
 			return nil
 		}
 
-		emails := users[delivery.UserGUID].Emails
-		if len(emails) > 0 {
-			delivery.Email = emails[0]
+		user := users[delivery.UserGUID]
+		if len(user.Emails) > 0 {
+			delivery.Email = user.Emails[0]
+		}
+
+		if delivery.Locale == "" {
+			delivery.Locale = user.Locale
 		}
 	}
 
@@ -162,14 +209,26 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 		"recipient": delivery.Email,
 	})
 
-	if p.shouldDeliver(delivery, logger) {
-		status := p.process(delivery, logger)
+	deliver, err := p.shouldDeliver(delivery, kind, attempt, logger)
+	if err != nil {
+		p.recordFailure(delivery, attempt, err, logger)
+		p.retryOrFail(job, delivery, err, policy, logger)
+		return nil
+	}
 
-		if status != common.StatusDelivered {
-			p.deliveryFailureHandler.Handle(job, logger)
-			return nil
-		} else {
+	if deliver {
+		status, err := p.process(delivery, attempt, logger)
+
+		switch status {
+		case common.StatusDelivered:
 			metrics.GetOrRegisterCounter("notifications.worker.delivered", nil).Inc(1)
+		case common.StatusUndeliverable:
+			metrics.GetOrRegisterCounter("notifications.worker.rejected", nil).Inc(1)
+		case common.StatusCancelled:
+			metrics.GetOrRegisterCounter("notifications.worker.cancelled", nil).Inc(1)
+		default:
+			p.retryOrFail(job, delivery, err, policy, logger)
+			return nil
 		}
 	} else {
 		metrics.GetOrRegisterCounter("notifications.worker.unsubscribed", nil).Inc(1)
@@ -178,85 +237,259 @@ func (p DeliveryJobProcessor) Process(job *gobble.Job, logger lager.Logger) erro
 	return nil
 }
 
-func (p DeliveryJobProcessor) process(delivery common.Delivery, logger lager.Logger) string {
+// retryOrFail hands the failed delivery to the failure handler and marks the
+// message as waiting to be retried, or as failed when the handler gave up on
+// it.
+func (p DeliveryJobProcessor) retryOrFail(job *gobble.Job, delivery common.Delivery, err error, policy common.RetryPolicy, logger lager.Logger) {
+	status := common.StatusFailed
+	if p.deliveryFailureHandler.Handle(job, err, policy, logger) {
+		status = common.StatusRetry
+	}
+
+	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, status, "", logger)
+}
+
+// process delivers the message and records its status, except when it
+// failed, which is left to retryOrFail.
+func (p DeliveryJobProcessor) process(delivery common.Delivery, attempt int, logger lager.Logger) (string, error) {
 	context, err := p.packager.PrepareContext(delivery, p.sender, p.domain)
 	if err != nil {
 		panic(err)
 	}
 
+	p.recordEvent(delivery, models.MessageEvent{
+		Type:        models.MessageEventAttempted,
+		Attempt:     attempt,
+		Description: fmt.Sprintf("delivering to %s", delivery.Email),
+	}, logger)
+
 	message, err := p.packager.Pack(context)
 	if err != nil {
 		logger.Info("template-pack-failed")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusFailed, "", logger)
-		return common.StatusFailed
+		p.recordFailure(delivery, attempt, err, logger)
+		return common.StatusFailed, err
 	}
 
-	status := p.sendMail(delivery.MessageID, message, logger)
-	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, status, "", logger)
+	err = p.messagesRepo.SetTemplateVersion(p.database.Connection(), delivery.MessageID, context.TemplateID, context.TemplateVersion)
+	if err != nil {
+		logger.Error("failed-template-version-update", err)
+	}
+
+	cancelled, err := p.cancelled(delivery, attempt, logger)
+	if err != nil {
+		logger.Error("failed-cancellation-check", err)
+		p.recordFailure(delivery, attempt, err, logger)
+		return common.StatusFailed, err
+	}
+
+	if cancelled {
+		return common.StatusCancelled, nil
+	}
+
+	err = p.messagesRepo.SetDeliveredTo(p.database.Connection(), delivery.MessageID, delivery.Email)
+	if err != nil {
+		logger.Error("failed-delivered-to-update", err)
+	}
+
+	status, err := p.sendMail(delivery.MessageID, message, logger)
+	if status != common.StatusFailed {
+		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, status, "", logger)
+	}
 
-	return status
+	if smtpError, ok := err.(mail.SMTPError); ok && status == common.StatusUndeliverable {
+		p.recordEvent(delivery, models.MessageEvent{
+			Type:        models.MessageEventUndeliverable,
+			Attempt:     attempt,
+			SMTPCode:    smtpError.Code,
+			Description: smtpError.Reason(),
+		}, logger)
+	} else if err != nil {
+		p.recordFailure(delivery, attempt, err, logger)
+	} else {
+		p.recordEvent(delivery, models.MessageEvent{
+			Type:    models.MessageEventDelivered,
+			Attempt: attempt,
+		}, logger)
+	}
+
+	return status, err
 }
 
-func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, logger lager.Logger) bool {
+// shouldDeliver returns an error when the suppression list cannot be
+// checked, so that the job is retried rather than given up on.
+func (p DeliveryJobProcessor) shouldDeliver(delivery common.Delivery, kind models.Kind, attempt int, logger lager.Logger) (bool, error) {
 	conn := p.database.Connection()
-	if p.isCritical(conn, delivery.Options.KindID, delivery.ClientID) {
-		return true
+
+	if delivery.Email != "" {
+		suppressed, err := p.suppressionsRepo.IsSuppressed(conn, delivery.Email)
+		if err != nil {
+			return false, err
+		}
+
+		if suppressed {
+			logger.Info("email-address-suppressed")
+			p.markUndeliverable(delivery, attempt, fmt.Sprintf("email address %q is suppressed after a bounce or complaint", delivery.Email), logger)
+			return false, nil
+		}
+	}
+
+	if kind.Critical {
+		return true, nil
 	}
 
 	globallyUnsubscribed, err := p.globalUnsubscribesRepo.Get(conn, delivery.UserGUID)
 	if err != nil || globallyUnsubscribed {
 		logger.Info("user-unsubscribed")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
-		return false
+		p.markUndeliverable(delivery, attempt, "user is unsubscribed from all notifications", logger)
+		return false, nil
 	}
 
 	isUnsubscribed, err := p.unsubscribesRepo.Get(conn, delivery.UserGUID, delivery.ClientID, delivery.Options.KindID)
 	if err != nil || isUnsubscribed {
 		logger.Info("user-unsubscribed")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
-		return false
+		p.markUndeliverable(delivery, attempt, "user is unsubscribed from this kind of notification", logger)
+		return false, nil
 	}
 
 	if delivery.Email == "" {
 		logger.Info("no-email-address-for-user")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
-		return false
+		p.markUndeliverable(delivery, attempt, "user has no email address", logger)
+		return false, nil
 	}
 
 	if !strings.Contains(delivery.Email, "@") {
 		logger.Info("malformatted-email-address")
-		p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
-		return false
+		p.markUndeliverable(delivery, attempt, fmt.Sprintf("email address %q is malformed", delivery.Email), logger)
+		return false, nil
 	}
 
-	return true
+	return true, nil
 }
 
-func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) string {
+// cancelled checks, as late as possible before the message is handed to the
+// SMTP server, whether the message or the send it belongs to was cancelled
+// while the job was reserved. A message of a cancelled send that was enqueued
+// after the send was cancelled is marked as cancelled here. An error is
+// returned when the check cannot be made, so that the job is retried rather
+// than delivered regardless.
+func (p DeliveryJobProcessor) cancelled(delivery common.Delivery, attempt int, logger lager.Logger) (bool, error) {
+	conn := p.database.Connection()
+
+	message, err := p.messagesRepo.FindByID(conn, delivery.MessageID)
+	if err != nil {
+		return false, err
+	}
+
+	if message.Status == common.StatusCancelled {
+		logger.Info("message-cancelled")
+		return true, nil
+	}
+
+	if message.SendID == "" {
+		return false, nil
+	}
+
+	send, err := p.sendsRepo.FindByID(conn, message.SendID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if !send.Cancelled {
+		return false, nil
+	}
+
+	logger.Info("send-cancelled", lager.Data{"send_id": send.ID})
+	p.messageStatusUpdater.Update(conn, delivery.MessageID, common.StatusCancelled, "", logger)
+	p.recordEvent(delivery, models.MessageEvent{
+		Type:        models.MessageEventCancelled,
+		Attempt:     attempt,
+		Description: "send was cancelled",
+	}, logger)
+
+	return true, nil
+}
+
+func (p DeliveryJobProcessor) markUndeliverable(delivery common.Delivery, attempt int, reason string, logger lager.Logger) {
+	p.messageStatusUpdater.Update(p.database.Connection(), delivery.MessageID, common.StatusUndeliverable, "", logger)
+	p.recordEvent(delivery, models.MessageEvent{
+		Type:        models.MessageEventUndeliverable,
+		Attempt:     attempt,
+		Description: reason,
+	}, logger)
+}
+
+func (p DeliveryJobProcessor) recordFailure(delivery common.Delivery, attempt int, err error, logger lager.Logger) {
+	event := models.MessageEvent{
+		Type:        models.MessageEventFailed,
+		Attempt:     attempt,
+		Description: err.Error(),
+	}
+
+	if smtpError, ok := err.(mail.SMTPError); ok {
+		event.SMTPCode = smtpError.Code
+		event.Description = smtpError.Reason()
+	}
+
+	p.recordEvent(delivery, event, logger)
+}
+
+func (p DeliveryJobProcessor) recordEvent(delivery common.Delivery, event models.MessageEvent, logger lager.Logger) {
+	event.MessageID = delivery.MessageID
+	p.messageEventRecorder.Record(p.database.Connection(), event, logger)
+}
+
+func (p DeliveryJobProcessor) sendMail(messageID string, message mail.Message, logger lager.Logger) (string, error) {
 	err := p.mailClient.Connect(logger)
 	if err != nil {
 		logger.Error("smtp-connection-error", err)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
 	logger.Info("delivery-start")
 
 	err = p.mailClient.Send(message, logger)
 	if err != nil {
+		if smtpError, ok := err.(mail.SMTPError); ok && smtpError.Permanent() {
+			logger.Error("delivery-rejected-smtp-error", err)
+			return common.StatusUndeliverable, err
+		}
+
 		logger.Error("delivery-failed-smtp-error", err)
-		return common.StatusFailed
+		return common.StatusFailed, err
 	}
 
 	logger.Info("message-sent")
 
-	return common.StatusDelivered
+	return common.StatusDelivered, nil
 }
 
-func (p DeliveryJobProcessor) isCritical(conn db.ConnectionInterface, kindID, clientID string) bool {
+func (p DeliveryJobProcessor) findKind(conn db.ConnectionInterface, kindID, clientID string) models.Kind {
 	kind, err := p.kindsRepo.Find(conn, kindID, clientID)
-	if _, ok := err.(models.NotFoundError); ok {
-		return false
+	if err != nil {
+		return models.Kind{}
+	}
+
+	return kind
+}
+
+// retryPolicy converts the retry policy registered for the kind into the one
+// used by the delivery failure handler. The give up deadline counts from when
+// the notification was first received.
+func retryPolicy(kind models.Kind, delivery common.Delivery) common.RetryPolicy {
+	policy := common.RetryPolicy{
+		MaxAttempts: kind.MaxAttempts,
+		BaseDelay:   time.Duration(kind.BaseDelay) * time.Second,
+		MaxDelay:    time.Duration(kind.MaxDelay) * time.Second,
+		Jitter:      kind.Jitter,
+	}
+
+	if kind.GiveUpAfter > 0 && !delivery.RequestReceived.IsZero() {
+		policy.Deadline = delivery.RequestReceived.Add(time.Duration(kind.GiveUpAfter) * time.Second)
 	}
 
-	return kind.Critical
+	return policy
 }
@@ -30,6 +30,9 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		delivery               common.Delivery
 		unsubscribesRepo       *mocks.UnsubscribesRepo
 		globalUnsubscribesRepo *mocks.GlobalUnsubscribesRepo
+		suppressionsRepo       *mocks.SuppressionsRepo
+		messagesRepo           *mocks.MessagesRepo
+		sendsRepo              *mocks.SendsRepo
 		kindsRepo              *mocks.KindsRepo
 		database               *mocks.Database
 		conn                   *mocks.Connection
@@ -41,6 +44,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		tokenLoader            *mocks.TokenLoader
 		messageID              string
 		messageStatusUpdater   *mocks.MessageStatusUpdater
+		messageEventRecorder   *mocks.MessageEventRecorder
 		deliveryFailureHandler *mocks.DeliveryFailureHandler
 	)
 
@@ -54,6 +58,9 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		mailClient = mocks.NewMailClient()
 		unsubscribesRepo = mocks.NewUnsubscribesRepo()
 		globalUnsubscribesRepo = mocks.NewGlobalUnsubscribesRepo()
+		suppressionsRepo = mocks.NewSuppressionsRepo()
+		messagesRepo = mocks.NewMessagesRepo()
+		sendsRepo = mocks.NewSendsRepo()
 
 		kindsRepo = mocks.NewKindsRepo()
 		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -86,6 +93,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 		}
 		receiptsRepo = mocks.NewReceiptsRepo()
 		messageStatusUpdater = mocks.NewMessageStatusUpdater()
+		messageEventRecorder = mocks.NewMessageEventRecorder()
 		deliveryFailureHandler = mocks.NewDeliveryFailureHandler()
 
 		cloak, err := conceal.NewCloak(encryptionKey)
@@ -97,7 +105,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Sender:  "from@example.com",
 			Domain:  "example.com",
 
-			Packager:    common.NewPackager(templateLoader, cloak),
+			Packager:    common.NewPackager(templateLoader, cloak, "", false, logger),
 			MailClient:  mailClient,
 			Database:    database,
 			TokenLoader: tokenLoader,
@@ -107,7 +115,11 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			ReceiptsRepo:           receiptsRepo,
 			UnsubscribesRepo:       unsubscribesRepo,
 			GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+			SuppressionsRepo:       suppressionsRepo,
+			MessagesRepo:           messagesRepo,
+			SendsRepo:              sendsRepo,
 			MessageStatusUpdater:   messageStatusUpdater,
+			MessageEventRecorder:   messageEventRecorder,
 			DeliveryFailureHandler: deliveryFailureHandler,
 		})
 
@@ -162,6 +174,38 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(templateLoader.LoadTemplatesCall.Receives.TemplateID).To(Equal("some-template-id"))
 		})
 
+		It("loads the template for the locale of the user", func() {
+			userLoader.LoadCall.Returns.Users = map[string]uaa.User{
+				"user-123": {Emails: []string{fakeUserEmail}, Locale: "de-DE"},
+			}
+
+			processor.Process(job, logger)
+
+			Expect(templateLoader.LoadTemplatesCall.Receives.Locale).To(Equal("de-DE"))
+		})
+
+		It("records the template version that rendered the message", func() {
+			templateLoader.LoadTemplatesCall.Returns.Templates.ID = "some-template-id"
+			templateLoader.LoadTemplatesCall.Returns.Templates.Version = 3
+
+			processor.Process(job, logger)
+
+			Expect(messagesRepo.SetTemplateVersionCall.CallCount).To(Equal(1))
+			Expect(messagesRepo.SetTemplateVersionCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.SetTemplateVersionCall.Receives.MessageID).To(Equal(messageID))
+			Expect(messagesRepo.SetTemplateVersionCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(messagesRepo.SetTemplateVersionCall.Receives.TemplateVersion).To(Equal(3))
+		})
+
+		It("records the address the message is delivered to", func() {
+			processor.Process(job, logger)
+
+			Expect(messagesRepo.SetDeliveredToCall.CallCount).To(Equal(1))
+			Expect(messagesRepo.SetDeliveredToCall.Receives.Connection).To(Equal(conn))
+			Expect(messagesRepo.SetDeliveredToCall.Receives.MessageID).To(Equal(messageID))
+			Expect(messagesRepo.SetDeliveredToCall.Receives.Email).To(Equal("user-123@example.com"))
+		})
+
 		It("logs successful delivery", func() {
 			processor.Process(job, logger)
 
@@ -193,7 +237,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Sender:  "from@example.com",
 				Domain:  "example.com",
 
-				Packager:    common.NewPackager(templateLoader, cloak),
+				Packager:    common.NewPackager(templateLoader, cloak, "", false, logger),
 				MailClient:  mailClient,
 				Database:    database,
 				TokenLoader: tokenLoader,
@@ -203,7 +247,11 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				ReceiptsRepo:           receiptsRepo,
 				UnsubscribesRepo:       unsubscribesRepo,
 				GlobalUnsubscribesRepo: globalUnsubscribesRepo,
+				SuppressionsRepo:       suppressionsRepo,
+				MessagesRepo:           messagesRepo,
+				SendsRepo:              sendsRepo,
 				MessageStatusUpdater:   messageStatusUpdater,
+				MessageEventRecorder:   messageEventRecorder,
 				DeliveryFailureHandler: deliveryFailureHandler,
 			})
 			processor.Process(job, logger)
@@ -227,6 +275,26 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 		})
 
+		It("records the attempt and the delivery in the message history", func() {
+			job.RetryCount = 2
+			processor.Process(job, logger)
+
+			Expect(messageEventRecorder.RecordCall.Receives.Connection).To(Equal(conn))
+			Expect(messageEventRecorder.RecordCall.Receives.Events).To(Equal([]models.MessageEvent{
+				{
+					MessageID:   messageID,
+					Type:        models.MessageEventAttempted,
+					Attempt:     3,
+					Description: "delivering to user-123@example.com",
+				},
+				{
+					MessageID: messageID,
+					Type:      models.MessageEventDelivered,
+					Attempt:   3,
+				},
+			}))
+		})
+
 		It("creates a reciept for the delivery", func() {
 			processor.Process(job, logger)
 
@@ -242,6 +310,7 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				processor.Process(job, logger)
 
 				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("something happened")))
 				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
 		})
@@ -254,8 +323,50 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				processor.Process(job, logger)
 
 				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("failed to load a zoned UAA token")))
 				Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
+
+			It("marks the message as waiting to be retried", func() {
+				deliveryFailureHandler.HandleCall.Returns.Retried = true
+				tokenLoader.LoadCall.Returns.Error = errors.New("failed to load a zoned UAA token")
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusRetry))
+			})
+
+			It("marks the message as failed once it is given up on", func() {
+				tokenLoader.LoadCall.Returns.Error = errors.New("failed to load a zoned UAA token")
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+			})
+
+			It("records the failure in the message history", func() {
+				tokenLoader.LoadCall.Returns.Error = errors.New("failed to load a zoned UAA token")
+				processor.Process(job, logger)
+
+				Expect(messageEventRecorder.RecordCall.Receives.Events).To(Equal([]models.MessageEvent{
+					{
+						MessageID:   messageID,
+						Type:        models.MessageEventFailed,
+						Attempt:     1,
+						Description: "failed to load a zoned UAA token",
+					},
+				}))
+			})
+		})
+
+		Context("when the user cannot be found", func() {
+			It("retries the job", func() {
+				job := gobble.NewJob(delivery)
+
+				userLoader.LoadCall.Returns.Users = map[string]uaa.User{}
+				processor.Process(job, logger)
+
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(`user "user-123" could not be found`))
+			})
 		})
 
 		It("ensures message delivery", func() {
@@ -307,7 +418,38 @@ var _ = Describe("DeliveryJobProcessor", func() {
 					processor.Process(job, logger)
 
 					Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+					Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("Error sending message!!!")))
 					Expect(deliveryFailureHandler.HandleCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
+					Expect(deliveryFailureHandler.HandleCall.Receives.Policy).To(Equal(common.RetryPolicy{}))
+				})
+
+				It("marks the job for retry using the retry policy of the kind", func() {
+					requestReceived := time.Now().Truncate(time.Second)
+					delivery.RequestReceived = requestReceived
+					job = gobble.NewJob(delivery)
+
+					kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+						{
+							ID:       "some-kind",
+							ClientID: "some-client",
+							RetryPolicy: models.RetryPolicy{
+								MaxAttempts: 20,
+								BaseDelay:   10,
+								MaxDelay:    600,
+								Jitter:      0.25,
+								GiveUpAfter: 3600,
+							},
+						},
+					}
+
+					processor.Process(job, logger)
+
+					policy := deliveryFailureHandler.HandleCall.Receives.Policy
+					Expect(policy.MaxAttempts).To(Equal(20))
+					Expect(policy.BaseDelay).To(Equal(10 * time.Second))
+					Expect(policy.MaxDelay).To(Equal(10 * time.Minute))
+					Expect(policy.Jitter).To(Equal(0.25))
+					Expect(policy.Deadline).To(BeTemporally("==", requestReceived.Add(1*time.Hour)))
 				})
 
 				It("logs an SMTP send error", func() {
@@ -339,6 +481,84 @@ var _ = Describe("DeliveryJobProcessor", func() {
 					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
 					Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 				})
+
+				It("updates the message status as retry when the delivery will be retried", func() {
+					deliveryFailureHandler.HandleCall.Returns.Retried = true
+
+					processor.Process(job, logger)
+
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusRetry))
+				})
+
+				It("records the SMTP response in the message history", func() {
+					mailClient.SendCall.Returns.Error = mail.SMTPError{Code: 451, EnhancedCode: "4.3.0", Message: "try again later"}
+					processor.Process(job, logger)
+
+					events := messageEventRecorder.RecordCall.Receives.Events
+					Expect(events).To(HaveLen(2))
+					Expect(events[1]).To(Equal(models.MessageEvent{
+						MessageID:   messageID,
+						Type:        models.MessageEventFailed,
+						Attempt:     1,
+						SMTPCode:    451,
+						Description: "4.3.0 try again later",
+					}))
+				})
+			})
+
+			Context("because the SMTP server permanently rejected the message", func() {
+				BeforeEach(func() {
+					mailClient.SendCall.Returns.Error = mail.SMTPError{Code: 550, EnhancedCode: "5.1.1", Message: "mailbox does not exist"}
+				})
+
+				It("does not retry the job", func() {
+					processor.Process(job, logger)
+
+					Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+				})
+
+				It("updates the message status as undeliverable", func() {
+					processor.Process(job, logger)
+
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+					Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+				})
+
+				It("records the rejection in the message history", func() {
+					processor.Process(job, logger)
+
+					events := messageEventRecorder.RecordCall.Receives.Events
+					Expect(events).To(HaveLen(2))
+					Expect(events[1]).To(Equal(models.MessageEvent{
+						MessageID:   messageID,
+						Type:        models.MessageEventUndeliverable,
+						Attempt:     1,
+						SMTPCode:    550,
+						Description: "5.1.1 mailbox does not exist",
+					}))
+				})
+
+				It("logs the rejection", func() {
+					processor.Process(job, logger)
+
+					lines, err := parseLogLines(buffer.Bytes())
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(lines).To(ContainElement(logLine{
+						Source:   "notifications",
+						Message:  "notifications.worker.delivery-rejected-smtp-error",
+						LogLevel: int(lager.ERROR),
+						Data: map[string]interface{}{
+							"session":         "1",
+							"error":           "550 5.1.1 mailbox does not exist",
+							"recipient":       "user-123@example.com",
+							"worker_id":       float64(1234),
+							"message_id":      "randomly-generated-guid",
+							"vcap_request_id": "some-request-id",
+						},
+					}))
+				})
 			})
 
 			Context("and the error is a connect error", func() {
@@ -412,6 +632,17 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Expect(mailClient.SendCall.CallCount).To(Equal(0))
 			})
 
+			It("records why the message is undeliverable in the message history", func() {
+				Expect(messageEventRecorder.RecordCall.Receives.Events).To(Equal([]models.MessageEvent{
+					{
+						MessageID:   messageID,
+						Type:        models.MessageEventUndeliverable,
+						Attempt:     1,
+						Description: "user is unsubscribed from all notifications",
+					},
+				}))
+			})
+
 			It("updates the message status as undeliverable", func() {
 				Expect(messageStatusUpdater.UpdateCall.Receives.Connection).To(Equal(conn))
 				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
@@ -420,6 +651,64 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the email address of the recipient is suppressed", func() {
+			BeforeEach(func() {
+				suppressionsRepo.IsSuppressedCall.Returns.Suppressed = true
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+					{
+						ID:       "some-kind",
+						ClientID: "some-client",
+						Critical: true,
+					},
+				}
+
+				processor.Process(job, logger)
+			})
+
+			It("checks the email address against the suppression list", func() {
+				Expect(suppressionsRepo.IsSuppressedCall.Receives.Connection).To(Equal(conn))
+				Expect(suppressionsRepo.IsSuppressedCall.Receives.Email).To(Equal("user-123@example.com"))
+			})
+
+			It("does not send the notification, even when it is critical", func() {
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("records why the message is undeliverable in the message history", func() {
+				Expect(messageEventRecorder.RecordCall.Receives.Events).To(Equal([]models.MessageEvent{
+					{
+						MessageID:   messageID,
+						Type:        models.MessageEventUndeliverable,
+						Attempt:     1,
+						Description: `email address "user-123@example.com" is suppressed after a bounce or complaint`,
+					},
+				}))
+			})
+
+			It("updates the message status as undeliverable", func() {
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusUndeliverable))
+			})
+		})
+
+		Context("when the suppression list cannot be checked", func() {
+			BeforeEach(func() {
+				suppressionsRepo.IsSuppressedCall.Returns.Error = errors.New("db is down")
+
+				processor.Process(job, logger)
+			})
+
+			It("does not send the notification", func() {
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+			})
+
+			It("retries the delivery instead of marking it undeliverable", func() {
+				Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("db is down")))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).NotTo(Equal(common.StatusUndeliverable))
+			})
+		})
+
 		Context("when the recipient hasn't unsubscribed, but doesn't have a valid email address", func() {
 			Context("when the recipient has no emails", func() {
 				BeforeEach(func() {
@@ -527,6 +816,19 @@ var _ = Describe("DeliveryJobProcessor", func() {
 				Expect(messageStatusUpdater.UpdateCall.Receives.Logger.SessionName()).To(Equal("notifications.worker"))
 			})
 
+			It("records why the message is undeliverable in the message history", func() {
+				processor.Process(job, logger)
+
+				Expect(messageEventRecorder.RecordCall.Receives.Events).To(Equal([]models.MessageEvent{
+					{
+						MessageID:   messageID,
+						Type:        models.MessageEventUndeliverable,
+						Attempt:     1,
+						Description: "user is unsubscribed from this kind of notification",
+					},
+				}))
+			})
+
 			Context("and the notification is not registered", func() {
 				It("does not send the email", func() {
 					processor.Process(job, logger)
@@ -625,6 +927,105 @@ var _ = Describe("DeliveryJobProcessor", func() {
 			})
 		})
 
+		Context("when the message was cancelled while the job was reserved", func() {
+			BeforeEach(func() {
+				messagesRepo.FindByIDCall.Returns.Message = models.Message{
+					ID:     messageID,
+					Status: common.StatusCancelled,
+				}
+			})
+
+			It("does not send the email", func() {
+				err := processor.Process(job, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal(messageID))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(BeEmpty())
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		Context("when the send of the message was cancelled", func() {
+			BeforeEach(func() {
+				messagesRepo.FindByIDCall.Returns.Message = models.Message{
+					ID:     messageID,
+					Status: common.StatusQueued,
+					SendID: "some-send-id",
+				}
+				sendsRepo.FindByIDCall.Returns.Send = models.Send{
+					ID:        "some-send-id",
+					Cancelled: true,
+				}
+			})
+
+			It("does not send the email", func() {
+				err := processor.Process(job, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(sendsRepo.FindByIDCall.Receives.SendID).To(Equal("some-send-id"))
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+			})
+
+			It("updates the message status as cancelled", func() {
+				processor.Process(job, logger)
+
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageID).To(Equal(messageID))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusCancelled))
+			})
+
+			It("records the cancellation in the message history", func() {
+				processor.Process(job, logger)
+
+				Expect(messageEventRecorder.RecordCall.Receives.Events).To(ContainElement(models.MessageEvent{
+					MessageID:   messageID,
+					Type:        models.MessageEventCancelled,
+					Attempt:     1,
+					Description: "send was cancelled",
+				}))
+			})
+		})
+
+		Context("when the cancellation of the message cannot be checked", func() {
+			BeforeEach(func() {
+				messagesRepo.FindByIDCall.Returns.Message = models.Message{
+					ID:     messageID,
+					Status: common.StatusQueued,
+					SendID: "some-send-id",
+				}
+				sendsRepo.FindByIDCall.Returns.Error = errors.New("database is down")
+			})
+
+			It("does not send the email and retries the job", func() {
+				err := processor.Process(job, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(0))
+				Expect(messageStatusUpdater.UpdateCall.Receives.MessageStatus).To(Equal(common.StatusFailed))
+				Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeTrue())
+				Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError("database is down"))
+			})
+		})
+
+		Context("when the send of the message no longer exists", func() {
+			BeforeEach(func() {
+				messagesRepo.FindByIDCall.Returns.Message = models.Message{
+					ID:     messageID,
+					Status: common.StatusQueued,
+					SendID: "some-send-id",
+				}
+				sendsRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+			})
+
+			It("sends the email", func() {
+				err := processor.Process(job, logger)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(mailClient.SendCall.CallCount).To(Equal(1))
+			})
+		})
+
 		Context("when the job contains malformed JSON", func() {
 			BeforeEach(func() {
 				job.Payload = `{"Space":"my-space","Options":{"HTML":"<p>some text that just abruptly ends`
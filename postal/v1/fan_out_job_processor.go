@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/pivotal-golang/lager"
+	"github.com/rcrowley/go-metrics"
+)
+
+type Dispatcher interface {
+	Dispatch(services.Dispatch) ([]services.Response, error)
+}
+
+type sendResolver interface {
+	FindByID(conn models.ConnectionInterface, sendID string) (models.Send, error)
+	MarkResolved(conn models.ConnectionInterface, sendID string) error
+}
+
+type FanOutJobProcessorConfig struct {
+	Database               db.DatabaseInterface
+	Strategies             map[string]Dispatcher
+	SendsRepo              sendResolver
+	DeliveryFailureHandler deliveryFailureHandler
+}
+
+// FanOutJobProcessor resolves the recipients of a fan-out job with the
+// strategy for its audience, enqueues a delivery for each of them and marks
+// the send as resolved. A job that fails is retried; recipients enqueued by
+// an earlier attempt are skipped by the strategy's enqueuer. The recipients
+// of a send that was cancelled before the job ran are never resolved.
+type FanOutJobProcessor struct {
+	database               db.DatabaseInterface
+	strategies             map[string]Dispatcher
+	sendsRepo              sendResolver
+	deliveryFailureHandler deliveryFailureHandler
+}
+
+func NewFanOutJobProcessor(config FanOutJobProcessorConfig) FanOutJobProcessor {
+	return FanOutJobProcessor{
+		database:               config.Database,
+		strategies:             config.Strategies,
+		sendsRepo:              config.SendsRepo,
+		deliveryFailureHandler: config.DeliveryFailureHandler,
+	}
+}
+
+func (p FanOutJobProcessor) Process(job *gobble.Job, logger lager.Logger) error {
+	var fanOut services.FanOut
+	err := job.Unmarshal(&fanOut)
+	if err != nil {
+		metrics.GetOrRegisterCounter("notifications.worker.panic.json", nil).Inc(1)
+
+		p.deliveryFailureHandler.Handle(job, err, common.RetryPolicy{}, logger)
+		return nil
+	}
+
+	logger = logger.Session("fan-out", lager.Data{
+		"send_id":         fanOut.Dispatch.SendID,
+		"audience":        fanOut.Audience,
+		"vcap_request_id": fanOut.Dispatch.VCAPRequest.ID,
+	})
+
+	strategy, ok := p.strategies[fanOut.Audience]
+	if !ok {
+		err = fmt.Errorf("unknown audience %q", fanOut.Audience)
+		logger.Error("unknown-audience", err)
+		job.Bury(err.Error())
+		return nil
+	}
+
+	dispatch := fanOut.Dispatch
+	dispatch.Connection = p.database.Connection()
+
+	send, err := p.sendsRepo.FindByID(dispatch.Connection, dispatch.SendID)
+	if err != nil {
+		logger.Error("find-send-failed", err)
+		p.deliveryFailureHandler.Handle(job, err, common.RetryPolicy{}, logger)
+		return nil
+	}
+
+	if send.Cancelled {
+		logger.Info("send-cancelled")
+		metrics.GetOrRegisterCounter("notifications.worker.cancelled", nil).Inc(1)
+		return nil
+	}
+
+	responses, err := strategy.Dispatch(dispatch)
+	if err != nil {
+		logger.Error("fan-out-failed", err)
+		p.deliveryFailureHandler.Handle(job, err, common.RetryPolicy{}, logger)
+		return nil
+	}
+
+	err = p.sendsRepo.MarkResolved(dispatch.Connection, dispatch.SendID)
+	if err != nil {
+		logger.Error("mark-resolved-failed", err)
+		p.deliveryFailureHandler.Handle(job, err, common.RetryPolicy{}, logger)
+		return nil
+	}
+
+	logger.Info("fanned-out", lager.Data{"recipients": len(responses)})
+	metrics.GetOrRegisterCounter("notifications.worker.fanned-out", nil).Inc(1)
+
+	return nil
+}
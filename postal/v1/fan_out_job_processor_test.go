@@ -0,0 +1,165 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FanOutJobProcessor", func() {
+	var (
+		processor              v1.FanOutJobProcessor
+		logger                 lager.Logger
+		buffer                 *bytes.Buffer
+		database               *mocks.Database
+		conn                   *mocks.Connection
+		organizationStrategy   *mocks.Strategy
+		spaceStrategy          *mocks.Strategy
+		sendsRepo              *mocks.SendsRepo
+		deliveryFailureHandler *mocks.DeliveryFailureHandler
+		job                    *gobble.Job
+	)
+
+	BeforeEach(func() {
+		buffer = bytes.NewBuffer([]byte{})
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.DEBUG))
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		organizationStrategy = mocks.NewStrategy()
+		spaceStrategy = mocks.NewStrategy()
+		sendsRepo = mocks.NewSendsRepo()
+		deliveryFailureHandler = mocks.NewDeliveryFailureHandler()
+
+		processor = v1.NewFanOutJobProcessor(v1.FanOutJobProcessorConfig{
+			Database: database,
+			Strategies: map[string]v1.Dispatcher{
+				services.AudienceOrganization: organizationStrategy,
+				services.AudienceSpace:        spaceStrategy,
+			},
+			SendsRepo:              sendsRepo,
+			DeliveryFailureHandler: deliveryFailureHandler,
+		})
+
+		job = gobble.NewJob(services.FanOut{
+			JobType:  services.FanOutJobType,
+			Audience: services.AudienceSpace,
+			Dispatch: services.Dispatch{
+				GUID:   "space-001",
+				SendID: "some-send-id",
+				VCAPRequest: services.DispatchVCAPRequest{
+					ID: "some-request-id",
+				},
+			},
+		})
+	})
+
+	It("dispatches the send with the strategy for its audience", func() {
+		err := processor.Process(job, logger)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(organizationStrategy.DispatchCallsCount).To(Equal(0))
+		Expect(spaceStrategy.DispatchCallsCount).To(Equal(1))
+
+		dispatch := spaceStrategy.DispatchCalls[0].Receives.Dispatch
+		Expect(dispatch.GUID).To(Equal("space-001"))
+		Expect(dispatch.SendID).To(Equal("some-send-id"))
+		Expect(dispatch.Connection).To(Equal(conn))
+
+		Expect(sendsRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+		Expect(sendsRepo.FindByIDCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(sendsRepo.MarkResolvedCall.Receives.Connection).To(Equal(conn))
+		Expect(sendsRepo.MarkResolvedCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+	})
+
+	Context("when the send was cancelled", func() {
+		It("does not resolve the recipients", func() {
+			sendsRepo.FindByIDCall.Returns.Send = models.Send{ID: "some-send-id", Cancelled: true}
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(spaceStrategy.DispatchCallsCount).To(Equal(0))
+			Expect(sendsRepo.MarkResolvedCall.WasCalled).To(BeFalse())
+			Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the send cannot be found", func() {
+		It("hands the job to the failure handler to be retried", func() {
+			sendsRepo.FindByIDCall.Returns.Error = errors.New("database is down")
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(spaceStrategy.DispatchCallsCount).To(Equal(0))
+			Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("database is down")))
+		})
+	})
+
+	Context("when the strategy fails", func() {
+		It("hands the job to the failure handler to be retried", func() {
+			spaceStrategy.DispatchCalls = []mocks.StrategyDispatchCall{
+				mocks.NewStrategyDispatchCall(nil, errors.New("cloud controller is down")),
+			}
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+			Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("cloud controller is down")))
+			Expect(sendsRepo.MarkResolvedCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the send cannot be marked as resolved", func() {
+		It("hands the job to the failure handler to be retried", func() {
+			sendsRepo.MarkResolvedCall.Returns.Error = errors.New("database is down")
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(deliveryFailureHandler.HandleCall.Receives.Job).To(Equal(job))
+			Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(MatchError(errors.New("database is down")))
+		})
+	})
+
+	Context("when the audience is unknown", func() {
+		It("buries the job", func() {
+			job = gobble.NewJob(services.FanOut{
+				JobType:  services.FanOutJobType,
+				Audience: "galaxy",
+			})
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(job.ShouldBury).To(BeTrue())
+			Expect(job.LastError).To(Equal(`unknown audience "galaxy"`))
+			Expect(deliveryFailureHandler.HandleCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the job cannot be unmarshalled", func() {
+		It("hands the job to the failure handler", func() {
+			job = &gobble.Job{Payload: "%%"}
+
+			err := processor.Process(job, logger)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deliveryFailureHandler.HandleCall.Receives.Error).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/lager"
+)
+
+type MessageEventRecorder struct {
+	messageEventsRepo MessageEventCreator
+}
+
+type MessageEventCreator interface {
+	Create(conn models.ConnectionInterface, event models.MessageEvent) (models.MessageEvent, error)
+}
+
+func NewMessageEventRecorder(messageEventsRepo MessageEventCreator) MessageEventRecorder {
+	return MessageEventRecorder{
+		messageEventsRepo: messageEventsRepo,
+	}
+}
+
+// Record appends an event to the delivery history of a message. A failure to
+// record the event is logged rather than failing the delivery.
+func (r MessageEventRecorder) Record(conn db.ConnectionInterface, event models.MessageEvent, logger lager.Logger) {
+	_, err := r.messageEventsRepo.Create(conn, event)
+	if err != nil {
+		logger.Session("message-event-recorder").Error("failed-message-event-create", err, lager.Data{
+			"event": event.Type,
+		})
+	}
+}
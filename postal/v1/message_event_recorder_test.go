@@ -0,0 +1,71 @@
+package v1_test
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/v1"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/lager"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageEventRecorder", func() {
+	var (
+		recorder          v1.MessageEventRecorder
+		messageEventsRepo *mocks.MessageEventsRepo
+		logger            lager.Logger
+		buffer            *bytes.Buffer
+		conn              *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		messageEventsRepo = mocks.NewMessageEventsRepo()
+
+		buffer = bytes.NewBuffer([]byte{})
+		logger = lager.NewLogger("notifications")
+		logger.RegisterSink(lager.NewWriterSink(buffer, lager.INFO))
+
+		recorder = v1.NewMessageEventRecorder(messageEventsRepo)
+	})
+
+	It("records the event", func() {
+		event := models.MessageEvent{
+			MessageID: "some-message-id",
+			Type:      models.MessageEventAttempted,
+			Attempt:   1,
+		}
+
+		recorder.Record(conn, event, logger)
+
+		Expect(messageEventsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+		Expect(messageEventsRepo.CreateCall.Receives.Events).To(Equal([]models.MessageEvent{event}))
+	})
+
+	Context("failure cases", func() {
+		It("logs the error when the repository fails to create the event", func() {
+			messageEventsRepo.CreateCall.Returns.Error = errors.New("failed to create")
+
+			recorder.Record(conn, models.MessageEvent{Type: models.MessageEventDelivered}, logger)
+
+			lines, err := parseLogLines(buffer.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(lines).To(HaveLen(1))
+			Expect(lines[0]).To(Equal(logLine{
+				Source:   "notifications",
+				Message:  "notifications.message-event-recorder.failed-message-event-create",
+				LogLevel: int(lager.ERROR),
+				Data: map[string]interface{}{
+					"session": "1",
+					"error":   "failed to create",
+					"event":   "delivered",
+				},
+			}))
+		})
+	})
+})
@@ -7,26 +7,23 @@ import (
 )
 
 type MessageStatusUpdater struct {
-	messagesRepo MessageUpserter
+	messagesRepo MessageStatusSetter
 }
 
-type MessageUpserter interface {
-	Upsert(conn models.ConnectionInterface, message models.Message) (models.Message, error)
+type MessageStatusSetter interface {
+	UpdateStatus(conn models.ConnectionInterface, messageID, status string) (models.Message, error)
 }
 
-func NewMessageStatusUpdater(messagesRepo MessageUpserter) MessageStatusUpdater {
+func NewMessageStatusUpdater(messagesRepo MessageStatusSetter) MessageStatusUpdater {
 	return MessageStatusUpdater{
 		messagesRepo: messagesRepo,
 	}
 }
 
 func (mu MessageStatusUpdater) Update(conn db.ConnectionInterface, messageID, messageStatus, campaignID string, logger lager.Logger) {
-	_, err := mu.messagesRepo.Upsert(conn, models.Message{
-		ID:         messageID,
-		Status:     messageStatus,
-	})
+	_, err := mu.messagesRepo.UpdateStatus(conn, messageID, messageStatus)
 	if err != nil {
-		logger.Session("message-updater").Error("failed-message-status-upsert", err, lager.Data{
+		logger.Session("message-updater").Error("failed-message-status-update", err, lager.Data{
 			"status": messageStatus,
 		})
 	}
@@ -25,11 +25,9 @@ var _ = Describe("MessageStatusUpdater", func() {
 	BeforeEach(func() {
 		conn = mocks.NewConnection()
 		messagesRepo = mocks.NewMessagesRepo()
-		messagesRepo.UpsertCall.Returns.Messages = []models.Message{
-			{
-				ID:         "some-message-id",
-				Status:     "message-status",
-			},
+		messagesRepo.UpdateStatusCall.Returns.Message = models.Message{
+			ID:     "some-message-id",
+			Status: "message-status",
 		}
 
 		buffer = bytes.NewBuffer([]byte{})
@@ -42,16 +40,14 @@ var _ = Describe("MessageStatusUpdater", func() {
 	It("updates the status of the message", func() {
 		updater.Update(conn, "some-message-id", "message-status", "campaign-id", logger)
 
-		Expect(messagesRepo.UpsertCall.Receives.Connection).To(Equal(conn))
-		Expect(messagesRepo.UpsertCall.Receives.Messages[0]).To(Equal(models.Message{
-			ID:         "some-message-id",
-			Status:     "message-status",
-		}))
+		Expect(messagesRepo.UpdateStatusCall.Receives.Connection).To(Equal(conn))
+		Expect(messagesRepo.UpdateStatusCall.Receives.MessageID).To(Equal("some-message-id"))
+		Expect(messagesRepo.UpdateStatusCall.Receives.Status).To(Equal("message-status"))
 	})
 
 	Context("failure cases", func() {
-		It("logs the error when the repository fails to upsert", func() {
-			messagesRepo.UpsertCall.Returns.Error = errors.New("failed to upsert")
+		It("logs the error when the repository fails to update the status", func() {
+			messagesRepo.UpdateStatusCall.Returns.Error = errors.New("failed to update")
 
 			updater.Update(conn, "some-message-id", "message-status", "campaign-id", logger)
 
@@ -63,11 +59,11 @@ var _ = Describe("MessageStatusUpdater", func() {
 
 			Expect(line).To(Equal(logLine{
 				Source:   "notifications",
-				Message:  "notifications.message-updater.failed-message-status-upsert",
+				Message:  "notifications.message-updater.failed-message-status-update",
 				LogLevel: int(lager.ERROR),
 				Data: map[string]interface{}{
 					"session": "1",
-					"error":   "failed to upsert",
+					"error":   "failed to update",
 					"status":  "message-status",
 				},
 			}))
@@ -18,26 +18,43 @@ type templateFinder interface {
 	FindByID(connection models.ConnectionInterface, templateID string) (models.Template, error)
 }
 
+type partialLister interface {
+	ListByNames(connection models.ConnectionInterface, names []string) ([]models.Partial, error)
+}
+
 type TemplatesLoader struct {
 	database db.DatabaseInterface
 
 	clientsRepo   clientFinder
 	kindsRepo     kindFinder
 	templatesRepo templateFinder
+	partialsRepo  partialLister
 }
 
-func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder) TemplatesLoader {
+func NewTemplatesLoader(database db.DatabaseInterface, clientsRepo clientFinder, kindsRepo kindFinder, templatesRepo templateFinder, partialsRepo partialLister) TemplatesLoader {
 	return TemplatesLoader{
 		database:      database,
 		clientsRepo:   clientsRepo,
 		kindsRepo:     kindsRepo,
 		templatesRepo: templatesRepo,
+		partialsRepo:  partialsRepo,
 	}
 }
 
-func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+// LoadTemplates loads the template of the kind, or of the client when the
+// kind uses the default template, localized for the recipient's locale. The
+// templates come with the layout of the client and with the partials that
+// they, or the partials they include, refer to.
+func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID, locale string) (common.Templates, error) {
 	conn := loader.database.Connection()
 
+	client, err := loader.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	templateID = client.TemplateID
+
 	if kindID != "" {
 		kind, err := loader.kindsRepo.Find(conn, kindID, clientID)
 		if err != nil {
@@ -45,25 +62,96 @@ func (loader TemplatesLoader) LoadTemplates(clientID, kindID, templateID string)
 		}
 
 		if kind.TemplateID != models.DefaultTemplateID {
-			return loader.loadTemplate(conn, kind.TemplateID)
+			templateID = kind.TemplateID
 		}
 	}
 
-	client, err := loader.clientsRepo.Find(conn, clientID)
+	templates, err := loader.loadTemplate(conn, templateID, locale)
 	if err != nil {
 		return common.Templates{}, err
 	}
 
-	return loader.loadTemplate(conn, client.TemplateID)
+	names := partialReferences(templates.Subject, templates.Text, templates.HTML)
+	if client.Layout != "" {
+		names = append(names, client.Layout)
+	}
+
+	templates.Partials, err = loader.loadPartials(conn, names)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
+	if client.Layout != "" {
+		templates.Layout = templates.Partials[client.Layout]
+	}
+
+	return templates, nil
 }
 
-func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID string) (common.Templates, error) {
+// loadPartials loads the named partials and then, a level at a time, the
+// partials that those include.
+func (loader TemplatesLoader) loadPartials(conn db.ConnectionInterface, names []string) (map[string]string, error) {
+	partials := map[string]string{}
+	requested := map[string]bool{}
+
+	for len(names) > 0 {
+		var batch []string
+		for _, name := range names {
+			if !requested[name] {
+				requested[name] = true
+				batch = append(batch, name)
+			}
+		}
+
+		if len(batch) == 0 {
+			break
+		}
+
+		found, err := loader.partialsRepo.ListByNames(conn, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		names = nil
+		for _, partial := range found {
+			partials[partial.Name] = partial.Body
+			names = append(names, partialReferences(partial.Body)...)
+		}
+	}
+
+	return partials, nil
+}
+
+// partialReferences lists the partials the sources include. A source that
+// cannot be parsed is left for the packager to report.
+func partialReferences(sources ...string) []string {
+	var names []string
+	for _, source := range sources {
+		references, err := models.PartialReferences(source)
+		if err != nil {
+			continue
+		}
+
+		names = append(names, references...)
+	}
+
+	return names
+}
+
+func (loader TemplatesLoader) loadTemplate(conn db.ConnectionInterface, templateID, locale string) (common.Templates, error) {
 	template, err := loader.templatesRepo.FindByID(conn, templateID)
 	if err != nil {
 		return common.Templates{}, err
 	}
 
+	template, err = template.Localized(locale)
+	if err != nil {
+		return common.Templates{}, err
+	}
+
 	return common.Templates{
+		ID:      template.ID,
+		Version: template.Version,
 		Subject: template.Subject,
 		Text:    template.Text,
 		HTML:    template.HTML,
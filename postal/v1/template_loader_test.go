@@ -19,6 +19,7 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo   *mocks.ClientsRepository
 		kindsRepo     *mocks.KindsRepo
 		templatesRepo *mocks.TemplatesRepo
+		partialsRepo  *mocks.PartialsRepo
 		conn          db.ConnectionInterface
 		database      *mocks.Database
 	)
@@ -27,12 +28,13 @@ var _ = Describe("TemplateLoader", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		partialsRepo = mocks.NewPartialsRepo()
 
 		conn = mocks.NewConnection()
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo)
+		loader = v1.NewTemplatesLoader(database, clientsRepo, kindsRepo, templatesRepo, partialsRepo)
 	})
 
 	Describe("LoadTemplates", func() {
@@ -67,6 +69,7 @@ var _ = Describe("TemplateLoader", func() {
 					HTML:    "<p>kind template</p>",
 					Text:    "some kind template text",
 					Subject: "kind subject",
+					Version: 3,
 				}
 
 				kindsRepo.FindCall.Returns.Kinds = []models.Kind{
@@ -79,12 +82,15 @@ var _ = Describe("TemplateLoader", func() {
 			})
 
 			It("returns the template belonging to the kind", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
-					HTML:    "<p>kind template</p>",
-					Text:    "some kind template text",
-					Subject: "kind subject",
+					ID:       "my-kind-template",
+					Version:  3,
+					HTML:     "<p>kind template</p>",
+					Text:     "some kind template text",
+					Subject:  "kind subject",
+					Partials: map[string]string{},
 				}))
 
 				Expect(templatesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
@@ -109,12 +115,14 @@ var _ = Describe("TemplateLoader", func() {
 			})
 
 			It("returns the template belonging to the client", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
-					HTML:    "<p>client template</p>",
-					Text:    "some client template text",
-					Subject: "client subject",
+					ID:       "my-client-template",
+					HTML:     "<p>client template</p>",
+					Text:     "some client template text",
+					Subject:  "client subject",
+					Partials: map[string]string{},
 				}))
 
 				Expect(templatesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
@@ -124,25 +132,128 @@ var _ = Describe("TemplateLoader", func() {
 
 		Context("when the neither client nor kind has a template", func() {
 			It("returns the default template", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates).To(Equal(common.Templates{
+					ID:       models.DefaultTemplateID,
+					HTML:     "<p>The default template</p>",
+					Text:     "The default template",
+					Subject:  "default subject",
+					Partials: map[string]string{},
+				}))
+			})
+		})
+
+		Context("when the template has a variant for the locale", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Template.Locales = `{"de": {"subject": "Betreff", "html": "<p>Die Vorlage</p>"}}`
+			})
+
+			It("returns the variant, falling back to the template for the fields it does not set", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "de_DE")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
-					HTML:    "<p>The default template</p>",
-					Text:    "The default template",
-					Subject: "default subject",
+					ID:       models.DefaultTemplateID,
+					HTML:     "<p>Die Vorlage</p>",
+					Text:     "The default template",
+					Subject:  "Betreff",
+					Partials: map[string]string{},
 				}))
 			})
+
+			It("returns the template when there is no variant for the locale", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "ja")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Subject).To(Equal("default subject"))
+			})
+		})
+
+		Context("when the variants of the template cannot be read", func() {
+			It("bubbles up the error", func() {
+				templatesRepo.FindByIDCall.Returns.Template.Locales = "{"
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "de")
+				Expect(err).To(HaveOccurred())
+			})
 		})
 
 		Context("when kindID is an empty string", func() {
 			It("does not look for a template belonging to the kind", func() {
-				templates, err := loader.LoadTemplates("my-client-id", "", "")
+				templates, err := loader.LoadTemplates("my-client-id", "", "", "")
 				Expect(err).ToNot(HaveOccurred())
 				Expect(templates).To(Equal(common.Templates{
-					HTML:    "<p>The default template</p>",
-					Text:    "The default template",
-					Subject: "default subject",
+					ID:       models.DefaultTemplateID,
+					HTML:     "<p>The default template</p>",
+					Text:     "The default template",
+					Subject:  "default subject",
+					Partials: map[string]string{},
+				}))
+			})
+		})
+
+		Context("when there are partials", func() {
+			BeforeEach(func() {
+				templatesRepo.FindByIDCall.Returns.Template.HTML = `<p>{{template "greeting" .}}</p>{{template "footer" .}}`
+				partialsRepo.ListByNamesCall.Returns.Partials = map[string]models.Partial{
+					"greeting":  {Name: "greeting", Body: "hello"},
+					"footer":    {Name: "footer", Body: `the footer {{template "signature" .}}`},
+					"signature": {Name: "signature", Body: "the signature"},
+					"unused":    {Name: "unused", Body: "not included anywhere"},
+					"my-layout": {Name: "my-layout", Body: "<main>{{.HTMLComponents.BodyContent}}</main>"},
+				}
+			})
+
+			It("returns only the partials the templates include", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Partials).To(Equal(map[string]string{
+					"greeting":  "hello",
+					"footer":    `the footer {{template "signature" .}}`,
+					"signature": "the signature",
 				}))
+				Expect(templates.Layout).To(BeEmpty())
+
+				Expect(partialsRepo.ListByNamesCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsRepo.ListByNamesCall.Receives.Names).To(Equal([][]string{
+					{"greeting", "footer"},
+					{"signature"},
+				}))
+			})
+
+			It("returns the layout of the client", func() {
+				clientsRepo.FindCall.Returns.Client.Layout = "my-layout"
+
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Layout).To(Equal("<main>{{.HTMLComponents.BodyContent}}</main>"))
+				Expect(templates.Partials).To(HaveKey("my-layout"))
+			})
+
+			It("loads each partial once when partials include each other", func() {
+				partialsRepo.ListByNamesCall.Returns.Partials["signature"] = models.Partial{Name: "signature", Body: `{{template "footer" .}}`}
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(partialsRepo.ListByNamesCall.Receives.Names).To(HaveLen(2))
+			})
+		})
+
+		Context("when the templates include no partials", func() {
+			It("does not look for partials", func() {
+				templates, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(templates.Partials).To(BeEmpty())
+				Expect(partialsRepo.ListByNamesCall.Receives.Names).To(BeEmpty())
+			})
+		})
+
+		Context("when the partials repo has an error", func() {
+			It("bubbles up the error", func() {
+				templatesRepo.FindByIDCall.Returns.Template.HTML = `{{template "footer" .}}`
+				partialsRepo.ListByNamesCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
 			})
 		})
 
@@ -150,7 +261,7 @@ var _ = Describe("TemplateLoader", func() {
 			It("bubbles up the error", func() {
 				kindsRepo.FindCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).To(HaveOccurred())
 			})
 
@@ -160,7 +271,7 @@ var _ = Describe("TemplateLoader", func() {
 			It("bubbles up the error", func() {
 				clientsRepo.FindCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "")
+				_, err := loader.LoadTemplates("my-client-id", "my-kind-id", "", "")
 				Expect(err).To(HaveOccurred())
 			})
 		})
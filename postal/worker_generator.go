@@ -7,11 +7,18 @@ type WorkerGenerator struct {
 
 type Worker interface {
 	Work()
+	Halt()
 }
 
-func (w WorkerGenerator) Work(workerFunc func(id int) Worker) {
+func (w WorkerGenerator) Work(workerFunc func(id int) Worker) []Worker {
+	var workers []Worker
+
 	firstID := w.InstanceIndex*w.Count + 1
 	for i := 0; i < w.Count; i++ {
-		workerFunc(firstID + i).Work()
+		worker := workerFunc(firstID + i)
+		worker.Work()
+		workers = append(workers, worker)
 	}
+
+	return workers
 }
@@ -13,11 +13,14 @@ func (m *mockWorker) Work() {
 	*m++
 }
 
+func (m *mockWorker) Halt() {}
+
 var _ = Describe("WorkerGenerator", func() {
 	Describe("#Work", func() {
 		var (
 			workerIDs []int
 			worker    mockWorker
+			workers   []postal.Worker
 		)
 
 		BeforeEach(func() {
@@ -28,7 +31,7 @@ var _ = Describe("WorkerGenerator", func() {
 				InstanceIndex: 2,
 			}
 
-			generator.Work(func(id int) postal.Worker {
+			workers = generator.Work(func(id int) postal.Worker {
 				workerIDs = append(workerIDs, id)
 				return &worker
 			})
@@ -41,5 +44,9 @@ var _ = Describe("WorkerGenerator", func() {
 		It("should do work on each worker", func() {
 			Expect(worker).To(BeEquivalentTo(5))
 		})
+
+		It("returns the workers so that they can be halted", func() {
+			Expect(workers).To(HaveLen(5))
+		})
 	})
 })
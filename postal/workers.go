@@ -0,0 +1,58 @@
+package postal
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+type queueCloser interface {
+	Close()
+}
+
+// Workers are the delivery workers started by Boot, along with the queue
+// they reserve their jobs from.
+type Workers struct {
+	workers []Worker
+	queue   queueCloser
+}
+
+func NewWorkers(workers []Worker, queue queueCloser) Workers {
+	return Workers{
+		workers: workers,
+		queue:   queue,
+	}
+}
+
+// Halt stops every worker once it has finished the job it is working on
+// and then closes the queue, releasing the jobs it reserved but did not hand
+// out. Workers that are still busy at the deadline are left to be cut off
+// when the process exits; their jobs are picked up again once their
+// reservations expire.
+func (w Workers) Halt(deadline time.Time) error {
+	var halted sync.WaitGroup
+	halted.Add(len(w.workers))
+	for _, worker := range w.workers {
+		go func(worker Worker) {
+			worker.Halt()
+			halted.Done()
+		}(worker)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		halted.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-time.After(deadline.Sub(time.Now())):
+		err = errors.New("workers were still busy when the shutdown deadline passed")
+	}
+
+	w.queue.Close()
+
+	return err
+}
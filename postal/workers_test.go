@@ -0,0 +1,73 @@
+package postal_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type haltingWorker struct {
+	halted  chan bool
+	release chan bool
+}
+
+func newHaltingWorker() *haltingWorker {
+	return &haltingWorker{
+		halted:  make(chan bool, 1),
+		release: make(chan bool),
+	}
+}
+
+func (w *haltingWorker) Work() {}
+
+func (w *haltingWorker) Halt() {
+	<-w.release
+	w.halted <- true
+}
+
+var _ = Describe("Workers", func() {
+	var (
+		idle    *haltingWorker
+		busy    *haltingWorker
+		queue   *mocks.Queue
+		workers postal.Workers
+	)
+
+	BeforeEach(func() {
+		idle = newHaltingWorker()
+		close(idle.release)
+		busy = newHaltingWorker()
+		queue = mocks.NewQueue()
+
+		workers = postal.NewWorkers([]postal.Worker{idle, busy}, queue)
+	})
+
+	Describe("Halt", func() {
+		It("waits for every worker to finish its job and then closes the queue", func() {
+			go func() {
+				<-time.After(10 * time.Millisecond)
+				close(busy.release)
+			}()
+
+			err := workers.Halt(time.Now().Add(time.Second))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(idle.halted).To(Receive())
+			Expect(busy.halted).To(Receive())
+			Expect(queue.CloseCall.WasCalled).To(BeTrue())
+		})
+
+		It("gives up on workers that are still busy at the deadline", func() {
+			err := workers.Halt(time.Now().Add(50 * time.Millisecond))
+			Expect(err).To(MatchError("workers were still busy when the shutdown deadline passed"))
+
+			Expect(idle.halted).To(Receive())
+			Expect(busy.halted).NotTo(Receive())
+			Expect(queue.CloseCall.WasCalled).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type AudienceValidator struct {
+	ValidateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Dispatch services.Dispatch
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewAudienceValidator() *AudienceValidator {
+	return &AudienceValidator{}
+}
+
+func (v *AudienceValidator) Validate(dispatch services.Dispatch) error {
+	v.ValidateCall.WasCalled = true
+	v.ValidateCall.Receives.Dispatch = dispatch
+
+	return v.ValidateCall.Returns.Error
+}
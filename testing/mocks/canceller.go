@@ -0,0 +1,52 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type Canceller struct {
+	CancelMessageCall struct {
+		Receives struct {
+			Database  services.DatabaseInterface
+			MessageID string
+			ClientID  string
+			Admin     bool
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	CancelSendCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			SendID   string
+			ClientID string
+			Admin    bool
+		}
+		Returns struct {
+			Count int
+			Error error
+		}
+	}
+}
+
+func NewCanceller() *Canceller {
+	return &Canceller{}
+}
+
+func (c *Canceller) CancelMessage(database services.DatabaseInterface, messageID, clientID string, admin bool) error {
+	c.CancelMessageCall.Receives.Database = database
+	c.CancelMessageCall.Receives.MessageID = messageID
+	c.CancelMessageCall.Receives.ClientID = clientID
+	c.CancelMessageCall.Receives.Admin = admin
+
+	return c.CancelMessageCall.Returns.Error
+}
+
+func (c *Canceller) CancelSend(database services.DatabaseInterface, sendID, clientID string, admin bool) (int, error) {
+	c.CancelSendCall.Receives.Database = database
+	c.CancelSendCall.Receives.SendID = sendID
+	c.CancelSendCall.Receives.ClientID = clientID
+	c.CancelSendCall.Receives.Admin = admin
+
+	return c.CancelSendCall.Returns.Count, c.CancelSendCall.Returns.Error
+}
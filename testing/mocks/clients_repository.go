@@ -35,6 +35,17 @@ type ClientsRepository struct {
 		}
 	}
 
+	FindAllByLayoutCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Layout     string
+		}
+		Returns struct {
+			Clients []models.Client
+			Error   error
+		}
+	}
+
 	UpdateCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -82,6 +93,13 @@ func (cr *ClientsRepository) FindAllByTemplateID(conn models.ConnectionInterface
 	return cr.FindAllByTemplateIDCall.Returns.Clients, cr.FindAllByTemplateIDCall.Returns.Error
 }
 
+func (cr *ClientsRepository) FindAllByLayout(conn models.ConnectionInterface, layout string) ([]models.Client, error) {
+	cr.FindAllByLayoutCall.Receives.Connection = conn
+	cr.FindAllByLayoutCall.Receives.Layout = layout
+
+	return cr.FindAllByLayoutCall.Returns.Clients, cr.FindAllByLayoutCall.Returns.Error
+}
+
 func (cr *ClientsRepository) Update(conn models.ConnectionInterface, client models.Client) (models.Client, error) {
 	cr.UpdateCall.Receives.Connection = conn
 	cr.UpdateCall.Receives.Client = client
@@ -0,0 +1,89 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/gobble"
+
+type DeadJobsQueue struct {
+	DeadJobsCall struct {
+		Returns struct {
+			DeadJobs []gobble.DeadJob
+			Error    error
+		}
+	}
+
+	FindDeadJobCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			DeadJob gobble.DeadJob
+			Error   error
+		}
+	}
+
+	ReplayCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			Job   *gobble.Job
+			Error error
+		}
+	}
+
+	ReplayAllCall struct {
+		Returns struct {
+			Count int
+			Error error
+		}
+	}
+
+	PurgeCall struct {
+		Receives struct {
+			ID int
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	PurgeAllCall struct {
+		Returns struct {
+			Count int
+			Error error
+		}
+	}
+}
+
+func NewDeadJobsQueue() *DeadJobsQueue {
+	return &DeadJobsQueue{}
+}
+
+func (q *DeadJobsQueue) DeadJobs() ([]gobble.DeadJob, error) {
+	return q.DeadJobsCall.Returns.DeadJobs, q.DeadJobsCall.Returns.Error
+}
+
+func (q *DeadJobsQueue) FindDeadJob(id int) (gobble.DeadJob, error) {
+	q.FindDeadJobCall.Receives.ID = id
+
+	return q.FindDeadJobCall.Returns.DeadJob, q.FindDeadJobCall.Returns.Error
+}
+
+func (q *DeadJobsQueue) Replay(id int) (*gobble.Job, error) {
+	q.ReplayCall.Receives.ID = id
+
+	return q.ReplayCall.Returns.Job, q.ReplayCall.Returns.Error
+}
+
+func (q *DeadJobsQueue) ReplayAll() (int, error) {
+	return q.ReplayAllCall.Returns.Count, q.ReplayAllCall.Returns.Error
+}
+
+func (q *DeadJobsQueue) Purge(id int) error {
+	q.PurgeCall.Receives.ID = id
+
+	return q.PurgeCall.Returns.Error
+}
+
+func (q *DeadJobsQueue) PurgeAll() (int, error) {
+	return q.PurgeAllCall.Returns.Count, q.PurgeAllCall.Returns.Error
+}
@@ -10,8 +10,13 @@ type DeliveryFailureHandler struct {
 		WasCalled bool
 		Receives  struct {
 			Job    common.Retryable
+			Error  error
+			Policy common.RetryPolicy
 			Logger lager.Logger
 		}
+		Returns struct {
+			Retried bool
+		}
 	}
 }
 
@@ -19,8 +24,12 @@ func NewDeliveryFailureHandler() *DeliveryFailureHandler {
 	return &DeliveryFailureHandler{}
 }
 
-func (h *DeliveryFailureHandler) Handle(job common.Retryable, logger lager.Logger) {
+func (h *DeliveryFailureHandler) Handle(job common.Retryable, err error, policy common.RetryPolicy, logger lager.Logger) bool {
 	h.HandleCall.WasCalled = true
 	h.HandleCall.Receives.Job = job
+	h.HandleCall.Receives.Error = err
+	h.HandleCall.Receives.Policy = policy
 	h.HandleCall.Receives.Logger = logger
+
+	return h.HandleCall.Returns.Retried
 }
@@ -0,0 +1,39 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type EmailResolver struct {
+	ResolveCall struct {
+		WasCalled bool
+		Receives  struct {
+			Users       []services.User
+			UserBatches [][]services.User
+			UAAHost     string
+		}
+		Returns struct {
+			Emails map[string]string
+			Error  error
+		}
+	}
+}
+
+func NewEmailResolver() *EmailResolver {
+	return &EmailResolver{}
+}
+
+func (m *EmailResolver) Resolve(users []services.User, uaaHost string) ([]services.User, error) {
+	m.ResolveCall.WasCalled = true
+	m.ResolveCall.Receives.Users = users
+	m.ResolveCall.Receives.UserBatches = append(m.ResolveCall.Receives.UserBatches, users)
+	m.ResolveCall.Receives.UAAHost = uaaHost
+
+	resolved := make([]services.User, len(users))
+	for i, user := range users {
+		if email, ok := m.ResolveCall.Returns.Emails[user.GUID]; ok {
+			user.Email = email
+		}
+		resolved[i] = user
+	}
+
+	return resolved, m.ResolveCall.Returns.Error
+}
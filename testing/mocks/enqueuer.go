@@ -10,9 +10,11 @@ import (
 type Enqueuer struct {
 	EnqueueCall struct {
 		WasCalled bool
+		CallCount int
 		Receives  struct {
 			Connection      services.ConnectionInterface
 			Users           []services.User
+			UserBatches     [][]services.User
 			Options         services.Options
 			Space           cf.CloudControllerSpace
 			Org             cf.CloudControllerOrganization
@@ -47,6 +49,7 @@ func (m *Enqueuer) Enqueue(
 
 	m.EnqueueCall.Receives.Connection = conn
 	m.EnqueueCall.Receives.Users = users
+	m.EnqueueCall.Receives.UserBatches = append(m.EnqueueCall.Receives.UserBatches, users)
 	m.EnqueueCall.Receives.Options = options
 	m.EnqueueCall.Receives.Space = space
 	m.EnqueueCall.Receives.Org = org
@@ -57,5 +60,6 @@ func (m *Enqueuer) Enqueue(
 	m.EnqueueCall.Receives.RequestReceived = reqReceived
 
 	m.EnqueueCall.WasCalled = true
+	m.EnqueueCall.CallCount++
 	return m.EnqueueCall.Returns.Responses, m.EnqueueCall.Returns.Err
 }
@@ -0,0 +1,25 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type FanOutStrategy struct {
+	DispatchCall struct {
+		Receives struct {
+			Dispatch services.Dispatch
+		}
+		Returns struct {
+			Response services.SendResponse
+			Error    error
+		}
+	}
+}
+
+func NewFanOutStrategy() *FanOutStrategy {
+	return &FanOutStrategy{}
+}
+
+func (s *FanOutStrategy) Dispatch(dispatch services.Dispatch) (services.SendResponse, error) {
+	s.DispatchCall.Receives.Dispatch = dispatch
+
+	return s.DispatchCall.Returns.Response, s.DispatchCall.Returns.Error
+}
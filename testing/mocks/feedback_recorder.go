@@ -0,0 +1,31 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type FeedbackRecorder struct {
+	RecordCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			Report     feedback.Report
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewFeedbackRecorder() *FeedbackRecorder {
+	return &FeedbackRecorder{}
+}
+
+func (r *FeedbackRecorder) Record(conn services.ConnectionInterface, report feedback.Report) error {
+	r.RecordCall.WasCalled = true
+	r.RecordCall.Receives.Connection = conn
+	r.RecordCall.Receives.Report = report
+
+	return r.RecordCall.Returns.Error
+}
@@ -27,6 +27,7 @@ type FindsUserIDs struct {
 	UserIDsBelongingToSpaceCall struct {
 		Receives struct {
 			SpaceGUID string
+			Role      string
 			Token     string
 		}
 		Returns struct {
@@ -55,8 +56,9 @@ func (f *FindsUserIDs) UserIDsBelongingToScope(token, scope string) ([]string, e
 	return f.UserIDsBelongingToScopeCall.Returns.UserIDs, f.UserIDsBelongingToScopeCall.Returns.Error
 }
 
-func (f *FindsUserIDs) UserIDsBelongingToSpace(spaceGUID, token string) ([]string, error) {
+func (f *FindsUserIDs) UserIDsBelongingToSpace(spaceGUID, role, token string) ([]string, error) {
 	f.UserIDsBelongingToSpaceCall.Receives.SpaceGUID = spaceGUID
+	f.UserIDsBelongingToSpaceCall.Receives.Role = role
 	f.UserIDsBelongingToSpaceCall.Receives.Token = token
 
 	return f.UserIDsBelongingToSpaceCall.Returns.UserIDs, f.UserIDsBelongingToSpaceCall.Returns.Error
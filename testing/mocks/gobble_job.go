@@ -10,6 +10,13 @@ type GobbleJob struct {
 		}
 	}
 
+	BuryCall struct {
+		WasCalled bool
+		Receives  struct {
+			Reason string
+		}
+	}
+
 	StateCall struct {
 		Returns struct {
 			Count int
@@ -27,6 +34,11 @@ func (j *GobbleJob) Retry(duration time.Duration) {
 	j.RetryCall.Receives.Duration = duration
 }
 
+func (j *GobbleJob) Bury(reason string) {
+	j.BuryCall.WasCalled = true
+	j.BuryCall.Receives.Reason = reason
+}
+
 func (j *GobbleJob) State() (int, time.Time) {
 	return j.StateCall.Returns.Count, j.StateCall.Returns.Time
 }
@@ -0,0 +1,80 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type IdempotencyKeeper struct {
+	ReserveCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			Key        string
+			Path       string
+			Body       []byte
+		}
+		Returns struct {
+			Response []byte
+			Found    bool
+			Error    error
+		}
+	}
+
+	CompleteCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			Key        string
+			Response   []byte
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ReleaseCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection services.ConnectionInterface
+			ClientID   string
+			Key        string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewIdempotencyKeeper() *IdempotencyKeeper {
+	return &IdempotencyKeeper{}
+}
+
+func (k *IdempotencyKeeper) Reserve(conn services.ConnectionInterface, clientID, key, path string, body []byte) ([]byte, bool, error) {
+	k.ReserveCall.WasCalled = true
+	k.ReserveCall.Receives.Connection = conn
+	k.ReserveCall.Receives.ClientID = clientID
+	k.ReserveCall.Receives.Key = key
+	k.ReserveCall.Receives.Path = path
+	k.ReserveCall.Receives.Body = body
+
+	return k.ReserveCall.Returns.Response, k.ReserveCall.Returns.Found, k.ReserveCall.Returns.Error
+}
+
+func (k *IdempotencyKeeper) Complete(conn services.ConnectionInterface, clientID, key string, response []byte) error {
+	k.CompleteCall.WasCalled = true
+	k.CompleteCall.Receives.Connection = conn
+	k.CompleteCall.Receives.ClientID = clientID
+	k.CompleteCall.Receives.Key = key
+	k.CompleteCall.Receives.Response = response
+
+	return k.CompleteCall.Returns.Error
+}
+
+func (k *IdempotencyKeeper) Release(conn services.ConnectionInterface, clientID, key string) error {
+	k.ReleaseCall.WasCalled = true
+	k.ReleaseCall.Receives.Connection = conn
+	k.ReleaseCall.Receives.ClientID = clientID
+	k.ReleaseCall.Receives.Key = key
+
+	return k.ReleaseCall.Returns.Error
+}
@@ -0,0 +1,90 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type IdempotencyKeysRepo struct {
+	CreateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection     models.ConnectionInterface
+			IdempotencyKey models.IdempotencyKey
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			Key        string
+		}
+		Returns struct {
+			IdempotencyKey models.IdempotencyKey
+			Error          error
+		}
+	}
+
+	SetResponseCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			Key        string
+			Response   string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	DeleteCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			ClientID   string
+			Key        string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewIdempotencyKeysRepo() *IdempotencyKeysRepo {
+	return &IdempotencyKeysRepo{}
+}
+
+func (r *IdempotencyKeysRepo) Create(conn models.ConnectionInterface, key models.IdempotencyKey) (models.IdempotencyKey, error) {
+	r.CreateCall.WasCalled = true
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.IdempotencyKey = key
+
+	return key, r.CreateCall.Returns.Error
+}
+
+func (r *IdempotencyKeysRepo) Find(conn models.ConnectionInterface, clientID, key string) (models.IdempotencyKey, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.ClientID = clientID
+	r.FindCall.Receives.Key = key
+
+	return r.FindCall.Returns.IdempotencyKey, r.FindCall.Returns.Error
+}
+
+func (r *IdempotencyKeysRepo) SetResponse(conn models.ConnectionInterface, clientID, key, response string) error {
+	r.SetResponseCall.Receives.Connection = conn
+	r.SetResponseCall.Receives.ClientID = clientID
+	r.SetResponseCall.Receives.Key = key
+	r.SetResponseCall.Receives.Response = response
+
+	return r.SetResponseCall.Returns.Error
+}
+
+func (r *IdempotencyKeysRepo) Delete(conn models.ConnectionInterface, clientID, key string) error {
+	r.DeleteCall.WasCalled = true
+	r.DeleteCall.Receives.Connection = conn
+	r.DeleteCall.Receives.ClientID = clientID
+	r.DeleteCall.Receives.Key = key
+
+	return r.DeleteCall.Returns.Error
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/collections"
+
+type LayoutAssigner struct {
+	AssignLayoutToClientCall struct {
+		Receives struct {
+			Connection collections.ConnectionInterface
+			ClientID   string
+			Layout     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewLayoutAssigner() *LayoutAssigner {
+	return &LayoutAssigner{}
+}
+
+func (a *LayoutAssigner) AssignLayoutToClient(connection collections.ConnectionInterface, clientID, layout string) error {
+	a.AssignLayoutToClientCall.Receives.Connection = connection
+	a.AssignLayoutToClientCall.Receives.ClientID = clientID
+	a.AssignLayoutToClientCall.Receives.Layout = layout
+
+	return a.AssignLayoutToClientCall.Returns.Error
+}
@@ -0,0 +1,44 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type MessageEventsRepo struct {
+	CreateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Events     []models.MessageEvent
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ListByMessageIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			MessageID  string
+		}
+		Returns struct {
+			Events []models.MessageEvent
+			Error  error
+		}
+	}
+}
+
+func NewMessageEventsRepo() *MessageEventsRepo {
+	return &MessageEventsRepo{}
+}
+
+func (r *MessageEventsRepo) Create(conn models.ConnectionInterface, event models.MessageEvent) (models.MessageEvent, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Events = append(r.CreateCall.Receives.Events, event)
+
+	return event, r.CreateCall.Returns.Error
+}
+
+func (r *MessageEventsRepo) ListByMessageID(conn models.ConnectionInterface, messageID string) ([]models.MessageEvent, error) {
+	r.ListByMessageIDCall.Receives.Connection = conn
+	r.ListByMessageIDCall.Receives.MessageID = messageID
+
+	return r.ListByMessageIDCall.Returns.Events, r.ListByMessageIDCall.Returns.Error
+}
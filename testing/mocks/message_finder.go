@@ -7,6 +7,8 @@ type MessageFinder struct {
 		Receives struct {
 			Database  services.DatabaseInterface
 			MessageID string
+			ClientID  string
+			Admin     bool
 		}
 		Returns struct {
 			Message services.Message
@@ -19,9 +21,11 @@ func NewMessageFinder() *MessageFinder {
 	return &MessageFinder{}
 }
 
-func (f *MessageFinder) Find(database services.DatabaseInterface, messageID string) (services.Message, error) {
+func (f *MessageFinder) Find(database services.DatabaseInterface, messageID, clientID string, admin bool) (services.Message, error) {
 	f.FindCall.Receives.Database = database
 	f.FindCall.Receives.MessageID = messageID
+	f.FindCall.Receives.ClientID = clientID
+	f.FindCall.Receives.Admin = admin
 
 	return f.FindCall.Returns.Message, f.FindCall.Returns.Error
 }
@@ -30,6 +30,18 @@ type MessagesRepo struct {
 		}
 	}
 
+	UpdateStatusCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			MessageID  string
+			Status     string
+		}
+		Returns struct {
+			Message models.Message
+			Error   error
+		}
+	}
+
 	FindByIDCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -41,6 +53,100 @@ type MessagesRepo struct {
 		}
 	}
 
+	FindEnqueuedRecipientsCall struct {
+		Receives struct {
+			Connection       models.ConnectionInterface
+			SendID           string
+			RecipientBatches [][]string
+		}
+		Returns struct {
+			Recipients []string
+			Error      error
+		}
+	}
+
+	CountBySendIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			SendID     string
+		}
+		Returns struct {
+			Counts []models.SendStatusCount
+			Error  error
+		}
+	}
+
+	ListBySendIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			SendID     string
+			Limit      int
+			Offset     int
+		}
+		Returns struct {
+			Messages []models.Message
+			Error    error
+		}
+	}
+
+	SetJobIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			MessageIDs []string
+			JobIDs     []int
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	SetTemplateVersionCall struct {
+		CallCount int
+		Receives  struct {
+			Connection      models.ConnectionInterface
+			MessageID       string
+			TemplateID      string
+			TemplateVersion int
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	SetDeliveredToCall struct {
+		CallCount int
+		Receives  struct {
+			Connection models.ConnectionInterface
+			MessageID  string
+			Email      string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	ListCancellableBySendIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			SendID     string
+		}
+		Returns struct {
+			Messages []models.Message
+			Error    error
+		}
+	}
+
+	CancelCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			MessageIDs []string
+		}
+		Returns struct {
+			Cancelled map[string]bool
+			Error     error
+		}
+	}
+
 	DeleteBeforeCall struct {
 		InvocationTimes []time.Time
 		CallCount       int
@@ -76,6 +182,14 @@ func (mr *MessagesRepo) Update(conn models.ConnectionInterface, message models.M
 	return mr.UpdateCall.Returns.Message, mr.UpdateCall.Returns.Error
 }
 
+func (mr *MessagesRepo) UpdateStatus(conn models.ConnectionInterface, messageID, status string) (models.Message, error) {
+	mr.UpdateStatusCall.Receives.Connection = conn
+	mr.UpdateStatusCall.Receives.MessageID = messageID
+	mr.UpdateStatusCall.Receives.Status = status
+
+	return mr.UpdateStatusCall.Returns.Message, mr.UpdateStatusCall.Returns.Error
+}
+
 func (mr *MessagesRepo) FindByID(conn models.ConnectionInterface, messageID string) (models.Message, error) {
 	mr.FindByIDCall.Receives.Connection = conn
 	mr.FindByIDCall.Receives.MessageID = messageID
@@ -83,6 +197,71 @@ func (mr *MessagesRepo) FindByID(conn models.ConnectionInterface, messageID stri
 	return mr.FindByIDCall.Returns.Message, mr.FindByIDCall.Returns.Error
 }
 
+func (mr *MessagesRepo) FindEnqueuedRecipients(conn models.ConnectionInterface, sendID string, recipients []string) ([]string, error) {
+	mr.FindEnqueuedRecipientsCall.Receives.Connection = conn
+	mr.FindEnqueuedRecipientsCall.Receives.SendID = sendID
+	mr.FindEnqueuedRecipientsCall.Receives.RecipientBatches = append(mr.FindEnqueuedRecipientsCall.Receives.RecipientBatches, recipients)
+
+	return mr.FindEnqueuedRecipientsCall.Returns.Recipients, mr.FindEnqueuedRecipientsCall.Returns.Error
+}
+
+func (mr *MessagesRepo) CountBySendID(conn models.ConnectionInterface, sendID string) ([]models.SendStatusCount, error) {
+	mr.CountBySendIDCall.Receives.Connection = conn
+	mr.CountBySendIDCall.Receives.SendID = sendID
+
+	return mr.CountBySendIDCall.Returns.Counts, mr.CountBySendIDCall.Returns.Error
+}
+
+func (mr *MessagesRepo) ListBySendID(conn models.ConnectionInterface, sendID string, limit, offset int) ([]models.Message, error) {
+	mr.ListBySendIDCall.Receives.Connection = conn
+	mr.ListBySendIDCall.Receives.SendID = sendID
+	mr.ListBySendIDCall.Receives.Limit = limit
+	mr.ListBySendIDCall.Receives.Offset = offset
+
+	return mr.ListBySendIDCall.Returns.Messages, mr.ListBySendIDCall.Returns.Error
+}
+
+func (mr *MessagesRepo) SetJobID(conn models.ConnectionInterface, messageID string, jobID int) error {
+	mr.SetJobIDCall.Receives.Connection = conn
+	mr.SetJobIDCall.Receives.MessageIDs = append(mr.SetJobIDCall.Receives.MessageIDs, messageID)
+	mr.SetJobIDCall.Receives.JobIDs = append(mr.SetJobIDCall.Receives.JobIDs, jobID)
+
+	return mr.SetJobIDCall.Returns.Error
+}
+
+func (mr *MessagesRepo) SetTemplateVersion(conn models.ConnectionInterface, messageID, templateID string, version int) error {
+	mr.SetTemplateVersionCall.CallCount++
+	mr.SetTemplateVersionCall.Receives.Connection = conn
+	mr.SetTemplateVersionCall.Receives.MessageID = messageID
+	mr.SetTemplateVersionCall.Receives.TemplateID = templateID
+	mr.SetTemplateVersionCall.Receives.TemplateVersion = version
+
+	return mr.SetTemplateVersionCall.Returns.Error
+}
+
+func (mr *MessagesRepo) SetDeliveredTo(conn models.ConnectionInterface, messageID, email string) error {
+	mr.SetDeliveredToCall.CallCount++
+	mr.SetDeliveredToCall.Receives.Connection = conn
+	mr.SetDeliveredToCall.Receives.MessageID = messageID
+	mr.SetDeliveredToCall.Receives.Email = email
+
+	return mr.SetDeliveredToCall.Returns.Error
+}
+
+func (mr *MessagesRepo) ListCancellableBySendID(conn models.ConnectionInterface, sendID string) ([]models.Message, error) {
+	mr.ListCancellableBySendIDCall.Receives.Connection = conn
+	mr.ListCancellableBySendIDCall.Receives.SendID = sendID
+
+	return mr.ListCancellableBySendIDCall.Returns.Messages, mr.ListCancellableBySendIDCall.Returns.Error
+}
+
+func (mr *MessagesRepo) Cancel(conn models.ConnectionInterface, messageID string) (bool, error) {
+	mr.CancelCall.Receives.Connection = conn
+	mr.CancelCall.Receives.MessageIDs = append(mr.CancelCall.Receives.MessageIDs, messageID)
+
+	return mr.CancelCall.Returns.Cancelled[messageID], mr.CancelCall.Returns.Error
+}
+
 func (mr *MessagesRepo) DeleteBefore(conn models.ConnectionInterface, thresholdTime time.Time) (int, error) {
 	mr.DeleteBeforeCall.Receives.Connection = conn
 	mr.DeleteBeforeCall.Receives.ThresholdTime = thresholdTime
@@ -23,6 +23,22 @@ type Notify struct {
 			Error    error
 		}
 	}
+
+	ExecuteFanOutCall struct {
+		Receives struct {
+			Connection    notify.ConnectionInterface
+			Request       *http.Request
+			Context       stack.Context
+			GUID          string
+			Strategy      notify.FanOutDispatcher
+			Validator     notify.ValidatorInterface
+			VCAPRequestID string
+		}
+		Returns struct {
+			Response []byte
+			Error    error
+		}
+	}
 }
 
 func NewNotify() *Notify {
@@ -42,3 +58,17 @@ func (n *Notify) Execute(connection notify.ConnectionInterface, req *http.Reques
 
 	return n.ExecuteCall.Returns.Response, n.ExecuteCall.Returns.Error
 }
+
+func (n *Notify) ExecuteFanOut(connection notify.ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy notify.FanOutDispatcher, validator notify.ValidatorInterface, vcapRequestID string) ([]byte, error) {
+
+	n.ExecuteFanOutCall.Receives.Connection = connection
+	n.ExecuteFanOutCall.Receives.Request = req
+	n.ExecuteFanOutCall.Receives.Context = context
+	n.ExecuteFanOutCall.Receives.GUID = guid
+	n.ExecuteFanOutCall.Receives.Strategy = strategy
+	n.ExecuteFanOutCall.Receives.Validator = validator
+	n.ExecuteFanOutCall.Receives.VCAPRequestID = vcapRequestID
+
+	return n.ExecuteFanOutCall.Returns.Response, n.ExecuteFanOutCall.Returns.Error
+}
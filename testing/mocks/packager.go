@@ -27,6 +27,17 @@ type Packager struct {
 			Error   error
 		}
 	}
+
+	PreviewCall struct {
+		Receives struct {
+			MessageContext common.MessageContext
+		}
+		Returns struct {
+			Message         mail.Message
+			ExecutionErrors []error
+			Error           error
+		}
+	}
 }
 
 func NewPackager() *Packager {
@@ -46,3 +57,9 @@ func (p *Packager) Pack(context common.MessageContext) (mail.Message, error) {
 
 	return p.PackCall.Returns.Message, p.PackCall.Returns.Error
 }
+
+func (p *Packager) Preview(context common.MessageContext) (mail.Message, []error, error) {
+	p.PreviewCall.Receives.MessageContext = context
+
+	return p.PreviewCall.Returns.Message, p.PreviewCall.Returns.ExecutionErrors, p.PreviewCall.Returns.Error
+}
@@ -0,0 +1,105 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type PartialsRepo struct {
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Partial models.Partial
+			Error   error
+		}
+	}
+
+	ListCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+		}
+		Returns struct {
+			Partials []models.Partial
+			Error    error
+		}
+	}
+
+	ListByNamesCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Names      [][]string
+		}
+		Returns struct {
+			Partials map[string]models.Partial
+			Error    error
+		}
+	}
+
+	UpsertCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Partial    models.Partial
+		}
+		Returns struct {
+			Partial models.Partial
+			Error   error
+		}
+	}
+
+	DestroyCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Name       string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewPartialsRepo() *PartialsRepo {
+	return &PartialsRepo{}
+}
+
+func (r *PartialsRepo) Find(conn models.ConnectionInterface, name string) (models.Partial, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.Name = name
+
+	return r.FindCall.Returns.Partial, r.FindCall.Returns.Error
+}
+
+func (r *PartialsRepo) List(conn models.ConnectionInterface) ([]models.Partial, error) {
+	r.ListCall.Receives.Connection = conn
+
+	return r.ListCall.Returns.Partials, r.ListCall.Returns.Error
+}
+
+func (r *PartialsRepo) ListByNames(conn models.ConnectionInterface, names []string) ([]models.Partial, error) {
+	r.ListByNamesCall.Receives.Connection = conn
+	r.ListByNamesCall.Receives.Names = append(r.ListByNamesCall.Receives.Names, names)
+
+	partials := []models.Partial{}
+	for _, name := range names {
+		if partial, ok := r.ListByNamesCall.Returns.Partials[name]; ok {
+			partials = append(partials, partial)
+		}
+	}
+
+	return partials, r.ListByNamesCall.Returns.Error
+}
+
+func (r *PartialsRepo) Upsert(conn models.ConnectionInterface, partial models.Partial) (models.Partial, error) {
+	r.UpsertCall.Receives.Connection = conn
+	r.UpsertCall.Receives.Partial = partial
+
+	return r.UpsertCall.Returns.Partial, r.UpsertCall.Returns.Error
+}
+
+func (r *PartialsRepo) Destroy(conn models.ConnectionInterface, name string) error {
+	r.DestroyCall.WasCalled = true
+	r.DestroyCall.Receives.Connection = conn
+	r.DestroyCall.Receives.Name = name
+
+	return r.DestroyCall.Returns.Error
+}
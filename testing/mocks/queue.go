@@ -28,6 +28,23 @@ type Queue struct {
 		}
 	}
 
+	BuryCall struct {
+		Receives struct {
+			Job *gobble.Job
+		}
+	}
+
+	RemoveCall struct {
+		Receives struct {
+			IDs        []int
+			Connection gobble.ExecutorInterface
+		}
+		Returns struct {
+			Removed int
+			Error   error
+		}
+	}
+
 	LenCall struct {
 		Returns struct {
 			Length int
@@ -50,6 +67,10 @@ type Queue struct {
 			Error   error
 		}
 	}
+
+	CloseCall struct {
+		WasCalled bool
+	}
 }
 
 func NewQueue() *Queue {
@@ -75,6 +96,17 @@ func (q *Queue) Requeue(job *gobble.Job) {
 	q.RequeueCall.Receives.Job = job
 }
 
+func (q *Queue) Bury(job *gobble.Job) {
+	q.BuryCall.Receives.Job = job
+}
+
+func (q *Queue) Remove(ids []int, connection gobble.ExecutorInterface) (int, error) {
+	q.RemoveCall.Receives.IDs = ids
+	q.RemoveCall.Receives.Connection = connection
+
+	return q.RemoveCall.Returns.Removed, q.RemoveCall.Returns.Error
+}
+
 func (q *Queue) Len() (int, error) {
 	return q.LenCall.Returns.Length, q.LenCall.Returns.Error
 }
@@ -88,3 +120,7 @@ func (q *Queue) Reserve(id string) <-chan *gobble.Job {
 func (q *Queue) RetryQueueLengths() (map[int]int, error) {
 	return q.RetryQueueLengthsCall.Returns.Lengths, q.RetryQueueLengthsCall.Returns.Error
 }
+
+func (q *Queue) Close() {
+	q.CloseCall.WasCalled = true
+}
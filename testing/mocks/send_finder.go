@@ -0,0 +1,35 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type SendFinder struct {
+	FindCall struct {
+		Receives struct {
+			Database services.DatabaseInterface
+			SendID   string
+			ClientID string
+			Admin    bool
+			Page     int
+			PerPage  int
+		}
+		Returns struct {
+			Send  services.Send
+			Error error
+		}
+	}
+}
+
+func NewSendFinder() *SendFinder {
+	return &SendFinder{}
+}
+
+func (f *SendFinder) Find(database services.DatabaseInterface, sendID, clientID string, admin bool, page, perPage int) (services.Send, error) {
+	f.FindCall.Receives.Database = database
+	f.FindCall.Receives.SendID = sendID
+	f.FindCall.Receives.ClientID = clientID
+	f.FindCall.Receives.Admin = admin
+	f.FindCall.Receives.Page = page
+	f.FindCall.Receives.PerPage = perPage
+
+	return f.FindCall.Returns.Send, f.FindCall.Returns.Error
+}
@@ -0,0 +1,83 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type SendsRepo struct {
+	CreateCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Send       models.Send
+		}
+		Returns struct {
+			Send  models.Send
+			Error error
+		}
+	}
+
+	FindByIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			SendID     string
+		}
+		Returns struct {
+			Send  models.Send
+			Error error
+		}
+	}
+
+	MarkResolvedCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			SendID     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	MarkCancelledCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			SendID     string
+		}
+		Returns struct {
+			Error error
+		}
+	}
+}
+
+func NewSendsRepo() *SendsRepo {
+	return &SendsRepo{}
+}
+
+func (r *SendsRepo) Create(conn models.ConnectionInterface, send models.Send) (models.Send, error) {
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Send = send
+
+	return r.CreateCall.Returns.Send, r.CreateCall.Returns.Error
+}
+
+func (r *SendsRepo) FindByID(conn models.ConnectionInterface, sendID string) (models.Send, error) {
+	r.FindByIDCall.Receives.Connection = conn
+	r.FindByIDCall.Receives.SendID = sendID
+
+	return r.FindByIDCall.Returns.Send, r.FindByIDCall.Returns.Error
+}
+
+func (r *SendsRepo) MarkResolved(conn models.ConnectionInterface, sendID string) error {
+	r.MarkResolvedCall.WasCalled = true
+	r.MarkResolvedCall.Receives.Connection = conn
+	r.MarkResolvedCall.Receives.SendID = sendID
+
+	return r.MarkResolvedCall.Returns.Error
+}
+
+func (r *SendsRepo) MarkCancelled(conn models.ConnectionInterface, sendID string) error {
+	r.MarkCancelledCall.WasCalled = true
+	r.MarkCancelledCall.Receives.Connection = conn
+	r.MarkCancelledCall.Receives.SendID = sendID
+
+	return r.MarkCancelledCall.Returns.Error
+}
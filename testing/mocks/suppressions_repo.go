@@ -0,0 +1,46 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type SuppressionsRepo struct {
+	AddCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection  models.ConnectionInterface
+			Suppression models.Suppression
+		}
+		Returns struct {
+			Error error
+		}
+	}
+
+	IsSuppressedCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			Email      string
+		}
+		Returns struct {
+			Suppressed bool
+			Error      error
+		}
+	}
+}
+
+func NewSuppressionsRepo() *SuppressionsRepo {
+	return &SuppressionsRepo{}
+}
+
+func (r *SuppressionsRepo) Add(conn models.ConnectionInterface, suppression models.Suppression) error {
+	r.AddCall.WasCalled = true
+	r.AddCall.Receives.Connection = conn
+	r.AddCall.Receives.Suppression = suppression
+
+	return r.AddCall.Returns.Error
+}
+
+func (r *SuppressionsRepo) IsSuppressed(conn models.ConnectionInterface, email string) (bool, error) {
+	r.IsSuppressedCall.Receives.Connection = conn
+	r.IsSuppressedCall.Receives.Email = email
+
+	return r.IsSuppressedCall.Returns.Suppressed, r.IsSuppressedCall.Returns.Error
+}
@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+type TemplateHistory struct {
+	ListCall struct {
+		Receives struct {
+			Database   services.DatabaseInterface
+			TemplateID string
+		}
+		Returns struct {
+			Versions []models.TemplateVersion
+			Error    error
+		}
+	}
+
+	DiffCall struct {
+		Receives struct {
+			Database   services.DatabaseInterface
+			TemplateID string
+			From       int
+			To         int
+		}
+		Returns struct {
+			Diff  services.TemplateDiff
+			Error error
+		}
+	}
+}
+
+func NewTemplateHistory() *TemplateHistory {
+	return &TemplateHistory{}
+}
+
+func (th *TemplateHistory) List(database services.DatabaseInterface, templateID string) ([]models.TemplateVersion, error) {
+	th.ListCall.Receives.Database = database
+	th.ListCall.Receives.TemplateID = templateID
+
+	return th.ListCall.Returns.Versions, th.ListCall.Returns.Error
+}
+
+func (th *TemplateHistory) Diff(database services.DatabaseInterface, templateID string, from, to int) (services.TemplateDiff, error) {
+	th.DiffCall.Receives.Database = database
+	th.DiffCall.Receives.TemplateID = templateID
+	th.DiffCall.Receives.From = from
+	th.DiffCall.Receives.To = to
+
+	return th.DiffCall.Returns.Diff, th.DiffCall.Returns.Error
+}
@@ -0,0 +1,29 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type TemplatePreviewer struct {
+	PreviewCall struct {
+		WasCalled bool
+		Receives  struct {
+			Template     services.PreviewTemplate
+			Notification services.PreviewNotification
+		}
+		Returns struct {
+			Preview services.Preview
+			Error   error
+		}
+	}
+}
+
+func NewTemplatePreviewer() *TemplatePreviewer {
+	return &TemplatePreviewer{}
+}
+
+func (p *TemplatePreviewer) Preview(template services.PreviewTemplate, notification services.PreviewNotification) (services.Preview, error) {
+	p.PreviewCall.WasCalled = true
+	p.PreviewCall.Receives.Template = template
+	p.PreviewCall.Receives.Notification = notification
+
+	return p.PreviewCall.Returns.Preview, p.PreviewCall.Returns.Error
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/models"
+
+type TemplateVersionsRepo struct {
+	CreateCall struct {
+		WasCalled bool
+		Receives  struct {
+			Connection models.ConnectionInterface
+			Version    models.TemplateVersion
+		}
+		Returns struct {
+			Version models.TemplateVersion
+			Error   error
+		}
+	}
+
+	FindCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+			Versions   []int
+		}
+		Returns struct {
+			Versions map[int]models.TemplateVersion
+			Error    error
+		}
+	}
+
+	ListByTemplateIDCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+			TemplateID string
+		}
+		Returns struct {
+			Versions []models.TemplateVersion
+			Error    error
+		}
+	}
+}
+
+func NewTemplateVersionsRepo() *TemplateVersionsRepo {
+	return &TemplateVersionsRepo{}
+}
+
+func (r *TemplateVersionsRepo) Create(conn models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error) {
+	r.CreateCall.WasCalled = true
+	r.CreateCall.Receives.Connection = conn
+	r.CreateCall.Receives.Version = version
+
+	return r.CreateCall.Returns.Version, r.CreateCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) Find(conn models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error) {
+	r.FindCall.Receives.Connection = conn
+	r.FindCall.Receives.TemplateID = templateID
+	r.FindCall.Receives.Versions = append(r.FindCall.Receives.Versions, version)
+
+	return r.FindCall.Returns.Versions[version], r.FindCall.Returns.Error
+}
+
+func (r *TemplateVersionsRepo) ListByTemplateID(conn models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error) {
+	r.ListByTemplateIDCall.Receives.Connection = conn
+	r.ListByTemplateIDCall.Receives.TemplateID = templateID
+
+	return r.ListByTemplateIDCall.Returns.Versions, r.ListByTemplateIDCall.Returns.Error
+}
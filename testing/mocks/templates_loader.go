@@ -8,6 +8,7 @@ type TemplatesLoader struct {
 			ClientID   string
 			KindID     string
 			TemplateID string
+			Locale     string
 		}
 		Returns struct {
 			Templates common.Templates
@@ -20,10 +21,11 @@ func NewTemplatesLoader() *TemplatesLoader {
 	return &TemplatesLoader{}
 }
 
-func (tl *TemplatesLoader) LoadTemplates(clientID, kindID, templateID string) (common.Templates, error) {
+func (tl *TemplatesLoader) LoadTemplates(clientID, kindID, templateID, locale string) (common.Templates, error) {
 	tl.LoadTemplatesCall.Receives.ClientID = clientID
 	tl.LoadTemplatesCall.Receives.KindID = kindID
 	tl.LoadTemplatesCall.Receives.TemplateID = templateID
+	tl.LoadTemplatesCall.Receives.Locale = locale
 
 	return tl.LoadTemplatesCall.Returns.Templates, tl.LoadTemplatesCall.Returns.Error
 }
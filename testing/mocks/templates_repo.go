@@ -24,6 +24,16 @@ type TemplatesRepo struct {
 		}
 	}
 
+	FindAllCall struct {
+		Receives struct {
+			Connection models.ConnectionInterface
+		}
+		Returns struct {
+			Templates []models.Template
+			Error     error
+		}
+	}
+
 	FindByIDCall struct {
 		Receives struct {
 			Connection models.ConnectionInterface
@@ -76,6 +86,12 @@ func (tr *TemplatesRepo) Destroy(conn models.ConnectionInterface, templateID str
 	return tr.DestroyCall.Returns.Error
 }
 
+func (tr *TemplatesRepo) FindAll(conn models.ConnectionInterface) ([]models.Template, error) {
+	tr.FindAllCall.Receives.Connection = conn
+
+	return tr.FindAllCall.Returns.Templates, tr.FindAllCall.Returns.Error
+}
+
 func (tr *TemplatesRepo) FindByID(conn models.ConnectionInterface, templateID string) (models.Template, error) {
 	tr.FindByIDCall.Receives.Connection = conn
 	tr.FindByIDCall.Receives.TemplateID = templateID
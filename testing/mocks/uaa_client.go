@@ -25,13 +25,16 @@ type ZonedUAAClient struct {
 	}
 
 	GetClientTokenCall struct {
-		Receives struct {
+		CallCount int
+		Receives  struct {
 			Host string
 		}
 		Returns struct {
 			Token string
 			Error error
 		}
+
+		Hook func()
 	}
 
 	UsersEmailsByIDsCall struct {
@@ -64,8 +67,13 @@ func (c *ZonedUAAClient) UsersGUIDsByScope(token, scope string) ([]string, error
 }
 
 func (c *ZonedUAAClient) GetClientToken(host string) (string, error) {
+	c.GetClientTokenCall.CallCount++
 	c.GetClientTokenCall.Receives.Host = host
 
+	if c.GetClientTokenCall.Hook != nil {
+		c.GetClientTokenCall.Hook()
+	}
+
 	return c.GetClientTokenCall.Returns.Token, c.GetClientTokenCall.Returns.Error
 }
 
@@ -0,0 +1,47 @@
+package mocks
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type Unsubscriber struct {
+	FindCall struct {
+		Receives struct {
+			Connection    services.ConnectionInterface
+			UnsubscribeID string
+		}
+		Returns struct {
+			Unsubscription services.Unsubscription
+			Error          error
+		}
+	}
+
+	UnsubscribeCall struct {
+		CallCount int
+		Receives  struct {
+			Connection    services.ConnectionInterface
+			UnsubscribeID string
+		}
+		Returns struct {
+			Unsubscription services.Unsubscription
+			Error          error
+		}
+	}
+}
+
+func NewUnsubscriber() *Unsubscriber {
+	return &Unsubscriber{}
+}
+
+func (u *Unsubscriber) Find(conn services.ConnectionInterface, unsubscribeID string) (services.Unsubscription, error) {
+	u.FindCall.Receives.Connection = conn
+	u.FindCall.Receives.UnsubscribeID = unsubscribeID
+
+	return u.FindCall.Returns.Unsubscription, u.FindCall.Returns.Error
+}
+
+func (u *Unsubscriber) Unsubscribe(conn services.ConnectionInterface, unsubscribeID string) (services.Unsubscription, error) {
+	u.UnsubscribeCall.CallCount++
+	u.UnsubscribeCall.Receives.Connection = conn
+	u.UnsubscribeCall.Receives.UnsubscribeID = unsubscribeID
+
+	return u.UnsubscribeCall.Returns.Unsubscription, u.UnsubscribeCall.Returns.Error
+}
@@ -4,9 +4,11 @@ import "github.com/cloudfoundry-incubator/notifications/uaa"
 
 type UserLoader struct {
 	LoadCall struct {
-		Receives struct {
-			UserGUIDs []string
-			Token     string
+		CallCount int
+		Receives  struct {
+			UserGUIDs       []string
+			UserGUIDBatches [][]string
+			Token           string
 		}
 		Returns struct {
 			Users map[string]uaa.User
@@ -20,7 +22,9 @@ func NewUserLoader() *UserLoader {
 }
 
 func (ul *UserLoader) Load(userGUIDs []string, token string) (map[string]uaa.User, error) {
+	ul.LoadCall.CallCount++
 	ul.LoadCall.Receives.UserGUIDs = userGUIDs
+	ul.LoadCall.Receives.UserGUIDBatches = append(ul.LoadCall.Receives.UserGUIDBatches, userGUIDs)
 	ul.LoadCall.Receives.Token = token
 
 	return ul.LoadCall.Returns.Users, ul.LoadCall.Returns.Error
@@ -2,7 +2,8 @@ package mocks
 
 type TokenLoader struct {
 	LoadCall struct {
-		Receives struct {
+		CallCount int
+		Receives  struct {
 			UAAHost string
 		}
 		Returns struct {
@@ -17,6 +18,7 @@ func NewTokenLoader() *TokenLoader {
 }
 
 func (t *TokenLoader) Load(uaaHost string) (string, error) {
+	t.LoadCall.CallCount++
 	t.LoadCall.Receives.UAAHost = uaaHost
 
 	return t.LoadCall.Returns.Token, t.LoadCall.Returns.Error
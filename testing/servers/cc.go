@@ -25,6 +25,7 @@ func NewCC(userNameToIdMap map[string]string) CC {
 	}
 
 	router.HandleFunc("/v2/spaces/{guid}", cc.GetSpace).Methods("GET")
+	router.HandleFunc("/v2/spaces/{guid}/{role:developers|managers|auditors}", cc.GetSpaceRoleUsers).Methods("GET")
 	router.HandleFunc("/v2/organizations/{guid}/users", cc.GetOrgUsers).Methods("GET")
 	router.HandleFunc("/v2/organizations/{guid}/managers", cc.GetOrgManagers).Methods("GET")
 	router.HandleFunc("/v2/organizations/{guid}/auditors", cc.GetOrgAuditors).Methods("GET")
@@ -147,6 +148,26 @@ func (cc CC) GetOrgUsers(w http.ResponseWriter, req *http.Request) {
 		desiredUsers = []string{}
 	}
 
+	cc.writeUsers(w, desiredUsers)
+}
+
+func (cc CC) GetSpaceRoleUsers(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	var desiredUsers []string
+	switch vars["guid"] + "/" + vars["role"] {
+	case "space-123/developers":
+		desiredUsers = []string{"user-456"}
+	case "space-123/managers":
+		desiredUsers = []string{"user-789"}
+	default:
+		desiredUsers = []string{}
+	}
+
+	cc.writeUsers(w, desiredUsers)
+}
+
+func (cc CC) writeUsers(w http.ResponseWriter, desiredUsers []string) {
 	users := []map[string]interface{}{}
 	for _, userName := range desiredUsers {
 		guid, ok := cc.userNameToIdMap[userName]
@@ -416,6 +416,32 @@ var UAAUsers = map[string]map[string]interface{}{
 		"origin":    "uaa",
 		"schemas":   []string{"urn:scim:schemas:core:1.0"},
 	},
+	"user-654": {
+		"id": "user-654",
+		"meta": map[string]interface{}{
+			"version":      4,
+			"created":      "2014-07-16T21:00:09.021Z",
+			"lastModified": "2014-08-04T19:16:29.172Z",
+		},
+		"userName": "User654",
+		"name":     map[string]string{},
+		"emails": []map[string]string{
+			{"value": "user-654@example.com"},
+		},
+		"groups": []map[string]string{
+			{
+				"value":   "some-group-guid",
+				"display": "notifications.write",
+				"type":    "DIRECT",
+			},
+		},
+		"locale":    "de-DE",
+		"approvals": []interface{}{},
+		"active":    true,
+		"verified":  false,
+		"origin":    "uaa",
+		"schemas":   []string{"urn:scim:schemas:core:1.0"},
+	},
 	"091b6583-0933-4d17-a5b6-66e54666c88e": {
 		"id": "091b6583-0933-4d17-a5b6-66e54666c88e",
 		"meta": map[string]interface{}{
@@ -1,26 +1,101 @@
 package uaa
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"sync"
 	"time"
 
 	metrics "github.com/rcrowley/go-metrics"
 )
 
+// tokenRefreshMargin is how long before a cached token expires that it is
+// replaced, so that a token is never handed out just as it stops working.
+const tokenRefreshMargin = 1 * time.Minute
+
 type uaaClient interface {
 	GetClientToken(string) (string, error)
 }
 
+type clock interface {
+	Now() time.Time
+}
+
+type cachedToken struct {
+	value     string
+	refreshAt time.Time
+}
+
+type tokenRefresh struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// TokenLoader hands out client credentials tokens, keeping one per UAA host
+// (one per identity zone) until shortly before it expires. Concurrent loads
+// that miss the cache share a single token grant.
 type TokenLoader struct {
-	uaa uaaClient
+	uaa   uaaClient
+	clock clock
+
+	mutex     sync.Mutex
+	tokens    map[string]cachedToken
+	refreshes map[string]*tokenRefresh
 }
 
-func NewTokenLoader(uaa uaaClient) *TokenLoader {
+func NewTokenLoader(uaa uaaClient, clock clock) *TokenLoader {
 	return &TokenLoader{
-		uaa: uaa,
+		uaa:       uaa,
+		clock:     clock,
+		tokens:    map[string]cachedToken{},
+		refreshes: map[string]*tokenRefresh{},
 	}
 }
 
 func (t *TokenLoader) Load(uaaHost string) (string, error) {
+	t.mutex.Lock()
+	if token, ok := t.tokens[uaaHost]; ok && t.clock.Now().Before(token.refreshAt) {
+		t.mutex.Unlock()
+
+		metrics.GetOrRegisterCounter("notifications.uaa.client-token-cache.hit", nil).Inc(1)
+		return token.value, nil
+	}
+
+	metrics.GetOrRegisterCounter("notifications.uaa.client-token-cache.miss", nil).Inc(1)
+
+	refresh, inFlight := t.refreshes[uaaHost]
+	if !inFlight {
+		refresh = &tokenRefresh{done: make(chan struct{})}
+		t.refreshes[uaaHost] = refresh
+	}
+	t.mutex.Unlock()
+
+	if inFlight {
+		<-refresh.done
+		return refresh.token, refresh.err
+	}
+
+	refresh.token, refresh.err = t.fetch(uaaHost)
+
+	t.mutex.Lock()
+	delete(t.refreshes, uaaHost)
+	if refresh.err == nil {
+		if expiresAt, ok := tokenExpiry(refresh.token); ok {
+			t.tokens[uaaHost] = cachedToken{
+				value:     refresh.token,
+				refreshAt: expiresAt.Add(-tokenRefreshMargin),
+			}
+		}
+	}
+	t.mutex.Unlock()
+	close(refresh.done)
+
+	return refresh.token, refresh.err
+}
+
+func (t *TokenLoader) fetch(uaaHost string) (string, error) {
 	then := time.Now()
 
 	token, err := t.uaa.GetClientToken(uaaHost)
@@ -28,3 +103,28 @@ func (t *TokenLoader) Load(uaaHost string) (string, error) {
 	metrics.GetOrRegisterTimer("notifications.external-requests.uaa.client-token", nil).Update(time.Since(then))
 	return token, err
 }
+
+// tokenExpiry reads the exp claim of a token. The token came straight from
+// UAA, so its signature is not checked. Tokens without an exp claim are not
+// cached.
+func tokenExpiry(token string) (time.Time, bool) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segments[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	err = json.Unmarshal(payload, &claims)
+	if err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+
+	return time.Unix(claims.Exp, 0), true
+}
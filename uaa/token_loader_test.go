@@ -1,25 +1,142 @@
 package uaa_test
 
 import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
+	"github.com/rcrowley/go-metrics"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("TokenLoader", func() {
+	var (
+		uaaClient   *mocks.ZonedUAAClient
+		clock       *mocks.Clock
+		tokenLoader *uaa.TokenLoader
+		now         time.Time
+		token       string
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2015, time.June, 8, 14, 0, 0, 0, time.UTC)
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = now
+
+		token = helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "notifications",
+			"exp":       now.Add(10 * time.Minute).Unix(),
+		})
+
+		uaaClient = mocks.NewZonedUAAClient()
+		uaaClient.GetClientTokenCall.Returns.Token = token
+
+		tokenLoader = uaa.NewTokenLoader(uaaClient, clock)
+	})
+
 	Describe("#Load", func() {
 		It("Gets a zoned client token based on hostname", func() {
-			uaaClient := mocks.NewZonedUAAClient()
+			loaded, err := tokenLoader.Load("my-uaa-zone")
+			Expect(loaded).To(Equal(token))
+			Expect(err).To(BeNil())
+
+			Expect(uaaClient.GetClientTokenCall.Receives.Host).To(Equal("my-uaa-zone"))
+		})
+
+		It("reuses the token of a host until shortly before it expires", func() {
+			hits := metrics.GetOrRegisterCounter("notifications.uaa.client-token-cache.hit", nil).Count()
+			misses := metrics.GetOrRegisterCounter("notifications.uaa.client-token-cache.miss", nil).Count()
+
+			for i := 0; i < 3; i++ {
+				loaded, err := tokenLoader.Load("my-uaa-zone")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loaded).To(Equal(token))
+			}
+			Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(1))
+
+			clock.NowCall.Returns.Time = now.Add(8 * time.Minute)
+			_, err := tokenLoader.Load("my-uaa-zone")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(1))
+
+			clock.NowCall.Returns.Time = now.Add(9 * time.Minute)
+			_, err = tokenLoader.Load("my-uaa-zone")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(2))
+
+			Expect(metrics.GetOrRegisterCounter("notifications.uaa.client-token-cache.hit", nil).Count() - hits).To(BeEquivalentTo(3))
+			Expect(metrics.GetOrRegisterCounter("notifications.uaa.client-token-cache.miss", nil).Count() - misses).To(BeEquivalentTo(2))
+		})
+
+		It("keeps a separate token for each host", func() {
+			_, err := tokenLoader.Load("my-uaa-zone")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = tokenLoader.Load("other-uaa-zone")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(2))
+			Expect(uaaClient.GetClientTokenCall.Receives.Host).To(Equal("other-uaa-zone"))
+		})
+
+		It("shares a single token grant between concurrent loads", func() {
+			release := make(chan struct{})
+			uaaClient.GetClientTokenCall.Hook = func() {
+				<-release
+			}
+
+			var loads sync.WaitGroup
+			for i := 0; i < 5; i++ {
+				loads.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer loads.Done()
+
+					loaded, err := tokenLoader.Load("my-uaa-zone")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(loaded).To(Equal(token))
+				}()
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			loads.Wait()
+
+			Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(1))
+		})
+
+		It("does not cache a token without an expiry", func() {
 			uaaClient.GetClientTokenCall.Returns.Token = "my-fake-token"
 
-			tokenLoader := uaa.NewTokenLoader(uaaClient)
+			for i := 0; i < 2; i++ {
+				loaded, err := tokenLoader.Load("my-uaa-zone")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loaded).To(Equal("my-fake-token"))
+			}
 
-			token, err := tokenLoader.Load("my-uaa-zone")
-			Expect(token).To(Equal("my-fake-token"))
-			Expect(err).To(BeNil())
+			Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(2))
+		})
 
-			Expect(uaaClient.GetClientTokenCall.Receives.Host).To(Equal("my-uaa-zone"))
+		Context("when the token grant fails", func() {
+			It("returns the error and tries again on the next load", func() {
+				uaaClient.GetClientTokenCall.Returns.Error = errors.New("uaa is down")
+
+				_, err := tokenLoader.Load("my-uaa-zone")
+				Expect(err).To(MatchError(errors.New("uaa is down")))
+
+				uaaClient.GetClientTokenCall.Returns.Error = nil
+
+				loaded, err := tokenLoader.Load("my-uaa-zone")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loaded).To(Equal(token))
+				Expect(uaaClient.GetClientTokenCall.CallCount).To(Equal(2))
+			})
 		})
 	})
 })
@@ -47,24 +47,25 @@ func (z ZonedUAAClient) GetClientToken(host string) (string, error) {
 	return uaaClient.Clients.GetToken(z.clientID, z.clientSecret)
 }
 
+// UsersEmailsByIDs looks up the email addresses and the locale of the users
+// with the given IDs. The IDs are split across as many /Users requests as it
+// takes to keep each request URL under the length UAA accepts.
 func (z ZonedUAAClient) UsersEmailsByIDs(token string, ids ...string) ([]User, error) {
 	uaaHost, err := z.tokenHost(token)
 	if err != nil {
 		return nil, err
 	}
 
-	uaaClient := uaaSSOGolang.NewUAA("", uaaHost, z.clientID, z.clientSecret, "")
-	uaaClient.VerifySSL = z.verifySSL
-	uaaClient.SetToken(token)
+	client := uaaSSOGolang.NewClient(uaaHost, z.verifySSL).WithAuthorizationToken(token)
 
 	var myUsers []User
-	users, err := uaaClient.UsersEmailsByIDs(ids...)
-	if err != nil {
-		return myUsers, err
-	}
+	for _, path := range usersQueryPaths(uaaHost, ids) {
+		users, err := usersFromQuery(client, path)
+		if err != nil {
+			return myUsers, err
+		}
 
-	for _, user := range users {
-		myUsers = append(myUsers, newUserFromSSOGolangUser(user))
+		myUsers = append(myUsers, users...)
 	}
 
 	return myUsers, nil
@@ -134,6 +135,7 @@ func newUserFromSSOGolangUser(uaaUser uaaSSOGolang.User) User {
 type User struct {
 	ID     string
 	Emails []string
+	Locale string
 }
 
 type Failure struct {
@@ -0,0 +1,75 @@
+package uaa
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	uaaSSOGolang "github.com/pivotal-cf/uaa-sso-golang/uaa"
+)
+
+type usersResponse struct {
+	Resources []struct {
+		ID     string `json:"id"`
+		Locale string `json:"locale"`
+		Emails []struct {
+			Value string `json:"value"`
+		} `json:"emails"`
+	} `json:"resources"`
+}
+
+func usersQueryPaths(host string, ids []string) []string {
+	var filters []string
+	for _, id := range ids {
+		filters = append(filters, fmt.Sprintf(`Id eq "%s"`, id))
+	}
+
+	var paths []string
+	start := 0
+	for i := range filters {
+		if i > start && len(host+usersQueryPath(filters[start:i+1])) > uaaSSOGolang.MaxQueryLength {
+			paths = append(paths, usersQueryPath(filters[start:i]))
+			start = i
+		}
+	}
+
+	return append(paths, usersQueryPath(filters[start:]))
+}
+
+func usersQueryPath(filters []string) string {
+	return fmt.Sprintf("/Users?attributes=emails,id,locale&filter=%s", url.QueryEscape(strings.Join(filters, " or ")))
+}
+
+func usersFromQuery(client uaaSSOGolang.Client, path string) ([]User, error) {
+	code, body, err := client.MakeRequest("GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if code > 399 {
+		return nil, uaaSSOGolang.NewFailure(code, body)
+	}
+
+	var response usersResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	for _, resource := range response.Resources {
+		user := User{
+			ID:     resource.ID,
+			Locale: resource.Locale,
+		}
+
+		for _, email := range resource.Emails {
+			user.Emails = append(user.Emails, email.Value)
+		}
+
+		users = append(users, user)
+	}
+
+	return users, nil
+}
@@ -64,4 +64,93 @@ var _ = Describe("Getting a Message's status", func() {
 			}))
 		})
 	})
+
+	It("Gets the status of a scheduled message", func() {
+		var messageGUID string
+
+		By("sending a notification to an email address with a send_at", func() {
+			status, responses, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", support.Notify{
+				Text:    "some text for the email",
+				Subject: "my-scheduled-subject",
+				SendAt:  time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0].Status).To(Equal("scheduled"))
+
+			messageGUID = responses[0].NotificationID
+		})
+
+		By("checking that the message waits for its send_at", func() {
+			Consistently(func() MessageReponse {
+				status, message, err := client.Messages.Get(clientToken.Access, messageGUID)
+				return MessageReponse{
+					Status:  status,
+					Message: message,
+					Error:   err,
+				}
+			}, 2*time.Second).Should(Equal(MessageReponse{
+				Status:  http.StatusOK,
+				Message: support.Message{Status: "scheduled"},
+				Error:   nil,
+			}))
+		})
+	})
+
+	It("Cancels a scheduled message", func() {
+		var messageGUID string
+
+		By("scheduling a notification to an email address", func() {
+			status, responses, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", support.Notify{
+				Text:    "some text for the email",
+				Subject: "my-cancelled-subject",
+				SendAt:  time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(responses).To(HaveLen(1))
+
+			messageGUID = responses[0].NotificationID
+		})
+
+		By("cancelling the message", func() {
+			status, err := client.Messages.Cancel(clientToken.Access, messageGUID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+
+		By("checking that the message has been cancelled", func() {
+			status, message, err := client.Messages.Get(clientToken.Access, messageGUID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(message.Status).To(Equal("cancelled"))
+		})
+
+		By("cancelling the message again", func() {
+			status, err := client.Messages.Cancel(clientToken.Access, messageGUID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	It("Does not cancel a message that does not exist", func() {
+		status, err := client.Messages.Cancel(clientToken.Access, "missing-message-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNotFound))
+	})
+
+	It("Rejects a send_at beyond the maximum horizon", func() {
+		status, _, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", support.Notify{
+			Text:    "some text for the email",
+			Subject: "my-scheduled-subject",
+			SendAt:  time.Now().Add(365 * 24 * time.Hour).Format(time.RFC3339),
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(422))
+	})
 })
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/acceptance/support"
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Getting a Send's status", func() {
+	var (
+		clientToken uaa.Token
+		client      *support.Client
+	)
+
+	BeforeEach(func() {
+		clientToken = GetClientTokenFor("notification-sender")
+		client = support.NewClient(Servers.Notifications.URL())
+	})
+
+	It("rolls up the status of a send to an email address", func() {
+		var sendID, messageID string
+
+		By("sending a notification to an email address", func() {
+			status, responses, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", support.Notify{
+				HTML:    "<header>this is an acceptance test</header>",
+				Text:    "some text for the email",
+				Subject: "my-special-subject",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(responses).To(HaveLen(1))
+			Expect(GUIDRegex.MatchString(responses[0].SendID)).To(BeTrue())
+
+			sendID = responses[0].SendID
+			messageID = responses[0].NotificationID
+		})
+
+		By("polling the sends endpoint until the send has finished", func() {
+			var send support.Send
+
+			Eventually(func() *string {
+				status, s, err := client.Sends.Get(clientToken.Access, sendID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal(http.StatusOK))
+
+				send = s
+				return send.FinishedAt
+			}, 10*time.Second).ShouldNot(BeNil())
+
+			Expect(send.ID).To(Equal(sendID))
+			Expect(send.Audience).To(Equal("email"))
+			Expect(send.Counts).To(Equal(support.SendCounts{Delivered: 1}))
+			Expect(send.Recipients.Total).To(Equal(1))
+			Expect(send.Recipients.Resources).To(HaveLen(1))
+			Expect(send.Recipients.Resources[0].MessageID).To(Equal(messageID))
+			Expect(send.Recipients.Resources[0].Status).To(Equal("delivered"))
+		})
+	})
+
+	It("rolls up the status of a send to a space", func() {
+		var sendID string
+
+		By("sending a notification to a space", func() {
+			status, response, err := client.Notify.Space(clientToken.Access, "space-123", support.Notify{
+				HTML:    "this is a space test",
+				Text:    "this is a space test",
+				Subject: "space-subject",
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusAccepted))
+
+			sendID = response.SendID
+		})
+
+		By("polling the sends endpoint until every recipient has been tried", func() {
+			var send support.Send
+
+			Eventually(func() *string {
+				status, s, err := client.Sends.Get(clientToken.Access, sendID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status).To(Equal(http.StatusOK))
+
+				send = s
+				return send.FinishedAt
+			}, 10*time.Second).ShouldNot(BeNil())
+
+			Expect(send.Audience).To(Equal("space"))
+			Expect(send.Counts.Queued).To(Equal(0))
+			Expect(send.Counts.Delivered).To(Equal(1))
+			Expect(send.Recipients.Total).To(Equal(3))
+			Expect(send.Recipients.Resources).To(HaveLen(3))
+		})
+	})
+
+	It("cancels a scheduled send", func() {
+		var sendID string
+
+		By("scheduling a notification to a space", func() {
+			status, response, err := client.Notify.Space(clientToken.Access, "space-123", support.Notify{
+				HTML:    "this is a space test",
+				Text:    "this is a space test",
+				Subject: "space-subject",
+				SendAt:  time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusAccepted))
+
+			sendID = response.SendID
+		})
+
+		By("waiting for the recipients of the send to be resolved", func() {
+			Eventually(func() int {
+				_, send, err := client.Sends.Get(clientToken.Access, sendID)
+				Expect(err).NotTo(HaveOccurred())
+
+				return send.Counts.Queued
+			}, 10*time.Second).Should(Equal(3))
+		})
+
+		By("cancelling the send", func() {
+			status, cancelled, err := client.Sends.Cancel(clientToken.Access, sendID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(cancelled).To(Equal(3))
+		})
+
+		By("checking that the send has finished without delivering anything", func() {
+			status, send, err := client.Sends.Get(clientToken.Access, sendID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			Expect(send.FinishedAt).NotTo(BeNil())
+			Expect(send.Counts).To(Equal(support.SendCounts{Cancelled: 3}))
+		})
+	})
+
+	It("returns a 404 when the send does not exist", func() {
+		status, _, err := client.Sends.Get(clientToken.Access, "missing-send-id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNotFound))
+	})
+})
@@ -127,4 +127,50 @@ var _ = Describe("Send a notification to an email", func() {
 			Expect(acceptanceNotification.Critical).To(BeFalse())
 		})
 	})
+	It("sends a notification only once when a request with an Idempotency-Key is retried", func() {
+		clientToken := GetClientTokenFor("notifications-sender")
+		client := support.NewClient(Servers.Notifications.URL())
+
+		notify := support.Notify{
+			Text:           "some text for the email",
+			Subject:        "my-idempotent-subject",
+			IdempotencyKey: "some-idempotency-key",
+		}
+
+		var original []support.NotifyResponse
+
+		By("sending a notification with an Idempotency-Key", func() {
+			status, responses, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", notify)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(responses).To(HaveLen(1))
+
+			original = responses
+		})
+
+		By("retrying the same request", func() {
+			status, responses, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", notify)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(responses).To(Equal(original))
+		})
+
+		By("reusing the key for a different request", func() {
+			notify.Subject = "some-other-subject"
+
+			status, _, err := client.Notify.Email(clientToken.Access, "John User <user@example.com>", notify)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusConflict))
+		})
+
+		By("verifying the message was sent once", func() {
+			Eventually(func() int {
+				return len(Servers.SMTP.Deliveries)
+			}, 10*time.Second).Should(Equal(1))
+
+			Consistently(func() int {
+				return len(Servers.SMTP.Deliveries)
+			}, 2*time.Second).Should(Equal(1))
+		})
+	})
 })
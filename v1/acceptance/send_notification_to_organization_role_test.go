@@ -63,23 +63,17 @@ var _ = Describe("Sending notifications to users with certain roles in an organi
 	})
 
 	It("sends a notification to each OrgManager in an organization", func() {
-		var response support.NotifyResponse
-
 		By("sending a notification to the OrgManager role", func() {
-			status, responses, err := client.Notify.OrganizationRole(clientToken.Access, "org-123", "OrgManager", support.Notify{
+			status, response, err := client.Notify.OrganizationRole(clientToken.Access, "org-123", "OrgManager", support.Notify{
 				KindID:  "organization-role-test",
 				HTML:    "this is another organization role test",
 				Text:    "this is an organization role test",
 				Subject: "organization-role-subject",
 			})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-			Expect(responses).To(HaveLen(1))
-
-			response = responses[0]
-			Expect(response.Recipient).To(Equal("user-456"))
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(GUIDRegex.MatchString(response.SendID)).To(BeTrue())
 			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
 			Expect(response.VCAPRequestID).To(Equal("some-totally-fake-vcap-request-id"))
 		})
 
@@ -97,7 +91,7 @@ var _ = Describe("Sending notifications to users with certain roles in an organi
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + response.NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp(`^X-CF-Notification-ID: [0-9a-f-]+$`)))
 			Expect(data).To(ContainElement("Subject: Phone home organization-role-subject"))
 			Expect(data).To(ContainElement("Cat"))
 			Expect(data).To(ContainElement("this is an organization role test"))
@@ -110,24 +104,18 @@ var _ = Describe("Sending notifications to users with certain roles in an organi
 	})
 
 	It("sends a notification to each auditor in an organization", func() {
-		var response support.NotifyResponse
-
 		By("sending a notification to the OrgAuditor role", func() {
-			status, responses, err := client.Notify.OrganizationRole(clientToken.Access, "org-123", "OrgAuditor", support.Notify{
+			status, response, err := client.Notify.OrganizationRole(clientToken.Access, "org-123", "OrgAuditor", support.Notify{
 				KindID:  "organization-role-test",
 				HTML:    "this is another organization role test",
 				Text:    "this is an organization role test",
 				Subject: "organization-role-subject",
 			})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-			Expect(responses).To(HaveLen(1))
-
-			response = responses[0]
-
-			Expect(response.Recipient).To(Equal("user-123"))
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(GUIDRegex.MatchString(response.SendID)).To(BeTrue())
 			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
+			Expect(response.VCAPRequestID).To(Equal("some-totally-fake-vcap-request-id"))
 		})
 
 		By("confirming that the messages were sent", func() {
@@ -144,7 +132,7 @@ var _ = Describe("Sending notifications to users with certain roles in an organi
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + response.NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp(`^X-CF-Notification-ID: [0-9a-f-]+$`)))
 			Expect(data).To(ContainElement("Subject: Phone home organization-role-subject"))
 			Expect(data).To(ContainElement("Cat"))
 			Expect(data).To(ContainElement("this is an organization role test"))
@@ -157,24 +145,18 @@ var _ = Describe("Sending notifications to users with certain roles in an organi
 	})
 
 	It("sends a notification to each billing manager in an organization", func() {
-		var response support.NotifyResponse
-
 		By("sending a notification to the BillingManager role", func() {
-			status, responses, err := client.Notify.OrganizationRole(clientToken.Access, "org-123", "BillingManager", support.Notify{
+			status, response, err := client.Notify.OrganizationRole(clientToken.Access, "org-123", "BillingManager", support.Notify{
 				KindID:  "organization-role-test",
 				HTML:    "this is another organization role test",
 				Text:    "this is an organization role test",
 				Subject: "organization-role-subject",
 			})
 			Expect(err).NotTo(HaveOccurred())
-			Expect(status).To(Equal(http.StatusOK))
-			Expect(responses).To(HaveLen(1))
-
-			response = responses[0]
-
-			Expect(response.Recipient).To(Equal("user-111"))
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(GUIDRegex.MatchString(response.SendID)).To(BeTrue())
 			Expect(response.Status).To(Equal("queued"))
-			Expect(GUIDRegex.MatchString(response.NotificationID)).To(BeTrue())
+			Expect(response.VCAPRequestID).To(Equal("some-totally-fake-vcap-request-id"))
 		})
 
 		By("confirming that the messages were sent", func() {
@@ -191,7 +173,7 @@ var _ = Describe("Sending notifications to users with certain roles in an organi
 
 			data := strings.Split(string(delivery.Data), "\n")
 			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
-			Expect(data).To(ContainElement("X-CF-Notification-ID: " + response.NotificationID))
+			Expect(data).To(ContainElement(MatchRegexp(`^X-CF-Notification-ID: [0-9a-f-]+$`)))
 			Expect(data).To(ContainElement("Subject: Phone home organization-role-subject"))
 			Expect(data).To(ContainElement("Cat"))
 			Expect(data).To(ContainElement("this is an organization role test"))
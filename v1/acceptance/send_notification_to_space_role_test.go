@@ -0,0 +1,106 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/acceptance/support"
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sending notifications to users with certain roles in a space", func() {
+	var (
+		templateID  string
+		clientID    string
+		clientToken uaa.Token
+		client      *support.Client
+	)
+
+	BeforeEach(func() {
+		clientID = "notifications-sender"
+		clientToken = GetClientTokenFor(clientID)
+		client = support.NewClient(Servers.Notifications.URL())
+		Servers.SMTP.Reset()
+
+		By("registering a notification", func() {
+			status, err := client.Notifications.Register(clientToken.Access, support.RegisterClient{
+				SourceName: "Notifications Sender",
+				Notifications: map[string]support.RegisterNotification{
+					"space-role-test": {
+						Description: "Space Role Test",
+					},
+				},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+
+		By("creating a template", func() {
+			var status int
+			var err error
+			status, templateID, err = client.Templates.Create(clientToken.Access, support.Template{
+				Name:    "ET",
+				Subject: "Phone home {{.Subject}}",
+				HTML:    "<h1>Cat</h1>{{.HTML}}<header>{{.Endorsement}}</header>",
+				Text:    "Cat\n{{.Text}}\n{{.Endorsement}}",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+			Expect(templateID).NotTo(Equal(""))
+		})
+
+		By("assigning the template to a client", func() {
+			status, err := client.Templates.AssignToClient(clientToken.Access, clientID, templateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	It("sends a notification to each SpaceDeveloper in a space", func() {
+		By("sending a notification to the SpaceDeveloper role", func() {
+			status, response, err := client.Notify.SpaceRole(clientToken.Access, "space-123", "SpaceDeveloper", support.Notify{
+				KindID:  "space-role-test",
+				HTML:    "this is another space role test",
+				Text:    "this is a space role test",
+				Subject: "space-role-subject",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusAccepted))
+			Expect(GUIDRegex.MatchString(response.SendID)).To(BeTrue())
+			Expect(response.Status).To(Equal("queued"))
+		})
+
+		By("confirming the message was sent to the developer only", func() {
+			Eventually(func() int {
+				return len(Servers.SMTP.Deliveries)
+			}, 10*time.Second).Should(Equal(1))
+			delivery := Servers.SMTP.Deliveries[0]
+
+			Expect(delivery.Recipients).To(Equal([]string{"user-456@example.com"}))
+
+			data := strings.Split(string(delivery.Data), "\n")
+			Expect(data).To(ContainElement("X-CF-Client-ID: notifications-sender"))
+			Expect(data).To(ContainElement("Subject: Phone home space-role-subject"))
+			Expect(data).To(ContainElement("this is a space role test"))
+
+			body := strings.Replace(string(delivery.Data), "=\n", "", -1)
+			Expect(body).To(ContainSubstring(`You received this message because you are a SpaceDeveloper in the "notifications-service" space in the "notifications-service" organization.`))
+		})
+	})
+
+	It("rejects a role that is not a space role", func() {
+		status, _, err := client.Notify.SpaceRole(clientToken.Access, "space-123", "OrgManager", support.Notify{
+			KindID:  "space-role-test",
+			HTML:    "this is another space role test",
+			Text:    "this is a space role test",
+			Subject: "space-role-subject",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(422))
+	})
+})
@@ -16,9 +16,11 @@ type Client struct {
 	hasNoRouter   bool
 	Notifications *NotificationsService
 	Templates     *TemplatesService
+	Partials      *PartialsService
 	Notify        *NotifyService
 	Preferences   *PreferencesService
 	Messages      *MessagesService
+	Sends         *SendsService
 	API           *APIService
 	HTTPClient    *http.Client
 }
@@ -38,6 +40,9 @@ func NewClient(host string) *Client {
 			client: client,
 		},
 	}
+	client.Partials = &PartialsService{
+		client: client,
+	}
 	client.Notify = &NotifyService{
 		client: client,
 	}
@@ -47,6 +52,9 @@ func NewClient(host string) *Client {
 	client.Messages = &MessagesService{
 		client: client,
 	}
+	client.Sends = &SendsService{
+		client: client,
+	}
 	client.API = &APIService{
 		client: client,
 	}
@@ -57,6 +65,10 @@ func NewClient(host string) *Client {
 }
 
 func (c Client) makeRequest(method, path string, content io.Reader, token string) (int, []byte, error) {
+	return c.makeRequestWithHeaders(method, path, content, token, nil)
+}
+
+func (c Client) makeRequestWithHeaders(method, path string, content io.Reader, token string, headers map[string]string) (int, []byte, error) {
 	request, err := http.NewRequest(method, path, content)
 	if err != nil {
 		return 0, []byte{}, err
@@ -64,6 +76,9 @@ func (c Client) makeRequest(method, path string, content io.Reader, token string
 	c.printRequest(request)
 
 	request.Header.Set("Authorization", "Bearer "+token)
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
 	if c.hasNoRouter {
 		request.Header.Set("X-Vcap-Request-Id", "some-totally-fake-vcap-request-id")
 	}
@@ -170,6 +185,10 @@ func (c Client) TemplatePath(templateID string) string {
 	return c.host + "/templates/" + templateID
 }
 
+func (c Client) TemplateVersionsPath(templateID string) string {
+	return c.host + "/templates/" + templateID + "/versions"
+}
+
 func (c Client) TemplateAssociationsPath(templateID string) string {
 	return c.host + "/templates/" + templateID + "/associations"
 }
@@ -178,6 +197,18 @@ func (c Client) ClientsTemplatePath(clientID string) string {
 	return c.host + "/clients/" + clientID + "/template"
 }
 
+func (c Client) ClientsLayoutPath(clientID string) string {
+	return c.host + "/clients/" + clientID + "/layout"
+}
+
+func (c Client) PartialsPath() string {
+	return c.host + "/partials"
+}
+
+func (c Client) PartialPath(name string) string {
+	return c.PartialsPath() + "/" + name
+}
+
 func (c Client) ClientsNotificationsTemplatePath(clientID, notificationID string) string {
 	return c.host + "/clients/" + clientID + "/notifications/" + notificationID + "/template"
 }
@@ -186,6 +217,14 @@ func (c Client) MessagePath(messageID string) string {
 	return c.host + "/messages/" + messageID
 }
 
+func (c Client) SendPath(sendID string) string {
+	return c.host + "/sends/" + sendID
+}
+
+func (c Client) SendCancelPath(sendID string) string {
+	return c.SendPath(sendID) + "/cancel"
+}
+
 func (c Client) InfoPath() string {
 	return c.host + "/info"
 }
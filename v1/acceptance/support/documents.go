@@ -13,11 +13,39 @@ type PreferenceDocument struct {
 }
 
 type Template struct {
+	Name     string                    `json:"name"`
+	Subject  string                    `json:"subject"`
+	Text     string                    `json:"text"`
+	HTML     string                    `json:"html"`
+	Metadata map[string]interface{}    `json:"metadata,omitempty"`
+	Locales  map[string]TemplateLocale `json:"locales,omitempty"`
+}
+
+type Partial struct {
+	Name string `json:"name"`
+	Body string `json:"body"`
+}
+
+type TemplateLocale struct {
+	Subject string `json:"subject,omitempty"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+}
+
+type TemplateVersion struct {
+	Version  int                    `json:"version"`
 	Name     string                 `json:"name"`
 	Subject  string                 `json:"subject"`
 	Text     string                 `json:"text"`
 	HTML     string                 `json:"html"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Metadata map[string]interface{} `json:"metadata"`
+	ClientID string                 `json:"client_id"`
+}
+
+type TemplateDiff struct {
+	From    int               `json:"from"`
+	To      int               `json:"to"`
+	Changes map[string]string `json:"changes"`
 }
 
 type TemplateListItem struct {
@@ -42,6 +70,8 @@ type notifyRequest struct {
 	Text    string `json:"text,omitempty"`
 	KindID  string `json:"kind_id,omitempty"`
 	ReplyTo string `json:"reply_to,omitempty"`
+	SendAt  string `json:"send_at,omitempty"`
+	Locale  string `json:"locale,omitempty"`
 }
 
 type NotifyResponse struct {
@@ -49,6 +79,38 @@ type NotifyResponse struct {
 	Recipient      string `json:"recipient"`
 	NotificationID string `json:"notification_id"`
 	VCAPRequestID  string `json:"vcap_request_id"`
+	SendID         string `json:"send_id"`
+}
+
+type SendResponse struct {
+	SendID        string `json:"send_id"`
+	Status        string `json:"status"`
+	VCAPRequestID string `json:"vcap_request_id"`
+}
+
+type Send struct {
+	ID         string     `json:"id"`
+	Audience   string     `json:"audience"`
+	Counts     SendCounts `json:"counts"`
+	FinishedAt *string    `json:"finished_at"`
+	Recipients struct {
+		Total     int             `json:"total"`
+		Resources []SendRecipient `json:"resources"`
+	} `json:"recipients"`
+}
+
+type SendCounts struct {
+	Queued        int `json:"queued"`
+	Delivered     int `json:"delivered"`
+	Failed        int `json:"failed"`
+	Undeliverable int `json:"undeliverable"`
+	Cancelled     int `json:"cancelled"`
+}
+
+type SendRecipient struct {
+	MessageID string `json:"message_id"`
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
 }
 
 type Message struct {
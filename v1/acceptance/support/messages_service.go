@@ -17,3 +17,8 @@ func (s MessagesService) Get(token, messageGUID string) (int, Message, error) {
 	err = json.Unmarshal(body, &message)
 	return status, message, err
 }
+
+func (s MessagesService) Cancel(token, messageGUID string) (int, error) {
+	status, _, err := s.client.makeRequest("DELETE", s.client.MessagePath(messageGUID), nil, token)
+	return status, err
+}
@@ -17,6 +17,9 @@ type Notify struct {
 	KindID            string
 	ReplyTo           string
 	SourceDescription string
+	SendAt            string
+	Locale            string
+	IdempotencyKey    string
 }
 
 func (nr notifyRequest) Merge(n Notify) notifyRequest {
@@ -25,20 +28,31 @@ func (nr notifyRequest) Merge(n Notify) notifyRequest {
 	nr.Text = n.Text
 	nr.KindID = n.KindID
 	nr.ReplyTo = n.ReplyTo
+	nr.SendAt = n.SendAt
+	nr.Locale = n.Locale
 
 	return nr
 }
 
-func (s NotifyService) notify(token, path string, notify Notify, reqBody notifyRequest) (int, []NotifyResponse, error) {
-	var responses []NotifyResponse
-
+func (s NotifyService) post(token, path string, notify Notify, reqBody notifyRequest) (int, []byte, error) {
 	reqBody = reqBody.Merge(notify)
 	body, err := json.Marshal(reqBody)
 	if err != nil {
-		return 0, responses, err
+		return 0, nil, err
 	}
 
-	status, responseBody, err := s.client.makeRequest("POST", path, bytes.NewBuffer(body), token)
+	headers := map[string]string{}
+	if notify.IdempotencyKey != "" {
+		headers["Idempotency-Key"] = notify.IdempotencyKey
+	}
+
+	return s.client.makeRequestWithHeaders("POST", path, bytes.NewBuffer(body), token, headers)
+}
+
+func (s NotifyService) notify(token, path string, notify Notify, reqBody notifyRequest) (int, []NotifyResponse, error) {
+	var responses []NotifyResponse
+
+	status, responseBody, err := s.post(token, path, notify, reqBody)
 	if err != nil {
 		return 0, responses, err
 	}
@@ -53,12 +67,30 @@ func (s NotifyService) notify(token, path string, notify Notify, reqBody notifyR
 	return status, responses, nil
 }
 
+func (s NotifyService) send(token, path string, notify Notify, reqBody notifyRequest) (int, SendResponse, error) {
+	var response SendResponse
+
+	status, responseBody, err := s.post(token, path, notify, reqBody)
+	if err != nil {
+		return 0, response, err
+	}
+
+	if status == http.StatusAccepted {
+		err = json.Unmarshal(responseBody, &response)
+		if err != nil {
+			return 0, response, err
+		}
+	}
+
+	return status, response, nil
+}
+
 func (s NotifyService) User(token, userGUID string, notify Notify) (int, []NotifyResponse, error) {
 	return s.notify(token, s.client.UsersPath(userGUID), notify, notifyRequest{})
 }
 
-func (s NotifyService) AllUsers(token string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.EveryonePath(), notify, notifyRequest{})
+func (s NotifyService) AllUsers(token string, notify Notify) (int, SendResponse, error) {
+	return s.send(token, s.client.EveryonePath(), notify, notifyRequest{})
 }
 
 func (s NotifyService) Email(token, email string, notify Notify) (int, []NotifyResponse, error) {
@@ -67,20 +99,26 @@ func (s NotifyService) Email(token, email string, notify Notify) (int, []NotifyR
 	})
 }
 
-func (s NotifyService) OrganizationRole(token, organizationGUID, role string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{
+func (s NotifyService) OrganizationRole(token, organizationGUID, role string, notify Notify) (int, SendResponse, error) {
+	return s.send(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{
 		Role: role,
 	})
 }
 
-func (s NotifyService) Organization(token, organizationGUID string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{})
+func (s NotifyService) Organization(token, organizationGUID string, notify Notify) (int, SendResponse, error) {
+	return s.send(token, s.client.OrganizationsPath(organizationGUID), notify, notifyRequest{})
+}
+
+func (s NotifyService) Scope(token, scope string, notify Notify) (int, SendResponse, error) {
+	return s.send(token, s.client.ScopesPath(scope), notify, notifyRequest{})
 }
 
-func (s NotifyService) Scope(token, scope string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.ScopesPath(scope), notify, notifyRequest{})
+func (s NotifyService) SpaceRole(token, spaceGUID, role string, notify Notify) (int, SendResponse, error) {
+	return s.send(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{
+		Role: role,
+	})
 }
 
-func (s NotifyService) Space(token, spaceGUID string, notify Notify) (int, []NotifyResponse, error) {
-	return s.notify(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{})
+func (s NotifyService) Space(token, spaceGUID string, notify Notify) (int, SendResponse, error) {
+	return s.send(token, s.client.SpacesPath(spaceGUID), notify, notifyRequest{})
 }
@@ -0,0 +1,94 @@
+package support
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+type PartialsService struct {
+	client *Client
+}
+
+func (s PartialsService) Set(token, name, body string) (int, Partial, error) {
+	var partial Partial
+
+	content, err := json.Marshal(map[string]string{
+		"body": body,
+	})
+	if err != nil {
+		return 0, partial, err
+	}
+
+	status, responseBody, err := s.client.makeRequest("PUT", s.client.PartialPath(name), bytes.NewBuffer(content), token)
+	if err != nil {
+		return 0, partial, err
+	}
+
+	if status == 200 {
+		err = json.Unmarshal(responseBody, &partial)
+		if err != nil {
+			return 0, partial, err
+		}
+	}
+
+	return status, partial, nil
+}
+
+func (s PartialsService) Get(token, name string) (int, Partial, error) {
+	var partial Partial
+
+	status, body, err := s.client.makeRequest("GET", s.client.PartialPath(name), nil, token)
+	if err != nil {
+		return 0, partial, err
+	}
+
+	err = json.Unmarshal(body, &partial)
+	if err != nil {
+		return 0, partial, err
+	}
+
+	return status, partial, nil
+}
+
+func (s PartialsService) List(token string) (int, []Partial, error) {
+	var list struct {
+		Partials []Partial `json:"partials"`
+	}
+
+	status, body, err := s.client.makeRequest("GET", s.client.PartialsPath(), nil, token)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(body, &list)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return status, list.Partials, nil
+}
+
+func (s PartialsService) Delete(token, name string) (int, error) {
+	status, _, err := s.client.makeRequest("DELETE", s.client.PartialPath(name), nil, token)
+	if err != nil {
+		return 0, err
+	}
+
+	return status, nil
+}
+
+func (s PartialsService) AssignLayoutToClient(token, clientID, layout string) (int, error) {
+	body, err := json.Marshal(map[string]string{
+		"layout": layout,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	status, _, err := s.client.makeRequest("PUT", s.client.ClientsLayoutPath(clientID), bytes.NewBuffer(body), token)
+	if err != nil {
+		return 0, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,36 @@
+package support
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type SendsService struct {
+	client *Client
+}
+
+func (s SendsService) Get(token, sendID string) (int, Send, error) {
+	var send Send
+
+	status, body, err := s.client.makeRequest("GET", s.client.SendPath(sendID), nil, token)
+	if err != nil {
+		return status, send, err
+	}
+
+	err = json.Unmarshal(body, &send)
+	return status, send, err
+}
+
+func (s SendsService) Cancel(token, sendID string) (int, int, error) {
+	var response struct {
+		Cancelled int `json:"cancelled"`
+	}
+
+	status, body, err := s.client.makeRequest("POST", s.client.SendCancelPath(sendID), nil, token)
+	if err != nil || status != http.StatusOK {
+		return status, 0, err
+	}
+
+	err = json.Unmarshal(body, &response)
+	return status, response.Cancelled, err
+}
@@ -3,6 +3,7 @@ package support
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 )
 
 type TemplatesService struct {
@@ -109,6 +110,58 @@ func (s TemplatesService) List(token string) (int, []TemplateListItem, error) {
 	return status, list, err
 }
 
+func (s TemplatesService) Versions(token, templateID string) (int, []TemplateVersion, error) {
+	var versions struct {
+		Versions []TemplateVersion `json:"versions"`
+	}
+
+	status, body, err := s.client.makeRequest("GET", s.client.TemplateVersionsPath(templateID), nil, token)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	err = json.Unmarshal(body, &versions)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return status, versions.Versions, nil
+}
+
+func (s TemplatesService) Diff(token, templateID string, from, to int) (int, TemplateDiff, error) {
+	var diff TemplateDiff
+
+	path := fmt.Sprintf("%s/diff?from=%d&to=%d", s.client.TemplateVersionsPath(templateID), from, to)
+	status, body, err := s.client.makeRequest("GET", path, nil, token)
+	if err != nil {
+		return 0, diff, err
+	}
+
+	err = json.Unmarshal(body, &diff)
+	if err != nil {
+		return 0, diff, err
+	}
+
+	return status, diff, nil
+}
+
+func (s TemplatesService) Rollback(token, templateID string, version int) (int, TemplateVersion, error) {
+	var templateVersion TemplateVersion
+
+	path := fmt.Sprintf("%s/%d/rollback", s.client.TemplateVersionsPath(templateID), version)
+	status, body, err := s.client.makeRequest("POST", path, nil, token)
+	if err != nil {
+		return 0, templateVersion, err
+	}
+
+	err = json.Unmarshal(body, &templateVersion)
+	if err != nil {
+		return 0, templateVersion, err
+	}
+
+	return status, templateVersion, nil
+}
+
 func (s TemplatesService) AssignToClient(token, clientID, templateID string) (int, error) {
 	body, err := json.Marshal(map[string]string{
 		"template": templateID,
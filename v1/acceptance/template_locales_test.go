@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/acceptance/support"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template Locales", func() {
+	var (
+		templateID  string
+		clientID    = "notifications-sender"
+		clientToken = GetClientTokenFor(clientID)
+		client      = support.NewClient(Servers.Notifications.URL())
+	)
+
+	BeforeEach(func() {
+		By("registering a notification", func() {
+			code, err := client.Notifications.Register(clientToken.Access, support.RegisterClient{
+				SourceName: "Notifications Sender",
+				Notifications: map[string]support.RegisterNotification{
+					"acceptance-test": {
+						Description: "Acceptance Test",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(code).To(Equal(http.StatusNoContent))
+		})
+
+		By("creating a template with locale variants", func() {
+			var status int
+			var err error
+			status, templateID, err = client.Templates.Create(clientToken.Access, support.Template{
+				Name:    "Star Wars",
+				Subject: "Awesomeness {{.Subject}}",
+				HTML:    "<p>Millenium Falcon</p>{{.HTML}}",
+				Text:    "Millenium Falcon\n{{.Text}}",
+				Locales: map[string]support.TemplateLocale{
+					"de": {
+						Subject: "Großartigkeit {{.Subject}}",
+						Text:    "Rasender Falke\n{{.Text}}",
+					},
+					"ja": {
+						Subject: "素晴らしさ {{.Subject}}",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+		})
+
+		By("assigning the template to a client", func() {
+			status, err := client.Templates.AssignToClient(clientToken.Access, clientID, templateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	It("shows every variant of the template", func() {
+		status, template, err := client.Templates.Get(clientToken.Access, templateID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(template.Locales).To(Equal(map[string]support.TemplateLocale{
+			"de": {
+				Subject: "Großartigkeit {{.Subject}}",
+				Text:    "Rasender Falke\n{{.Text}}",
+			},
+			"ja": {
+				Subject: "素晴らしさ {{.Subject}}",
+			},
+		}))
+	})
+
+	It("renders the variant for the locale of the user", func() {
+		status, _, err := client.Notify.User(clientToken.Access, "user-654", support.Notify{
+			KindID:  "acceptance-test",
+			Text:    "hello from the acceptance test",
+			Subject: "my-special-subject",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(func() int {
+			return len(Servers.SMTP.Deliveries)
+		}, 10*time.Second).Should(Equal(1))
+
+		data := strings.Split(string(Servers.SMTP.Deliveries[0].Data), "\n")
+		Expect(data).To(ContainElement("Subject: Großartigkeit my-special-subject"))
+		Expect(data).To(ContainElement("Rasender Falke"))
+	})
+
+	It("renders the variant for the locale given in the request", func() {
+		status, _, err := client.Notify.User(clientToken.Access, "user-654", support.Notify{
+			KindID:  "acceptance-test",
+			Text:    "hello from the acceptance test",
+			Subject: "my-special-subject",
+			Locale:  "ja-JP",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(func() int {
+			return len(Servers.SMTP.Deliveries)
+		}, 10*time.Second).Should(Equal(1))
+
+		data := strings.Split(string(Servers.SMTP.Deliveries[0].Data), "\n")
+		Expect(data).To(ContainElement("Subject: 素晴らしさ my-special-subject"))
+		Expect(data).To(ContainElement("Millenium Falcon"))
+	})
+
+	It("renders the template itself when the user has no locale", func() {
+		status, _, err := client.Notify.User(clientToken.Access, "user-123", support.Notify{
+			KindID:  "acceptance-test",
+			Text:    "hello from the acceptance test",
+			Subject: "my-special-subject",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(func() int {
+			return len(Servers.SMTP.Deliveries)
+		}, 10*time.Second).Should(Equal(1))
+
+		data := strings.Split(string(Servers.SMTP.Deliveries[0].Data), "\n")
+		Expect(data).To(ContainElement("Subject: Awesomeness my-special-subject"))
+		Expect(data).To(ContainElement("Millenium Falcon"))
+	})
+})
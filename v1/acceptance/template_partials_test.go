@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/acceptance/support"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template Partials", func() {
+	var (
+		templateID  string
+		clientID    = "notifications-sender"
+		clientToken = GetClientTokenFor(clientID)
+		client      = support.NewClient(Servers.Notifications.URL())
+	)
+
+	BeforeEach(func() {
+		By("registering a notification", func() {
+			code, err := client.Notifications.Register(clientToken.Access, support.RegisterClient{
+				SourceName: "Notifications Sender",
+				Notifications: map[string]support.RegisterNotification{
+					"acceptance-test": {
+						Description: "Acceptance Test",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(code).To(Equal(http.StatusNoContent))
+		})
+
+		By("creating the partials", func() {
+			status, _, err := client.Partials.Set(clientToken.Access, "footer", "Unsubscribe at example.com")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+
+			status, _, err = client.Partials.Set(clientToken.Access, "brand-layout", "<html>\n<main>\n{{.HTMLComponents.BodyContent}}\n</main>\n{{template \"footer\" .}}\n</html>")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+		})
+
+		By("creating a template that includes a partial", func() {
+			var status int
+			var err error
+			status, templateID, err = client.Templates.Create(clientToken.Access, support.Template{
+				Name:    "Star Wars",
+				Subject: "Awesomeness {{.Subject}}",
+				HTML:    "<p>Millenium Falcon</p>",
+				Text:    "{{.Text}}\n{{template \"footer\" .}}",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusCreated))
+		})
+
+		By("assigning the template and the layout to the client", func() {
+			status, err := client.Templates.AssignToClient(clientToken.Access, clientID, templateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+
+			status, err = client.Partials.AssignLayoutToClient(clientToken.Access, clientID, "brand-layout")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+	})
+
+	It("lists the partials", func() {
+		status, partials, err := client.Partials.List(clientToken.Access)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(partials).To(Equal([]support.Partial{
+			{Name: "brand-layout", Body: "<html>\n<main>\n{{.HTMLComponents.BodyContent}}\n</main>\n{{template \"footer\" .}}\n</html>"},
+			{Name: "footer", Body: "Unsubscribe at example.com"},
+		}))
+
+		status, partial, err := client.Partials.Get(clientToken.Access, "footer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+		Expect(partial).To(Equal(support.Partial{Name: "footer", Body: "Unsubscribe at example.com"}))
+	})
+
+	It("renders the partials and wraps the html in the layout of the client", func() {
+		status, _, err := client.Notify.User(clientToken.Access, "user-123", support.Notify{
+			KindID:  "acceptance-test",
+			Text:    "hello from the acceptance test",
+			HTML:    "<p>this is html</p>",
+			Subject: "my-special-subject",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusOK))
+
+		Eventually(func() int {
+			return len(Servers.SMTP.Deliveries)
+		}, 10*time.Second).Should(Equal(1))
+
+		data := strings.Split(string(Servers.SMTP.Deliveries[0].Data), "\n")
+		Expect(data).To(ContainElement("hello from the acceptance test"))
+		Expect(data).To(ContainElement("<main>"))
+		Expect(data).To(ContainElement("<p>Millenium Falcon</p>"))
+		Expect(data).To(ContainElement("</main>"))
+		Expect(data).To(ContainElement("Unsubscribe at example.com"))
+	})
+
+	It("refuses to delete the partials that are in use", func() {
+		status, err := client.Partials.Delete(clientToken.Access, "footer")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusConflict))
+
+		status, err = client.Partials.Delete(clientToken.Access, "brand-layout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusConflict))
+
+		status, err = client.Partials.AssignLayoutToClient(clientToken.Access, clientID, "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNoContent))
+
+		status, err = client.Partials.Delete(clientToken.Access, "brand-layout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNoContent))
+
+		status, _, err = client.Partials.Get(clientToken.Access, "brand-layout")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/acceptance/support"
+	"github.com/pivotal-cf/uaa-sso-golang/uaa"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template versions", func() {
+	var (
+		client      *support.Client
+		clientToken uaa.Token
+		templateID  string
+	)
+
+	BeforeEach(func() {
+		clientToken = GetClientTokenFor("notifications-admin")
+		client = support.NewClient(Servers.Notifications.URL())
+
+		var err error
+		_, templateID, err = client.Templates.Create(clientToken.Access, support.Template{
+			Name:    "Versioned",
+			Subject: "First subject",
+			HTML:    "<p>First</p>",
+			Text:    "First",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("keeps every version of the template and rolls back to an earlier one", func() {
+		By("updating the template", func() {
+			status, err := client.Templates.Update(clientToken.Access, templateID, support.Template{
+				Name:    "Versioned",
+				Subject: "Second subject",
+				HTML:    "<p>Second</p>",
+				Text:    "Second",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusNoContent))
+		})
+
+		By("listing the versions", func() {
+			status, versions, err := client.Templates.Versions(clientToken.Access, templateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(versions).To(HaveLen(2))
+
+			Expect(versions[0].Version).To(Equal(2))
+			Expect(versions[0].Subject).To(Equal("Second subject"))
+			Expect(versions[0].ClientID).To(Equal("notifications-admin"))
+			Expect(versions[1].Version).To(Equal(1))
+			Expect(versions[1].Subject).To(Equal("First subject"))
+		})
+
+		By("comparing the versions", func() {
+			status, diff, err := client.Templates.Diff(clientToken.Access, templateID, 1, 2)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(diff.Changes).To(Equal(map[string]string{
+				"subject": "-First subject\n+Second subject\n",
+				"html":    "-<p>First</p>\n+<p>Second</p>\n",
+				"text":    "-First\n+Second\n",
+			}))
+		})
+
+		By("rolling back to the first version", func() {
+			status, version, err := client.Templates.Rollback(clientToken.Access, templateID, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(version.Version).To(Equal(3))
+		})
+
+		By("verifying that the template has the content of the first version", func() {
+			status, template, err := client.Templates.Get(clientToken.Access, templateID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(template.Subject).To(Equal("First subject"))
+			Expect(template.HTML).To(Equal("<p>First</p>"))
+		})
+	})
+
+	It("returns a 404 when rolling back to a version that does not exist", func() {
+		status, _, err := client.Templates.Rollback(clientToken.Access, templateID, 42)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status).To(Equal(http.StatusNotFound))
+	})
+})
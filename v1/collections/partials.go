@@ -0,0 +1,235 @@
+package collections
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type PartialInUseError struct {
+	Err error
+}
+
+func (e PartialInUseError) Error() string {
+	return e.Err.Error()
+}
+
+type LayoutAssignmentError struct {
+	Err error
+}
+
+func (e LayoutAssignmentError) Error() string {
+	return e.Err.Error()
+}
+
+type partialsRepository interface {
+	Find(connection models.ConnectionInterface, name string) (models.Partial, error)
+	List(connection models.ConnectionInterface) ([]models.Partial, error)
+	Upsert(connection models.ConnectionInterface, partial models.Partial) (models.Partial, error)
+	Destroy(connection models.ConnectionInterface, name string) error
+}
+
+type layoutClientsRepository interface {
+	Find(connection models.ConnectionInterface, clientID string) (models.Client, error)
+	FindAllByLayout(connection models.ConnectionInterface, layout string) ([]models.Client, error)
+	Update(connection models.ConnectionInterface, client models.Client) (models.Client, error)
+}
+
+type allTemplatesRepository interface {
+	FindAll(connection models.ConnectionInterface) ([]models.Template, error)
+}
+
+type Partial struct {
+	Name      string
+	Body      string
+	UpdatedAt time.Time
+}
+
+// PartialsCollection manages the partials that templates include and that
+// clients use as the layout of their HTML.
+type PartialsCollection struct {
+	clientsRepo   layoutClientsRepository
+	templatesRepo allTemplatesRepository
+	partialsRepo  partialsRepository
+}
+
+func NewPartialsCollection(clientsRepo layoutClientsRepository, templatesRepo allTemplatesRepository, partialsRepo partialsRepository) PartialsCollection {
+	return PartialsCollection{
+		clientsRepo:   clientsRepo,
+		templatesRepo: templatesRepo,
+		partialsRepo:  partialsRepo,
+	}
+}
+
+// Set creates the partial, or replaces the body of the partial with the same
+// name.
+func (c PartialsCollection) Set(conn ConnectionInterface, partial Partial) (Partial, error) {
+	stored, err := c.partialsRepo.Upsert(conn, models.Partial{
+		Name: partial.Name,
+		Body: partial.Body,
+	})
+	if err != nil {
+		return Partial{}, err
+	}
+
+	return newPartial(stored), nil
+}
+
+func (c PartialsCollection) Get(conn ConnectionInterface, name string) (Partial, error) {
+	partial, err := c.partialsRepo.Find(conn, name)
+	if err != nil {
+		return Partial{}, err
+	}
+
+	return newPartial(partial), nil
+}
+
+func (c PartialsCollection) List(conn ConnectionInterface) ([]Partial, error) {
+	partials, err := c.partialsRepo.List(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []Partial{}
+	for _, partial := range partials {
+		list = append(list, newPartial(partial))
+	}
+
+	return list, nil
+}
+
+// Delete removes the partial unless a client uses it as its layout, or a
+// template or another partial includes it. The checks and the removal are
+// made in one transaction.
+func (c PartialsCollection) Delete(conn ConnectionInterface, name string) error {
+	transaction := conn.Transaction()
+	if err := transaction.Begin(); err != nil {
+		return err
+	}
+
+	err := c.delete(transaction, name)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	return transaction.Commit()
+}
+
+func (c PartialsCollection) delete(conn models.ConnectionInterface, name string) error {
+	_, err := c.partialsRepo.Find(conn, name)
+	if err != nil {
+		return err
+	}
+
+	clients, err := c.clientsRepo.FindAllByLayout(conn, name)
+	if err != nil {
+		return err
+	}
+
+	if len(clients) > 0 {
+		return PartialInUseError{fmt.Errorf("Partial %q is the layout of client %q", name, clients[0].ID)}
+	}
+
+	templates, err := c.templatesRepo.FindAll(conn)
+	if err != nil {
+		return err
+	}
+
+	for _, template := range templates {
+		sources := []string{template.Subject, template.Text, template.HTML}
+
+		variants, err := models.ParseTemplateLocales(template.Locales)
+		if err != nil {
+			return err
+		}
+
+		for _, variant := range variants {
+			sources = append(sources, variant.Subject, variant.Text, variant.HTML)
+		}
+
+		if includesPartial(name, sources...) {
+			return PartialInUseError{fmt.Errorf("Partial %q is used by template %q", name, template.ID)}
+		}
+	}
+
+	partials, err := c.partialsRepo.List(conn)
+	if err != nil {
+		return err
+	}
+
+	for _, partial := range partials {
+		if partial.Name != name && includesPartial(name, partial.Body) {
+			return PartialInUseError{fmt.Errorf("Partial %q is used by partial %q", name, partial.Name)}
+		}
+	}
+
+	return c.partialsRepo.Destroy(conn, name)
+}
+
+// LayoutOf returns the name of the partial the client uses as its layout. It
+// is empty when the client uses the default layout or is not registered.
+func (c PartialsCollection) LayoutOf(conn ConnectionInterface, clientID string) (string, error) {
+	client, err := c.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return client.Layout, nil
+}
+
+// AssignLayoutToClient makes the partial the layout of the client. An empty
+// layout restores the default one.
+func (c PartialsCollection) AssignLayoutToClient(conn ConnectionInterface, clientID, layout string) error {
+	client, err := c.clientsRepo.Find(conn, clientID)
+	if err != nil {
+		return err
+	}
+
+	if layout != "" {
+		_, err = c.partialsRepo.Find(conn, layout)
+		if err != nil {
+			if _, ok := err.(models.NotFoundError); ok {
+				return LayoutAssignmentError{fmt.Errorf("No partial named %q", layout)}
+			}
+			return err
+		}
+	}
+
+	client.Layout = layout
+
+	_, err = c.clientsRepo.Update(conn, client)
+	return err
+}
+
+// includesPartial reports whether any of the sources includes the partial.
+// A source that cannot be parsed includes nothing, since it cannot be
+// rendered either.
+func includesPartial(name string, sources ...string) bool {
+	for _, source := range sources {
+		references, err := models.PartialReferences(source)
+		if err != nil {
+			continue
+		}
+
+		for _, reference := range references {
+			if reference == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func newPartial(partial models.Partial) Partial {
+	return Partial{
+		Name:      partial.Name,
+		Body:      partial.Body,
+		UpdatedAt: partial.UpdatedAt,
+	}
+}
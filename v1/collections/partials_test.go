@@ -0,0 +1,319 @@
+package collections_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialsCollection", func() {
+	var (
+		clientsRepo   *mocks.ClientsRepository
+		templatesRepo *mocks.TemplatesRepo
+		partialsRepo  *mocks.PartialsRepo
+		conn          *mocks.Connection
+		transaction   *mocks.Transaction
+		updatedAt     time.Time
+
+		collection collections.PartialsCollection
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+		updatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+		clientsRepo = mocks.NewClientsRepository()
+		templatesRepo = mocks.NewTemplatesRepo()
+		partialsRepo = mocks.NewPartialsRepo()
+
+		collection = collections.NewPartialsCollection(clientsRepo, templatesRepo, partialsRepo)
+	})
+
+	Describe("Set", func() {
+		It("stores the partial", func() {
+			partialsRepo.UpsertCall.Returns.Partial = models.Partial{
+				Primary:   4,
+				Name:      "footer",
+				Body:      "<p>the footer</p>",
+				UpdatedAt: updatedAt,
+			}
+
+			partial, err := collection.Set(conn, collections.Partial{
+				Name: "footer",
+				Body: "<p>the footer</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.Partial{
+				Name:      "footer",
+				Body:      "<p>the footer</p>",
+				UpdatedAt: updatedAt,
+			}))
+
+			Expect(partialsRepo.UpsertCall.Receives.Connection).To(Equal(conn))
+			Expect(partialsRepo.UpsertCall.Receives.Partial).To(Equal(models.Partial{
+				Name: "footer",
+				Body: "<p>the footer</p>",
+			}))
+		})
+
+		It("returns the errors from the repo", func() {
+			partialsRepo.UpsertCall.Returns.Error = errors.New("boom")
+
+			_, err := collection.Set(conn, collections.Partial{Name: "footer"})
+			Expect(err).To(MatchError(errors.New("boom")))
+		})
+	})
+
+	Describe("Get", func() {
+		It("finds the partial by name", func() {
+			partialsRepo.FindCall.Returns.Partial = models.Partial{
+				Name:      "footer",
+				Body:      "<p>the footer</p>",
+				UpdatedAt: updatedAt,
+			}
+
+			partial, err := collection.Get(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial).To(Equal(collections.Partial{
+				Name:      "footer",
+				Body:      "<p>the footer</p>",
+				UpdatedAt: updatedAt,
+			}))
+			Expect(partialsRepo.FindCall.Receives.Name).To(Equal("footer"))
+		})
+
+		It("returns the errors from the repo", func() {
+			partialsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := collection.Get(conn, "footer")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the partials", func() {
+			partialsRepo.ListCall.Returns.Partials = []models.Partial{
+				{Name: "footer", Body: "the footer"},
+				{Name: "header", Body: "the header"},
+			}
+
+			partials, err := collection.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal([]collections.Partial{
+				{Name: "footer", Body: "the footer"},
+				{Name: "header", Body: "the header"},
+			}))
+		})
+
+		It("returns an empty list when there are no partials", func() {
+			partialsRepo.ListCall.Returns.Partials = []models.Partial{}
+
+			partials, err := collection.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(Equal([]collections.Partial{}))
+		})
+
+		It("returns the errors from the repo", func() {
+			partialsRepo.ListCall.Returns.Error = errors.New("boom")
+
+			_, err := collection.List(conn)
+			Expect(err).To(MatchError(errors.New("boom")))
+		})
+	})
+
+	Describe("Delete", func() {
+		BeforeEach(func() {
+			partialsRepo.FindCall.Returns.Partial = models.Partial{Name: "footer"}
+			partialsRepo.ListCall.Returns.Partials = []models.Partial{
+				{Name: "footer", Body: "the footer"},
+				{Name: "header", Body: "the header"},
+			}
+			templatesRepo.FindAllCall.Returns.Templates = []models.Template{
+				{ID: "some-template", HTML: `{{template "header" .}}{{.HTML}}`},
+			}
+		})
+
+		It("deletes a partial that nothing uses", func() {
+			err := collection.Delete(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(clientsRepo.FindAllByLayoutCall.Receives.Layout).To(Equal("footer"))
+			Expect(partialsRepo.DestroyCall.Receives.Connection).To(Equal(transaction))
+			Expect(partialsRepo.DestroyCall.Receives.Name).To(Equal("footer"))
+		})
+
+		It("checks that the partial is unused and deletes it in one transaction", func() {
+			err := collection.Delete(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(partialsRepo.FindCall.Receives.Connection).To(Equal(transaction))
+			Expect(clientsRepo.FindAllByLayoutCall.Receives.Connection).To(Equal(transaction))
+			Expect(templatesRepo.FindAllCall.Receives.Connection).To(Equal(transaction))
+			Expect(partialsRepo.ListCall.Receives.Connection).To(Equal(transaction))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		It("reports that the partial cannot be found", func() {
+			partialsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := collection.Delete(conn, "missing")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			Expect(partialsRepo.DestroyCall.WasCalled).To(BeFalse())
+		})
+
+		It("refuses to delete the layout of a client", func() {
+			clientsRepo.FindAllByLayoutCall.Returns.Clients = []models.Client{{ID: "some-client"}}
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.PartialInUseError{Err: errors.New(`Partial "footer" is the layout of client "some-client"`)}))
+			Expect(partialsRepo.DestroyCall.WasCalled).To(BeFalse())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+
+		It("refuses to delete a partial that a template includes", func() {
+			templatesRepo.FindAllCall.Returns.Templates = append(templatesRepo.FindAllCall.Returns.Templates, models.Template{
+				ID:   "other-template",
+				Text: `{{.Text}}{{template "footer" .}}`,
+			})
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.PartialInUseError{Err: errors.New(`Partial "footer" is used by template "other-template"`)}))
+			Expect(partialsRepo.DestroyCall.WasCalled).To(BeFalse())
+		})
+
+		It("refuses to delete a partial that a locale variant of a template includes", func() {
+			templatesRepo.FindAllCall.Returns.Templates = append(templatesRepo.FindAllCall.Returns.Templates, models.Template{
+				ID:      "other-template",
+				Locales: `{"de": {"html": "{{template \"footer\" .}}"}}`,
+			})
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.PartialInUseError{Err: errors.New(`Partial "footer" is used by template "other-template"`)}))
+		})
+
+		It("refuses to delete a partial that another partial includes", func() {
+			partialsRepo.ListCall.Returns.Partials = append(partialsRepo.ListCall.Returns.Partials, models.Partial{
+				Name: "layout",
+				Body: `{{.HTMLComponents.BodyContent}}{{template "footer" .}}`,
+			})
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(collections.PartialInUseError{Err: errors.New(`Partial "footer" is used by partial "layout"`)}))
+			Expect(partialsRepo.DestroyCall.WasCalled).To(BeFalse())
+		})
+
+		It("deletes a partial that only includes itself", func() {
+			partialsRepo.ListCall.Returns.Partials = []models.Partial{
+				{Name: "footer", Body: `{{if .Text}}{{template "footer" .}}{{end}}`},
+			}
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partialsRepo.DestroyCall.WasCalled).To(BeTrue())
+		})
+
+		It("returns the errors from the repos", func() {
+			templatesRepo.FindAllCall.Returns.Error = errors.New("boom")
+
+			err := collection.Delete(conn, "footer")
+			Expect(err).To(MatchError(errors.New("boom")))
+			Expect(partialsRepo.DestroyCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Describe("LayoutOf", func() {
+		It("returns the layout of the client", func() {
+			clientsRepo.FindCall.Returns.Client = models.Client{
+				ID:     "my-client",
+				Layout: "my-layout",
+			}
+
+			layout, err := collection.LayoutOf(conn, "my-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layout).To(Equal("my-layout"))
+
+			Expect(clientsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+		})
+
+		It("returns no layout for a client that is not registered", func() {
+			clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			layout, err := collection.LayoutOf(conn, "missing-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(layout).To(BeEmpty())
+		})
+
+		It("propagates other errors", func() {
+			clientsRepo.FindCall.Returns.Error = errors.New("db is down")
+
+			_, err := collection.LayoutOf(conn, "my-client")
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+	})
+
+	Describe("AssignLayoutToClient", func() {
+		BeforeEach(func() {
+			clientsRepo.FindCall.Returns.Client = models.Client{
+				ID:         "my-client",
+				TemplateID: "my-template",
+			}
+		})
+
+		It("assigns the layout to the client", func() {
+			err := collection.AssignLayoutToClient(conn, "my-client", "my-layout")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(clientsRepo.FindCall.Receives.ClientID).To(Equal("my-client"))
+			Expect(partialsRepo.FindCall.Receives.Name).To(Equal("my-layout"))
+			Expect(clientsRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(clientsRepo.UpdateCall.Receives.Client).To(Equal(models.Client{
+				ID:         "my-client",
+				TemplateID: "my-template",
+				Layout:     "my-layout",
+			}))
+		})
+
+		It("restores the default layout when the layout is empty", func() {
+			clientsRepo.FindCall.Returns.Client.Layout = "my-layout"
+
+			err := collection.AssignLayoutToClient(conn, "my-client", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(partialsRepo.FindCall.Receives.Name).To(BeEmpty())
+			Expect(clientsRepo.UpdateCall.Receives.Client.Layout).To(BeEmpty())
+		})
+
+		It("reports that the client cannot be found", func() {
+			clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := collection.AssignLayoutToClient(conn, "missing-client", "my-layout")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+
+		It("reports that the partial cannot be found", func() {
+			partialsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := collection.AssignLayoutToClient(conn, "my-client", "missing-layout")
+			Expect(err).To(MatchError(collections.LayoutAssignmentError{Err: errors.New(`No partial named "missing-layout"`)}))
+		})
+
+		It("returns the errors from the clients repo", func() {
+			clientsRepo.UpdateCall.Returns.Error = errors.New("boom")
+
+			err := collection.AssignLayoutToClient(conn, "my-client", "my-layout")
+			Expect(err).To(MatchError(errors.New("boom")))
+		})
+	})
+})
@@ -40,6 +40,10 @@ type templatesRepository interface {
 	Destroy(connection models.ConnectionInterface, templateID string) error
 }
 
+type templateVersionsRepository interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+}
+
 type TemplateAssociation struct {
 	ClientID       string
 	NotificationID string
@@ -55,16 +59,18 @@ type Template struct {
 }
 
 type TemplatesCollection struct {
-	clientsRepo   clientsRepository
-	kindsRepo     kindsRepository
-	templatesRepo templatesRepository
+	clientsRepo          clientsRepository
+	kindsRepo            kindsRepository
+	templatesRepo        templatesRepository
+	templateVersionsRepo templateVersionsRepository
 }
 
-func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository) TemplatesCollection {
+func NewTemplatesCollection(clientsRepo clientsRepository, kindsRepo kindsRepository, templatesRepo templatesRepository, templateVersionsRepo templateVersionsRepository) TemplatesCollection {
 	return TemplatesCollection{
-		clientsRepo:   clientsRepo,
-		kindsRepo:     kindsRepo,
-		templatesRepo: templatesRepo,
+		clientsRepo:          clientsRepo,
+		kindsRepo:            kindsRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
 	}
 }
 
@@ -173,7 +179,9 @@ func (c TemplatesCollection) ListAssociations(conn ConnectionInterface, template
 	return associations, nil
 }
 
-func (c TemplatesCollection) Create(connection ConnectionInterface, template Template) (Template, error) {
+// Create stores the template and records it as its first version, authored
+// by the client.
+func (c TemplatesCollection) Create(connection ConnectionInterface, template Template, clientID string) (Template, error) {
 	tmpl, err := c.templatesRepo.Create(connection, models.Template{
 		Name:     template.Name,
 		Text:     template.Text,
@@ -185,6 +193,11 @@ func (c TemplatesCollection) Create(connection ConnectionInterface, template Tem
 		return Template{}, err
 	}
 
+	_, err = c.templateVersionsRepo.Create(connection, models.NewTemplateVersion(tmpl, clientID))
+	if err != nil {
+		return Template{}, err
+	}
+
 	return Template{
 		ID:       tmpl.ID,
 		Name:     tmpl.Name,
@@ -13,10 +13,11 @@ import (
 
 var _ = Describe("TemplatesCollection", func() {
 	var (
-		kindsRepo     *mocks.KindsRepo
-		clientsRepo   *mocks.ClientsRepository
-		templatesRepo *mocks.TemplatesRepo
-		conn          *mocks.Connection
+		kindsRepo            *mocks.KindsRepo
+		clientsRepo          *mocks.ClientsRepository
+		templatesRepo        *mocks.TemplatesRepo
+		templateVersionsRepo *mocks.TemplateVersionsRepo
+		conn                 *mocks.Connection
 
 		collection collections.TemplatesCollection
 	)
@@ -27,8 +28,9 @@ var _ = Describe("TemplatesCollection", func() {
 		clientsRepo = mocks.NewClientsRepository()
 		kindsRepo = mocks.NewKindsRepo()
 		templatesRepo = mocks.NewTemplatesRepo()
+		templateVersionsRepo = mocks.NewTemplateVersionsRepo()
 
-		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+		collection = collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
 	})
 
 	Describe("AssignToClient", func() {
@@ -334,6 +336,7 @@ var _ = Describe("TemplatesCollection", func() {
 				HTML:     "some-html",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
+				Version:  1,
 			}
 
 			template, err := collection.Create(conn, collections.Template{
@@ -342,7 +345,7 @@ var _ = Describe("TemplatesCollection", func() {
 				HTML:     "some-html",
 				Subject:  "some-subject",
 				Metadata: "some-metadata",
-			})
+			}, "some-client")
 			Expect(err).ToNot(HaveOccurred())
 			Expect(template).To(Equal(collections.Template{
 				ID:       "some-template-guid",
@@ -363,10 +366,41 @@ var _ = Describe("TemplatesCollection", func() {
 			}))
 		})
 
+		It("records the template as its first version", func() {
+			templatesRepo.CreateCall.Returns.Template = models.Template{
+				ID:      "some-template-guid",
+				Name:    "some-template-name",
+				Text:    "some-text",
+				Version: 1,
+			}
+
+			_, err := collection.Create(conn, collections.Template{
+				Name: "some-template-name",
+				Text: "some-text",
+			}, "some-client")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(templateVersionsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(templateVersionsRepo.CreateCall.Receives.Version).To(Equal(models.TemplateVersion{
+				TemplateID: "some-template-guid",
+				Version:    1,
+				Name:       "some-template-name",
+				Text:       "some-text",
+				ClientID:   "some-client",
+			}))
+		})
+
 		It("propagates errors from repo", func() {
 			templatesRepo.CreateCall.Returns.Error = errors.New("Boom!")
 
-			_, err := collection.Create(conn, collections.Template{})
+			_, err := collection.Create(conn, collections.Template{}, "some-client")
+			Expect(err).To(Equal(errors.New("Boom!")))
+		})
+
+		It("propagates errors from the versions repo", func() {
+			templateVersionsRepo.CreateCall.Returns.Error = errors.New("Boom!")
+
+			_, err := collection.Create(conn, collections.Template{}, "some-client")
 			Expect(err).To(Equal(errors.New("Boom!")))
 		})
 	})
@@ -4,7 +4,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
 )
 
 type ClientsRepo struct{}
@@ -42,7 +43,9 @@ func (repo ClientsRepo) Update(conn ConnectionInterface, client Client) (Client,
 			return client, err
 		}
 
+		// Registration leaves both assignments alone.
 		client.TemplateID = existingClient.TemplateID
+		client.Layout = existingClient.Layout
 	}
 
 	_, err := conn.Update(&client)
@@ -83,10 +86,20 @@ func (repo ClientsRepo) FindAllByTemplateID(conn ConnectionInterface, templateID
 	return clients, nil
 }
 
+func (repo ClientsRepo) FindAllByLayout(conn ConnectionInterface, layout string) ([]Client, error) {
+	clients := []Client{}
+	_, err := conn.Select(&clients, "SELECT * FROM `clients` WHERE `layout` = ?", layout)
+	if err != nil {
+		return clients, err
+	}
+
+	return clients, nil
+}
+
 func (repo ClientsRepo) create(conn ConnectionInterface, client Client) (Client, error) {
 	err := conn.Insert(&client)
 	if err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
+		if db.IsDuplicateError(err) {
 			err = DuplicateError{errors.New("duplicate record")}
 		}
 		return client, err
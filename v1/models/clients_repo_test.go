@@ -128,6 +128,24 @@ var _ = Describe("ClientsRepo", func() {
 				Expect(client.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
 			})
 
+			It("keeps the existing layout", func() {
+				client, err := repo.Upsert(conn, models.Client{
+					ID:         "my-client",
+					TemplateID: "my-template",
+					Layout:     "my-layout",
+				})
+				if err != nil {
+					panic(err)
+				}
+
+				client.TemplateID = models.DoNotSetTemplateID
+				client.Layout = ""
+
+				client, err = repo.Update(conn, client)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(client.Layout).To(Equal("my-layout"))
+			})
+
 			It("returns a record not found error when the record does not exist", func() {
 				client := models.Client{
 					ID: "my-client",
@@ -222,4 +240,27 @@ var _ = Describe("ClientsRepo", func() {
 			Expect(returnedClients).To(ContainElement(client1))
 		})
 	})
+
+	Describe("FindAllByLayout", func() {
+		It("returns a list of clients with the given layout", func() {
+			client1, err := repo.Upsert(conn, models.Client{
+				ID:     "i-have-a-layout",
+				Layout: "some-layout",
+			})
+			if err != nil {
+				panic(err)
+			}
+			_, err = repo.Upsert(conn, models.Client{
+				ID: "i-dont-have-a-layout",
+			})
+			if err != nil {
+				panic(err)
+			}
+
+			returnedClients, err := repo.FindAllByLayout(conn, "some-layout")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(returnedClients).To(HaveLen(1))
+			Expect(returnedClients).To(ContainElement(client1))
+		})
+	})
 })
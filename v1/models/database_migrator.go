@@ -6,20 +6,21 @@ import (
 	"io/ioutil"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/db"
 	sql_migrate "github.com/rubenv/sql-migrate"
 )
 
 type DatabaseMigrator struct {
 }
 
-func (d DatabaseMigrator) Migrate(db *sql.DB, migrationsPath string) {
+func (d DatabaseMigrator) Migrate(sqlDB *sql.DB, migrationsPath string) {
 	sql_migrate.SetTable("notifications_model_migrations")
 
 	migrations := &sql_migrate.FileMigrationSource{
 		Dir: migrationsPath,
 	}
 
-	_, err := sql_migrate.Exec(db, "mysql", migrations, sql_migrate.Up)
+	_, err := sql_migrate.Exec(sqlDB, db.DriverName(sqlDB), migrations, sql_migrate.Up)
 	if err != nil {
 		panic(err)
 	}
@@ -27,6 +28,7 @@ func (d DatabaseMigrator) Migrate(db *sql.DB, migrationsPath string) {
 
 func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath string) {
 	repo := NewTemplatesRepo()
+	versionsRepo := NewTemplateVersionsRepo()
 	bytes, err := ioutil.ReadFile(defaultTemplatePath)
 	if err != nil {
 		panic(err)
@@ -52,7 +54,7 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 			panic(err)
 		}
 
-		_, err = repo.Create(conn, Template{
+		createdTemplate, err := repo.Create(conn, Template{
 			ID:       DefaultTemplateID,
 			Name:     template.Name,
 			Subject:  template.Subject,
@@ -64,17 +66,34 @@ func (d DatabaseMigrator) Seed(database DatabaseInterface, defaultTemplatePath s
 			panic(err)
 		}
 
+		_, err = versionsRepo.Create(conn, NewTemplateVersion(createdTemplate, ""))
+		if err != nil {
+			panic(err)
+		}
+
 		return
 	}
 
 	if !existingTemplate.Overridden {
-		existingTemplate.Name = template.Name
-		existingTemplate.Subject = template.Subject
-		existingTemplate.HTML = template.HTML
-		existingTemplate.Text = template.Text
-		existingTemplate.Metadata = string(template.Metadata)
-		existingTemplate.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
-		_, err = conn.Update(&existingTemplate)
+		seededTemplate := existingTemplate
+		seededTemplate.Name = template.Name
+		seededTemplate.Subject = template.Subject
+		seededTemplate.HTML = template.HTML
+		seededTemplate.Text = template.Text
+		seededTemplate.Metadata = string(template.Metadata)
+
+		if seededTemplate == existingTemplate {
+			return
+		}
+
+		seededTemplate.Version = existingTemplate.Version + 1
+		seededTemplate.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+		_, err = conn.Update(&seededTemplate)
+		if err != nil {
+			panic(err)
+		}
+
+		_, err = versionsRepo.Create(conn, NewTemplateVersion(seededTemplate, ""))
 		if err != nil {
 			panic(err)
 		}
@@ -85,6 +85,11 @@ var _ = Describe("DatabaseMigrator", func() {
 			Expect(template.HTML).To(Equal("<p>{{.Endorsement}}</p>{{.HTML}}"))
 			Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}"))
 			Expect(template.Metadata).To(Equal("{}"))
+			Expect(template.Version).To(Equal(1))
+
+			version, err := models.NewTemplateVersionsRepo().Find(connection, models.DefaultTemplateID, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.Subject).To(Equal("CF Notification: {{.Subject}}"))
 		})
 
 		It("can be called multiple times without panicking", func() {
@@ -121,6 +126,20 @@ var _ = Describe("DatabaseMigrator", func() {
 				Expect(template.Text).To(Equal("{{.Endorsement}}\n{{.Text}}"))
 				Expect(template.Metadata).To(Equal("{}"))
 				Expect(template.Overridden).To(BeFalse())
+				Expect(template.Version).To(Equal(2))
+
+				version, err := models.NewTemplateVersionsRepo().Find(connection, models.DefaultTemplateID, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(version.Name).To(Equal("Default Template"))
+			})
+
+			It("does not record a version when the file is unchanged", func() {
+				dbMigrator.Seed(database, defaultTemplatePath)
+				dbMigrator.Seed(database, defaultTemplatePath)
+
+				versions, err := models.NewTemplateVersionsRepo().ListByTemplateID(connection, models.DefaultTemplateID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(versions).To(HaveLen(1))
 			})
 		})
 
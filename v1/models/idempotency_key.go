@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// IdempotencyKey records the Idempotency-Key a client sent with a notify
+// request, a hash of that request and the response it was given. The response
+// is empty while the request is still being processed.
+type IdempotencyKey struct {
+	Primary     int       `db:"primary"`
+	ClientID    string    `db:"client_id"`
+	Key         string    `db:"idempotency_key"`
+	RequestHash string    `db:"request_hash"`
+	Response    string    `db:"response"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (k *IdempotencyKey) PreInsert(executor gorp.SqlExecutor) error {
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+)
+
+type IdempotencyKeysRepo struct{}
+
+func NewIdempotencyKeysRepo() IdempotencyKeysRepo {
+	return IdempotencyKeysRepo{}
+}
+
+// Create stores the key, returning a DuplicateError when the client has
+// already used it.
+func (repo IdempotencyKeysRepo) Create(conn ConnectionInterface, key IdempotencyKey) (IdempotencyKey, error) {
+	err := conn.Insert(&key)
+	if err != nil {
+		if db.IsDuplicateError(err) {
+			err = DuplicateError{errors.New("duplicate record")}
+		}
+		return IdempotencyKey{}, err
+	}
+
+	return key, nil
+}
+
+func (repo IdempotencyKeysRepo) Find(conn ConnectionInterface, clientID, key string) (IdempotencyKey, error) {
+	idempotencyKey := IdempotencyKey{}
+	err := conn.SelectOne(&idempotencyKey, "SELECT * FROM `idempotency_keys` WHERE `client_id` = ? AND `idempotency_key` = ?", clientID, key)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return IdempotencyKey{}, NotFoundError{fmt.Errorf("Idempotency key %q could not be found", key)}
+		}
+		return IdempotencyKey{}, err
+	}
+
+	return idempotencyKey, nil
+}
+
+func (repo IdempotencyKeysRepo) SetResponse(conn ConnectionInterface, clientID, key, response string) error {
+	_, err := conn.Exec("UPDATE `idempotency_keys` SET `response` = ? WHERE `client_id` = ? AND `idempotency_key` = ?", response, clientID, key)
+	return err
+}
+
+func (repo IdempotencyKeysRepo) Delete(conn ConnectionInterface, clientID, key string) error {
+	_, err := conn.Exec("DELETE FROM `idempotency_keys` WHERE `client_id` = ? AND `idempotency_key` = ?", clientID, key)
+	return err
+}
+
+// DeleteBefore removes the keys that were created before the threshold.
+func (repo IdempotencyKeysRepo) DeleteBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
+	result, err := conn.Exec("DELETE FROM `idempotency_keys` WHERE `created_at` < ?", threshold.UTC())
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), nil
+}
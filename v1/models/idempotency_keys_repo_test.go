@@ -0,0 +1,89 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IdempotencyKeysRepo", func() {
+	var (
+		repo models.IdempotencyKeysRepo
+		conn *db.Connection
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection().(*db.Connection)
+		repo = models.NewIdempotencyKeysRepo()
+	})
+
+	It("stores a key and the response given to its request", func() {
+		key, err := repo.Create(conn, models.IdempotencyKey{
+			ClientID:    "some-client",
+			Key:         "some-key",
+			RequestHash: "some-hash",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+
+		err = repo.SetResponse(conn, "some-client", "some-key", `[{"status":"queued"}]`)
+		Expect(err).NotTo(HaveOccurred())
+
+		key, err = repo.Find(conn, "some-client", "some-key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(key.RequestHash).To(Equal("some-hash"))
+		Expect(key.Response).To(Equal(`[{"status":"queued"}]`))
+	})
+
+	It("scopes keys to the client that sent them", func() {
+		_, err := repo.Create(conn, models.IdempotencyKey{ClientID: "some-client", Key: "some-key"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Create(conn, models.IdempotencyKey{ClientID: "other-client", Key: "some-key"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Find(conn, "third-client", "some-key")
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+	})
+
+	It("returns a duplicate error when the client has already used the key", func() {
+		_, err := repo.Create(conn, models.IdempotencyKey{ClientID: "some-client", Key: "some-key"})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Create(conn, models.IdempotencyKey{ClientID: "some-client", Key: "some-key"})
+		Expect(err).To(BeAssignableToTypeOf(models.DuplicateError{}))
+	})
+
+	It("deletes a key", func() {
+		_, err := repo.Create(conn, models.IdempotencyKey{ClientID: "some-client", Key: "some-key"})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.Delete(conn, "some-client", "some-key")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Find(conn, "some-client", "some-key")
+		Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+	})
+
+	It("deletes the keys created before the threshold", func() {
+		_, err := repo.Create(conn, models.IdempotencyKey{ClientID: "some-client", Key: "old-key", CreatedAt: time.Now().Add(-48 * time.Hour).UTC()})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = repo.Create(conn, models.IdempotencyKey{ClientID: "some-client", Key: "new-key"})
+		Expect(err).NotTo(HaveOccurred())
+
+		count, err := repo.DeleteBefore(conn, time.Now().Add(-24*time.Hour))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(count).To(Equal(1))
+
+		_, err = repo.Find(conn, "some-client", "new-key")
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
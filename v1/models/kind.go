@@ -15,6 +15,22 @@ type Kind struct {
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
 	TemplateID  string    `db:"template_id"`
+	RetryPolicy
+
+	// HasRetryPolicy marks a kind whose RetryPolicy was given, so that an
+	// update stores it even when it is zero, returning the kind to the
+	// default policy. Updates of other kinds keep the existing policy.
+	HasRetryPolicy bool `db:"-"`
+}
+
+// RetryPolicy controls how failed deliveries of a kind are retried. Delays
+// are in seconds. Zero values fall back to the worker defaults.
+type RetryPolicy struct {
+	MaxAttempts int     `db:"retry_max_attempts"`
+	BaseDelay   int     `db:"retry_base_delay"`
+	MaxDelay    int     `db:"retry_max_delay"`
+	Jitter      float64 `db:"retry_jitter"`
+	GiveUpAfter int     `db:"retry_give_up_after"`
 }
 
 func (k Kind) TemplateToUse() string {
@@ -4,8 +4,9 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strings"
 	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
 )
 
 type IDSet []string
@@ -28,7 +29,7 @@ func NewKindsRepo() KindsRepo {
 func (repo KindsRepo) create(conn ConnectionInterface, kind Kind) (Kind, error) {
 	err := conn.Insert(&kind)
 	if err != nil {
-		if strings.Contains(err.Error(), "Duplicate entry") {
+		if db.IsDuplicateError(err) {
 			err = DuplicateError{errors.New("duplicate record")}
 		}
 		return kind, err
@@ -71,6 +72,9 @@ func (repo KindsRepo) Update(conn ConnectionInterface, kind Kind) (Kind, error)
 	if kind.TemplateID == DoNotSetTemplateID {
 		kind.TemplateID = existingKind.TemplateID
 	}
+	if !kind.HasRetryPolicy {
+		kind.RetryPolicy = existingKind.RetryPolicy
+	}
 
 	_, err = conn.Update(&kind)
 	if err != nil {
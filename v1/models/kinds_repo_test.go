@@ -132,6 +132,74 @@ var _ = Describe("KindsRepo", func() {
 				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Notification with ID \"my-kind\" belonging to client \"my-client\" could not be found")}))
 			})
 		})
+
+		Context("when the retry policy is not set", func() {
+			It("updates the record in the database, using the existing retry policy", func() {
+				policy := models.RetryPolicy{
+					MaxAttempts: 20,
+					BaseDelay:   10,
+					MaxDelay:    600,
+					Jitter:      0.5,
+					GiveUpAfter: 86400,
+				}
+
+				kind, err := repo.Upsert(conn, models.Kind{
+					ID:          "my-kind",
+					ClientID:    "my-client",
+					RetryPolicy: policy,
+				})
+				if err != nil {
+					panic(err)
+				}
+
+				kind.Description = "My Kind"
+				kind.RetryPolicy = models.RetryPolicy{}
+
+				_, err = repo.Update(conn, kind)
+				if err != nil {
+					panic(err)
+				}
+
+				kind, err = repo.Find(conn, "my-kind", "my-client")
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(kind.Description).To(Equal("My Kind"))
+				Expect(kind.RetryPolicy).To(Equal(policy))
+			})
+		})
+
+		Context("when the retry policy is reset", func() {
+			It("updates the record in the database, going back to the default retry policy", func() {
+				kind, err := repo.Upsert(conn, models.Kind{
+					ID:       "my-kind",
+					ClientID: "my-client",
+					RetryPolicy: models.RetryPolicy{
+						MaxAttempts: 20,
+						BaseDelay:   10,
+					},
+				})
+				if err != nil {
+					panic(err)
+				}
+
+				kind.RetryPolicy = models.RetryPolicy{}
+				kind.HasRetryPolicy = true
+
+				_, err = repo.Update(conn, kind)
+				if err != nil {
+					panic(err)
+				}
+
+				kind, err = repo.Find(conn, "my-kind", "my-client")
+				if err != nil {
+					panic(err)
+				}
+
+				Expect(kind.RetryPolicy).To(Equal(models.RetryPolicy{}))
+			})
+		})
 	})
 
 	Describe("Upsert", func() {
@@ -6,15 +6,39 @@ import (
 	"gopkg.in/gorp.v1"
 )
 
+const (
+	// MessageStatusScheduled is the status of a message that waits for its
+	// send_at before it is delivered.
+	MessageStatusScheduled = "scheduled"
+
+	// MessageStatusCancelled is the status of a message that was cancelled
+	// before it was delivered.
+	MessageStatusCancelled = "cancelled"
+)
+
 type Message struct {
-	ID         string    `db:"id"`
-	Status     string    `db:"status"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID              string    `db:"id"`
+	Status          string    `db:"status"`
+	Recipient       string    `db:"recipient"`
+	ClientID        string    `db:"client_id"`
+	KindID          string    `db:"kind_id"`
+	VCAPRequestID   string    `db:"vcap_request_id"`
+	SendID          string    `db:"send_id"`
+	JobID           int       `db:"job_id"`
+	TemplateID      string    `db:"template_id"`
+	TemplateVersion int       `db:"template_version"`
+	DeliveredTo     string    `db:"delivered_to"`
+	QueuedAt        time.Time `db:"queued_at"`
+	UpdatedAt       time.Time `db:"updated_at"`
 }
 
 func (m *Message) PreInsert(s gorp.SqlExecutor) error {
 	m.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
 
+	if m.QueuedAt.IsZero() {
+		m.QueuedAt = m.UpdatedAt
+	}
+
 	return nil
 }
 
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+const (
+	MessageEventQueued        = "queued"
+	MessageEventAttempted     = "attempted"
+	MessageEventDelivered     = "delivered"
+	MessageEventFailed        = "failed"
+	MessageEventUndeliverable = "undeliverable"
+	MessageEventBounced       = "bounced"
+	MessageEventComplained    = "complained"
+	MessageEventCancelled     = "cancelled"
+)
+
+type MessageEvent struct {
+	Primary     int       `db:"primary"`
+	MessageID   string    `db:"message_id"`
+	Type        string    `db:"type"`
+	Attempt     int       `db:"attempt"`
+	SMTPCode    int       `db:"smtp_code"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (e *MessageEvent) PreInsert(s gorp.SqlExecutor) error {
+	e.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
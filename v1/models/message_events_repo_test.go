@@ -0,0 +1,73 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MessageEventsRepo", func() {
+	var (
+		repo models.MessageEventsRepo
+		conn db.ConnectionInterface
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		repo = models.NewMessageEventsRepo()
+	})
+
+	Describe("Create", func() {
+		It("records an event for the message", func() {
+			event, err := repo.Create(conn, models.MessageEvent{
+				MessageID:   "some-message-id",
+				Type:        models.MessageEventFailed,
+				Attempt:     2,
+				SMTPCode:    451,
+				Description: "451 try again later",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(event.Primary).NotTo(BeZero())
+			Expect(event.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+	})
+
+	Describe("ListByMessageID", func() {
+		It("lists the events for the message in the order they were recorded", func() {
+			for _, eventType := range []string{models.MessageEventQueued, models.MessageEventAttempted, models.MessageEventDelivered} {
+				_, err := repo.Create(conn, models.MessageEvent{
+					MessageID: "some-message-id",
+					Type:      eventType,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Create(conn, models.MessageEvent{
+				MessageID: "other-message-id",
+				Type:      models.MessageEventQueued,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := repo.ListByMessageID(conn, "some-message-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(HaveLen(3))
+			Expect(events[0].Type).To(Equal(models.MessageEventQueued))
+			Expect(events[1].Type).To(Equal(models.MessageEventAttempted))
+			Expect(events[2].Type).To(Equal(models.MessageEventDelivered))
+		})
+
+		It("returns an empty list when the message has no events", func() {
+			events, err := repo.ListByMessageID(conn, "missing-message-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+	})
+})
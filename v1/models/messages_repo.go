@@ -3,6 +3,7 @@ package models
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -68,8 +69,132 @@ func (repo MessagesRepo) Upsert(conn ConnectionInterface, message Message) (Mess
 	}
 }
 
+// UpdateStatus changes only the status of a message, leaving the details
+// recorded when it was queued intact.
+func (repo MessagesRepo) UpdateStatus(conn ConnectionInterface, messageID, status string) (Message, error) {
+	message, err := repo.FindByID(conn, messageID)
+
+	switch err.(type) {
+	case NotFoundError:
+		return repo.Create(conn, Message{ID: messageID, Status: status})
+	case nil:
+		message.Status = status
+		return repo.Update(conn, message)
+	default:
+		return message, err
+	}
+}
+
+// SetJobID records the ID of the job that delivers the message, so that the
+// job can be removed when the message is cancelled.
+func (repo MessagesRepo) SetJobID(conn ConnectionInterface, messageID string, jobID int) error {
+	_, err := conn.Exec("UPDATE `messages` SET `job_id` = ? WHERE `id` = ?", jobID, messageID)
+	return err
+}
+
+// SetTemplateVersion records the version of the template that rendered the
+// message.
+func (repo MessagesRepo) SetTemplateVersion(conn ConnectionInterface, messageID, templateID string, version int) error {
+	_, err := conn.Exec("UPDATE `messages` SET `template_id` = ?, `template_version` = ? WHERE `id` = ?", templateID, version, messageID)
+	return err
+}
+
+// SetDeliveredTo records the address the message is handed to the SMTP
+// server for, so that bounce and complaint reports can be matched against it.
+func (repo MessagesRepo) SetDeliveredTo(conn ConnectionInterface, messageID, email string) error {
+	_, err := conn.Exec("UPDATE `messages` SET `delivered_to` = ? WHERE `id` = ?", email, messageID)
+	return err
+}
+
+// ListCancellableBySendID lists the messages of a send that have not been
+// delivered, rejected or cancelled yet.
+func (repo MessagesRepo) ListCancellableBySendID(conn ConnectionInterface, sendID string) ([]Message, error) {
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `send_id` = ? AND `status` NOT IN (?, ?, ?)", sendID, "delivered", "undeliverable", MessageStatusCancelled)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// Cancel marks the message as cancelled unless it has already been delivered
+// or rejected, and reports whether it did.
+func (repo MessagesRepo) Cancel(conn ConnectionInterface, messageID string) (bool, error) {
+	updatedAt := time.Now().Truncate(1 * time.Second).UTC()
+	result, err := conn.Exec("UPDATE `messages` SET `status` = ?, `updated_at` = ? WHERE `id` = ? AND `status` NOT IN (?, ?)", MessageStatusCancelled, updatedAt, messageID, "delivered", "undeliverable")
+	if err != nil {
+		return false, err
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindEnqueuedRecipients lists which of the given recipients already have a
+// message for the send. It is asked about one chunk of recipients at a time,
+// so a large send is never loaded whole.
+func (repo MessagesRepo) FindEnqueuedRecipients(conn ConnectionInterface, sendID string, recipients []string) ([]string, error) {
+	enqueued := []string{}
+	if len(recipients) == 0 {
+		return enqueued, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(recipients)), ", ")
+	args := []interface{}{sendID}
+	for _, recipient := range recipients {
+		args = append(args, recipient)
+	}
+
+	var messages []Message
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `send_id` = ? AND `recipient` IN ("+placeholders+")", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, message := range messages {
+		enqueued = append(enqueued, message.Recipient)
+	}
+
+	return enqueued, nil
+}
+
+// CountBySendID counts the messages of a send by status.
+func (repo MessagesRepo) CountBySendID(conn ConnectionInterface, sendID string) ([]SendStatusCount, error) {
+	var counts []SendStatusCount
+	_, err := conn.Select(&counts, "SELECT `status`, COUNT(*) AS `count`, MAX(`updated_at`) AS `updated_at` FROM `messages` WHERE `send_id` = ? GROUP BY `status`", sendID)
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ListBySendID lists a page of the messages of a send in the order they were
+// queued.
+func (repo MessagesRepo) ListBySendID(conn ConnectionInterface, sendID string, limit, offset int) ([]Message, error) {
+	messages := []Message{}
+	_, err := conn.Select(&messages, "SELECT * FROM `messages` WHERE `send_id` = ? ORDER BY `queued_at`, `id` LIMIT ? OFFSET ?", sendID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// DeleteBefore removes the messages, and their events, that have not changed
+// since the threshold. Scheduled messages are kept until they are delivered.
 func (repo MessagesRepo) DeleteBefore(conn ConnectionInterface, threshold time.Time) (int, error) {
-	result, err := conn.Exec("DELETE FROM `messages` WHERE `updated_at` < ?", threshold.UTC())
+	_, err := conn.Exec("DELETE FROM `message_events` WHERE `message_id` IN (SELECT `id` FROM `messages` WHERE `updated_at` < ? AND `status` <> ?)", threshold.UTC(), MessageStatusScheduled)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := conn.Exec("DELETE FROM `messages` WHERE `updated_at` < ? AND `status` <> ?", threshold.UTC(), MessageStatusScheduled)
 	if err != nil {
 		return 0, err
 	}
@@ -77,5 +202,11 @@ func (repo MessagesRepo) DeleteBefore(conn ConnectionInterface, threshold time.T
 	if err != nil {
 		return 0, err
 	}
+
+	_, err = conn.Exec("DELETE FROM `sends` WHERE `created_at` < ? AND `id` NOT IN (SELECT `send_id` FROM `messages`)", threshold.UTC())
+	if err != nil {
+		return 0, err
+	}
+
 	return int(count), nil
 }
@@ -47,6 +47,24 @@ var _ = Describe("MessagesRepo", func() {
 			Expect(message.ID).To(Equal("first-random-guid"))
 		})
 
+		It("records when the message was queued", func() {
+			message.Recipient = "user@example.com"
+			message.ClientID = "some-client"
+			message.KindID = "some-kind"
+			message.VCAPRequestID = "some-request-id"
+
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			messageFound, err := repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageFound.Recipient).To(Equal("user@example.com"))
+			Expect(messageFound.ClientID).To(Equal("some-client"))
+			Expect(messageFound.KindID).To(Equal("some-kind"))
+			Expect(messageFound.VCAPRequestID).To(Equal("some-request-id"))
+			Expect(messageFound.QueuedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		})
+
 		It("returns an error when the guid generator errors", func() {
 			guidGenerator.GenerateCall.Returns.Error = errors.New("something bad")
 
@@ -112,6 +130,204 @@ var _ = Describe("MessagesRepo", func() {
 		})
 	})
 
+	Describe("UpdateStatus", func() {
+		It("changes the status without losing the queued details", func() {
+			message.Recipient = "user@example.com"
+			message.VCAPRequestID = "some-request-id"
+
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.UpdateStatus(conn, message.ID, common.StatusFailed)
+			Expect(err).NotTo(HaveOccurred())
+
+			messageFound, err := repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageFound.Status).To(Equal(common.StatusFailed))
+			Expect(messageFound.Recipient).To(Equal("user@example.com"))
+			Expect(messageFound.VCAPRequestID).To(Equal("some-request-id"))
+			Expect(messageFound.QueuedAt).To(Equal(message.QueuedAt))
+		})
+
+		It("creates the message when it does not exist", func() {
+			_, err := repo.UpdateStatus(conn, "some-message-id", common.StatusDelivered)
+			Expect(err).NotTo(HaveOccurred())
+
+			messageFound, err := repo.FindByID(conn, "some-message-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageFound.Status).To(Equal(common.StatusDelivered))
+		})
+	})
+
+	Describe("FindEnqueuedRecipients", func() {
+		It("lists which of the recipients have a message that belongs to the send", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid"}
+
+			_, err := repo.Create(conn, models.Message{Status: common.StatusQueued, Recipient: "user-1", SendID: "some-send-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Message{Status: common.StatusQueued, Recipient: "user-2", SendID: "some-send-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.Message{Status: common.StatusQueued, Recipient: "user-3", SendID: "another-send-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			recipients, err := repo.FindEnqueuedRecipients(conn, "some-send-id", []string{"user-2", "user-3", "user-4"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recipients).To(ConsistOf("user-2"))
+		})
+
+		It("finds nothing when there are no recipients to look for", func() {
+			recipients, err := repo.FindEnqueuedRecipients(conn, "some-send-id", []string{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recipients).To(BeEmpty())
+		})
+	})
+
+	Describe("CountBySendID", func() {
+		It("counts the messages of the send by status", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"first-random-guid", "second-random-guid", "third-random-guid", "fourth-random-guid"}
+
+			for _, m := range []models.Message{
+				{Status: common.StatusQueued, Recipient: "user-1", SendID: "some-send-id"},
+				{Status: common.StatusDelivered, Recipient: "user-2", SendID: "some-send-id"},
+				{Status: common.StatusDelivered, Recipient: "user-3", SendID: "some-send-id"},
+				{Status: common.StatusFailed, Recipient: "user-4", SendID: "another-send-id"},
+			} {
+				_, err := repo.Create(conn, m)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			counts, err := repo.CountBySendID(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(counts).To(HaveLen(2))
+
+			byStatus := map[string]int{}
+			for _, count := range counts {
+				byStatus[count.Status] = count.Count
+				Expect(count.UpdatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+			}
+			Expect(byStatus).To(Equal(map[string]int{
+				common.StatusQueued:    1,
+				common.StatusDelivered: 2,
+			}))
+		})
+	})
+
+	Describe("ListBySendID", func() {
+		It("lists a page of the messages of the send", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"a-guid", "b-guid", "c-guid"}
+
+			for _, recipient := range []string{"user-1", "user-2", "user-3"} {
+				_, err := repo.Create(conn, models.Message{Status: common.StatusQueued, Recipient: recipient, SendID: "some-send-id"})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			messages, err := repo.ListBySendID(conn, "some-send-id", 2, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[0].Recipient).To(Equal("user-2"))
+			Expect(messages[1].Recipient).To(Equal("user-3"))
+		})
+	})
+
+	Describe("SetJobID", func() {
+		It("records the job that delivers the message", func() {
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.SetJobID(conn, message.ID, 42)
+			Expect(err).NotTo(HaveOccurred())
+
+			message, err = repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.JobID).To(Equal(42))
+		})
+	})
+
+	Describe("SetTemplateVersion", func() {
+		It("records the template version that rendered the message", func() {
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.SetTemplateVersion(conn, message.ID, "some-template-id", 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			message, err = repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.TemplateID).To(Equal("some-template-id"))
+			Expect(message.TemplateVersion).To(Equal(3))
+		})
+	})
+
+	Describe("SetDeliveredTo", func() {
+		It("records the address the message was delivered to", func() {
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.SetDeliveredTo(conn, message.ID, "someone@example.com")
+			Expect(err).NotTo(HaveOccurred())
+
+			message, err = repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.DeliveredTo).To(Equal("someone@example.com"))
+		})
+	})
+
+	Describe("ListCancellableBySendID", func() {
+		It("lists the messages of the send that are still waiting to be delivered", func() {
+			guidGenerator.GenerateCall.Returns.IDs = []string{"a-guid", "b-guid", "c-guid", "d-guid", "e-guid", "f-guid"}
+
+			statuses := []string{common.StatusQueued, models.MessageStatusScheduled, common.StatusFailed, common.StatusDelivered, common.StatusUndeliverable, models.MessageStatusCancelled}
+			for _, status := range statuses {
+				_, err := repo.Create(conn, models.Message{Status: status, Recipient: "user-" + status, SendID: "some-send-id"})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Create(conn, models.Message{ID: "other-guid", Status: common.StatusQueued, SendID: "other-send-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			messages, err := repo.ListCancellableBySendID(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			var ids []string
+			for _, message := range messages {
+				ids = append(ids, message.ID)
+			}
+			Expect(ids).To(ConsistOf("a-guid", "b-guid", "c-guid"))
+		})
+	})
+
+	Describe("Cancel", func() {
+		It("cancels a message that has not been delivered", func() {
+			message.Status = models.MessageStatusScheduled
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			cancelled, err := repo.Cancel(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cancelled).To(BeTrue())
+
+			message, err = repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.Status).To(Equal(models.MessageStatusCancelled))
+		})
+
+		It("does not cancel a message that has been delivered", func() {
+			message.Status = common.StatusDelivered
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			cancelled, err := repo.Cancel(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cancelled).To(BeFalse())
+
+			message, err = repo.FindByID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.Status).To(Equal(common.StatusDelivered))
+		})
+	})
+
 	Describe("DeleteBefore", func() {
 		It("Deletes messages older than the input time", func() {
 			message, err := repo.Create(conn, message)
@@ -126,6 +342,25 @@ var _ = Describe("MessagesRepo", func() {
 
 		})
 
+		It("Deletes the events of the deleted messages", func() {
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			eventsRepo := models.NewMessageEventsRepo()
+			_, err = eventsRepo.Create(conn, models.MessageEvent{
+				MessageID: message.ID,
+				Type:      models.MessageEventQueued,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.DeleteBefore(conn, time.Now().Add(1*time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+
+			events, err := eventsRepo.ListByMessageID(conn, message.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events).To(BeEmpty())
+		})
+
 		It("Does not delete messages younger than the input time", func() {
 			message, err := repo.Create(conn, message)
 			Expect(err).NotTo(HaveOccurred())
@@ -137,5 +372,48 @@ var _ = Describe("MessagesRepo", func() {
 			_, err = repo.FindByID(conn, message.ID)
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("Does not delete scheduled messages", func() {
+			message.Status = models.MessageStatusScheduled
+			message, err := repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			itemsDeleted, err := repo.DeleteBefore(conn, time.Now().Add(1*time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(itemsDeleted).To(Equal(0))
+
+			_, err = repo.FindByID(conn, message.ID)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Deletes the old sends that no longer have messages", func() {
+			sendsRepo := models.NewSendsRepo(mocks.NewIDGenerator().Generate)
+			_, err := sendsRepo.Create(conn, models.Send{ID: "old-send-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = sendsRepo.Create(conn, models.Send{ID: "active-send-id"})
+			Expect(err).NotTo(HaveOccurred())
+
+			message.SendID = "active-send-id"
+			_, err = repo.Create(conn, message)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.DeleteBefore(conn, time.Now().Add(-1*time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			_, err = sendsRepo.FindByID(conn, "old-send-id")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = conn.Exec("UPDATE `sends` SET `created_at` = ?", time.Now().Add(-2*time.Hour).UTC())
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = repo.DeleteBefore(conn, time.Now().Add(-1*time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = sendsRepo.FindByID(conn, "old-send-id")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+
+			_, err = sendsRepo.FindByID(conn, "active-send-id")
+			Expect(err).ToNot(HaveOccurred())
+		})
 	})
 })
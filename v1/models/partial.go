@@ -0,0 +1,101 @@
+package models
+
+import (
+	"regexp"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+var partialNameFormat = regexp.MustCompile(`^[a-zA-Z0-9]+([._-][a-zA-Z0-9]+)*$`)
+
+// reservedPartialNames are the names the packager compiles the parts of a
+// message under, so a partial with one of them would replace that part.
+var reservedPartialNames = []string{"subject", "text", "html", "html-wrapper", "endorsement"}
+
+// Partial is a named template that other templates include with
+// {{template "name" .}}. A client may also use a partial as the layout that
+// wraps the HTML of its notifications.
+type Partial struct {
+	Primary   int       `db:"primary"`
+	Name      string    `db:"name"`
+	Body      string    `db:"body"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (p *Partial) PreInsert(s gorp.SqlExecutor) error {
+	if (p.CreatedAt == time.Time{}) {
+		p.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+	p.UpdatedAt = p.CreatedAt
+
+	return nil
+}
+
+// ValidPartialName reports whether the name can be given to a partial: words
+// of letters and digits joined by dots, hyphens or underscores, other than
+// the names the packager reserves for the parts of a message.
+func ValidPartialName(name string) bool {
+	for _, reserved := range reservedPartialNames {
+		if name == reserved {
+			return false
+		}
+	}
+
+	return partialNameFormat.MatchString(name)
+}
+
+// PartialReferences lists the names of the templates that the source
+// includes with the template action, leaving out the ones the source defines
+// itself.
+func PartialReferences(source string) ([]string, error) {
+	tmpl, err := template.New("source").Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var references []string
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			references = appendPartialReferences(references, t.Tree.Root)
+		}
+	}
+
+	var names []string
+	for _, name := range references {
+		if name == tmpl.Name() || tmpl.Lookup(name) == nil {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func appendPartialReferences(names []string, node parse.Node) []string {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return names
+		}
+
+		for _, child := range n.Nodes {
+			names = appendPartialReferences(names, child)
+		}
+	case *parse.IfNode:
+		names = appendPartialReferences(names, n.List)
+		names = appendPartialReferences(names, n.ElseList)
+	case *parse.RangeNode:
+		names = appendPartialReferences(names, n.List)
+		names = appendPartialReferences(names, n.ElseList)
+	case *parse.WithNode:
+		names = appendPartialReferences(names, n.List)
+		names = appendPartialReferences(names, n.ElseList)
+	case *parse.TemplateNode:
+		names = append(names, n.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,66 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Partial", func() {
+	Describe("ValidPartialName", func() {
+		It("accepts words joined by dots, hyphens and underscores", func() {
+			Expect(models.ValidPartialName("footer")).To(BeTrue())
+			Expect(models.ValidPartialName("brand.header")).To(BeTrue())
+			Expect(models.ValidPartialName("Main_Layout-2")).To(BeTrue())
+		})
+
+		It("rejects anything else", func() {
+			Expect(models.ValidPartialName("")).To(BeFalse())
+			Expect(models.ValidPartialName("my footer")).To(BeFalse())
+			Expect(models.ValidPartialName("-footer")).To(BeFalse())
+			Expect(models.ValidPartialName("foot\"er")).To(BeFalse())
+		})
+
+		It("rejects the names of the parts of a message", func() {
+			Expect(models.ValidPartialName("html")).To(BeFalse())
+			Expect(models.ValidPartialName("html-wrapper")).To(BeFalse())
+			Expect(models.ValidPartialName("subject")).To(BeFalse())
+		})
+	})
+
+	Describe("PartialReferences", func() {
+		It("lists the templates the source includes", func() {
+			names, err := models.PartialReferences(`{{template "header" .}}{{if .Text}}{{template "body" .}}{{else}}{{template "empty"}}{{end}}{{range .Items}}{{with .}}{{template "item" .}}{{end}}{{end}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(ConsistOf("header", "body", "empty", "item"))
+		})
+
+		It("includes the references made from defined templates", func() {
+			names, err := models.PartialReferences(`{{define "wrapper"}}{{template "footer" .}}{{end}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(ConsistOf("footer"))
+		})
+
+		It("leaves out the templates the source defines itself", func() {
+			names, err := models.PartialReferences(`{{define "row"}}<tr>{{template "cell" .}}</tr>{{end}}{{template "row" .}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(ConsistOf("cell"))
+		})
+
+		It("returns nothing when the source includes no templates", func() {
+			names, err := models.PartialReferences("just {{.Text}}")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(BeEmpty())
+
+			names, err = models.PartialReferences("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(BeEmpty())
+		})
+
+		It("returns an error when the source cannot be parsed", func() {
+			_, err := models.PartialReferences("{{template")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type PartialsRepo struct{}
+
+func NewPartialsRepo() PartialsRepo {
+	return PartialsRepo{}
+}
+
+func (repo PartialsRepo) Find(conn ConnectionInterface, name string) (Partial, error) {
+	partial := Partial{}
+	err := conn.SelectOne(&partial, "SELECT * FROM `partials` WHERE `name` = ?", name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return partial, NotFoundError{fmt.Errorf("Partial with name %q could not be found", name)}
+		}
+		return partial, err
+	}
+
+	return partial, nil
+}
+
+// List lists every partial, ordered by name.
+func (repo PartialsRepo) List(conn ConnectionInterface) ([]Partial, error) {
+	partials := []Partial{}
+	_, err := conn.Select(&partials, "SELECT * FROM `partials` ORDER BY `name`")
+	if err != nil {
+		return nil, err
+	}
+
+	return partials, nil
+}
+
+// ListByNames lists the partials with the given names, ordered by name.
+// Names without a partial are left out.
+func (repo PartialsRepo) ListByNames(conn ConnectionInterface, names []string) ([]Partial, error) {
+	partials := []Partial{}
+	if len(names) == 0 {
+		return partials, nil
+	}
+
+	args := []interface{}{}
+	for _, name := range names {
+		args = append(args, name)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(names)), ", ")
+	_, err := conn.Select(&partials, "SELECT * FROM `partials` WHERE `name` IN ("+placeholders+") ORDER BY `name`", args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return partials, nil
+}
+
+// Upsert creates the partial, or replaces the body of the partial that
+// already has its name.
+func (repo PartialsRepo) Upsert(conn ConnectionInterface, partial Partial) (Partial, error) {
+	existingPartial, err := repo.Find(conn, partial.Name)
+	switch err.(type) {
+	case NotFoundError:
+		err = conn.Insert(&partial)
+		if err != nil {
+			return Partial{}, err
+		}
+
+		return partial, nil
+	case nil:
+		partial.Primary = existingPartial.Primary
+		partial.CreatedAt = existingPartial.CreatedAt
+		partial.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+		_, err = conn.Update(&partial)
+		if err != nil {
+			return Partial{}, err
+		}
+
+		return partial, nil
+	default:
+		return Partial{}, err
+	}
+}
+
+func (repo PartialsRepo) Destroy(conn ConnectionInterface, name string) error {
+	partial, err := repo.Find(conn, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.Delete(&partial)
+	return err
+}
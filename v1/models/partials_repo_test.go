@@ -0,0 +1,129 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialsRepo", func() {
+	var (
+		repo      models.PartialsRepo
+		conn      db.ConnectionInterface
+		createdAt time.Time
+	)
+
+	BeforeEach(func() {
+		repo = models.NewPartialsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+		createdAt = time.Now().Add(-1 * time.Hour).Truncate(1 * time.Second).UTC()
+	})
+
+	Describe("Upsert", func() {
+		It("creates the partial when it is new", func() {
+			partial, err := repo.Upsert(conn, models.Partial{
+				Name:      "footer",
+				Body:      "<p>the footer</p>",
+				CreatedAt: createdAt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial.UpdatedAt).To(Equal(createdAt))
+
+			foundPartial, err := repo.Find(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundPartial).To(Equal(partial))
+		})
+
+		It("replaces the body of the partial with the same name", func() {
+			original, err := repo.Upsert(conn, models.Partial{
+				Name:      "footer",
+				Body:      "<p>the footer</p>",
+				CreatedAt: createdAt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			partial, err := repo.Upsert(conn, models.Partial{
+				Name: "footer",
+				Body: "<p>the new footer</p>",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partial.Primary).To(Equal(original.Primary))
+			Expect(partial.CreatedAt).To(Equal(createdAt))
+			Expect(partial.UpdatedAt).To(BeTemporally("~", time.Now().UTC(), 2*time.Second))
+
+			foundPartial, err := repo.Find(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundPartial.Body).To(Equal("<p>the new footer</p>"))
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when there is no partial with the name", func() {
+			_, err := repo.Find(conn, "missing")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Partial with name \"missing\" could not be found")}))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists the partials ordered by name", func() {
+			for _, name := range []string{"header", "footer", "layout"} {
+				_, err := repo.Upsert(conn, models.Partial{Name: name, Body: name})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			partials, err := repo.List(conn)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(HaveLen(3))
+			Expect(partials[0].Name).To(Equal("footer"))
+			Expect(partials[1].Name).To(Equal("header"))
+			Expect(partials[2].Name).To(Equal("layout"))
+		})
+	})
+
+	Describe("ListByNames", func() {
+		It("lists the partials with the given names", func() {
+			for _, name := range []string{"header", "footer", "layout"} {
+				_, err := repo.Upsert(conn, models.Partial{Name: name, Body: name})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			partials, err := repo.ListByNames(conn, []string{"layout", "footer", "missing"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(HaveLen(2))
+			Expect(partials[0].Name).To(Equal("footer"))
+			Expect(partials[1].Name).To(Equal("layout"))
+		})
+
+		It("lists nothing when no names are given", func() {
+			partials, err := repo.ListByNames(conn, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(partials).To(BeEmpty())
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("deletes the partial", func() {
+			_, err := repo.Upsert(conn, models.Partial{Name: "footer", Body: "the footer"})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.Destroy(conn, "footer")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Find(conn, "footer")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+
+		It("returns a not found error when there is no partial with the name", func() {
+			err := repo.Destroy(conn, "missing")
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+		})
+	})
+})
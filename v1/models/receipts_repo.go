@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+)
 
 type ReceiptsRepo struct{}
 
@@ -10,6 +14,9 @@ func NewReceiptsRepo() ReceiptsRepo {
 
 func (repo ReceiptsRepo) upsert(conn ConnectionInterface, receipt Receipt) error {
 	query := "INSERT INTO `receipts` (`user_guid`, `client_id`, `kind_id`, `count`, `created_at`) VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE `count`=`count`+1"
+	if db.IsPostgres(conn.GetDbMap().Dialect) {
+		query = "INSERT INTO `receipts` (`user_guid`, `client_id`, `kind_id`, `count`, `created_at`) VALUES (?, ?, ?, ?, ?) ON CONFLICT (`user_guid`, `client_id`, `kind_id`) DO UPDATE SET `count`=`receipts`.`count`+1"
+	}
 	_, err := conn.Exec(query, receipt.UserGUID, receipt.ClientID, receipt.KindID, 1, time.Now().Truncate(1*time.Second).UTC())
 	if err != nil {
 		return err
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// Send links the messages created for a single notify request. Resolved is
+// set once every recipient of the send has a message, and Cancelled once the
+// send has been cancelled.
+type Send struct {
+	ID            string    `db:"id"`
+	ClientID      string    `db:"client_id"`
+	KindID        string    `db:"kind_id"`
+	Audience      string    `db:"audience"`
+	VCAPRequestID string    `db:"vcap_request_id"`
+	Resolved      bool      `db:"resolved"`
+	Cancelled     bool      `db:"cancelled"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+func (s *Send) PreInsert(executor gorp.SqlExecutor) error {
+	if s.CreatedAt.IsZero() {
+		s.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	return nil
+}
+
+// SendStatusCount is the number of messages of a send that have the given
+// status, along with the time the last of them was updated.
+type SendStatusCount struct {
+	Status    string    `db:"status"`
+	Count     int       `db:"count"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
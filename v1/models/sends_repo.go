@@ -0,0 +1,59 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type SendsRepo struct {
+	generateID IDGeneratorFunc
+}
+
+func NewSendsRepo(guidGenerator IDGeneratorFunc) SendsRepo {
+	return SendsRepo{
+		generateID: guidGenerator,
+	}
+}
+
+func (repo SendsRepo) Create(conn ConnectionInterface, send Send) (Send, error) {
+	if send.ID == "" {
+		var err error
+		send.ID, err = repo.generateID()
+		if err != nil {
+			return Send{}, err
+		}
+	}
+
+	err := conn.Insert(&send)
+	if err != nil {
+		return Send{}, err
+	}
+
+	return send, nil
+}
+
+func (repo SendsRepo) FindByID(conn ConnectionInterface, sendID string) (Send, error) {
+	send := Send{}
+	err := conn.SelectOne(&send, "SELECT * FROM `sends` WHERE `id` = ?", sendID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return Send{}, NotFoundError{fmt.Errorf("Send with ID %q could not be found", sendID)}
+		}
+		return Send{}, err
+	}
+
+	return send, nil
+}
+
+// MarkResolved records that every recipient of the send has a message.
+func (repo SendsRepo) MarkResolved(conn ConnectionInterface, sendID string) error {
+	_, err := conn.Exec("UPDATE `sends` SET `resolved` = ? WHERE `id` = ?", true, sendID)
+	return err
+}
+
+// MarkCancelled records that the send has been cancelled, so that recipients
+// resolved afterwards are not delivered to.
+func (repo SendsRepo) MarkCancelled(conn ConnectionInterface, sendID string) error {
+	_, err := conn.Exec("UPDATE `sends` SET `cancelled` = ? WHERE `id` = ?", true, sendID)
+	return err
+}
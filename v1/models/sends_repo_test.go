@@ -0,0 +1,98 @@
+package models_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendsRepo", func() {
+	var (
+		repo          models.SendsRepo
+		conn          db.ConnectionInterface
+		guidGenerator *mocks.IDGenerator
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+
+		guidGenerator = mocks.NewIDGenerator()
+		guidGenerator.GenerateCall.Returns.IDs = []string{"some-send-id"}
+
+		repo = models.NewSendsRepo(guidGenerator.Generate)
+	})
+
+	Describe("Create", func() {
+		It("inserts a send into the database", func() {
+			send, err := repo.Create(conn, models.Send{
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				Audience:      "space",
+				VCAPRequestID: "some-request-id",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send.ID).To(Equal("some-send-id"))
+			Expect(send.CreatedAt).To(BeTemporally("~", time.Now(), 2*time.Second))
+
+			found, err := repo.FindByID(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found.ClientID).To(Equal("some-client"))
+			Expect(found.KindID).To(Equal("some-kind"))
+			Expect(found.Audience).To(Equal("space"))
+			Expect(found.VCAPRequestID).To(Equal("some-request-id"))
+			Expect(found.Resolved).To(BeFalse())
+		})
+
+		It("returns an error when the guid generator errors", func() {
+			guidGenerator.GenerateCall.Returns.Error = errors.New("some error")
+
+			_, err := repo.Create(conn, models.Send{})
+			Expect(err).To(MatchError(errors.New("some error")))
+		})
+	})
+
+	Describe("FindByID", func() {
+		It("returns a NotFoundError when the send does not exist", func() {
+			_, err := repo.FindByID(conn, "missing-send-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: fmt.Errorf("Send with ID %q could not be found", "missing-send-id")}))
+		})
+	})
+
+	Describe("MarkResolved", func() {
+		It("marks the send as resolved", func() {
+			_, err := repo.Create(conn, models.Send{})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.MarkResolved(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			send, err := repo.FindByID(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send.Resolved).To(BeTrue())
+		})
+	})
+
+	Describe("MarkCancelled", func() {
+		It("marks the send as cancelled", func() {
+			_, err := repo.Create(conn, models.Send{})
+			Expect(err).NotTo(HaveOccurred())
+
+			err = repo.MarkCancelled(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			send, err := repo.FindByID(conn, "some-send-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send.Cancelled).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+const (
+	SuppressionReasonBounce    = "bounce"
+	SuppressionReasonComplaint = "complaint"
+)
+
+// Suppression marks an email address that must no longer be sent to, either
+// because mail to it hard-bounced or because its owner complained.
+type Suppression struct {
+	Primary     int       `db:"primary"`
+	Email       string    `db:"email"`
+	Reason      string    `db:"reason"`
+	MessageID   string    `db:"message_id"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+func (s *Suppression) PreInsert(executor gorp.SqlExecutor) error {
+	s.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+)
+
+type SuppressionsRepo struct{}
+
+func NewSuppressionsRepo() SuppressionsRepo {
+	return SuppressionsRepo{}
+}
+
+// Add suppresses the email address. An address that is already suppressed
+// keeps its original reason.
+func (repo SuppressionsRepo) Add(conn ConnectionInterface, suppression Suppression) error {
+	suppression.Email = strings.ToLower(suppression.Email)
+
+	suppressed, err := repo.IsSuppressed(conn, suppression.Email)
+	if err != nil {
+		return err
+	}
+
+	if suppressed {
+		return nil
+	}
+
+	err = conn.Insert(&suppression)
+	if err != nil {
+		if db.IsDuplicateError(err) {
+			return nil
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (repo SuppressionsRepo) IsSuppressed(conn ConnectionInterface, email string) (bool, error) {
+	_, err := repo.find(conn, email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (repo SuppressionsRepo) find(conn ConnectionInterface, email string) (Suppression, error) {
+	suppression := Suppression{}
+	err := conn.SelectOne(&suppression, "SELECT * FROM `suppressions` WHERE `email` = ?", strings.ToLower(email))
+	if err != nil {
+		return Suppression{}, err
+	}
+
+	return suppression, nil
+}
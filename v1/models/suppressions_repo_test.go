@@ -0,0 +1,57 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SuppressionsRepo", func() {
+	var (
+		repo models.SuppressionsRepo
+		conn *db.Connection
+	)
+
+	BeforeEach(func() {
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection().(*db.Connection)
+		repo = models.NewSuppressionsRepo()
+	})
+
+	It("suppresses an email address, ignoring its case", func() {
+		err := repo.Add(conn, models.Suppression{
+			Email:     "Someone@Example.com",
+			Reason:    models.SuppressionReasonBounce,
+			MessageID: "some-message-id",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressed, err := repo.IsSuppressed(conn, "someone@example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(suppressed).To(BeTrue())
+	})
+
+	It("does not suppress other email addresses", func() {
+		suppressed, err := repo.IsSuppressed(conn, "someone@example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(suppressed).To(BeFalse())
+	})
+
+	It("keeps the original suppression when an address is suppressed again", func() {
+		err := repo.Add(conn, models.Suppression{Email: "someone@example.com", Reason: models.SuppressionReasonBounce})
+		Expect(err).NotTo(HaveOccurred())
+
+		err = repo.Add(conn, models.Suppression{Email: "someone@example.com", Reason: models.SuppressionReasonComplaint})
+		Expect(err).NotTo(HaveOccurred())
+
+		suppressions := []models.Suppression{}
+		_, err = conn.Select(&suppressions, "SELECT * FROM `suppressions`")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(suppressions).To(HaveLen(1))
+		Expect(suppressions[0].Reason).To(Equal(models.SuppressionReasonBounce))
+	})
+})
@@ -24,6 +24,8 @@ type Template struct {
 	CreatedAt  time.Time `db:"created_at"`
 	UpdatedAt  time.Time `db:"updated_at"`
 	Overridden bool      `db:"overridden"`
+	Version    int       `db:"version"`
+	Locales    string    `db:"locales"`
 }
 
 func (t *Template) PreInsert(s gorp.SqlExecutor) error {
@@ -40,5 +42,13 @@ func (t *Template) PreInsert(s gorp.SqlExecutor) error {
 	}
 	t.UpdatedAt = t.CreatedAt
 
+	if t.Version == 0 {
+		t.Version = 1
+	}
+
+	if t.Locales == "" {
+		t.Locales = "{}"
+	}
+
 	return nil
 }
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var localeFormat = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{1,8})*$`)
+
+// TemplateVariant holds the content of a template translated for a locale.
+// A field left empty falls back to the content of the template itself.
+type TemplateVariant struct {
+	Subject string `json:"subject,omitempty"`
+	Text    string `json:"text,omitempty"`
+	HTML    string `json:"html,omitempty"`
+}
+
+// NormalizeLocale lowercases the locale and replaces underscores with
+// hyphens, so that "de_DE", "de-DE" and "DE-de" all name the same locale.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(locale), "_", "-", -1))
+}
+
+// ValidLocale reports whether the locale is a language tag like "de" or
+// "pt-BR", in either case and with either separator.
+func ValidLocale(locale string) bool {
+	return localeFormat.MatchString(NormalizeLocale(locale))
+}
+
+// ParseTemplateLocales reads the locale variants stored on a template or one
+// of its versions, keyed by normalized locale.
+func ParseTemplateLocales(locales string) (map[string]TemplateVariant, error) {
+	variants := map[string]TemplateVariant{}
+	if locales == "" {
+		return variants, nil
+	}
+
+	var stored map[string]TemplateVariant
+	err := json.Unmarshal([]byte(locales), &stored)
+	if err != nil {
+		return variants, err
+	}
+
+	for locale, variant := range stored {
+		variants[NormalizeLocale(locale)] = variant
+	}
+
+	return variants, nil
+}
+
+// Localized returns the template with the content of the variants that
+// match the locale. The fallback chain starts with the locale itself and
+// drops its subtags one at a time, so "de-CH" falls back to "de" and then to
+// the template. Each field is taken from the first variant in the chain that
+// sets it.
+func (t Template) Localized(locale string) (Template, error) {
+	locale = NormalizeLocale(locale)
+	if locale == "" {
+		return t, nil
+	}
+
+	variants, err := ParseTemplateLocales(t.Locales)
+	if err != nil {
+		return t, err
+	}
+
+	chain := []string{locale}
+	for index := strings.LastIndex(locale, "-"); index > 0; index = strings.LastIndex(locale, "-") {
+		locale = locale[:index]
+		chain = append(chain, locale)
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		variant := variants[chain[i]]
+
+		if variant.Subject != "" {
+			t.Subject = variant.Subject
+		}
+
+		if variant.Text != "" {
+			t.Text = variant.Text
+		}
+
+		if variant.HTML != "" {
+			t.HTML = variant.HTML
+		}
+	}
+
+	return t, nil
+}
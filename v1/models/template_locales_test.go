@@ -0,0 +1,129 @@
+package models_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Template locales", func() {
+	Describe("NormalizeLocale", func() {
+		It("lowercases the locale and uses hyphens as the separator", func() {
+			Expect(models.NormalizeLocale("de_DE")).To(Equal("de-de"))
+			Expect(models.NormalizeLocale(" pt-BR ")).To(Equal("pt-br"))
+			Expect(models.NormalizeLocale("")).To(Equal(""))
+		})
+	})
+
+	Describe("ValidLocale", func() {
+		It("accepts language tags", func() {
+			Expect(models.ValidLocale("en")).To(BeTrue())
+			Expect(models.ValidLocale("de_DE")).To(BeTrue())
+			Expect(models.ValidLocale("zh-Hant-TW")).To(BeTrue())
+		})
+
+		It("rejects anything else", func() {
+			Expect(models.ValidLocale("")).To(BeFalse())
+			Expect(models.ValidLocale("german")).To(BeFalse())
+			Expect(models.ValidLocale("de--DE")).To(BeFalse())
+			Expect(models.ValidLocale("../de")).To(BeFalse())
+		})
+	})
+
+	Describe("ParseTemplateLocales", func() {
+		It("keys the variants by normalized locale", func() {
+			variants, err := models.ParseTemplateLocales(`{"de_DE": {"subject": "Betreff"}, "ja": {"text": "テキスト"}}`)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variants).To(Equal(map[string]models.TemplateVariant{
+				"de-de": {Subject: "Betreff"},
+				"ja":    {Text: "テキスト"},
+			}))
+		})
+
+		It("treats an empty value as having no variants", func() {
+			variants, err := models.ParseTemplateLocales("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(variants).To(BeEmpty())
+		})
+
+		It("returns an error when the value is not valid JSON", func() {
+			_, err := models.ParseTemplateLocales("{")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Localized", func() {
+		var template models.Template
+
+		BeforeEach(func() {
+			template = models.Template{
+				ID:      "some-template-id",
+				Subject: "Subject",
+				Text:    "Text",
+				HTML:    "<p>HTML</p>",
+				Locales: `{
+					"de": {"subject": "Betreff", "text": "Text auf Deutsch", "html": "<p>Deutsch</p>"},
+					"de-CH": {"subject": "Betreff (CH)"},
+					"ja": {"text": "テキスト"}
+				}`,
+			}
+		})
+
+		It("uses the variant that matches the locale exactly", func() {
+			localized, err := template.Localized("de")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized.Subject).To(Equal("Betreff"))
+			Expect(localized.Text).To(Equal("Text auf Deutsch"))
+			Expect(localized.HTML).To(Equal("<p>Deutsch</p>"))
+			Expect(localized.ID).To(Equal("some-template-id"))
+		})
+
+		It("matches the locale regardless of case and separator", func() {
+			localized, err := template.Localized("DE_ch")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized.Subject).To(Equal("Betreff (CH)"))
+		})
+
+		It("takes the fields a regional variant leaves empty from its language", func() {
+			localized, err := template.Localized("de-CH")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized.Subject).To(Equal("Betreff (CH)"))
+			Expect(localized.Text).To(Equal("Text auf Deutsch"))
+			Expect(localized.HTML).To(Equal("<p>Deutsch</p>"))
+		})
+
+		It("falls back to the language when the region has no variant", func() {
+			localized, err := template.Localized("de-AT")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized.Subject).To(Equal("Betreff"))
+		})
+
+		It("falls back to the template for the fields the variant leaves empty", func() {
+			localized, err := template.Localized("ja")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized.Subject).To(Equal("Subject"))
+			Expect(localized.Text).To(Equal("テキスト"))
+			Expect(localized.HTML).To(Equal("<p>HTML</p>"))
+		})
+
+		It("returns the template unchanged when no variant matches", func() {
+			localized, err := template.Localized("fr-FR")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized).To(Equal(template))
+		})
+
+		It("returns the template unchanged when there is no locale", func() {
+			localized, err := template.Localized("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(localized).To(Equal(template))
+		})
+
+		It("returns an error when the variants cannot be read", func() {
+			template.Locales = "{"
+
+			_, err := template.Localized("de")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gopkg.in/gorp.v1"
+)
+
+// TemplateVersion is an immutable copy of a template as it was saved, along
+// with the client that saved it.
+type TemplateVersion struct {
+	Primary    int       `db:"primary"`
+	TemplateID string    `db:"template_id"`
+	Version    int       `db:"version"`
+	Name       string    `db:"name"`
+	Subject    string    `db:"subject"`
+	Text       string    `db:"text"`
+	HTML       string    `db:"html"`
+	Metadata   string    `db:"metadata"`
+	Locales    string    `db:"locales"`
+	ClientID   string    `db:"client_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// NewTemplateVersion copies the content of the template into a version
+// authored by the given client.
+func NewTemplateVersion(template Template, clientID string) TemplateVersion {
+	return TemplateVersion{
+		TemplateID: template.ID,
+		Version:    template.Version,
+		Name:       template.Name,
+		Subject:    template.Subject,
+		Text:       template.Text,
+		HTML:       template.HTML,
+		Metadata:   template.Metadata,
+		Locales:    template.Locales,
+		ClientID:   clientID,
+		CreatedAt:  template.UpdatedAt,
+	}
+}
+
+func (v *TemplateVersion) PreInsert(executor gorp.SqlExecutor) error {
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now().Truncate(1 * time.Second).UTC()
+	}
+
+	if v.Locales == "" {
+		v.Locales = "{}"
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package models_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/db"
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateVersionsRepo", func() {
+	var (
+		repo      models.TemplateVersionsRepo
+		conn      db.ConnectionInterface
+		createdAt time.Time
+	)
+
+	BeforeEach(func() {
+		repo = models.NewTemplateVersionsRepo()
+		database := db.NewDatabase(sqlDB, db.Config{})
+		helpers.TruncateTables(database)
+		conn = database.Connection()
+		createdAt = time.Now().Add(-1 * time.Hour).Truncate(1 * time.Second).UTC()
+	})
+
+	Describe("Create", func() {
+		It("stores the version", func() {
+			version, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "some-name",
+				Subject:    "some-subject",
+				Text:       "some-text",
+				HTML:       "<p>some-html</p>",
+				Metadata:   "{}",
+				Locales:    `{"de": {"subject": "ein Betreff"}}`,
+				ClientID:   "some-client",
+				CreatedAt:  createdAt,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			foundVersion, err := repo.Find(conn, "some-template-id", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(foundVersion).To(Equal(version))
+		})
+
+		It("sets the creation time and locales when they are missing", func() {
+			version, err := repo.Create(conn, models.TemplateVersion{
+				TemplateID: "some-template-id",
+				Version:    1,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(version.CreatedAt).To(BeTemporally("~", time.Now().UTC(), 2*time.Second))
+			Expect(version.Locales).To(Equal("{}"))
+		})
+
+		It("refuses to store the same version twice", func() {
+			_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: 1})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Find", func() {
+		It("returns a not found error when the version does not exist", func() {
+			_, err := repo.Find(conn, "some-template-id", 7)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("Version 7 of template with ID \"some-template-id\" could not be found")}))
+		})
+	})
+
+	Describe("ListByTemplateID", func() {
+		It("lists the versions of the template, newest first", func() {
+			for _, version := range []int{1, 3, 2} {
+				_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "some-template-id", Version: version})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			_, err := repo.Create(conn, models.TemplateVersion{TemplateID: "other-template-id", Version: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			versions, err := repo.ListByTemplateID(conn, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+
+			var numbers []int
+			for _, version := range versions {
+				numbers = append(numbers, version.Version)
+			}
+			Expect(numbers).To(Equal([]int{3, 2, 1}))
+		})
+	})
+})
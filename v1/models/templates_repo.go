@@ -24,9 +24,16 @@ func (repo TemplatesRepo) FindByID(conn ConnectionInterface, templateID string)
 	return template, nil
 }
 
+// Update bumps the version of the template. The row is locked while it is
+// read, so that concurrent updates made in transactions are given distinct
+// versions instead of both writing the next one.
 func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, template Template) (Template, error) {
-	existingTemplate, err := repo.FindByID(conn, templateID)
+	existingTemplate := Template{}
+	err := conn.SelectOne(&existingTemplate, "SELECT * FROM `templates` WHERE `id`=? FOR UPDATE", templateID)
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return existingTemplate, NotFoundError{fmt.Errorf("Template with ID %q could not be found", templateID)}
+		}
 		return existingTemplate, err
 	}
 
@@ -35,6 +42,7 @@ func (repo TemplatesRepo) Update(conn ConnectionInterface, templateID string, te
 	template.CreatedAt = existingTemplate.CreatedAt
 	template.UpdatedAt = time.Now().Truncate(1 * time.Second).UTC()
 	template.Overridden = true
+	template.Version = existingTemplate.Version + 1
 
 	_, err = conn.Update(&template)
 	if err != nil {
@@ -53,6 +61,15 @@ func (repo TemplatesRepo) ListIDsAndNames(conn ConnectionInterface) ([]Template,
 	return templates, nil
 }
 
+func (repo TemplatesRepo) FindAll(conn ConnectionInterface) ([]Template, error) {
+	templates := []Template{}
+	_, err := conn.Select(&templates, "SELECT * FROM `templates`")
+	if err != nil {
+		return []Template{}, err
+	}
+	return templates, nil
+}
+
 func (repo TemplatesRepo) Create(conn ConnectionInterface, template Template) (Template, error) {
 	err := conn.Insert(&template)
 	if err != nil {
@@ -69,6 +86,10 @@ func (repo TemplatesRepo) Destroy(conn ConnectionInterface, templateID string) e
 	}
 
 	_, err = conn.Delete(&template)
+	if err != nil {
+		return err
+	}
 
+	_, err = conn.Exec("DELETE FROM `template_versions` WHERE `template_id` = ?", templateID)
 	return err
 }
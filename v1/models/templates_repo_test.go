@@ -86,6 +86,8 @@ var _ = Describe("TemplatesRepo", func() {
 			Expect(foundTemplate.HTML).To(Equal(newTemplate.HTML))
 			Expect(foundTemplate.CreatedAt).To(BeTemporally("~", time.Now().UTC(), 2*time.Second))
 			Expect(foundTemplate.UpdatedAt).To(BeTemporally("~", time.Now().UTC(), 2*time.Second))
+			Expect(foundTemplate.Version).To(Equal(1))
+			Expect(foundTemplate.Locales).To(Equal("{}"))
 		})
 	})
 
@@ -99,6 +101,7 @@ var _ = Describe("TemplatesRepo", func() {
 				Text:     "some newer text",
 				HTML:     "<p>new HTML</p>",
 				Metadata: "{\"cloudy\": true}",
+				Locales:  `{"de": {"subject": "Ein neuer Betreff"}}`,
 			}
 		})
 
@@ -117,10 +120,21 @@ var _ = Describe("TemplatesRepo", func() {
 				Expect(foundTemplate.Text).To(Equal(aNewTemplate.Text))
 				Expect(foundTemplate.HTML).To(Equal(aNewTemplate.HTML))
 				Expect(foundTemplate.Metadata).To(Equal(aNewTemplate.Metadata))
+				Expect(foundTemplate.Locales).To(Equal(aNewTemplate.Locales))
 				Expect(foundTemplate.CreatedAt).To(Equal(createdAt))
 				Expect(foundTemplate.UpdatedAt).ToNot(Equal(createdAt))
 				Expect(foundTemplate.UpdatedAt).To(BeTemporally(">", createdAt))
 				Expect(foundTemplate.Overridden).To(BeTrue())
+				Expect(foundTemplate.Version).To(Equal(2))
+			})
+
+			It("increments the version on every update", func() {
+				_, err := repo.Update(conn, template.ID, aNewTemplate)
+				Expect(err).ToNot(HaveOccurred())
+
+				updatedTemplate, err := repo.Update(conn, template.ID, aNewTemplate)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(updatedTemplate.Version).To(Equal(3))
 			})
 		})
 
@@ -166,6 +180,33 @@ var _ = Describe("TemplatesRepo", func() {
 		})
 	})
 
+	Describe("#FindAll", func() {
+		It("returns every template in full", func() {
+			secondTemplate := models.Template{
+				ID:        "star_template",
+				Name:      "Shooting Stars",
+				Text:      "pretty",
+				HTML:      "<h1>Awe</h1>",
+				CreatedAt: createdAt,
+			}
+
+			conn.Insert(&secondTemplate)
+
+			templates, err := repo.FindAll(conn)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(templates).To(HaveLen(2))
+
+			texts := map[string]string{}
+			for _, t := range templates {
+				texts[t.ID] = t.Text
+			}
+			Expect(texts).To(Equal(map[string]string{
+				"raptor_template": "run and hide",
+				"star_template":   "pretty",
+			}))
+		})
+	})
+
 	Describe("#Destroy", func() {
 		Context("the template exists in the database", func() {
 			It("deletes the template by templateID", func() {
@@ -180,6 +221,19 @@ var _ = Describe("TemplatesRepo", func() {
 				_, err = repo.FindByID(conn, template.ID)
 				Expect(err).To(MatchError(models.NotFoundError{Err: fmt.Errorf("Template with ID %q could not be found", template.ID)}))
 			})
+
+			It("deletes the versions of the template", func() {
+				versionsRepo := models.NewTemplateVersionsRepo()
+				_, err := versionsRepo.Create(conn, models.NewTemplateVersion(template, "some-client"))
+				Expect(err).ToNot(HaveOccurred())
+
+				err = repo.Destroy(conn, template.ID)
+				Expect(err).ToNot(HaveOccurred())
+
+				versions, err := versionsRepo.ListByTemplateID(conn, template.ID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(versions).To(BeEmpty())
+			})
 		})
 
 		Context("the template does not exist in the database", func() {
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type messagesCanceller interface {
+	FindByID(conn models.ConnectionInterface, messageID string) (models.Message, error)
+	ListCancellableBySendID(conn models.ConnectionInterface, sendID string) ([]models.Message, error)
+	Cancel(conn models.ConnectionInterface, messageID string) (bool, error)
+}
+
+type sendsCanceller interface {
+	FindByID(conn models.ConnectionInterface, sendID string) (models.Send, error)
+	MarkCancelled(conn models.ConnectionInterface, sendID string) error
+}
+
+type jobRemover interface {
+	Remove(ids []int, connection gobble.ExecutorInterface) (int, error)
+}
+
+// Canceller stops messages that have not been delivered yet. The jobs of the
+// cancelled messages are removed from the queue unless a worker has already
+// reserved them, in which case the worker drops the message before sending
+// it. Only the client that sent a message, or an admin, may cancel it.
+type Canceller struct {
+	messagesRepo      messagesCanceller
+	messageEventsRepo messageEventsCreator
+	sendsRepo         sendsCanceller
+	queue             jobRemover
+}
+
+func NewCanceller(messagesRepo messagesCanceller, messageEventsRepo messageEventsCreator, sendsRepo sendsCanceller, queue jobRemover) Canceller {
+	return Canceller{
+		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
+		sendsRepo:         sendsRepo,
+		queue:             queue,
+	}
+}
+
+func (c Canceller) CancelMessage(database DatabaseInterface, messageID, clientID string, admin bool) error {
+	transaction := database.Connection().Transaction()
+	if err := transaction.Begin(); err != nil {
+		return err
+	}
+
+	message, err := c.messagesRepo.FindByID(transaction, messageID)
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if !admin && message.ClientID != clientID {
+		transaction.Rollback()
+		return CancelForbiddenError{}
+	}
+
+	if message.Status == models.MessageStatusCancelled {
+		transaction.Rollback()
+		return nil
+	}
+
+	cancelled, err := c.cancel(transaction, []models.Message{message})
+	if err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if cancelled == 0 {
+		transaction.Rollback()
+		return MessageNotCancellableError{fmt.Errorf("Message with ID %q can no longer be cancelled", messageID)}
+	}
+
+	return transaction.Commit()
+}
+
+// CancelSend cancels every message of the send that has not been delivered
+// yet, and returns how many were cancelled. Recipients that are resolved
+// after the send is cancelled are not delivered to.
+func (c Canceller) CancelSend(database DatabaseInterface, sendID, clientID string, admin bool) (int, error) {
+	transaction := database.Connection().Transaction()
+	if err := transaction.Begin(); err != nil {
+		return 0, err
+	}
+
+	send, err := c.sendsRepo.FindByID(transaction, sendID)
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	if !admin && send.ClientID != clientID {
+		transaction.Rollback()
+		return 0, CancelForbiddenError{}
+	}
+
+	err = c.sendsRepo.MarkCancelled(transaction, sendID)
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	messages, err := c.messagesRepo.ListCancellableBySendID(transaction, sendID)
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	cancelled, err := c.cancel(transaction, messages)
+	if err != nil {
+		transaction.Rollback()
+		return 0, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return 0, err
+	}
+
+	return cancelled, nil
+}
+
+func (c Canceller) cancel(conn ConnectionInterface, messages []models.Message) (int, error) {
+	var jobIDs []int
+	var cancelled int
+
+	for _, message := range messages {
+		ok, err := c.messagesRepo.Cancel(conn, message.ID)
+		if err != nil {
+			return 0, err
+		}
+
+		if !ok {
+			continue
+		}
+		cancelled++
+
+		_, err = c.messageEventsRepo.Create(conn, models.MessageEvent{
+			MessageID: message.ID,
+			Type:      models.MessageEventCancelled,
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		if message.JobID != 0 {
+			jobIDs = append(jobIDs, message.JobID)
+		}
+	}
+
+	_, err := c.queue.Remove(jobIDs, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	return cancelled, nil
+}
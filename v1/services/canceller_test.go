@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Canceller", func() {
+	var (
+		canceller         services.Canceller
+		messagesRepo      *mocks.MessagesRepo
+		messageEventsRepo *mocks.MessageEventsRepo
+		sendsRepo         *mocks.SendsRepo
+		queue             *mocks.Queue
+		database          *mocks.Database
+		conn              *mocks.Connection
+		transaction       *mocks.Transaction
+	)
+
+	BeforeEach(func() {
+		messagesRepo = mocks.NewMessagesRepo()
+		messageEventsRepo = mocks.NewMessageEventsRepo()
+		sendsRepo = mocks.NewSendsRepo()
+		queue = mocks.NewQueue()
+
+		transaction = mocks.NewTransaction()
+		conn = mocks.NewConnection()
+		conn.TransactionCall.Returns.Transaction = transaction
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		canceller = services.NewCanceller(messagesRepo, messageEventsRepo, sendsRepo, queue)
+	})
+
+	Describe("CancelMessage", func() {
+		BeforeEach(func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:       "some-message-id",
+				ClientID: "some-client",
+				Status:   models.MessageStatusScheduled,
+				JobID:    42,
+			}
+			messagesRepo.CancelCall.Returns.Cancelled = map[string]bool{"some-message-id": true}
+		})
+
+		It("cancels the message and removes its job", func() {
+			err := canceller.CancelMessage(database, "some-message-id", "some-client", false)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(transaction))
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(messagesRepo.CancelCall.Receives.MessageIDs).To(Equal([]string{"some-message-id"}))
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(Equal([]models.MessageEvent{
+				{MessageID: "some-message-id", Type: models.MessageEventCancelled},
+			}))
+			Expect(queue.RemoveCall.Receives.IDs).To(Equal([]int{42}))
+			Expect(queue.RemoveCall.Receives.Connection).To(Equal(transaction))
+
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		It("lets an admin cancel the message of another client", func() {
+			err := canceller.CancelMessage(database, "some-message-id", "other-client", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messagesRepo.CancelCall.Receives.MessageIDs).To(Equal([]string{"some-message-id"}))
+		})
+
+		It("does nothing when the message has already been cancelled", func() {
+			messagesRepo.FindByIDCall.Returns.Message.Status = models.MessageStatusCancelled
+
+			err := canceller.CancelMessage(database, "some-message-id", "some-client", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messagesRepo.CancelCall.Receives.MessageIDs).To(BeEmpty())
+		})
+
+		Context("when the message belongs to another client", func() {
+			It("returns a forbidden error", func() {
+				err := canceller.CancelMessage(database, "some-message-id", "other-client", false)
+				Expect(err).To(MatchError(services.CancelForbiddenError{}))
+				Expect(messagesRepo.CancelCall.Receives.MessageIDs).To(BeEmpty())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the message has already been delivered", func() {
+			It("returns a not cancellable error", func() {
+				messagesRepo.CancelCall.Returns.Cancelled = map[string]bool{}
+
+				err := canceller.CancelMessage(database, "some-message-id", "some-client", false)
+				Expect(err).To(BeAssignableToTypeOf(services.MessageNotCancellableError{}))
+				Expect(messageEventsRepo.CreateCall.Receives.Events).To(BeEmpty())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the message cannot be found", func() {
+			It("returns the error", func() {
+				messagesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				err := canceller.CancelMessage(database, "some-message-id", "some-client", false)
+				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			})
+		})
+
+		Context("when the job cannot be removed", func() {
+			It("rolls back and returns the error", func() {
+				queue.RemoveCall.Returns.Error = errors.New("database is down")
+
+				err := canceller.CancelMessage(database, "some-message-id", "some-client", false)
+				Expect(err).To(MatchError(errors.New("database is down")))
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("CancelSend", func() {
+		BeforeEach(func() {
+			sendsRepo.FindByIDCall.Returns.Send = models.Send{
+				ID:       "some-send-id",
+				ClientID: "some-client",
+			}
+			messagesRepo.ListCancellableBySendIDCall.Returns.Messages = []models.Message{
+				{ID: "message-1", JobID: 1},
+				{ID: "message-2", JobID: 2},
+				{ID: "message-3", JobID: 3},
+			}
+			messagesRepo.CancelCall.Returns.Cancelled = map[string]bool{
+				"message-1": true,
+				"message-3": true,
+			}
+		})
+
+		It("marks the send as cancelled and cancels its messages", func() {
+			cancelled, err := canceller.CancelSend(database, "some-send-id", "some-client", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cancelled).To(Equal(2))
+
+			Expect(sendsRepo.MarkCancelledCall.Receives.Connection).To(Equal(transaction))
+			Expect(sendsRepo.MarkCancelledCall.Receives.SendID).To(Equal("some-send-id"))
+			Expect(messagesRepo.ListCancellableBySendIDCall.Receives.SendID).To(Equal("some-send-id"))
+			Expect(messagesRepo.CancelCall.Receives.MessageIDs).To(Equal([]string{"message-1", "message-2", "message-3"}))
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(Equal([]models.MessageEvent{
+				{MessageID: "message-1", Type: models.MessageEventCancelled},
+				{MessageID: "message-3", Type: models.MessageEventCancelled},
+			}))
+			Expect(queue.RemoveCall.Receives.IDs).To(Equal([]int{1, 3}))
+
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		Context("when the send belongs to another client", func() {
+			It("returns a forbidden error", func() {
+				_, err := canceller.CancelSend(database, "some-send-id", "other-client", false)
+				Expect(err).To(MatchError(services.CancelForbiddenError{}))
+				Expect(sendsRepo.MarkCancelledCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the send cannot be found", func() {
+			It("returns the error", func() {
+				sendsRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				_, err := canceller.CancelSend(database, "some-send-id", "some-client", false)
+				Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+			})
+		})
+
+		Context("when the messages cannot be listed", func() {
+			It("rolls back and returns the error", func() {
+				messagesRepo.ListCancellableBySendIDCall.Returns.Error = errors.New("database is down")
+
+				_, err := canceller.CancelSend(database, "some-send-id", "some-client", false)
+				Expect(err).To(MatchError(errors.New("database is down")))
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type sendRecipientsFinder interface {
+	FindEnqueuedRecipients(conn models.ConnectionInterface, sendID string, recipients []string) ([]string, error)
+}
+
+type emailResolver interface {
+	Resolve(users []User, uaaHost string) ([]User, error)
+}
+
+// ChunkedEnqueuer enqueues the deliveries of a fan-out job in chunks, each
+// in its own transaction, resolving the email addresses of each chunk of
+// recipients in batches first. Recipients of the chunk that already have a
+// message for the send are skipped, so a fan-out job that is retried picks
+// up where it left off without loading every message of the send.
+type ChunkedEnqueuer struct {
+	enqueuer      enqueuer
+	messagesRepo  sendRecipientsFinder
+	emailResolver emailResolver
+	chunkSize     int
+}
+
+func NewChunkedEnqueuer(enqueuer enqueuer, messagesRepo sendRecipientsFinder, emailResolver emailResolver, chunkSize int) ChunkedEnqueuer {
+	return ChunkedEnqueuer{
+		enqueuer:      enqueuer,
+		messagesRepo:  messagesRepo,
+		emailResolver: emailResolver,
+		chunkSize:     chunkSize,
+	}
+}
+
+func (enqueuer ChunkedEnqueuer) Enqueue(
+	conn ConnectionInterface,
+	users []User,
+	options Options,
+	space cf.CloudControllerSpace,
+	organization cf.CloudControllerOrganization,
+	clientID,
+	uaaHost,
+	scope,
+	vcapRequestID string,
+	reqReceived time.Time) ([]Response, error) {
+
+	var responses []Response
+	for start := 0; start < len(users); start += enqueuer.chunkSize {
+		end := start + enqueuer.chunkSize
+		if end > len(users) {
+			end = len(users)
+		}
+
+		remaining, err := enqueuer.notEnqueued(conn, options.SendID, users[start:end])
+		if err != nil {
+			return responses, err
+		}
+
+		if len(remaining) == 0 {
+			continue
+		}
+
+		chunkUsers, err := enqueuer.emailResolver.Resolve(remaining, uaaHost)
+		if err != nil {
+			return responses, err
+		}
+
+		chunk, err := enqueuer.enqueuer.Enqueue(conn, chunkUsers, options, space, organization, clientID, uaaHost, scope, vcapRequestID, reqReceived)
+		if err != nil {
+			return responses, err
+		}
+
+		responses = append(responses, chunk...)
+	}
+
+	return responses, nil
+}
+
+func (enqueuer ChunkedEnqueuer) notEnqueued(conn ConnectionInterface, sendID string, users []User) ([]User, error) {
+	recipients := make([]string, 0, len(users))
+	for _, user := range users {
+		recipients = append(recipients, user.Recipient())
+	}
+
+	enqueued, err := enqueuer.messagesRepo.FindEnqueuedRecipients(conn, sendID, recipients)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := map[string]bool{}
+	for _, recipient := range enqueued {
+		skip[recipient] = true
+	}
+
+	var remaining []User
+	for _, user := range users {
+		if !skip[user.Recipient()] {
+			remaining = append(remaining, user)
+		}
+	}
+
+	return remaining, nil
+}
@@ -0,0 +1,148 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ChunkedEnqueuer", func() {
+	var (
+		enqueuer     services.ChunkedEnqueuer
+		inner        *mocks.Enqueuer
+		messagesRepo *mocks.MessagesRepo
+		resolver     *mocks.EmailResolver
+		conn         *mocks.Connection
+		users        []services.User
+		reqReceived  time.Time
+	)
+
+	BeforeEach(func() {
+		inner = mocks.NewEnqueuer()
+		inner.EnqueueCall.Returns.Responses = []services.Response{{Status: "queued"}}
+
+		messagesRepo = mocks.NewMessagesRepo()
+		conn = mocks.NewConnection()
+		reqReceived = time.Now()
+
+		users = []services.User{
+			{GUID: "user-1"},
+			{GUID: "user-2"},
+			{GUID: "user-3"},
+			{GUID: "user-4"},
+			{GUID: "user-5"},
+		}
+
+		resolver = mocks.NewEmailResolver()
+
+		enqueuer = services.NewChunkedEnqueuer(inner, messagesRepo, resolver, 2)
+	})
+
+	It("enqueues the users in chunks", func() {
+		responses, err := enqueuer.Enqueue(conn, users, services.Options{SendID: "some-send-id"}, cf.CloudControllerSpace{Name: "the-space"}, cf.CloudControllerOrganization{Name: "the-org"}, "some-client", "uaa", "some.scope", "some-request-id", reqReceived)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(responses).To(HaveLen(3))
+
+		Expect(messagesRepo.FindEnqueuedRecipientsCall.Receives.Connection).To(Equal(conn))
+		Expect(messagesRepo.FindEnqueuedRecipientsCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(messagesRepo.FindEnqueuedRecipientsCall.Receives.RecipientBatches).To(Equal([][]string{
+			{"user-1", "user-2"},
+			{"user-3", "user-4"},
+			{"user-5"},
+		}))
+
+		Expect(inner.EnqueueCall.Receives.UserBatches).To(Equal([][]services.User{
+			{{GUID: "user-1"}, {GUID: "user-2"}},
+			{{GUID: "user-3"}, {GUID: "user-4"}},
+			{{GUID: "user-5"}},
+		}))
+		Expect(inner.EnqueueCall.Receives.Options.SendID).To(Equal("some-send-id"))
+		Expect(inner.EnqueueCall.Receives.Space.Name).To(Equal("the-space"))
+		Expect(inner.EnqueueCall.Receives.Org.Name).To(Equal("the-org"))
+		Expect(inner.EnqueueCall.Receives.Client).To(Equal("some-client"))
+		Expect(inner.EnqueueCall.Receives.UAAHost).To(Equal("uaa"))
+		Expect(inner.EnqueueCall.Receives.Scope).To(Equal("some.scope"))
+		Expect(inner.EnqueueCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+		Expect(inner.EnqueueCall.Receives.RequestReceived).To(Equal(reqReceived))
+	})
+
+	It("resolves the emails of each chunk before enqueueing it", func() {
+		resolver.ResolveCall.Returns.Emails = map[string]string{
+			"user-1": "user-1@example.com",
+			"user-4": "user-4@example.com",
+		}
+
+		_, err := enqueuer.Enqueue(conn, users, services.Options{SendID: "some-send-id"}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "some-client", "uaa", "", "some-request-id", reqReceived)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(resolver.ResolveCall.Receives.UAAHost).To(Equal("uaa"))
+		Expect(resolver.ResolveCall.Receives.UserBatches).To(Equal([][]services.User{
+			{{GUID: "user-1"}, {GUID: "user-2"}},
+			{{GUID: "user-3"}, {GUID: "user-4"}},
+			{{GUID: "user-5"}},
+		}))
+
+		Expect(inner.EnqueueCall.Receives.UserBatches).To(Equal([][]services.User{
+			{{GUID: "user-1", Email: "user-1@example.com"}, {GUID: "user-2"}},
+			{{GUID: "user-3"}, {GUID: "user-4", Email: "user-4@example.com"}},
+			{{GUID: "user-5"}},
+		}))
+	})
+
+	It("skips the recipients that were enqueued by an earlier attempt", func() {
+		messagesRepo.FindEnqueuedRecipientsCall.Returns.Recipients = []string{"user-1", "user-2", "user-4"}
+
+		_, err := enqueuer.Enqueue(conn, users, services.Options{SendID: "some-send-id"}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "some-client", "uaa", "", "some-request-id", reqReceived)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inner.EnqueueCall.Receives.UserBatches).To(Equal([][]services.User{
+			{{GUID: "user-3"}},
+			{{GUID: "user-5"}},
+		}))
+	})
+
+	It("does nothing when every recipient has been enqueued", func() {
+		messagesRepo.FindEnqueuedRecipientsCall.Returns.Recipients = []string{"user-1", "user-2", "user-3", "user-4", "user-5"}
+
+		responses, err := enqueuer.Enqueue(conn, users, services.Options{SendID: "some-send-id"}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "some-client", "uaa", "", "some-request-id", reqReceived)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(responses).To(BeEmpty())
+		Expect(inner.EnqueueCall.WasCalled).To(BeFalse())
+	})
+
+	Context("when the enqueued recipients cannot be found", func() {
+		It("returns the error", func() {
+			messagesRepo.FindEnqueuedRecipientsCall.Returns.Error = errors.New("database is down")
+
+			_, err := enqueuer.Enqueue(conn, users, services.Options{}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "some-client", "uaa", "", "some-request-id", reqReceived)
+			Expect(err).To(MatchError(errors.New("database is down")))
+			Expect(inner.EnqueueCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the emails of a chunk cannot be resolved", func() {
+		It("stops and returns the error", func() {
+			resolver.ResolveCall.Returns.Error = errors.New("uaa is down")
+
+			_, err := enqueuer.Enqueue(conn, users, services.Options{}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "some-client", "uaa", "", "some-request-id", reqReceived)
+			Expect(err).To(MatchError(errors.New("uaa is down")))
+			Expect(inner.EnqueueCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when a chunk fails to enqueue", func() {
+		It("stops and returns the error", func() {
+			inner.EnqueueCall.Returns.Err = errors.New("queue is down")
+
+			_, err := enqueuer.Enqueue(conn, users, services.Options{}, cf.CloudControllerSpace{}, cf.CloudControllerOrganization{}, "some-client", "uaa", "", "some-request-id", reqReceived)
+			Expect(err).To(MatchError(errors.New("queue is down")))
+			Expect(inner.EnqueueCall.CallCount).To(Equal(1))
+		})
+	})
+})
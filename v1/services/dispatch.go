@@ -6,10 +6,14 @@ type Dispatch struct {
 	JobType    string
 	GUID       string
 	Role       string
-	Connection ConnectionInterface
+	Connection ConnectionInterface `json:"-"`
 	UAAHost    string
 	TemplateID string
 	CampaignID string
+	SendID     string
+	SendAt     time.Time
+	Priority   int
+	Locale     string
 
 	VCAPRequest DispatchVCAPRequest
 	Message     DispatchMessage
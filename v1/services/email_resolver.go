@@ -0,0 +1,81 @@
+package services
+
+import "github.com/cloudfoundry-incubator/notifications/uaa"
+
+// userLookupChunkSize caps the number of users looked up in a single UAA
+// /Users request, which keeps the filter short and the results on one page.
+const userLookupChunkSize = 100
+
+type loadsUserEmails interface {
+	Load(guids []string, token string) (map[string]uaa.User, error)
+}
+
+// EmailResolver looks up the email addresses and locales of users in batches
+// when the recipients of a send are expanded, so that their deliveries do not
+// each have to ask UAA for them.
+type EmailResolver struct {
+	tokenLoader loadsTokens
+	userLoader  loadsUserEmails
+}
+
+func NewEmailResolver(tokenLoader loadsTokens, userLoader loadsUserEmails) EmailResolver {
+	return EmailResolver{
+		tokenLoader: tokenLoader,
+		userLoader:  userLoader,
+	}
+}
+
+// Resolve fills in the email address and locale of every user that has a
+// GUID but no email. Users that UAA does not return an email for are left
+// without one and are looked up again when they are delivered to.
+func (resolver EmailResolver) Resolve(users []User, uaaHost string) ([]User, error) {
+	var guids []string
+	for _, user := range users {
+		if user.GUID != "" && user.Email == "" {
+			guids = append(guids, user.GUID)
+		}
+	}
+
+	if len(guids) == 0 {
+		return users, nil
+	}
+
+	token, err := resolver.tokenLoader.Load(uaaHost)
+	if err != nil {
+		return users, err
+	}
+
+	found := map[string]uaa.User{}
+	for start := 0; start < len(guids); start += userLookupChunkSize {
+		end := start + userLookupChunkSize
+		if end > len(guids) {
+			end = len(guids)
+		}
+
+		chunk, err := resolver.userLoader.Load(guids[start:end], token)
+		if err != nil {
+			return users, err
+		}
+
+		for guid, user := range chunk {
+			found[guid] = user
+		}
+	}
+
+	resolved := make([]User, len(users))
+	for i, user := range users {
+		if user.Email == "" {
+			uaaUser := found[user.GUID]
+			if len(uaaUser.Emails) > 0 {
+				user.Email = uaaUser.Emails[0]
+			}
+
+			if user.Locale == "" {
+				user.Locale = uaaUser.Locale
+			}
+		}
+		resolved[i] = user
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/uaa"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EmailResolver", func() {
+	var (
+		resolver    services.EmailResolver
+		tokenLoader *mocks.TokenLoader
+		userLoader  *mocks.UserLoader
+	)
+
+	BeforeEach(func() {
+		tokenLoader = mocks.NewTokenLoader()
+		tokenLoader.LoadCall.Returns.Token = "some-token"
+
+		userLoader = mocks.NewUserLoader()
+		userLoader.LoadCall.Returns.Users = map[string]uaa.User{
+			"user-1": {ID: "user-1", Emails: []string{"user-1@example.com"}, Locale: "de-DE"},
+			"user-3": {ID: "user-3", Emails: []string{"user-3@example.com", "other@example.com"}},
+		}
+
+		resolver = services.NewEmailResolver(tokenLoader, userLoader)
+	})
+
+	It("fills in the emails and locales of the users", func() {
+		users, err := resolver.Resolve([]services.User{
+			{GUID: "user-1"},
+			{GUID: "user-2"},
+			{GUID: "user-3"},
+		}, "some-uaa-host")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(users).To(Equal([]services.User{
+			{GUID: "user-1", Email: "user-1@example.com", Locale: "de-DE"},
+			{GUID: "user-2"},
+			{GUID: "user-3", Email: "user-3@example.com"},
+		}))
+
+		Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("some-uaa-host"))
+		Expect(userLoader.LoadCall.Receives.UserGUIDs).To(Equal([]string{"user-1", "user-2", "user-3"}))
+		Expect(userLoader.LoadCall.Receives.Token).To(Equal("some-token"))
+	})
+
+	It("looks the users up in chunks of 100 with a single token", func() {
+		var users []services.User
+		for i := 0; i < 250; i++ {
+			users = append(users, services.User{GUID: fmt.Sprintf("user-%d", i)})
+		}
+
+		_, err := resolver.Resolve(users, "some-uaa-host")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tokenLoader.LoadCall.CallCount).To(Equal(1))
+		Expect(userLoader.LoadCall.CallCount).To(Equal(3))
+
+		batches := userLoader.LoadCall.Receives.UserGUIDBatches
+		Expect(batches[0]).To(HaveLen(100))
+		Expect(batches[1]).To(HaveLen(100))
+		Expect(batches[2]).To(HaveLen(50))
+		Expect(batches[0][0]).To(Equal("user-0"))
+		Expect(batches[2][49]).To(Equal("user-249"))
+	})
+
+	It("does not look up users that already have an email", func() {
+		users, err := resolver.Resolve([]services.User{
+			{Email: "someone@example.com"},
+			{GUID: "user-1"},
+			{GUID: "user-4", Email: "user-4@example.com"},
+		}, "some-uaa-host")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(userLoader.LoadCall.Receives.UserGUIDs).To(Equal([]string{"user-1"}))
+		Expect(users).To(Equal([]services.User{
+			{Email: "someone@example.com"},
+			{GUID: "user-1", Email: "user-1@example.com", Locale: "de-DE"},
+			{GUID: "user-4", Email: "user-4@example.com"},
+		}))
+	})
+
+	It("does not talk to UAA when there is nothing to resolve", func() {
+		_, err := resolver.Resolve([]services.User{{Email: "someone@example.com"}}, "some-uaa-host")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tokenLoader.LoadCall.CallCount).To(Equal(0))
+		Expect(userLoader.LoadCall.CallCount).To(Equal(0))
+	})
+
+	Context("when the token cannot be loaded", func() {
+		It("returns the error", func() {
+			tokenLoader.LoadCall.Returns.Error = errors.New("uaa is down")
+
+			_, err := resolver.Resolve([]services.User{{GUID: "user-1"}}, "some-uaa-host")
+			Expect(err).To(MatchError(errors.New("uaa is down")))
+			Expect(userLoader.LoadCall.CallCount).To(Equal(0))
+		})
+	})
+
+	Context("when the users cannot be loaded", func() {
+		It("returns the error", func() {
+			userLoader.LoadCall.Returns.Error = errors.New("uaa is down")
+
+			_, err := resolver.Resolve([]services.User{{GUID: "user-1"}}, "some-uaa-host")
+			Expect(err).To(MatchError(errors.New("uaa is down")))
+		})
+	})
+})
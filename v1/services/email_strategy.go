@@ -4,12 +4,14 @@ import (
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
 const EmailEndorsement = "This message was sent directly to your email address."
 
 type EmailStrategy struct {
-	enqueuer enqueuer
+	enqueuer  enqueuer
+	sendsRepo sendCreator
 }
 
 type enqueuer interface {
@@ -26,13 +28,25 @@ type enqueuer interface {
 		reqReceived time.Time) ([]Response, error)
 }
 
-func NewEmailStrategy(enqueuer enqueuer) EmailStrategy {
+func NewEmailStrategy(enqueuer enqueuer, sendsRepo sendCreator) EmailStrategy {
 	return EmailStrategy{
-		enqueuer: enqueuer,
+		enqueuer:  enqueuer,
+		sendsRepo: sendsRepo,
 	}
 }
 
 func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
+	send, err := strategy.sendsRepo.Create(dispatch.Connection, models.Send{
+		ClientID:      dispatch.Client.ID,
+		KindID:        dispatch.Kind.ID,
+		Audience:      AudienceEmail,
+		VCAPRequestID: dispatch.VCAPRequest.ID,
+		Resolved:      true,
+	})
+	if err != nil {
+		return []Response{}, err
+	}
+
 	options := Options{
 		To:                dispatch.Message.To,
 		ReplyTo:           dispatch.Message.ReplyTo,
@@ -43,6 +57,10 @@ func (strategy EmailStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		Endorsement:       EmailEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		SendID:            send.ID,
+		SendAt:            dispatch.SendAt,
+		Priority:          dispatch.Priority,
+		Locale:            dispatch.Locale,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -1,10 +1,12 @@
 package services_test
 
 import (
+	"errors"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 
 	. "github.com/onsi/ginkgo"
@@ -17,13 +19,16 @@ var _ = Describe("EmailStrategy", func() {
 	Describe("Dispatch", func() {
 		var (
 			enqueuer        *mocks.Enqueuer
+			sendsRepo       *mocks.SendsRepo
 			conn            *mocks.Connection
 			requestReceived time.Time
 		)
 
 		BeforeEach(func() {
 			enqueuer = mocks.NewEnqueuer()
-			emailStrategy = services.NewEmailStrategy(enqueuer)
+			sendsRepo = mocks.NewSendsRepo()
+			sendsRepo.CreateCall.Returns.Send = models.Send{ID: "some-send-id"}
+			emailStrategy = services.NewEmailStrategy(enqueuer, sendsRepo)
 			conn = mocks.NewConnection()
 			requestReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:37:35.181067085-07:00")
 		})
@@ -81,6 +86,7 @@ var _ = Describe("EmailStrategy", func() {
 					To:          "dr@strangelove.com",
 					Role:        "",
 					Endorsement: services.EmailEndorsement,
+					SendID:      "some-send-id",
 				}))
 				Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 				Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
@@ -89,6 +95,25 @@ var _ = Describe("EmailStrategy", func() {
 				Expect(enqueuer.EnqueueCall.Receives.VCAPRequestID).To(Equal("some-vcap-request-id"))
 				Expect(enqueuer.EnqueueCall.Receives.RequestReceived).To(Equal(requestReceived))
 				Expect(enqueuer.EnqueueCall.Receives.UAAHost).To(Equal("uaahost"))
+
+				Expect(sendsRepo.CreateCall.Receives.Connection).To(Equal(conn))
+				Expect(sendsRepo.CreateCall.Receives.Send).To(Equal(models.Send{
+					ClientID:      "some-client-id",
+					KindID:        "some-kind-id",
+					Audience:      services.AudienceEmail,
+					VCAPRequestID: "some-vcap-request-id",
+					Resolved:      true,
+				}))
+			})
+		})
+
+		Context("when the send cannot be recorded", func() {
+			It("returns the error without enqueuing anything", func() {
+				sendsRepo.CreateCall.Returns.Error = errors.New("database is down")
+
+				_, err := emailStrategy.Dispatch(services.Dispatch{Connection: conn})
+				Expect(err).To(MatchError(errors.New("database is down")))
+				Expect(enqueuer.EnqueueCall.WasCalled).To(BeFalse())
 			})
 		})
 	})
@@ -10,7 +10,10 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 )
 
-const StatusQueued = "queued"
+const (
+	StatusQueued    = "queued"
+	StatusScheduled = models.MessageStatusScheduled
+)
 
 type Options struct {
 	ReplyTo           string
@@ -24,6 +27,10 @@ type Options struct {
 	Role              string
 	Endorsement       string
 	TemplateID        string
+	SendID            string
+	SendAt            time.Time
+	Priority          int
+	Locale            string
 }
 
 type Delivery struct {
@@ -38,10 +45,16 @@ type Delivery struct {
 	Scope           string
 	VCAPRequestID   string
 	RequestReceived time.Time
+	Locale          string
 }
 
 type messagesRepoUpserter interface {
 	Upsert(models.ConnectionInterface, models.Message) (models.Message, error)
+	SetJobID(conn models.ConnectionInterface, messageID string, jobID int) error
+}
+
+type messageEventsCreator interface {
+	Create(models.ConnectionInterface, models.MessageEvent) (models.MessageEvent, error)
 }
 
 type queueInterface interface {
@@ -55,13 +68,15 @@ type gobbleInitializer interface {
 type Enqueuer struct {
 	queue             queueInterface
 	messagesRepo      messagesRepoUpserter
+	messageEventsRepo messageEventsCreator
 	gobbleInitializer gobbleInitializer
 }
 
-func NewEnqueuer(queue queueInterface, messagesRepo messagesRepoUpserter, gobbleInitializer gobbleInitializer) Enqueuer {
+func NewEnqueuer(queue queueInterface, messagesRepo messagesRepoUpserter, messageEventsRepo messageEventsCreator, gobbleInitializer gobbleInitializer) Enqueuer {
 	return Enqueuer{
 		queue:             queue,
 		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
 		gobbleInitializer: gobbleInitializer,
 	}
 }
@@ -80,6 +95,11 @@ func (enqueuer Enqueuer) Enqueue(
 
 	var responses []Response
 
+	status := StatusQueued
+	if options.SendAt.After(reqReceived) {
+		status = StatusScheduled
+	}
+
 	transaction := conn.Transaction()
 	enqueuer.gobbleInitializer.InitializeDBMap(transaction.GetDbMap())
 
@@ -88,8 +108,24 @@ func (enqueuer Enqueuer) Enqueue(
 	}
 
 	for _, user := range users {
+		recipient := user.Recipient()
+
 		message, err := enqueuer.messagesRepo.Upsert(transaction, models.Message{
-			Status: StatusQueued,
+			Status:        status,
+			Recipient:     recipient,
+			ClientID:      clientID,
+			KindID:        options.KindID,
+			VCAPRequestID: vcapRequestID,
+			SendID:        options.SendID,
+		})
+		if err != nil {
+			transaction.Rollback()
+			return []Response{}, err
+		}
+
+		_, err = enqueuer.messageEventsRepo.Create(transaction, models.MessageEvent{
+			MessageID: message.ID,
+			Type:      models.MessageEventQueued,
 		})
 		if err != nil {
 			transaction.Rollback()
@@ -108,7 +144,15 @@ func (enqueuer Enqueuer) Enqueue(
 			Scope:           scope,
 			VCAPRequestID:   vcapRequestID,
 			RequestReceived: reqReceived,
+			Locale:          user.Locale,
 		})
+		if status == StatusScheduled {
+			// A send_at in the past must not make the job look like it has
+			// been waiting, or it would be aged ahead of the queue; the queue
+			// stamps unscheduled jobs with the time they are enqueued.
+			job.ActiveAt = options.SendAt
+		}
+		job.Priority = options.Priority
 
 		_, err = enqueuer.queue.Enqueue(job, transaction)
 		if err != nil {
@@ -116,9 +160,10 @@ func (enqueuer Enqueuer) Enqueue(
 			return []Response{}, err
 		}
 
-		recipient := user.Email
-		if recipient == "" {
-			recipient = user.GUID
+		err = enqueuer.messagesRepo.SetJobID(transaction, message.ID, job.ID)
+		if err != nil {
+			transaction.Rollback()
+			return []Response{}, err
 		}
 
 		responses = append(responses, Response{
@@ -126,6 +171,7 @@ func (enqueuer Enqueuer) Enqueue(
 			NotificationID: message.ID,
 			Recipient:      recipient,
 			VCAPRequestID:  vcapRequestID,
+			SendID:         options.SendID,
 		})
 	}
 
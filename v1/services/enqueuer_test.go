@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -24,6 +25,7 @@ var _ = Describe("Enqueuer", func() {
 		org               cf.CloudControllerOrganization
 		reqReceived       time.Time
 		messagesRepo      *mocks.MessagesRepo
+		messageEventsRepo *mocks.MessageEventsRepo
 	)
 
 	BeforeEach(func() {
@@ -61,7 +63,9 @@ var _ = Describe("Enqueuer", func() {
 			},
 		}
 
-		enqueuer = services.NewEnqueuer(queue, messagesRepo, gobbleInitializer)
+		messageEventsRepo = mocks.NewMessageEventsRepo()
+
+		enqueuer = services.NewEnqueuer(queue, messagesRepo, messageEventsRepo, gobbleInitializer)
 	})
 
 	Describe("Enqueue", func() {
@@ -99,6 +103,22 @@ var _ = Describe("Enqueuer", func() {
 			}))
 		})
 
+		It("keeps the GUID as the recipient of a user whose email and locale were resolved", func() {
+			users := []services.User{{GUID: "user-1", Email: "user-1@example.com", Locale: "de-DE"}}
+			responses, err := enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0].Recipient).To(Equal("user-1"))
+
+			var delivery services.Delivery
+			err = queue.EnqueueCall.Receives.Jobs[0].Unmarshal(&delivery)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(delivery.UserGUID).To(Equal("user-1"))
+			Expect(delivery.Email).To(Equal("user-1@example.com"))
+			Expect(delivery.Locale).To(Equal("de-DE"))
+		})
+
 		It("enqueues jobs with the deliveries", func() {
 			users := []services.User{
 				{GUID: "user-1"},
@@ -172,16 +192,94 @@ var _ = Describe("Enqueuer", func() {
 		})
 
 		It("upserts a StatusQueued for each of the jobs", func() {
-			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}, {GUID: "user-3"}, {GUID: "user-4"}}
-			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			users := []services.User{{GUID: "user-1"}, {Email: "user-2@example.com"}, {GUID: "user-3"}, {GUID: "user-4"}}
+			enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
 
 			messages := messagesRepo.UpsertCall.Receives.Messages
 			Expect(messages).To(HaveLen(4))
 			Expect(messages).To(Equal([]models.Message{
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
-				{Status: services.StatusQueued},
+				{Status: services.StatusQueued, Recipient: "user-1", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+				{Status: services.StatusQueued, Recipient: "user-2@example.com", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+				{Status: services.StatusQueued, Recipient: "user-3", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+				{Status: services.StatusQueued, Recipient: "user-4", ClientID: "the-client", KindID: "the-kind", VCAPRequestID: "some-request-id"},
+			}))
+		})
+
+		It("links the messages to the send", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			responses, err := enqueuer.Enqueue(conn, users, services.Options{KindID: "the-kind", SendID: "some-send-id"}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			messages := messagesRepo.UpsertCall.Receives.Messages
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[0].SendID).To(Equal("some-send-id"))
+			Expect(messages[1].SendID).To(Equal("some-send-id"))
+
+			Expect(responses).To(HaveLen(2))
+			Expect(responses[0].SendID).To(Equal("some-send-id"))
+			Expect(responses[1].SendID).To(Equal("some-send-id"))
+		})
+
+		It("schedules the deliveries of a send with a send_at", func() {
+			sendAt := reqReceived.Add(2 * time.Hour)
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{SendAt: sendAt}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			messages := messagesRepo.UpsertCall.Receives.Messages
+			Expect(messages).To(HaveLen(2))
+			Expect(messages[0].Status).To(Equal(services.StatusScheduled))
+			Expect(messages[1].Status).To(Equal(services.StatusScheduled))
+
+			jobs := queue.EnqueueCall.Receives.Jobs
+			Expect(jobs).To(HaveLen(2))
+			Expect(jobs[0].ActiveAt).To(Equal(sendAt))
+			Expect(jobs[1].ActiveAt).To(Equal(sendAt))
+		})
+
+		It("enqueues the deliveries with the priority of the send", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{Priority: gobble.PriorityHigh}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			jobs := queue.EnqueueCall.Receives.Jobs
+			Expect(jobs).To(HaveLen(2))
+			Expect(jobs[0].Priority).To(Equal(gobble.PriorityHigh))
+			Expect(jobs[1].Priority).To(Equal(gobble.PriorityHigh))
+		})
+
+		It("queues the deliveries of a send with a send_at in the past", func() {
+			users := []services.User{{GUID: "user-1"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{SendAt: reqReceived.Add(-time.Hour)}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.UpsertCall.Receives.Messages[0].Status).To(Equal(services.StatusQueued))
+			Expect(queue.EnqueueCall.Receives.Jobs[0].ActiveAt.IsZero()).To(BeTrue())
+		})
+
+		It("records the job that delivers each of the messages", func() {
+			queue.EnqueueCall.Hook = func() {
+				jobs := queue.EnqueueCall.Receives.Jobs
+				jobs[len(jobs)-1].ID = 100 + len(jobs)
+			}
+
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.SetJobIDCall.Receives.Connection).To(Equal(transaction))
+			Expect(messagesRepo.SetJobIDCall.Receives.MessageIDs).To(Equal([]string{"first-random-guid", "second-random-guid"}))
+			Expect(messagesRepo.SetJobIDCall.Receives.JobIDs).To(Equal([]int{101, 102}))
+		})
+
+		It("records a queued event for each of the messages", func() {
+			users := []services.User{{GUID: "user-1"}, {GUID: "user-2"}}
+			enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+			Expect(messageEventsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(Equal([]models.MessageEvent{
+				{MessageID: "first-random-guid", Type: models.MessageEventQueued},
+				{MessageID: "second-random-guid", Type: models.MessageEventQueued},
 			}))
 		})
 
@@ -227,6 +325,24 @@ var _ = Describe("Enqueuer", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("rolls back the transaction when there is an error recording the queued event", func() {
+				messageEventsRepo.CreateCall.Returns.Error = errors.New("BOOM!")
+				_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("rolls back the transaction when the job cannot be recorded on the message", func() {
+				messagesRepo.SetJobIDCall.Returns.Error = errors.New("BOOM!")
+				_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
+
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(err).To(HaveOccurred())
+			})
+
 			It("rolls back the transaction when there is an error in enqueuing", func() {
 				queue.EnqueueCall.Returns.Error = errors.New("BOOM!")
 				_, err := enqueuer.Enqueue(conn, users, services.Options{}, space, org, "the-client", "my-uaa-host", "my.scope", "some-request-id", reqReceived)
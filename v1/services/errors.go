@@ -69,3 +69,53 @@ type DefaultScopeError struct{}
 func (d DefaultScopeError) Error() string {
 	return "You cannot send a notification to a default scope"
 }
+
+type InvalidUnsubscribeIDError struct {
+	Err error
+}
+
+func (e InvalidUnsubscribeIDError) Error() string {
+	return "The unsubscribe ID is invalid: " + e.Err.Error()
+}
+
+type ExpiredUnsubscribeIDError struct{}
+
+func (e ExpiredUnsubscribeIDError) Error() string {
+	return "The unsubscribe ID has expired"
+}
+
+type CancelForbiddenError struct{}
+
+func (e CancelForbiddenError) Error() string {
+	return "Only the client that sent the notification, or a holder of notifications.admin, may cancel it"
+}
+
+type SendForbiddenError struct{}
+
+func (e SendForbiddenError) Error() string {
+	return "Only the client that sent the notification, or a holder of notifications.admin, may view its send"
+}
+
+type MessageNotCancellableError struct {
+	Err error
+}
+
+func (e MessageNotCancellableError) Error() string {
+	return e.Err.Error()
+}
+
+type IdempotencyKeyConflictError struct {
+	Message string
+}
+
+func (e IdempotencyKeyConflictError) Error() string {
+	return e.Message
+}
+
+type TemplateRollbackError struct {
+	Err error
+}
+
+func (e TemplateRollbackError) Error() string {
+	return e.Err.Error()
+}
@@ -39,6 +39,10 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		SendID:            dispatch.SendID,
+		SendAt:            dispatch.SendAt,
+		Priority:          dispatch.Priority,
+		Locale:            dispatch.Locale,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -75,3 +79,8 @@ func (strategy EveryoneStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		dispatch.VCAPRequest.ID,
 		dispatch.VCAPRequest.ReceiptTime)
 }
+
+// Validate accepts every send, there is no audience to check.
+func (strategy EveryoneStrategy) Validate(dispatch Dispatch) error {
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+const FanOutJobType = "fanout"
+
+const (
+	AudienceOrganization = "organization"
+	AudienceSpace        = "space"
+	AudienceUAAScope     = "uaa_scope"
+	AudienceEveryone     = "everyone"
+	AudienceUser         = "user"
+	AudienceEmail        = "email"
+)
+
+// FanOut is the payload of the job that resolves the recipients of an
+// organization, space, scope or everyone send and enqueues a delivery for
+// each of them.
+type FanOut struct {
+	JobType  string
+	Audience string
+	Dispatch Dispatch
+}
+
+type SendResponse struct {
+	SendID        string `json:"send_id"`
+	Status        string `json:"status"`
+	VCAPRequestID string `json:"vcap_request_id"`
+}
+
+type sendCreator interface {
+	Create(models.ConnectionInterface, models.Send) (models.Send, error)
+}
+
+type AudienceValidator interface {
+	Validate(Dispatch) error
+}
+
+// FanOutStrategy accepts a send to an audience that may be too large to
+// resolve during the request. It checks the audience and leaves resolving
+// the recipients to a fan-out job.
+type FanOutStrategy struct {
+	audience          string
+	validator         AudienceValidator
+	queue             queueInterface
+	gobbleInitializer gobbleInitializer
+	sendsRepo         sendCreator
+}
+
+func NewFanOutStrategy(audience string, validator AudienceValidator, queue queueInterface, gobbleInitializer gobbleInitializer, sendsRepo sendCreator) FanOutStrategy {
+	return FanOutStrategy{
+		audience:          audience,
+		validator:         validator,
+		queue:             queue,
+		gobbleInitializer: gobbleInitializer,
+		sendsRepo:         sendsRepo,
+	}
+}
+
+func (strategy FanOutStrategy) Dispatch(dispatch Dispatch) (SendResponse, error) {
+	err := strategy.validator.Validate(dispatch)
+	if err != nil {
+		return SendResponse{}, err
+	}
+
+	transaction := dispatch.Connection.Transaction()
+	strategy.gobbleInitializer.InitializeDBMap(transaction.GetDbMap())
+
+	if err := transaction.Begin(); err != nil {
+		return SendResponse{}, err
+	}
+
+	send, err := strategy.sendsRepo.Create(transaction, models.Send{
+		ClientID:      dispatch.Client.ID,
+		KindID:        dispatch.Kind.ID,
+		Audience:      strategy.audience,
+		VCAPRequestID: dispatch.VCAPRequest.ID,
+	})
+	if err != nil {
+		transaction.Rollback()
+		return SendResponse{}, err
+	}
+
+	dispatch.SendID = send.ID
+	job := gobble.NewJob(FanOut{
+		JobType:  FanOutJobType,
+		Audience: strategy.audience,
+		Dispatch: dispatch,
+	})
+	job.Priority = dispatch.Priority
+
+	_, err = strategy.queue.Enqueue(job, transaction)
+	if err != nil {
+		transaction.Rollback()
+		return SendResponse{}, err
+	}
+
+	if err := transaction.Commit(); err != nil {
+		return SendResponse{}, err
+	}
+
+	status := StatusQueued
+	if dispatch.SendAt.After(dispatch.VCAPRequest.ReceiptTime) {
+		status = StatusScheduled
+	}
+
+	return SendResponse{
+		SendID:        dispatch.SendID,
+		Status:        status,
+		VCAPRequestID: dispatch.VCAPRequest.ID,
+	}, nil
+}
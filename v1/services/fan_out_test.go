@@ -0,0 +1,169 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"gopkg.in/gorp.v1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FanOutStrategy", func() {
+	var (
+		strategy          services.FanOutStrategy
+		validator         *mocks.AudienceValidator
+		queue             *mocks.Queue
+		gobbleInitializer *mocks.GobbleInitializer
+		sendsRepo         *mocks.SendsRepo
+		conn              *mocks.Connection
+		transaction       *mocks.Transaction
+		dispatch          services.Dispatch
+	)
+
+	BeforeEach(func() {
+		validator = mocks.NewAudienceValidator()
+		queue = mocks.NewQueue()
+		gobbleInitializer = mocks.NewGobbleInitializer()
+		sendsRepo = mocks.NewSendsRepo()
+		sendsRepo.CreateCall.Returns.Send = models.Send{ID: "some-send-id"}
+
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		transaction.Connection = mocks.NewConnection()
+		transaction.GetDbMapCall.Returns.DbMap = &gorp.DbMap{}
+		conn.TransactionCall.Returns.Transaction = transaction
+
+		dispatch = services.Dispatch{
+			GUID:       "org-001",
+			Role:       "OrgManager",
+			Connection: conn,
+			UAAHost:    "uaa",
+			Client: services.DispatchClient{
+				ID:          "some-client",
+				Description: "Some Client",
+			},
+			Kind: services.DispatchKind{
+				ID:          "some-kind",
+				Description: "Some Kind",
+			},
+			VCAPRequest: services.DispatchVCAPRequest{
+				ID: "some-request-id",
+			},
+			Message: services.DispatchMessage{
+				Subject: "the subject",
+				Text:    "the text",
+			},
+		}
+
+		strategy = services.NewFanOutStrategy(services.AudienceOrganization, validator, queue, gobbleInitializer, sendsRepo)
+	})
+
+	Describe("Dispatch", func() {
+		It("records the send and enqueues a fan-out job in a transaction", func() {
+			response, err := strategy.Dispatch(dispatch)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(response).To(Equal(services.SendResponse{
+				SendID:        "some-send-id",
+				Status:        "queued",
+				VCAPRequestID: "some-request-id",
+			}))
+
+			Expect(validator.ValidateCall.Receives.Dispatch.GUID).To(Equal("org-001"))
+			Expect(sendsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(sendsRepo.CreateCall.Receives.Send).To(Equal(models.Send{
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				Audience:      services.AudienceOrganization,
+				VCAPRequestID: "some-request-id",
+			}))
+
+			Expect(gobbleInitializer.InitializeDBMapCall.Receives.DbMap).To(Equal(transaction.GetDbMapCall.Returns.DbMap))
+			Expect(queue.EnqueueCall.Receives.Connection).To(Equal(transaction))
+			Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(1))
+
+			var fanOut services.FanOut
+			err = queue.EnqueueCall.Receives.Jobs[0].Unmarshal(&fanOut)
+			Expect(err).NotTo(HaveOccurred())
+
+			expectedDispatch := dispatch
+			expectedDispatch.Connection = nil
+			expectedDispatch.SendID = "some-send-id"
+
+			Expect(fanOut).To(Equal(services.FanOut{
+				JobType:  services.FanOutJobType,
+				Audience: services.AudienceOrganization,
+				Dispatch: expectedDispatch,
+			}))
+
+			Expect(transaction.BeginCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
+		})
+
+		Context("when the send is scheduled", func() {
+			It("resolves the recipients right away and responds that the send is scheduled", func() {
+				dispatch.VCAPRequest.ReceiptTime = time.Now().UTC().Truncate(time.Second)
+				dispatch.SendAt = dispatch.VCAPRequest.ReceiptTime.Add(2 * time.Hour)
+
+				response, err := strategy.Dispatch(dispatch)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(response.Status).To(Equal("scheduled"))
+
+				Expect(queue.EnqueueCall.Receives.Jobs).To(HaveLen(1))
+				Expect(queue.EnqueueCall.Receives.Jobs[0].ActiveAt.IsZero()).To(BeTrue())
+
+				var fanOut services.FanOut
+				err = queue.EnqueueCall.Receives.Jobs[0].Unmarshal(&fanOut)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fanOut.Dispatch.SendAt).To(Equal(dispatch.SendAt))
+			})
+		})
+
+		It("enqueues the fan-out job with the priority of the send", func() {
+			dispatch.Priority = gobble.PriorityHigh
+
+			_, err := strategy.Dispatch(dispatch)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(queue.EnqueueCall.Receives.Jobs[0].Priority).To(Equal(gobble.PriorityHigh))
+		})
+
+		Context("when the audience is not valid", func() {
+			It("returns the error without enqueuing anything", func() {
+				validator.ValidateCall.Returns.Error = errors.New("no such organization")
+
+				_, err := strategy.Dispatch(dispatch)
+				Expect(err).To(MatchError(errors.New("no such organization")))
+				Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+			})
+		})
+
+		Context("when the send cannot be recorded", func() {
+			It("rolls back and returns the error", func() {
+				sendsRepo.CreateCall.Returns.Error = errors.New("database is down")
+
+				_, err := strategy.Dispatch(dispatch)
+				Expect(err).To(MatchError(errors.New("database is down")))
+				Expect(queue.EnqueueCall.Receives.Jobs).To(BeEmpty())
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			})
+		})
+
+		Context("when the job cannot be enqueued", func() {
+			It("returns the error", func() {
+				queue.EnqueueCall.Returns.Error = errors.New("queue is down")
+
+				_, err := strategy.Dispatch(dispatch)
+				Expect(err).To(MatchError(errors.New("queue is down")))
+				Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+				Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+			})
+		})
+	})
+})
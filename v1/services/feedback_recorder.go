@@ -0,0 +1,112 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type feedbackMessagesRepo interface {
+	FindByID(models.ConnectionInterface, string) (models.Message, error)
+	UpdateStatus(conn models.ConnectionInterface, messageID, status string) (models.Message, error)
+}
+
+type suppressionsAdder interface {
+	Add(models.ConnectionInterface, models.Suppression) error
+}
+
+// FeedbackRecorder applies bounce and complaint reports: it adds them to the
+// history of the message they refer to and suppresses the addresses that
+// hard-bounced or complained.
+type FeedbackRecorder struct {
+	messagesRepo      feedbackMessagesRepo
+	messageEventsRepo messageEventsCreator
+	suppressionsRepo  suppressionsAdder
+}
+
+func NewFeedbackRecorder(messagesRepo feedbackMessagesRepo, messageEventsRepo messageEventsCreator, suppressionsRepo suppressionsAdder) FeedbackRecorder {
+	return FeedbackRecorder{
+		messagesRepo:      messagesRepo,
+		messageEventsRepo: messageEventsRepo,
+		suppressionsRepo:  suppressionsRepo,
+	}
+}
+
+// Record applies the report only when it names a known message and the
+// address it reports is the address that message was delivered to. Reports
+// that cannot be matched are dropped, so that a forged report cannot
+// suppress an address the service never sent to.
+func (r FeedbackRecorder) Record(conn ConnectionInterface, report feedback.Report) error {
+	if report.NotificationID == "" {
+		return nil
+	}
+
+	message, err := r.messagesRepo.FindByID(conn, report.NotificationID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return nil
+		}
+		return err
+	}
+
+	deliveredTo := message.DeliveredTo
+	if deliveredTo == "" && strings.Contains(message.Recipient, "@") {
+		deliveredTo = message.Recipient
+	}
+
+	if deliveredTo == "" {
+		return nil
+	}
+
+	if report.Recipient != "" && !strings.EqualFold(report.Recipient, deliveredTo) {
+		return nil
+	}
+
+	err = r.recordEvent(conn, report)
+	if err != nil {
+		return err
+	}
+
+	if !(report.HardBounce() || report.Type == feedback.Complaint) {
+		return nil
+	}
+
+	reason := models.SuppressionReasonBounce
+	if report.Type == feedback.Complaint {
+		reason = models.SuppressionReasonComplaint
+	}
+
+	return r.suppressionsRepo.Add(conn, models.Suppression{
+		Email:       deliveredTo,
+		Reason:      reason,
+		MessageID:   report.NotificationID,
+		Description: report.Description(),
+	})
+}
+
+func (r FeedbackRecorder) recordEvent(conn ConnectionInterface, report feedback.Report) error {
+	eventType := models.MessageEventBounced
+	if report.Type == feedback.Complaint {
+		eventType = models.MessageEventComplained
+	}
+
+	_, err := r.messageEventsRepo.Create(conn, models.MessageEvent{
+		MessageID:   report.NotificationID,
+		Type:        eventType,
+		Description: report.Description(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if report.HardBounce() {
+		_, err = r.messagesRepo.UpdateStatus(conn, report.NotificationID, common.StatusUndeliverable)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
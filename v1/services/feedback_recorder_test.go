@@ -0,0 +1,217 @@
+package services_test
+
+import (
+	"errors"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FeedbackRecorder", func() {
+	var (
+		recorder          services.FeedbackRecorder
+		messagesRepo      *mocks.MessagesRepo
+		messageEventsRepo *mocks.MessageEventsRepo
+		suppressionsRepo  *mocks.SuppressionsRepo
+		conn              *mocks.Connection
+		hardBounce        feedback.Report
+	)
+
+	BeforeEach(func() {
+		messagesRepo = mocks.NewMessagesRepo()
+		messagesRepo.FindByIDCall.Returns.Message = models.Message{
+			ID:          "some-message-id",
+			Recipient:   "some-user-guid",
+			DeliveredTo: "someone@example.com",
+		}
+		messageEventsRepo = mocks.NewMessageEventsRepo()
+		suppressionsRepo = mocks.NewSuppressionsRepo()
+		conn = mocks.NewConnection()
+
+		hardBounce = feedback.Report{
+			Type:           feedback.Bounce,
+			NotificationID: "some-message-id",
+			Recipient:      "someone@example.com",
+			Action:         "failed",
+			Status:         "5.1.1",
+			Diagnostic:     "550 5.1.1 user unknown",
+		}
+
+		recorder = services.NewFeedbackRecorder(messagesRepo, messageEventsRepo, suppressionsRepo)
+	})
+
+	Context("when the report is a hard bounce", func() {
+		It("records the bounce in the message history and marks the message undeliverable", func() {
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(Equal([]models.MessageEvent{
+				{
+					MessageID:   "some-message-id",
+					Type:        models.MessageEventBounced,
+					Description: "failed 5.1.1: 550 5.1.1 user unknown",
+				},
+			}))
+
+			Expect(messagesRepo.UpdateStatusCall.Receives.MessageID).To(Equal("some-message-id"))
+			Expect(messagesRepo.UpdateStatusCall.Receives.Status).To(Equal(common.StatusUndeliverable))
+		})
+
+		It("suppresses the recipient", func() {
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.AddCall.Receives.Connection).To(Equal(conn))
+			Expect(suppressionsRepo.AddCall.Receives.Suppression).To(Equal(models.Suppression{
+				Email:       "someone@example.com",
+				Reason:      models.SuppressionReasonBounce,
+				MessageID:   "some-message-id",
+				Description: "failed 5.1.1: 550 5.1.1 user unknown",
+			}))
+		})
+
+		It("matches the address regardless of case", func() {
+			hardBounce.Recipient = "SomeOne@Example.com"
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.AddCall.Receives.Suppression.Email).To(Equal("someone@example.com"))
+		})
+
+		It("falls back to the address the message was delivered to", func() {
+			hardBounce.Recipient = ""
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.AddCall.Receives.Suppression.Email).To(Equal("someone@example.com"))
+		})
+
+		It("takes the address from the recipient of messages sent before delivered addresses were recorded", func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:        "some-message-id",
+				Recipient: "someone@example.com",
+			}
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.AddCall.Receives.Suppression.Email).To(Equal("someone@example.com"))
+		})
+	})
+
+	Context("when the report cannot be matched to a delivery", func() {
+		It("ignores reports without a notification ID", func() {
+			hardBounce.NotificationID = ""
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(BeEmpty())
+			Expect(suppressionsRepo.AddCall.WasCalled).To(BeFalse())
+		})
+
+		It("ignores reports for messages that are unknown", func() {
+			messagesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(BeEmpty())
+			Expect(suppressionsRepo.AddCall.WasCalled).To(BeFalse())
+		})
+
+		It("ignores reports for an address the message was not delivered to", func() {
+			hardBounce.Recipient = "someone-else@example.com"
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(BeEmpty())
+			Expect(messagesRepo.UpdateStatusCall.Receives.MessageID).To(BeEmpty())
+			Expect(suppressionsRepo.AddCall.WasCalled).To(BeFalse())
+		})
+
+		It("ignores reports for messages whose delivered address is unknown", func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:        "some-message-id",
+				Recipient: "some-user-guid",
+			}
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(suppressionsRepo.AddCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the report is a soft bounce", func() {
+		It("records the bounce without suppressing the recipient", func() {
+			err := recorder.Record(conn, feedback.Report{
+				Type:           feedback.Bounce,
+				NotificationID: "some-message-id",
+				Recipient:      "someone@example.com",
+				Action:         "delayed",
+				Status:         "4.2.2",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(HaveLen(1))
+			Expect(messagesRepo.UpdateStatusCall.Receives.MessageID).To(BeEmpty())
+			Expect(suppressionsRepo.AddCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the report is a complaint", func() {
+		It("records the complaint and suppresses the recipient", func() {
+			err := recorder.Record(conn, feedback.Report{
+				Type:           feedback.Complaint,
+				NotificationID: "some-message-id",
+				Recipient:      "someone@example.com",
+				FeedbackType:   "abuse",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(messageEventsRepo.CreateCall.Receives.Events).To(Equal([]models.MessageEvent{
+				{
+					MessageID:   "some-message-id",
+					Type:        models.MessageEventComplained,
+					Description: "abuse complaint",
+				},
+			}))
+			Expect(messagesRepo.UpdateStatusCall.Receives.MessageID).To(BeEmpty())
+			Expect(suppressionsRepo.AddCall.Receives.Suppression.Reason).To(Equal(models.SuppressionReasonComplaint))
+		})
+	})
+
+	Context("failure cases", func() {
+		It("returns errors from finding the message", func() {
+			messagesRepo.FindByIDCall.Returns.Error = errors.New("db is down")
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+
+		It("returns errors from recording the event", func() {
+			messageEventsRepo.CreateCall.Returns.Error = errors.New("db is down")
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+
+		It("returns errors from suppressing the recipient", func() {
+			suppressionsRepo.AddCall.Returns.Error = errors.New("db is down")
+
+			err := recorder.Record(conn, hardBounce)
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+	})
+})
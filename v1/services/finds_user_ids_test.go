@@ -58,7 +58,7 @@ var _ = Describe("FindsUserIDs", func() {
 		})
 
 		It("returns the user IDs for the space", func() {
-			guids, err := finder.UserIDsBelongingToSpace("space-001", "token")
+			guids, err := finder.UserIDsBelongingToSpace("space-001", "", "token")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(guids).To(Equal([]string{"user-123", "user-789"}))
 
@@ -66,11 +66,51 @@ var _ = Describe("FindsUserIDs", func() {
 			Expect(cc.GetUsersBySpaceGuidCall.Receives.Token).To(Equal("token"))
 		})
 
+		Context("when a role is given", func() {
+			BeforeEach(func() {
+				cc.GetDevelopersBySpaceGuidCall.Returns.Users = []cf.CloudControllerUser{{GUID: "developer-123"}}
+				cc.GetManagersBySpaceGuidCall.Returns.Users = []cf.CloudControllerUser{{GUID: "manager-123"}}
+				cc.GetAuditorsBySpaceGuidCall.Returns.Users = []cf.CloudControllerUser{{GUID: "auditor-123"}}
+			})
+
+			It("returns the developers of the space for SpaceDeveloper", func() {
+				guids, err := finder.UserIDsBelongingToSpace("space-001", "SpaceDeveloper", "token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"developer-123"}))
+
+				Expect(cc.GetDevelopersBySpaceGuidCall.Receives.SpaceGUID).To(Equal("space-001"))
+				Expect(cc.GetDevelopersBySpaceGuidCall.Receives.Token).To(Equal("token"))
+			})
+
+			It("returns the managers of the space for SpaceManager", func() {
+				guids, err := finder.UserIDsBelongingToSpace("space-001", "SpaceManager", "token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"manager-123"}))
+
+				Expect(cc.GetManagersBySpaceGuidCall.Receives.SpaceGUID).To(Equal("space-001"))
+			})
+
+			It("returns the auditors of the space for SpaceAuditor", func() {
+				guids, err := finder.UserIDsBelongingToSpace("space-001", "SpaceAuditor", "token")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(guids).To(Equal([]string{"auditor-123"}))
+
+				Expect(cc.GetAuditorsBySpaceGuidCall.Receives.SpaceGUID).To(Equal("space-001"))
+			})
+
+			It("returns the error from CloudController", func() {
+				cc.GetDevelopersBySpaceGuidCall.Returns.Error = errors.New("BOOM!")
+
+				_, err := finder.UserIDsBelongingToSpace("space-001", "SpaceDeveloper", "token")
+				Expect(err).To(MatchError(errors.New("BOOM!")))
+			})
+		})
+
 		Context("when CloudController causes an error", func() {
 			It("returns the error", func() {
 				cc.GetUsersBySpaceGuidCall.Returns.Error = errors.New("BOOM!")
 
-				_, err := finder.UserIDsBelongingToSpace("space-001", "token")
+				_, err := finder.UserIDsBelongingToSpace("space-001", "", "token")
 				Expect(err).To(MatchError(errors.New("BOOM!")))
 			})
 		})
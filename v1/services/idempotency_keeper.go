@@ -0,0 +1,103 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type idempotencyKeysRepo interface {
+	Create(models.ConnectionInterface, models.IdempotencyKey) (models.IdempotencyKey, error)
+	Find(conn models.ConnectionInterface, clientID, key string) (models.IdempotencyKey, error)
+	SetResponse(conn models.ConnectionInterface, clientID, key, response string) error
+	Delete(conn models.ConnectionInterface, clientID, key string) error
+}
+
+// IdempotencyKeeper remembers the response given to a notify request that
+// carried an Idempotency-Key, so that a retry of the request gets the same
+// response instead of sending the notification again. Keys are scoped to the
+// client that sent them and are forgotten once their lifetime has passed.
+type IdempotencyKeeper struct {
+	repo     idempotencyKeysRepo
+	clock    clock
+	lifetime time.Duration
+}
+
+func NewIdempotencyKeeper(repo idempotencyKeysRepo, clock clock, lifetime time.Duration) IdempotencyKeeper {
+	return IdempotencyKeeper{
+		repo:     repo,
+		clock:    clock,
+		lifetime: lifetime,
+	}
+}
+
+// Reserve claims the key for the request. When the key was already used for
+// the same request, the original response is returned along with true. A key
+// used for a different request, or for a request that is still being
+// processed, is rejected with an IdempotencyKeyConflictError.
+func (k IdempotencyKeeper) Reserve(conn ConnectionInterface, clientID, key, path string, body []byte) ([]byte, bool, error) {
+	requestHash := hashRequest(path, body)
+
+	existing, err := k.repo.Find(conn, clientID, key)
+	switch err.(type) {
+	case nil:
+		if k.clock.Now().After(existing.CreatedAt.Add(k.lifetime)) {
+			err = k.repo.Delete(conn, clientID, key)
+			if err != nil {
+				return nil, false, err
+			}
+			break
+		}
+
+		if existing.RequestHash != requestHash {
+			return nil, false, IdempotencyKeyConflictError{Message: fmt.Sprintf("Idempotency-Key %q was already used for a different request", key)}
+		}
+
+		if existing.Response == "" {
+			return nil, false, IdempotencyKeyConflictError{Message: fmt.Sprintf("A request with Idempotency-Key %q is still being processed", key)}
+		}
+
+		return []byte(existing.Response), true, nil
+	case models.NotFoundError:
+	default:
+		return nil, false, err
+	}
+
+	_, err = k.repo.Create(conn, models.IdempotencyKey{
+		ClientID:    clientID,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   k.clock.Now().Truncate(time.Second).UTC(),
+	})
+	if err != nil {
+		if _, ok := err.(models.DuplicateError); ok {
+			return nil, false, IdempotencyKeyConflictError{Message: fmt.Sprintf("A request with Idempotency-Key %q is still being processed", key)}
+		}
+		return nil, false, err
+	}
+
+	return nil, false, nil
+}
+
+// Complete stores the response given to the request that reserved the key.
+func (k IdempotencyKeeper) Complete(conn ConnectionInterface, clientID, key string, response []byte) error {
+	return k.repo.SetResponse(conn, clientID, key, string(response))
+}
+
+// Release gives up the key after its request failed, so that it can be
+// retried.
+func (k IdempotencyKeeper) Release(conn ConnectionInterface, clientID, key string) error {
+	return k.repo.Delete(conn, clientID, key)
+}
+
+func hashRequest(path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,157 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IdempotencyKeeper", func() {
+	var (
+		keeper      services.IdempotencyKeeper
+		repo        *mocks.IdempotencyKeysRepo
+		clock       *mocks.Clock
+		conn        *mocks.Connection
+		now         time.Time
+		requestHash string
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2015, time.June, 8, 14, 40, 12, 0, time.UTC)
+
+		repo = mocks.NewIdempotencyKeysRepo()
+		repo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = now
+		conn = mocks.NewConnection()
+
+		keeper = services.NewIdempotencyKeeper(repo, clock, 24*time.Hour)
+
+		_, _, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+		Expect(err).NotTo(HaveOccurred())
+		requestHash = repo.CreateCall.Receives.IdempotencyKey.RequestHash
+
+		repo.CreateCall.WasCalled = false
+	})
+
+	Describe("Reserve", func() {
+		It("reserves a key that has not been used", func() {
+			response, found, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+			Expect(response).To(BeNil())
+
+			Expect(repo.FindCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(repo.FindCall.Receives.Key).To(Equal("some-key"))
+			Expect(repo.CreateCall.Receives.Connection).To(Equal(conn))
+			Expect(repo.CreateCall.Receives.IdempotencyKey).To(Equal(models.IdempotencyKey{
+				ClientID:    "some-client",
+				Key:         "some-key",
+				RequestHash: requestHash,
+				CreatedAt:   now,
+			}))
+		})
+
+		It("hashes the path along with the body of the request", func() {
+			_, _, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-002", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.CreateCall.Receives.IdempotencyKey.RequestHash).NotTo(Equal(requestHash))
+		})
+
+		It("returns the original response when the same request is repeated", func() {
+			repo.FindCall.Returns.Error = nil
+			repo.FindCall.Returns.IdempotencyKey = models.IdempotencyKey{
+				RequestHash: requestHash,
+				Response:    `[{"status":"queued"}]`,
+				CreatedAt:   now.Add(-time.Hour),
+			}
+
+			response, found, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(string(response)).To(Equal(`[{"status":"queued"}]`))
+			Expect(repo.CreateCall.WasCalled).To(BeFalse())
+		})
+
+		It("rejects a key that was used for a different request", func() {
+			repo.FindCall.Returns.Error = nil
+			repo.FindCall.Returns.IdempotencyKey = models.IdempotencyKey{
+				RequestHash: "some-other-hash",
+				Response:    `[{"status":"queued"}]`,
+				CreatedAt:   now.Add(-time.Hour),
+			}
+
+			_, _, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).To(MatchError(services.IdempotencyKeyConflictError{Message: `Idempotency-Key "some-key" was already used for a different request`}))
+		})
+
+		It("rejects a key whose request is still being processed", func() {
+			repo.FindCall.Returns.Error = nil
+			repo.FindCall.Returns.IdempotencyKey = models.IdempotencyKey{
+				RequestHash: requestHash,
+				CreatedAt:   now,
+			}
+
+			_, _, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).To(MatchError(services.IdempotencyKeyConflictError{Message: `A request with Idempotency-Key "some-key" is still being processed`}))
+		})
+
+		It("rejects a key that another request reserved first", func() {
+			repo.CreateCall.Returns.Error = models.DuplicateError{Err: errors.New("duplicate record")}
+
+			_, _, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).To(BeAssignableToTypeOf(services.IdempotencyKeyConflictError{}))
+		})
+
+		It("reserves an expired key again", func() {
+			repo.FindCall.Returns.Error = nil
+			repo.FindCall.Returns.IdempotencyKey = models.IdempotencyKey{
+				RequestHash: "some-other-hash",
+				Response:    `[{"status":"queued"}]`,
+				CreatedAt:   now.Add(-25 * time.Hour),
+			}
+
+			_, found, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeFalse())
+
+			Expect(repo.DeleteCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(repo.DeleteCall.Receives.Key).To(Equal("some-key"))
+			Expect(repo.CreateCall.WasCalled).To(BeTrue())
+		})
+
+		It("returns other errors from the repo", func() {
+			repo.FindCall.Returns.Error = errors.New("database is down")
+
+			_, _, err := keeper.Reserve(conn, "some-client", "some-key", "/spaces/space-001", []byte(`{"kind_id":"some-kind"}`))
+			Expect(err).To(MatchError(errors.New("database is down")))
+		})
+	})
+
+	Describe("Complete", func() {
+		It("stores the response for the key", func() {
+			err := keeper.Complete(conn, "some-client", "some-key", []byte(`[{"status":"queued"}]`))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.SetResponseCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(repo.SetResponseCall.Receives.Key).To(Equal("some-key"))
+			Expect(repo.SetResponseCall.Receives.Response).To(Equal(`[{"status":"queued"}]`))
+		})
+	})
+
+	Describe("Release", func() {
+		It("deletes the key", func() {
+			err := keeper.Release(conn, "some-client", "some-key")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(repo.DeleteCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(repo.DeleteCall.Receives.Key).To(Equal("some-key"))
+		})
+	})
+})
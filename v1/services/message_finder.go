@@ -1,30 +1,95 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 type Message struct {
-	Status string
+	Status          string
+	Recipient       string
+	ClientID        string
+	KindID          string
+	VCAPRequestID   string
+	TemplateID      string
+	TemplateVersion int
+	QueuedAt        time.Time
+	UpdatedAt       time.Time
+	Events          []MessageEvent
+}
+
+type MessageEvent struct {
+	Type        string
+	Attempt     int
+	SMTPCode    int
+	Description string
+	CreatedAt   time.Time
 }
 
 type messagesRepoFinder interface {
 	FindByID(models.ConnectionInterface, string) (models.Message, error)
 }
 
+type messageEventsLister interface {
+	ListByMessageID(models.ConnectionInterface, string) ([]models.MessageEvent, error)
+}
+
 type MessageFinder struct {
-	repo messagesRepoFinder
+	repo       messagesRepoFinder
+	eventsRepo messageEventsLister
 }
 
-func NewMessageFinder(repo messagesRepoFinder) MessageFinder {
+func NewMessageFinder(repo messagesRepoFinder, eventsRepo messageEventsLister) MessageFinder {
 	return MessageFinder{
-		repo: repo,
+		repo:       repo,
+		eventsRepo: eventsRepo,
 	}
 }
 
-func (finder MessageFinder) Find(database DatabaseInterface, messageID string) (Message, error) {
-	message, err := finder.repo.FindByID(database.Connection(), messageID)
+// Find returns the message with its delivery history. A message sent by
+// another client is reported as not found, unless the caller is an admin, so
+// that its recipient and history are not revealed.
+func (finder MessageFinder) Find(database DatabaseInterface, messageID, clientID string, admin bool) (Message, error) {
+	conn := database.Connection()
+
+	message, err := finder.repo.FindByID(conn, messageID)
+	if err != nil {
+		return Message{}, err
+	}
+
+	if !admin && message.ClientID != clientID {
+		return Message{}, models.NotFoundError{Err: fmt.Errorf("Message with ID %q could not be found", messageID)}
+	}
+
+	events, err := finder.eventsRepo.ListByMessageID(conn, messageID)
 	if err != nil {
 		return Message{}, err
 	}
 
-	return Message{Status: message.Status}, nil
+	result := Message{
+		Status:          message.Status,
+		Recipient:       message.Recipient,
+		ClientID:        message.ClientID,
+		KindID:          message.KindID,
+		VCAPRequestID:   message.VCAPRequestID,
+		TemplateID:      message.TemplateID,
+		TemplateVersion: message.TemplateVersion,
+		QueuedAt:        message.QueuedAt,
+		UpdatedAt:       message.UpdatedAt,
+		Events:          []MessageEvent{},
+	}
+
+	for _, event := range events {
+		result.Events = append(result.Events, MessageEvent{
+			Type:        event.Type,
+			Attempt:     event.Attempt,
+			SMTPCode:    event.SMTPCode,
+			Description: event.Description,
+			CreatedAt:   event.CreatedAt,
+		})
+	}
+
+	return result, nil
 }
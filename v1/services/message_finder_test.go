@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
@@ -14,26 +15,28 @@ import (
 
 var _ = Describe("MessageFinder.Find", func() {
 	var (
-		finder       services.MessageFinder
-		messagesRepo *mocks.MessagesRepo
-		database     *mocks.Database
-		conn         *mocks.Connection
+		finder            services.MessageFinder
+		messagesRepo      *mocks.MessagesRepo
+		messageEventsRepo *mocks.MessageEventsRepo
+		database          *mocks.Database
+		conn              *mocks.Connection
 	)
 
 	BeforeEach(func() {
 		messagesRepo = mocks.NewMessagesRepo()
+		messageEventsRepo = mocks.NewMessageEventsRepo()
 		conn = mocks.NewConnection()
 		database = mocks.NewDatabase()
 		database.ConnectionCall.Returns.Connection = conn
 
-		finder = services.NewMessageFinder(messagesRepo)
+		finder = services.NewMessageFinder(messagesRepo, messageEventsRepo)
 	})
 
 	Context("when a message exists with the given id", func() {
 		It("returns the right Message struct", func() {
-			messagesRepo.FindByIDCall.Returns.Message = models.Message{Status: common.StatusDelivered}
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{Status: common.StatusDelivered, ClientID: "some-client"}
 
-			message, err := finder.Find(database, "a-message-id")
+			message, err := finder.Find(database, "a-message-id", "some-client", false)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(message.Status).To(Equal(common.StatusDelivered))
@@ -41,13 +44,80 @@ var _ = Describe("MessageFinder.Find", func() {
 			Expect(messagesRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
 			Expect(messagesRepo.FindByIDCall.Receives.MessageID).To(Equal("a-message-id"))
 		})
+
+		It("includes the delivery history of the message", func() {
+			queuedAt := time.Now().UTC().Truncate(time.Second)
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{
+				ID:              "a-message-id",
+				Status:          common.StatusFailed,
+				Recipient:       "user@example.com",
+				ClientID:        "some-client",
+				KindID:          "some-kind",
+				VCAPRequestID:   "some-request-id",
+				TemplateID:      "some-template",
+				TemplateVersion: 3,
+				QueuedAt:        queuedAt,
+				UpdatedAt:       queuedAt.Add(time.Minute),
+			}
+			messageEventsRepo.ListByMessageIDCall.Returns.Events = []models.MessageEvent{
+				{Primary: 1, MessageID: "a-message-id", Type: models.MessageEventQueued, CreatedAt: queuedAt},
+				{Primary: 2, MessageID: "a-message-id", Type: models.MessageEventFailed, Attempt: 1, SMTPCode: 451, Description: "451 try again later", CreatedAt: queuedAt.Add(time.Minute)},
+			}
+
+			message, err := finder.Find(database, "a-message-id", "some-client", false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message).To(Equal(services.Message{
+				Status:          common.StatusFailed,
+				Recipient:       "user@example.com",
+				ClientID:        "some-client",
+				KindID:          "some-kind",
+				VCAPRequestID:   "some-request-id",
+				TemplateID:      "some-template",
+				TemplateVersion: 3,
+				QueuedAt:        queuedAt,
+				UpdatedAt:       queuedAt.Add(time.Minute),
+				Events: []services.MessageEvent{
+					{Type: models.MessageEventQueued, CreatedAt: queuedAt},
+					{Type: models.MessageEventFailed, Attempt: 1, SMTPCode: 451, Description: "451 try again later", CreatedAt: queuedAt.Add(time.Minute)},
+				},
+			}))
+
+			Expect(messageEventsRepo.ListByMessageIDCall.Receives.Connection).To(Equal(conn))
+			Expect(messageEventsRepo.ListByMessageIDCall.Receives.MessageID).To(Equal("a-message-id"))
+		})
+	})
+
+	Context("when the message was sent by another client", func() {
+		BeforeEach(func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{Status: common.StatusDelivered, ClientID: "other-client"}
+		})
+
+		It("reports the message as not found", func() {
+			_, err := finder.Find(database, "a-message-id", "some-client", false)
+			Expect(err).To(BeAssignableToTypeOf(models.NotFoundError{}))
+			Expect(err).To(MatchError(`Message with ID "a-message-id" could not be found`))
+		})
+
+		It("finds the message for an admin", func() {
+			message, err := finder.Find(database, "a-message-id", "some-client", true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(message.ClientID).To(Equal("other-client"))
+		})
 	})
 
 	Context("when the underlying repo returns an error", func() {
 		It("bubbles up the error", func() {
 			messagesRepo.FindByIDCall.Returns.Error = errors.New("some error")
 
-			_, err := finder.Find(database, "a-message-id")
+			_, err := finder.Find(database, "a-message-id", "some-client", false)
+			Expect(err).To(MatchError(errors.New("some error")))
+		})
+
+		It("bubbles up errors listing the events", func() {
+			messagesRepo.FindByIDCall.Returns.Message = models.Message{ClientID: "some-client"}
+			messageEventsRepo.ListByMessageIDCall.Returns.Error = errors.New("some error")
+
+			_, err := finder.Find(database, "a-message-id", "some-client", false)
 			Expect(err).To(MatchError(errors.New("some error")))
 		})
 	})
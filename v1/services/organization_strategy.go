@@ -43,6 +43,10 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 		Endorsement:       OrganizationEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		SendID:            dispatch.SendID,
+		SendAt:            dispatch.SendAt,
+		Priority:          dispatch.Priority,
+		Locale:            dispatch.Locale,
 		Role:              dispatch.Role,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
@@ -88,3 +92,15 @@ func (strategy OrganizationStrategy) Dispatch(dispatch Dispatch) ([]Response, er
 		dispatch.VCAPRequest.ID,
 		dispatch.VCAPRequest.ReceiptTime)
 }
+
+// Validate checks that the organization exists before its members are
+// resolved in the background.
+func (strategy OrganizationStrategy) Validate(dispatch Dispatch) error {
+	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
+	if err != nil {
+		return err
+	}
+
+	_, err = strategy.organizationLoader.Load(dispatch.GUID, token)
+	return err
+}
@@ -226,4 +226,26 @@ var _ = Describe("Organization Strategy", func() {
 			})
 		})
 	})
+
+	Describe("Validate", func() {
+		It("loads the organization", func() {
+			err := strategy.Validate(services.Dispatch{
+				GUID:    "org-001",
+				UAAHost: "uaa",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("uaa"))
+			Expect(organizationLoader.LoadCall.Receives.OrganizationGUID).To(Equal("org-001"))
+			Expect(organizationLoader.LoadCall.Receives.Token).To(Equal(token))
+			Expect(findsUserIDs.UserIDsBelongingToOrganizationCall.Receives.OrgGUID).To(BeEmpty())
+		})
+
+		It("returns the error when the organization cannot be loaded", func() {
+			organizationLoader.LoadCall.Returns.Errors = []error{errors.New("BOOM!")}
+
+			err := strategy.Validate(services.Dispatch{GUID: "org-001"})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
 })
@@ -31,6 +31,12 @@ type TemplatesRepo interface {
 	Update(connection models.ConnectionInterface, templateID string, template models.Template) (models.Template, error)
 }
 
+type TemplateVersionsRepo interface {
+	Create(connection models.ConnectionInterface, version models.TemplateVersion) (models.TemplateVersion, error)
+	Find(connection models.ConnectionInterface, templateID string, version int) (models.TemplateVersion, error)
+	ListByTemplateID(connection models.ConnectionInterface, templateID string) ([]models.TemplateVersion, error)
+}
+
 type UnsubscribesRepo interface {
 	Set(connection models.ConnectionInterface, userID string, clientID string, kindID string, unsubscribe bool) error
 }
@@ -5,4 +5,5 @@ type Response struct {
 	Recipient      string `json:"recipient"`
 	NotificationID string `json:"notification_id"`
 	VCAPRequestID  string `json:"vcap_request_id"`
+	SendID         string `json:"send_id,omitempty"`
 }
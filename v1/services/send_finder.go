@@ -0,0 +1,135 @@
+package services
+
+import (
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+// Send summarizes the messages created for a single notify request.
+// FinishedAt is nil while the recipients of the send are still being
+// resolved, unless it was cancelled, or any of its messages is waiting to be
+// delivered.
+type Send struct {
+	ID            string
+	ClientID      string
+	KindID        string
+	Audience      string
+	VCAPRequestID string
+	Counts        SendCounts
+	StartedAt     time.Time
+	FinishedAt    *time.Time
+	Recipients    []SendRecipient
+}
+
+type SendCounts struct {
+	Queued        int
+	Delivered     int
+	Failed        int
+	Undeliverable int
+	Cancelled     int
+}
+
+func (c SendCounts) Total() int {
+	return c.Queued + c.Delivered + c.Failed + c.Undeliverable + c.Cancelled
+}
+
+type SendRecipient struct {
+	MessageID string
+	Recipient string
+	Status    string
+}
+
+type sendsRepoFinder interface {
+	FindByID(models.ConnectionInterface, string) (models.Send, error)
+}
+
+type sendMessagesLister interface {
+	CountBySendID(models.ConnectionInterface, string) ([]models.SendStatusCount, error)
+	ListBySendID(conn models.ConnectionInterface, sendID string, limit, offset int) ([]models.Message, error)
+}
+
+type SendFinder struct {
+	sendsRepo    sendsRepoFinder
+	messagesRepo sendMessagesLister
+}
+
+func NewSendFinder(sendsRepo sendsRepoFinder, messagesRepo sendMessagesLister) SendFinder {
+	return SendFinder{
+		sendsRepo:    sendsRepo,
+		messagesRepo: messagesRepo,
+	}
+}
+
+// Find returns the send with the counts of its messages by status and the
+// given page of its recipients. Messages that are waiting to be retried are
+// counted as queued. Only the client that created the send, or an admin, may
+// find it.
+func (finder SendFinder) Find(database DatabaseInterface, sendID, clientID string, admin bool, page, perPage int) (Send, error) {
+	conn := database.Connection()
+
+	send, err := finder.sendsRepo.FindByID(conn, sendID)
+	if err != nil {
+		return Send{}, err
+	}
+
+	if !admin && send.ClientID != clientID {
+		return Send{}, SendForbiddenError{}
+	}
+
+	counts, err := finder.messagesRepo.CountBySendID(conn, sendID)
+	if err != nil {
+		return Send{}, err
+	}
+
+	messages, err := finder.messagesRepo.ListBySendID(conn, sendID, perPage, (page-1)*perPage)
+	if err != nil {
+		return Send{}, err
+	}
+
+	result := Send{
+		ID:            send.ID,
+		ClientID:      send.ClientID,
+		KindID:        send.KindID,
+		Audience:      send.Audience,
+		VCAPRequestID: send.VCAPRequestID,
+		StartedAt:     send.CreatedAt,
+		Recipients:    []SendRecipient{},
+	}
+
+	finishedAt := send.CreatedAt
+	for _, count := range counts {
+		switch count.Status {
+		case common.StatusDelivered:
+			result.Counts.Delivered += count.Count
+		case common.StatusFailed:
+			result.Counts.Failed += count.Count
+		case common.StatusUndeliverable:
+			result.Counts.Undeliverable += count.Count
+		case common.StatusCancelled:
+			result.Counts.Cancelled += count.Count
+		default:
+			// queued, scheduled and retry
+			result.Counts.Queued += count.Count
+		}
+
+		if count.UpdatedAt.After(finishedAt) {
+			finishedAt = count.UpdatedAt
+		}
+	}
+
+	if (send.Resolved || send.Cancelled) && result.Counts.Queued == 0 {
+		result.FinishedAt = &finishedAt
+	}
+
+	for _, message := range messages {
+		result.Recipients = append(result.Recipients, SendRecipient{
+			MessageID: message.ID,
+			Recipient: message.Recipient,
+			Status:    message.Status,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SendFinder.Find", func() {
+	var (
+		finder       services.SendFinder
+		sendsRepo    *mocks.SendsRepo
+		messagesRepo *mocks.MessagesRepo
+		database     *mocks.Database
+		conn         *mocks.Connection
+		createdAt    time.Time
+	)
+
+	BeforeEach(func() {
+		createdAt = time.Now().UTC().Truncate(time.Second)
+
+		sendsRepo = mocks.NewSendsRepo()
+		sendsRepo.FindByIDCall.Returns.Send = models.Send{
+			ID:            "some-send-id",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			Audience:      services.AudienceSpace,
+			VCAPRequestID: "some-request-id",
+			Resolved:      true,
+			CreatedAt:     createdAt,
+		}
+
+		messagesRepo = mocks.NewMessagesRepo()
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+
+		finder = services.NewSendFinder(sendsRepo, messagesRepo)
+	})
+
+	It("rolls up the messages of the send by status", func() {
+		messagesRepo.CountBySendIDCall.Returns.Counts = []models.SendStatusCount{
+			{Status: common.StatusQueued, Count: 1, UpdatedAt: createdAt},
+			{Status: common.StatusRetry, Count: 2, UpdatedAt: createdAt.Add(time.Minute)},
+			{Status: common.StatusDelivered, Count: 3, UpdatedAt: createdAt.Add(time.Minute)},
+			{Status: common.StatusFailed, Count: 4, UpdatedAt: createdAt.Add(time.Minute)},
+			{Status: common.StatusUndeliverable, Count: 5, UpdatedAt: createdAt.Add(time.Minute)},
+			{Status: common.StatusCancelled, Count: 6, UpdatedAt: createdAt.Add(time.Minute)},
+		}
+		messagesRepo.ListBySendIDCall.Returns.Messages = []models.Message{
+			{ID: "message-1", Recipient: "user-1", Status: common.StatusQueued},
+			{ID: "message-2", Recipient: "user-2", Status: common.StatusDelivered},
+		}
+
+		send, err := finder.Find(database, "some-send-id", "some-client", false, 3, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(send).To(Equal(services.Send{
+			ID:            "some-send-id",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			Audience:      services.AudienceSpace,
+			VCAPRequestID: "some-request-id",
+			Counts: services.SendCounts{
+				Queued:        3,
+				Delivered:     3,
+				Failed:        4,
+				Undeliverable: 5,
+				Cancelled:     6,
+			},
+			StartedAt: createdAt,
+			Recipients: []services.SendRecipient{
+				{MessageID: "message-1", Recipient: "user-1", Status: common.StatusQueued},
+				{MessageID: "message-2", Recipient: "user-2", Status: common.StatusDelivered},
+			},
+		}))
+		Expect(send.Counts.Total()).To(Equal(21))
+
+		Expect(sendsRepo.FindByIDCall.Receives.Connection).To(Equal(conn))
+		Expect(sendsRepo.FindByIDCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(messagesRepo.CountBySendIDCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(messagesRepo.ListBySendIDCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(messagesRepo.ListBySendIDCall.Receives.Limit).To(Equal(2))
+		Expect(messagesRepo.ListBySendIDCall.Receives.Offset).To(Equal(4))
+	})
+
+	It("is finished when every message has left the queue", func() {
+		messagesRepo.CountBySendIDCall.Returns.Counts = []models.SendStatusCount{
+			{Status: common.StatusDelivered, Count: 3, UpdatedAt: createdAt.Add(time.Minute)},
+			{Status: common.StatusFailed, Count: 1, UpdatedAt: createdAt.Add(2 * time.Minute)},
+		}
+
+		send, err := finder.Find(database, "some-send-id", "some-client", false, 1, 50)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(send.FinishedAt).NotTo(BeNil())
+		Expect(*send.FinishedAt).To(Equal(createdAt.Add(2 * time.Minute)))
+		Expect(send.Recipients).To(BeEmpty())
+	})
+
+	It("is not finished while its recipients are being resolved", func() {
+		sendsRepo.FindByIDCall.Returns.Send.Resolved = false
+		messagesRepo.CountBySendIDCall.Returns.Counts = []models.SendStatusCount{
+			{Status: common.StatusDelivered, Count: 3, UpdatedAt: createdAt.Add(time.Minute)},
+		}
+
+		send, err := finder.Find(database, "some-send-id", "some-client", false, 1, 50)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(send.FinishedAt).To(BeNil())
+	})
+
+	It("is finished when it was cancelled before its recipients were resolved", func() {
+		sendsRepo.FindByIDCall.Returns.Send.Resolved = false
+		sendsRepo.FindByIDCall.Returns.Send.Cancelled = true
+		messagesRepo.CountBySendIDCall.Returns.Counts = []models.SendStatusCount{
+			{Status: common.StatusCancelled, Count: 2, UpdatedAt: createdAt.Add(time.Minute)},
+		}
+
+		send, err := finder.Find(database, "some-send-id", "some-client", false, 1, 50)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(send.FinishedAt).NotTo(BeNil())
+	})
+
+	Context("when the send belongs to another client", func() {
+		It("forbids the client from viewing it", func() {
+			_, err := finder.Find(database, "some-send-id", "other-client", false, 1, 50)
+			Expect(err).To(MatchError(services.SendForbiddenError{}))
+			Expect(messagesRepo.CountBySendIDCall.Receives.SendID).To(BeEmpty())
+		})
+
+		It("allows an admin to view it", func() {
+			send, err := finder.Find(database, "some-send-id", "other-client", true, 1, 50)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(send.ID).To(Equal("some-send-id"))
+		})
+	})
+
+	Context("when the send cannot be found", func() {
+		It("returns the error", func() {
+			sendsRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := finder.Find(database, "some-send-id", "some-client", false, 1, 50)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Context("when the messages cannot be counted", func() {
+		It("returns the error", func() {
+			messagesRepo.CountBySendIDCall.Returns.Error = errors.New("database is down")
+
+			_, err := finder.Find(database, "some-send-id", "some-client", false, 1, 50)
+			Expect(err).To(MatchError(errors.New("database is down")))
+		})
+	})
+
+	Context("when the messages cannot be listed", func() {
+		It("returns the error", func() {
+			messagesRepo.ListBySendIDCall.Returns.Error = errors.New("database is down")
+
+			_, err := finder.Find(database, "some-send-id", "some-client", false, 1, 50)
+			Expect(err).To(MatchError(errors.New("database is down")))
+		})
+	})
+})
@@ -2,10 +2,13 @@ package services
 
 import "github.com/cloudfoundry-incubator/notifications/cf"
 
-const SpaceEndorsement = `You received this message because you belong to the "{{.Space}}" space in the "{{.Organization}}" organization.`
+const (
+	SpaceEndorsement     = `You received this message because you belong to the "{{.Space}}" space in the "{{.Organization}}" organization.`
+	SpaceRoleEndorsement = `You received this message because you are a {{.SpaceRole}} in the "{{.Space}}" space in the "{{.Organization}}" organization.`
+)
 
 type spaceUserIDFinder interface {
-	UserIDsBelongingToSpace(spaceGUID, token string) (userIDs []string, err error)
+	UserIDsBelongingToSpace(spaceGUID, role, token string) (userIDs []string, err error)
 }
 
 type loadsSpaces interface {
@@ -43,6 +46,10 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		Endorsement:       SpaceEndorsement,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		SendID:            dispatch.SendID,
+		SendAt:            dispatch.SendAt,
+		Priority:          dispatch.Priority,
+		Locale:            dispatch.Locale,
 		Role:              dispatch.Role,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
@@ -52,12 +59,16 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		},
 	}
 
+	if dispatch.Role != "" {
+		options.Endorsement = SpaceRoleEndorsement
+	}
+
 	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
 	if err != nil {
 		return responses, err
 	}
 
-	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToSpace(dispatch.GUID, token)
+	userGUIDs, err := strategy.findsUserIDs.UserIDsBelongingToSpace(dispatch.GUID, options.Role, token)
 	if err != nil {
 		return responses, err
 	}
@@ -89,3 +100,15 @@ func (strategy SpaceStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		dispatch.VCAPRequest.ID,
 		dispatch.VCAPRequest.ReceiptTime)
 }
+
+// Validate checks that the space exists before its members are resolved in
+// the background.
+func (strategy SpaceStrategy) Validate(dispatch Dispatch) error {
+	token, err := strategy.tokenLoader.Load(dispatch.UAAHost)
+	if err != nil {
+		return err
+	}
+
+	_, err = strategy.spaceLoader.Load(dispatch.GUID, token)
+	return err
+}
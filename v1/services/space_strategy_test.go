@@ -146,9 +146,32 @@ var _ = Describe("Space Strategy", func() {
 					Expect(tokenLoader.LoadCall.Receives.UAAHost).To(Equal("uaa"))
 
 					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.SpaceGUID).To(Equal("space-001"))
+					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Role).To(Equal(""))
 					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Token).To(Equal(token))
 				})
 			})
+
+			Context("when a role is given", func() {
+				It("finds the users with the role and endorses the message with it", func() {
+					_, err := strategy.Dispatch(services.Dispatch{
+						GUID:       "space-001",
+						Connection: conn,
+						Role:       "SpaceDeveloper",
+						Message: services.DispatchMessage{
+							Subject: "this is the subject",
+							Text:    "Please reset your password by clicking on this link...",
+						},
+						UAAHost: "uaa",
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(enqueuer.EnqueueCall.Receives.Options.Role).To(Equal("SpaceDeveloper"))
+					Expect(enqueuer.EnqueueCall.Receives.Options.Endorsement).To(Equal(services.SpaceRoleEndorsement))
+
+					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.SpaceGUID).To(Equal("space-001"))
+					Expect(findsUserIDs.UserIDsBelongingToSpaceCall.Receives.Role).To(Equal("SpaceDeveloper"))
+				})
+			})
 		})
 
 		Context("failure cases", func() {
@@ -182,4 +205,24 @@ var _ = Describe("Space Strategy", func() {
 			})
 		})
 	})
+
+	Describe("Validate", func() {
+		It("loads the space", func() {
+			err := strategy.Validate(services.Dispatch{
+				GUID:    "space-001",
+				UAAHost: "uaa",
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(spaceLoader.LoadCall.Receives.SpaceGUID).To(Equal("space-001"))
+			Expect(spaceLoader.LoadCall.Receives.Token).To(Equal(token))
+		})
+
+		It("returns the error when the space cannot be loaded", func() {
+			spaceLoader.LoadCall.Returns.Errors = []error{errors.New("BOOM!")}
+
+			err := strategy.Validate(services.Dispatch{GUID: "space-001"})
+			Expect(err).To(MatchError(errors.New("BOOM!")))
+		})
+	})
 })
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+// TemplateDiff holds the line by line differences between two versions of a
+// template, keyed by the name of each field that changed. The fields of the
+// locale variants are named like "locales.de.subject". Unchanged lines are
+// prefixed with a space, removed lines with "-" and added lines with "+".
+type TemplateDiff struct {
+	TemplateID string
+	From       int
+	To         int
+	Changes    map[string]string
+}
+
+type TemplateHistory struct {
+	templatesRepo        TemplatesRepo
+	templateVersionsRepo TemplateVersionsRepo
+}
+
+func NewTemplateHistory(templatesRepo TemplatesRepo, templateVersionsRepo TemplateVersionsRepo) TemplateHistory {
+	return TemplateHistory{
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
+	}
+}
+
+// List returns the versions of the template, newest first.
+func (history TemplateHistory) List(database DatabaseInterface, templateID string) ([]models.TemplateVersion, error) {
+	conn := database.Connection()
+
+	_, err := history.templatesRepo.FindByID(conn, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history.templateVersionsRepo.ListByTemplateID(conn, templateID)
+}
+
+func (history TemplateHistory) Diff(database DatabaseInterface, templateID string, from, to int) (TemplateDiff, error) {
+	conn := database.Connection()
+
+	fromVersion, err := history.templateVersionsRepo.Find(conn, templateID, from)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	toVersion, err := history.templateVersionsRepo.Find(conn, templateID, to)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	diff := TemplateDiff{
+		TemplateID: templateID,
+		From:       from,
+		To:         to,
+		Changes:    map[string]string{},
+	}
+
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"name", fromVersion.Name, toVersion.Name},
+		{"subject", fromVersion.Subject, toVersion.Subject},
+		{"text", fromVersion.Text, toVersion.Text},
+		{"html", fromVersion.HTML, toVersion.HTML},
+		{"metadata", fromVersion.Metadata, toVersion.Metadata},
+	}
+
+	fromLocales, err := models.ParseTemplateLocales(fromVersion.Locales)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	toLocales, err := models.ParseTemplateLocales(toVersion.Locales)
+	if err != nil {
+		return TemplateDiff{}, err
+	}
+
+	var locales []string
+	for locale := range fromLocales {
+		locales = append(locales, locale)
+	}
+	for locale := range toLocales {
+		if _, ok := fromLocales[locale]; !ok {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		fromVariant, toVariant := fromLocales[locale], toLocales[locale]
+		prefix := "locales." + locale + "."
+
+		fields = append(fields, []struct {
+			name     string
+			from, to string
+		}{
+			{prefix + "subject", fromVariant.Subject, toVariant.Subject},
+			{prefix + "text", fromVariant.Text, toVariant.Text},
+			{prefix + "html", fromVariant.HTML, toVariant.HTML},
+		}...)
+	}
+
+	for _, field := range fields {
+		if field.from != field.to {
+			diff.Changes[field.name] = diffLines(field.from, field.to)
+		}
+	}
+
+	return diff, nil
+}
+
+// maxDiffCells bounds the table diffLines builds for the lines that differ.
+// Beyond it the changed lines are shown as removed and then added, which is a
+// correct diff, only not the shortest one.
+const maxDiffCells = 250000
+
+// diffLines compares the texts line by line. The lines the texts start and
+// end with in common are set aside, and the rest is compared using their
+// longest common subsequence of lines.
+func diffLines(from, to string) string {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []string
+	for _, line := range a[:prefix] {
+		lines = append(lines, " "+line)
+	}
+
+	lines = append(lines, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, " "+line)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func diffMiddle(a, b []string) []string {
+	var lines []string
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			lines = append(lines, "-"+line)
+		}
+		for _, line := range b {
+			lines = append(lines, "+"+line)
+		}
+		return lines
+	}
+
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, " "+a[i])
+			i++
+			j++
+		case common[i+1][j] >= common[i][j+1]:
+			lines = append(lines, "-"+a[i])
+			i++
+		default:
+			lines = append(lines, "+"+b[j])
+			j++
+		}
+	}
+
+	for ; i < len(a); i++ {
+		lines = append(lines, "-"+a[i])
+	}
+
+	for ; j < len(b); j++ {
+		lines = append(lines, "+"+b[j])
+	}
+
+	return lines
+}
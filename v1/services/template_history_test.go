@@ -0,0 +1,164 @@
+package services_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplateHistory", func() {
+	var (
+		conn                 *mocks.Connection
+		database             *mocks.Database
+		templatesRepo        *mocks.TemplatesRepo
+		templateVersionsRepo *mocks.TemplateVersionsRepo
+		history              services.TemplateHistory
+	)
+
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		templatesRepo = mocks.NewTemplatesRepo()
+		templateVersionsRepo = mocks.NewTemplateVersionsRepo()
+
+		history = services.NewTemplateHistory(templatesRepo, templateVersionsRepo)
+	})
+
+	Describe("List", func() {
+		It("lists the versions of the template", func() {
+			templateVersionsRepo.ListByTemplateIDCall.Returns.Versions = []models.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2},
+				{TemplateID: "some-template-id", Version: 1},
+			}
+
+			versions, err := history.List(database, "some-template-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(versions).To(Equal([]models.TemplateVersion{
+				{TemplateID: "some-template-id", Version: 2},
+				{TemplateID: "some-template-id", Version: 1},
+			}))
+
+			Expect(templatesRepo.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templateVersionsRepo.ListByTemplateIDCall.Receives.Connection).To(Equal(conn))
+			Expect(templateVersionsRepo.ListByTemplateIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+		})
+
+		It("returns an error when the template does not exist", func() {
+			templatesRepo.FindByIDCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := history.List(database, "missing-template-id")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+
+	Describe("Diff", func() {
+		BeforeEach(func() {
+			templateVersionsRepo.FindCall.Returns.Versions = map[int]models.TemplateVersion{
+				1: {
+					Version:  1,
+					Name:     "some-name",
+					Subject:  "some-subject",
+					Text:     "first line\nsecond line\nthird line",
+					HTML:     "<p>hello</p>",
+					Metadata: "{}",
+				},
+				3: {
+					Version:  3,
+					Name:     "some-name",
+					Subject:  "other-subject",
+					Text:     "first line\nthird line\nfourth line",
+					HTML:     "<p>hello</p>",
+					Metadata: "{}",
+				},
+			}
+		})
+
+		It("compares the fields that changed line by line", func() {
+			diff, err := history.Diff(database, "some-template-id", 1, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(templateVersionsRepo.FindCall.Receives.TemplateID).To(Equal("some-template-id"))
+			Expect(templateVersionsRepo.FindCall.Receives.Versions).To(Equal([]int{1, 3}))
+
+			Expect(diff).To(Equal(services.TemplateDiff{
+				TemplateID: "some-template-id",
+				From:       1,
+				To:         3,
+				Changes: map[string]string{
+					"subject": "-some-subject\n+other-subject\n",
+					"text":    " first line\n-second line\n third line\n+fourth line\n",
+				},
+			}))
+		})
+
+		It("compares the fields of the locale variants", func() {
+			versions := templateVersionsRepo.FindCall.Returns.Versions
+
+			first := versions[1]
+			first.Locales = `{"de": {"subject": "Betreff", "text": "Text"}, "ja": {"text": "テキスト"}}`
+			versions[1] = first
+
+			third := versions[3]
+			third.Locales = `{"de": {"subject": "Neuer Betreff", "text": "Text"}, "fr": {"html": "<p>bonjour</p>"}}`
+			versions[3] = third
+
+			diff, err := history.Diff(database, "some-template-id", 1, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(diff.Changes).To(HaveKeyWithValue("locales.de.subject", "-Betreff\n+Neuer Betreff\n"))
+			Expect(diff.Changes).To(HaveKeyWithValue("locales.ja.text", "-テキスト\n+\n"))
+			Expect(diff.Changes).To(HaveKeyWithValue("locales.fr.html", "-\n+<p>bonjour</p>\n"))
+			Expect(diff.Changes).NotTo(HaveKey("locales.de.text"))
+		})
+
+		It("shows large changes as removed and then added lines", func() {
+			var fromLines, toLines []string
+			for i := 0; i < 1000; i++ {
+				fromLines = append(fromLines, fmt.Sprintf("old line %d", i))
+				toLines = append(toLines, fmt.Sprintf("new line %d", i))
+			}
+
+			versions := templateVersionsRepo.FindCall.Returns.Versions
+			first := versions[1]
+			first.HTML = "<html>\n" + strings.Join(fromLines, "\n") + "\n</html>"
+			versions[1] = first
+
+			third := versions[3]
+			third.HTML = "<html>\n" + strings.Join(toLines, "\n") + "\n</html>"
+			versions[3] = third
+
+			diff, err := history.Diff(database, "some-template-id", 1, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			lines := strings.Split(strings.TrimSuffix(diff.Changes["html"], "\n"), "\n")
+			Expect(lines).To(HaveLen(2002))
+			Expect(lines[0]).To(Equal(" <html>"))
+			Expect(lines[1]).To(Equal("-old line 0"))
+			Expect(lines[1000]).To(Equal("-old line 999"))
+			Expect(lines[1001]).To(Equal("+new line 0"))
+			Expect(lines[2000]).To(Equal("+new line 999"))
+			Expect(lines[2001]).To(Equal(" </html>"))
+		})
+
+		It("has no changes when the versions are the same", func() {
+			diff, err := history.Diff(database, "some-template-id", 1, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff.Changes).To(BeEmpty())
+		})
+
+		It("returns an error when a version does not exist", func() {
+			templateVersionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := history.Diff(database, "some-template-id", 1, 7)
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+		})
+	})
+})
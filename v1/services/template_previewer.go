@@ -0,0 +1,144 @@
+package services
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/pivotal-golang/conceal"
+)
+
+// PreviewMessageID stands in for the message ID when a template is previewed.
+const PreviewMessageID = "preview"
+
+// PreviewTemplate is the template to render, along with the partials it
+// may include and the layout of the client, when it has one.
+type PreviewTemplate struct {
+	Subject  string
+	Text     string
+	HTML     string
+	Layout   string
+	Partials map[string]string
+}
+
+// PreviewNotification is the sample notification a template is rendered
+// with. The endorsement follows from the audience it names: a space, an
+// organization or otherwise a single user.
+type PreviewNotification struct {
+	To                string
+	ReplyTo           string
+	Subject           string
+	Text              string
+	HTML              HTML
+	KindID            string
+	KindDescription   string
+	SourceDescription string
+	ClientID          string
+	Space             string
+	Organization      string
+}
+
+type Preview struct {
+	Subject string
+	Text    string
+	HTML    string
+	MIME    string
+	Errors  []string
+}
+
+type previewPackager interface {
+	Preview(context common.MessageContext) (mail.Message, []error, error)
+}
+
+type TemplatePreviewer struct {
+	packager previewPackager
+	cloak    conceal.CloakInterface
+	clock    clock
+	sender   string
+	domain   string
+}
+
+func NewTemplatePreviewer(packager previewPackager, cloak conceal.CloakInterface, clock clock, sender, domain string) TemplatePreviewer {
+	return TemplatePreviewer{
+		packager: packager,
+		cloak:    cloak,
+		clock:    clock,
+		sender:   sender,
+		domain:   domain,
+	}
+}
+
+// Preview renders the template with the sample notification the way a
+// delivery would be packed. Errors hit while executing the templates are
+// returned in the preview; a template that cannot be parsed is an error.
+func (previewer TemplatePreviewer) Preview(template PreviewTemplate, notification PreviewNotification) (Preview, error) {
+	if template.Subject == "" {
+		template.Subject = "{{.Subject}}"
+	}
+
+	endorsement := UserEndorsement
+	switch {
+	case notification.Space != "":
+		endorsement = SpaceEndorsement
+	case notification.Organization != "":
+		endorsement = OrganizationEndorsement
+	}
+
+	delivery := common.Delivery{
+		MessageID: PreviewMessageID,
+		Email:     notification.To,
+		ClientID:  notification.ClientID,
+		Options: common.Options{
+			ReplyTo:           notification.ReplyTo,
+			Subject:           notification.Subject,
+			KindID:            notification.KindID,
+			KindDescription:   notification.KindDescription,
+			SourceDescription: notification.SourceDescription,
+			Text:              notification.Text,
+			Endorsement:       endorsement,
+			HTML: common.HTML{
+				BodyContent:    notification.HTML.BodyContent,
+				BodyAttributes: notification.HTML.BodyAttributes,
+				Head:           notification.HTML.Head,
+				Doctype:        notification.HTML.Doctype,
+			},
+		},
+		Space:           cf.CloudControllerSpace{Name: notification.Space},
+		Organization:    cf.CloudControllerOrganization{Name: notification.Organization},
+		RequestReceived: previewer.clock.Now(),
+	}
+
+	context := common.NewMessageContext(delivery, previewer.sender, previewer.domain, previewer.cloak, common.Templates{
+		Subject:  template.Subject,
+		Text:     template.Text,
+		HTML:     template.HTML,
+		Layout:   template.Layout,
+		Partials: template.Partials,
+	})
+
+	message, executionErrors, err := previewer.packager.Preview(context)
+	if err != nil {
+		return Preview{}, err
+	}
+
+	preview := Preview{
+		Subject: message.Subject,
+		Errors:  []string{},
+	}
+
+	for _, part := range message.Body {
+		switch part.ContentType {
+		case "text/plain":
+			preview.Text = part.Content
+		case "text/html":
+			preview.HTML = part.Content
+		}
+	}
+
+	for _, executionError := range executionErrors {
+		preview.Errors = append(preview.Errors, executionError.Error())
+	}
+
+	preview.MIME = message.Data()
+
+	return preview, nil
+}
@@ -0,0 +1,135 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/mail"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TemplatePreviewer", func() {
+	var (
+		previewer services.TemplatePreviewer
+		packager  *mocks.Packager
+		cloak     *mocks.Cloak
+		clock     *mocks.Clock
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		packager = mocks.NewPackager()
+		packager.PreviewCall.Returns.Message = mail.Message{
+			From:    "no-reply@example.com",
+			To:      "user@example.com",
+			Subject: "compiled subject",
+			Body: []mail.Part{
+				{ContentType: "text/plain", Content: "compiled text"},
+				{ContentType: "text/html", Content: "<p>compiled html</p>"},
+			},
+		}
+
+		cloak = mocks.NewCloak()
+		cloak.VeilCall.Returns.CipherText = []byte("some-unsubscribe-id")
+
+		now = time.Now().UTC()
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = now
+
+		previewer = services.NewTemplatePreviewer(packager, cloak, clock, "no-reply@example.com", "example.com")
+	})
+
+	It("packs the template with a context built from the sample notification", func() {
+		preview, err := previewer.Preview(services.PreviewTemplate{
+			Subject: "Subject: {{.Subject}}",
+			Text:    "Text: {{.Text}}",
+			HTML:    "<h1>{{.HTML}}</h1>",
+			Layout:  "<main>{{.HTMLComponents.BodyContent}}</main>",
+			Partials: map[string]string{
+				"footer": "the footer",
+			},
+		}, services.PreviewNotification{
+			To:                "user@example.com",
+			Subject:           "the subject",
+			Text:              "the text",
+			HTML:              services.HTML{BodyContent: "<p>the html</p>"},
+			KindID:            "some-kind",
+			KindDescription:   "Some Kind",
+			SourceDescription: "Some Source",
+			ClientID:          "some-client",
+			Space:             "some-space",
+			Organization:      "some-org",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		context := packager.PreviewCall.Receives.MessageContext
+		Expect(context.From).To(Equal("no-reply@example.com"))
+		Expect(context.Domain).To(Equal("example.com"))
+		Expect(context.To).To(Equal("user@example.com"))
+		Expect(context.Subject).To(Equal("the subject"))
+		Expect(context.Text).To(Equal("the text"))
+		Expect(context.HTML).To(Equal("<p>the html</p>"))
+		Expect(context.KindDescription).To(Equal("Some Kind"))
+		Expect(context.SourceDescription).To(Equal("Some Source"))
+		Expect(context.ClientID).To(Equal("some-client"))
+		Expect(context.MessageID).To(Equal(services.PreviewMessageID))
+		Expect(context.Space).To(Equal("some-space"))
+		Expect(context.Organization).To(Equal("some-org"))
+		Expect(context.Endorsement).To(Equal(services.SpaceEndorsement))
+		Expect(context.RequestReceived).To(Equal(now))
+		Expect(context.SubjectTemplate).To(Equal("Subject: {{.Subject}}"))
+		Expect(context.TextTemplate).To(Equal("Text: {{.Text}}"))
+		Expect(context.HTMLTemplate).To(Equal("<h1>{{.HTML}}</h1>"))
+		Expect(context.Partials).To(Equal(map[string]string{"footer": "the footer"}))
+		Expect(context.LayoutTemplate).To(Equal("<main>{{.HTMLComponents.BodyContent}}</main>"))
+
+		Expect(preview.Subject).To(Equal("compiled subject"))
+		Expect(preview.Text).To(Equal("compiled text"))
+		Expect(preview.HTML).To(Equal("<p>compiled html</p>"))
+		Expect(preview.MIME).To(ContainSubstring("Subject: compiled subject"))
+		Expect(preview.MIME).To(ContainSubstring("compiled text"))
+		Expect(preview.Errors).To(BeEmpty())
+	})
+
+	It("defaults the subject template", func() {
+		_, err := previewer.Preview(services.PreviewTemplate{HTML: "{{.HTML}}"}, services.PreviewNotification{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(packager.PreviewCall.Receives.MessageContext.SubjectTemplate).To(Equal("{{.Subject}}"))
+	})
+
+	It("endorses the message for the audience of the sample notification", func() {
+		_, err := previewer.Preview(services.PreviewTemplate{}, services.PreviewNotification{Organization: "some-org"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packager.PreviewCall.Receives.MessageContext.Endorsement).To(Equal(services.OrganizationEndorsement))
+
+		_, err = previewer.Preview(services.PreviewTemplate{}, services.PreviewNotification{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(packager.PreviewCall.Receives.MessageContext.Endorsement).To(Equal(services.UserEndorsement))
+	})
+
+	It("returns the errors hit while executing the templates", func() {
+		packager.PreviewCall.Returns.ExecutionErrors = []error{
+			errors.New(`template: text:1:3: executing "text" at <.Missing>: can't evaluate field Missing`),
+		}
+
+		preview, err := previewer.Preview(services.PreviewTemplate{}, services.PreviewNotification{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(preview.Errors).To(Equal([]string{
+			`template: text:1:3: executing "text" at <.Missing>: can't evaluate field Missing`,
+		}))
+	})
+
+	Context("when the template cannot be packed", func() {
+		It("returns the error", func() {
+			packager.PreviewCall.Returns.Error = errors.New("template: text:1: unclosed action")
+
+			_, err := previewer.Preview(services.PreviewTemplate{}, services.PreviewNotification{})
+			Expect(err).To(MatchError(errors.New("template: text:1: unclosed action")))
+		})
+	})
+})
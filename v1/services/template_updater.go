@@ -1,21 +1,145 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/v1/models"
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
+
+type partialsByNameLister interface {
+	ListByNames(connection models.ConnectionInterface, names []string) ([]models.Partial, error)
+}
 
 type TemplateUpdater struct {
-	templatesRepo TemplatesRepo
+	templatesRepo        TemplatesRepo
+	templateVersionsRepo TemplateVersionsRepo
+	partialsRepo         partialsByNameLister
 }
 
-func NewTemplateUpdater(templatesRepo TemplatesRepo) TemplateUpdater {
+func NewTemplateUpdater(templatesRepo TemplatesRepo, templateVersionsRepo TemplateVersionsRepo, partialsRepo partialsByNameLister) TemplateUpdater {
 	return TemplateUpdater{
-		templatesRepo: templatesRepo,
+		templatesRepo:        templatesRepo,
+		templateVersionsRepo: templateVersionsRepo,
+		partialsRepo:         partialsRepo,
+	}
+}
+
+// Update saves the template and records the result as a new version authored
+// by the client.
+func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template, clientID string) error {
+	_, err := updater.save(database.Connection(), templateID, template, clientID)
+	return err
+}
+
+// Rollback restores the content of an earlier version of the template. The
+// restored content is recorded as a new version, so the history is kept. A
+// version that includes a partial that has since been deleted is not
+// restored, since none of its messages could be rendered.
+func (updater TemplateUpdater) Rollback(database DatabaseInterface, templateID string, version int, clientID string) (models.Template, error) {
+	conn := database.Connection()
+
+	templateVersion, err := updater.templateVersionsRepo.Find(conn, templateID, version)
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	missing, err := updater.missingPartials(conn, templateVersion)
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	if len(missing) > 0 {
+		return models.Template{}, TemplateRollbackError{fmt.Errorf("Version %d of template %q includes partial %q, which no longer exists", version, templateID, missing[0])}
 	}
+
+	return updater.save(conn, templateID, models.Template{
+		Name:     templateVersion.Name,
+		Subject:  templateVersion.Subject,
+		Text:     templateVersion.Text,
+		HTML:     templateVersion.HTML,
+		Metadata: templateVersion.Metadata,
+		Locales:  templateVersion.Locales,
+	}, clientID)
 }
 
-func (updater TemplateUpdater) Update(database DatabaseInterface, templateID string, template models.Template) error {
-	_, err := updater.templatesRepo.Update(database.Connection(), templateID, template)
+func (updater TemplateUpdater) save(conn models.ConnectionInterface, templateID string, template models.Template, clientID string) (models.Template, error) {
+	transaction := conn.Transaction()
+	if err := transaction.Begin(); err != nil {
+		return models.Template{}, err
+	}
+
+	template, err := updater.templatesRepo.Update(transaction, templateID, template)
+	if err != nil {
+		transaction.Rollback()
+		return models.Template{}, err
+	}
+
+	_, err = updater.templateVersionsRepo.Create(transaction, models.NewTemplateVersion(template, clientID))
+	if err != nil {
+		transaction.Rollback()
+		return models.Template{}, err
+	}
+
+	err = transaction.Commit()
 	if err != nil {
-		return err
+		return models.Template{}, err
 	}
-	return nil
+
+	return template, nil
+}
+
+// missingPartials lists, by name, the partials that the version includes but
+// that no longer exist.
+func (updater TemplateUpdater) missingPartials(conn models.ConnectionInterface, version models.TemplateVersion) ([]string, error) {
+	sources := []string{version.Subject, version.Text, version.HTML}
+
+	variants, err := models.ParseTemplateLocales(version.Locales)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, variant := range variants {
+		sources = append(sources, variant.Subject, variant.Text, variant.HTML)
+	}
+
+	referenced := map[string]bool{}
+	for _, source := range sources {
+		references, err := models.PartialReferences(source)
+		if err != nil {
+			continue
+		}
+
+		for _, reference := range references {
+			referenced[reference] = true
+		}
+	}
+
+	if len(referenced) == 0 {
+		return nil, nil
+	}
+
+	names := []string{}
+	for name := range referenced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	partials, err := updater.partialsRepo.ListByNames(conn, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, partial := range partials {
+		delete(referenced, partial.Name)
+	}
+
+	missing := []string{}
+	for _, name := range names {
+		if referenced[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing, nil
 }
@@ -2,6 +2,7 @@ package services_test
 
 import (
 	"errors"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -12,45 +13,201 @@ import (
 )
 
 var _ = Describe("Updater", func() {
-	Describe("Update", func() {
-		var (
-			conn          *mocks.Connection
-			database      *mocks.Database
-			templatesRepo *mocks.TemplatesRepo
-			updater       services.TemplateUpdater
-		)
+	var (
+		conn                 *mocks.Connection
+		transaction          *mocks.Transaction
+		database             *mocks.Database
+		templatesRepo        *mocks.TemplatesRepo
+		templateVersionsRepo *mocks.TemplateVersionsRepo
+		partialsRepo         *mocks.PartialsRepo
+		updater              services.TemplateUpdater
+		updatedAt            time.Time
+	)
 
-		BeforeEach(func() {
-			conn = mocks.NewConnection()
-			database = mocks.NewDatabase()
-			database.ConnectionCall.Returns.Connection = conn
-			templatesRepo = mocks.NewTemplatesRepo()
+	BeforeEach(func() {
+		conn = mocks.NewConnection()
+		transaction = mocks.NewTransaction()
+		conn.TransactionCall.Returns.Transaction = transaction
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		templatesRepo = mocks.NewTemplatesRepo()
+		templateVersionsRepo = mocks.NewTemplateVersionsRepo()
+		partialsRepo = mocks.NewPartialsRepo()
+		updatedAt = time.Now().Truncate(time.Second).UTC()
 
-			updater = services.NewTemplateUpdater(templatesRepo)
-		})
+		updater = services.NewTemplateUpdater(templatesRepo, templateVersionsRepo, partialsRepo)
+	})
 
+	Describe("Update", func() {
 		It("Inserts templates into the templates repo", func() {
 			err := updater.Update(database, "my-awesome-id", models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
-			})
+			}, "some-client")
 			Expect(err).ToNot(HaveOccurred())
 
-			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(conn))
+			Expect(templatesRepo.UpdateCall.Receives.Connection).To(Equal(transaction))
 			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
 			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
 				Name: "gobble template",
 				Text: "gobble",
 				HTML: "<p>gobble</p>",
 			}))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		It("records the updated template as a new version", func() {
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:        "my-awesome-id",
+				Name:      "gobble template",
+				Text:      "gobble",
+				HTML:      "<p>gobble</p>",
+				UpdatedAt: updatedAt,
+				Version:   4,
+			}
+
+			err := updater.Update(database, "my-awesome-id", models.Template{}, "some-client")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(templateVersionsRepo.CreateCall.Receives.Connection).To(Equal(transaction))
+			Expect(templateVersionsRepo.CreateCall.Receives.Version).To(Equal(models.TemplateVersion{
+				TemplateID: "my-awesome-id",
+				Version:    4,
+				Name:       "gobble template",
+				Text:       "gobble",
+				HTML:       "<p>gobble</p>",
+				ClientID:   "some-client",
+				CreatedAt:  updatedAt,
+			}))
 		})
 
 		It("propagates errors from repo", func() {
 			templatesRepo.UpdateCall.Returns.Error = errors.New("Boom!")
 
-			err := updater.Update(database, "unimportant", models.Template{})
+			err := updater.Update(database, "unimportant", models.Template{}, "some-client")
 			Expect(err).To(MatchError(errors.New("Boom!")))
+
+			Expect(templateVersionsRepo.CreateCall.WasCalled).To(BeFalse())
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+		})
+
+		It("rolls back the update when the version cannot be recorded", func() {
+			templateVersionsRepo.CreateCall.Returns.Error = errors.New("duplicate version")
+
+			err := updater.Update(database, "unimportant", models.Template{}, "some-client")
+			Expect(err).To(MatchError(errors.New("duplicate version")))
+
+			Expect(transaction.RollbackCall.WasCalled).To(BeTrue())
+			Expect(transaction.CommitCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Describe("Rollback", func() {
+		BeforeEach(func() {
+			templateVersionsRepo.FindCall.Returns.Versions = map[int]models.TemplateVersion{
+				2: {
+					TemplateID: "my-awesome-id",
+					Version:    2,
+					Name:       "old name",
+					Subject:    "old subject",
+					Text:       "old text",
+					HTML:       "<p>old</p>",
+					Metadata:   "{}",
+					Locales:    `{"de":{"subject":"alter Betreff"}}`,
+					ClientID:   "another-client",
+				},
+			}
+
+			templatesRepo.UpdateCall.Returns.Template = models.Template{
+				ID:       "my-awesome-id",
+				Name:     "old name",
+				Subject:  "old subject",
+				Text:     "old text",
+				HTML:     "<p>old</p>",
+				Metadata: "{}",
+				Version:  5,
+			}
+		})
+
+		It("saves the content of the version as a new version", func() {
+			template, err := updater.Rollback(database, "my-awesome-id", 2, "some-client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(template.Version).To(Equal(5))
+
+			Expect(templateVersionsRepo.FindCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+			Expect(templateVersionsRepo.FindCall.Receives.Versions).To(Equal([]int{2}))
+
+			Expect(templatesRepo.UpdateCall.Receives.TemplateID).To(Equal("my-awesome-id"))
+			Expect(templatesRepo.UpdateCall.Receives.Template).To(Equal(models.Template{
+				Name:     "old name",
+				Subject:  "old subject",
+				Text:     "old text",
+				HTML:     "<p>old</p>",
+				Metadata: "{}",
+				Locales:  `{"de":{"subject":"alter Betreff"}}`,
+			}))
+
+			Expect(templateVersionsRepo.CreateCall.Receives.Version.Version).To(Equal(5))
+			Expect(templateVersionsRepo.CreateCall.Receives.Version.ClientID).To(Equal("some-client"))
+			Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+		})
+
+		Context("when the version includes partials", func() {
+			BeforeEach(func() {
+				templateVersionsRepo.FindCall.Returns.Versions = map[int]models.TemplateVersion{
+					2: {
+						TemplateID: "my-awesome-id",
+						Version:    2,
+						Text:       `{{template "header" .}}old text`,
+						HTML:       `{{define "row"}}<tr></tr>{{end}}{{template "row" .}}`,
+						Locales:    `{"de":{"html":"{{template \"footer\" .}}"}}`,
+					},
+				}
+			})
+
+			It("restores the version when its partials still exist", func() {
+				partialsRepo.ListByNamesCall.Returns.Partials = map[string]models.Partial{
+					"header": {Name: "header"},
+					"footer": {Name: "footer"},
+				}
+
+				_, err := updater.Rollback(database, "my-awesome-id", 2, "some-client")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(partialsRepo.ListByNamesCall.Receives.Connection).To(Equal(conn))
+				Expect(partialsRepo.ListByNamesCall.Receives.Names).To(Equal([][]string{{"footer", "header"}}))
+				Expect(transaction.CommitCall.WasCalled).To(BeTrue())
+			})
+
+			It("does not restore a version that includes a deleted partial", func() {
+				partialsRepo.ListByNamesCall.Returns.Partials = map[string]models.Partial{
+					"header": {Name: "header"},
+				}
+
+				_, err := updater.Rollback(database, "my-awesome-id", 2, "some-client")
+				Expect(err).To(MatchError(services.TemplateRollbackError{Err: errors.New(`Version 2 of template "my-awesome-id" includes partial "footer", which no longer exists`)}))
+
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+
+			It("propagates errors listing the partials", func() {
+				partialsRepo.ListByNamesCall.Returns.Error = errors.New("db is down")
+
+				_, err := updater.Rollback(database, "my-awesome-id", 2, "some-client")
+				Expect(err).To(MatchError(errors.New("db is down")))
+
+				Expect(transaction.BeginCall.WasCalled).To(BeFalse())
+			})
+		})
+
+		It("propagates errors finding the version", func() {
+			templateVersionsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+			_, err := updater.Rollback(database, "my-awesome-id", 9, "some-client")
+			Expect(err).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+
+			Expect(transaction.BeginCall.WasCalled).To(BeFalse())
 		})
 	})
 })
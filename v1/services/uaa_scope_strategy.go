@@ -36,6 +36,10 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		SendID:            dispatch.SendID,
+		SendAt:            dispatch.SendAt,
+		Priority:          dispatch.Priority,
+		Locale:            dispatch.Locale,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
@@ -76,6 +80,15 @@ func (strategy UAAScopeStrategy) Dispatch(dispatch Dispatch) ([]Response, error)
 		dispatch.VCAPRequest.ReceiptTime)
 }
 
+// Validate rejects the default scopes, which every user holds.
+func (strategy UAAScopeStrategy) Validate(dispatch Dispatch) error {
+	if strategy.scopeIsDefault(dispatch.GUID) {
+		return DefaultScopeError{}
+	}
+
+	return nil
+}
+
 func (strategy UAAScopeStrategy) scopeIsDefault(scope string) bool {
 	for _, singleScope := range strategy.defaultScopes {
 		if scope == singleScope {
@@ -165,4 +165,16 @@ var _ = Describe("UAA Scope Strategy", func() {
 			})
 		})
 	})
+
+	Describe("Validate", func() {
+		It("accepts a scope that is not a default scope", func() {
+			Expect(strategy.Validate(services.Dispatch{GUID: "great.scope"})).To(Succeed())
+		})
+
+		It("rejects the default scopes", func() {
+			for _, scope := range defaultScopes {
+				Expect(strategy.Validate(services.Dispatch{GUID: scope})).To(MatchError(services.DefaultScopeError{}))
+			}
+		})
+	})
 })
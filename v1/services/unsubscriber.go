@@ -0,0 +1,109 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/pivotal-golang/conceal"
+)
+
+type Unsubscription struct {
+	UserGUID          string
+	ClientID          string
+	KindID            string
+	KindDescription   string
+	SourceDescription string
+}
+
+type clock interface {
+	Now() time.Time
+}
+
+type Unsubscriber struct {
+	cloak            conceal.CloakInterface
+	clock            clock
+	lifetime         time.Duration
+	clientsRepo      ClientsRepo
+	kindsRepo        KindsRepo
+	unsubscribesRepo UnsubscribesRepo
+}
+
+func NewUnsubscriber(cloak conceal.CloakInterface, clock clock, lifetime time.Duration, clientsRepo ClientsRepo, kindsRepo KindsRepo, unsubscribesRepo UnsubscribesRepo) Unsubscriber {
+	return Unsubscriber{
+		cloak:            cloak,
+		clock:            clock,
+		lifetime:         lifetime,
+		clientsRepo:      clientsRepo,
+		kindsRepo:        kindsRepo,
+		unsubscribesRepo: unsubscribesRepo,
+	}
+}
+
+// Find decodes the unsubscribe ID and describes the notification it refers to
+// without changing any preferences.
+func (u Unsubscriber) Find(conn ConnectionInterface, unsubscribeID string) (Unsubscription, error) {
+	id, err := common.UnveilUnsubscribeID(u.cloak, unsubscribeID)
+	if err != nil {
+		return Unsubscription{}, InvalidUnsubscribeIDError{err}
+	}
+
+	if u.lifetime > 0 && !id.IssuedAt.IsZero() && u.clock.Now().After(id.IssuedAt.Add(u.lifetime)) {
+		return Unsubscription{}, ExpiredUnsubscribeIDError{}
+	}
+
+	kind, err := u.kindsRepo.Find(conn, id.KindID, id.ClientID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return Unsubscription{}, InvalidUnsubscribeIDError{err}
+		}
+		return Unsubscription{}, err
+	}
+
+	client, err := u.clientsRepo.Find(conn, id.ClientID)
+	if err != nil {
+		if _, ok := err.(models.NotFoundError); ok {
+			return Unsubscription{}, InvalidUnsubscribeIDError{err}
+		}
+		return Unsubscription{}, err
+	}
+
+	if kind.Critical {
+		return Unsubscription{}, CriticalKindError{fmt.Errorf("The kind '%s' for the '%s' client is critical and cannot be unsubscribed from", kind.ID, client.ID)}
+	}
+
+	unsubscription := Unsubscription{
+		UserGUID:          id.UserGUID,
+		ClientID:          client.ID,
+		KindID:            kind.ID,
+		KindDescription:   kind.Description,
+		SourceDescription: client.Description,
+	}
+
+	if unsubscription.KindDescription == "" {
+		unsubscription.KindDescription = kind.ID
+	}
+
+	if unsubscription.SourceDescription == "" {
+		unsubscription.SourceDescription = client.ID
+	}
+
+	return unsubscription, nil
+}
+
+// Unsubscribe decodes the unsubscribe ID and unsubscribes the user it
+// identifies from the notification kind.
+func (u Unsubscriber) Unsubscribe(conn ConnectionInterface, unsubscribeID string) (Unsubscription, error) {
+	unsubscription, err := u.Find(conn, unsubscribeID)
+	if err != nil {
+		return Unsubscription{}, err
+	}
+
+	err = u.unsubscribesRepo.Set(conn, unsubscription.UserGUID, unsubscription.ClientID, unsubscription.KindID, true)
+	if err != nil {
+		return Unsubscription{}, err
+	}
+
+	return unsubscription, nil
+}
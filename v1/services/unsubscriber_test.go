@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Unsubscriber", func() {
+	var (
+		unsubscriber     services.Unsubscriber
+		cloak            *mocks.Cloak
+		clock            *mocks.Clock
+		clientsRepo      *mocks.ClientsRepository
+		kindsRepo        *mocks.KindsRepo
+		unsubscribesRepo *mocks.UnsubscribesRepo
+		conn             *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		cloak = mocks.NewCloak()
+		cloak.UnveilCall.Returns.PlainText = []byte("some-user|some-client|some-kind|1433799612")
+
+		clock = mocks.NewClock()
+		clock.NowCall.Returns.Time = time.Unix(1433799612, 0).Add(1 * time.Hour)
+
+		clientsRepo = mocks.NewClientsRepository()
+		clientsRepo.FindCall.Returns.Client = models.Client{
+			ID:          "some-client",
+			Description: "Some Client",
+		}
+
+		kindsRepo = mocks.NewKindsRepo()
+		kindsRepo.FindCall.Returns.Kinds = []models.Kind{
+			{
+				ID:          "some-kind",
+				ClientID:    "some-client",
+				Description: "Some Kind",
+			},
+		}
+
+		unsubscribesRepo = mocks.NewUnsubscribesRepo()
+		conn = mocks.NewConnection()
+
+		unsubscriber = services.NewUnsubscriber(cloak, clock, 24*time.Hour, clientsRepo, kindsRepo, unsubscribesRepo)
+	})
+
+	Describe("Find", func() {
+		It("describes the notification the unsubscribe ID refers to", func() {
+			unsubscription, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unsubscription).To(Equal(services.Unsubscription{
+				UserGUID:          "some-user",
+				ClientID:          "some-client",
+				KindID:            "some-kind",
+				KindDescription:   "Some Kind",
+				SourceDescription: "Some Client",
+			}))
+
+			Expect(cloak.UnveilCall.Receives.CipherText).To(Equal([]byte("some-unsubscribe-id")))
+			Expect(kindsRepo.FindCall.Receives.Connection).To(Equal(conn))
+			Expect(kindsRepo.FindCall.Receives.KindID).To(Equal("some-kind"))
+			Expect(kindsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(clientsRepo.FindCall.Receives.ClientID).To(Equal("some-client"))
+		})
+
+		It("falls back to the IDs when descriptions are missing", func() {
+			kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "some-client"}}
+			clientsRepo.FindCall.Returns.Client = models.Client{ID: "some-client"}
+
+			unsubscription, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unsubscription.KindDescription).To(Equal("some-kind"))
+			Expect(unsubscription.SourceDescription).To(Equal("some-client"))
+		})
+
+		It("accepts legacy unsubscribe IDs that do not carry an issued at time", func() {
+			cloak.UnveilCall.Returns.PlainText = []byte("some-user|some-client|some-kind")
+			clock.NowCall.Returns.Time = time.Now().Add(10 * 365 * 24 * time.Hour)
+
+			_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not expire unsubscribe IDs when the lifetime is zero", func() {
+			clock.NowCall.Returns.Time = time.Unix(1433799612, 0).Add(10 * 365 * 24 * time.Hour)
+			unsubscriber = services.NewUnsubscriber(cloak, clock, 0, clientsRepo, kindsRepo, unsubscribesRepo)
+
+			_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("failure cases", func() {
+			It("returns an invalid unsubscribe ID error when the ID cannot be unveiled", func() {
+				cloak.UnveilCall.Returns.Error = errors.New("bad cipher text")
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(MatchError(services.InvalidUnsubscribeIDError{Err: errors.New("bad cipher text")}))
+			})
+
+			It("returns an invalid unsubscribe ID error when the ID is malformed", func() {
+				cloak.UnveilCall.Returns.PlainText = []byte("garbage")
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError{}))
+			})
+
+			It("returns an expired unsubscribe ID error when the ID has outlived its lifetime", func() {
+				clock.NowCall.Returns.Time = time.Unix(1433799612, 0).Add(25 * time.Hour)
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(MatchError(services.ExpiredUnsubscribeIDError{}))
+			})
+
+			It("returns an invalid unsubscribe ID error when the kind does not exist", func() {
+				kindsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError{}))
+			})
+
+			It("returns an invalid unsubscribe ID error when the client does not exist", func() {
+				clientsRepo.FindCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError{}))
+			})
+
+			It("returns other repo errors untouched", func() {
+				kindsRepo.FindCall.Returns.Error = errors.New("db is down")
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(MatchError(errors.New("db is down")))
+			})
+
+			It("returns a critical kind error when the kind is critical", func() {
+				kindsRepo.FindCall.Returns.Kinds = []models.Kind{{ID: "some-kind", ClientID: "some-client", Critical: true}}
+
+				_, err := unsubscriber.Find(conn, "some-unsubscribe-id")
+				Expect(err).To(BeAssignableToTypeOf(services.CriticalKindError{}))
+			})
+		})
+	})
+
+	Describe("Unsubscribe", func() {
+		It("unsubscribes the user from the kind", func() {
+			unsubscription, err := unsubscriber.Unsubscribe(conn, "some-unsubscribe-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(unsubscription.UserGUID).To(Equal("some-user"))
+
+			Expect(unsubscribesRepo.SetCall.Receives.Connection).To(Equal(conn))
+			Expect(unsubscribesRepo.SetCall.Receives.UserID).To(Equal("some-user"))
+			Expect(unsubscribesRepo.SetCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(unsubscribesRepo.SetCall.Receives.KindID).To(Equal("some-kind"))
+			Expect(unsubscribesRepo.SetCall.Receives.Unsubscribe).To(BeTrue())
+		})
+
+		It("does not unsubscribe when the ID is invalid", func() {
+			cloak.UnveilCall.Returns.Error = errors.New("bad cipher text")
+
+			_, err := unsubscriber.Unsubscribe(conn, "some-unsubscribe-id")
+			Expect(err).To(BeAssignableToTypeOf(services.InvalidUnsubscribeIDError{}))
+			Expect(unsubscribesRepo.SetCall.Receives.UserID).To(BeEmpty())
+		})
+
+		It("returns an error when the repo fails", func() {
+			unsubscribesRepo.SetCall.Returns.Error = errors.New("db is down")
+
+			_, err := unsubscriber.Unsubscribe(conn, "some-unsubscribe-id")
+			Expect(err).To(MatchError(errors.New("db is down")))
+		})
+	})
+})
@@ -1,6 +1,17 @@
 package services
 
 type User struct {
-	GUID  string
-	Email string
+	GUID   string
+	Email  string
+	Locale string
+}
+
+// Recipient identifies the user in the messages of a send: the user GUID,
+// or the email address for a send to an email address.
+func (user User) Recipient() string {
+	if user.GUID != "" {
+		return user.GUID
+	}
+
+	return user.Email
 }
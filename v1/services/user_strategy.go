@@ -1,20 +1,36 @@
 package services
 
-import "github.com/cloudfoundry-incubator/notifications/cf"
+import (
+	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 const UserEndorsement = "This message was sent directly to you."
 
 type UserStrategy struct {
-	enqueuer enqueuer
+	enqueuer  enqueuer
+	sendsRepo sendCreator
 }
 
-func NewUserStrategy(enqueuer enqueuer) UserStrategy {
+func NewUserStrategy(enqueuer enqueuer, sendsRepo sendCreator) UserStrategy {
 	return UserStrategy{
-		enqueuer: enqueuer,
+		enqueuer:  enqueuer,
+		sendsRepo: sendsRepo,
 	}
 }
 
 func (strategy UserStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
+	send, err := strategy.sendsRepo.Create(dispatch.Connection, models.Send{
+		ClientID:      dispatch.Client.ID,
+		KindID:        dispatch.Kind.ID,
+		Audience:      AudienceUser,
+		VCAPRequestID: dispatch.VCAPRequest.ID,
+		Resolved:      true,
+	})
+	if err != nil {
+		return []Response{}, err
+	}
+
 	options := Options{
 		ReplyTo:           dispatch.Message.ReplyTo,
 		Subject:           dispatch.Message.Subject,
@@ -25,6 +41,10 @@ func (strategy UserStrategy) Dispatch(dispatch Dispatch) ([]Response, error) {
 		SourceDescription: dispatch.Client.Description,
 		Text:              dispatch.Message.Text,
 		TemplateID:        dispatch.TemplateID,
+		SendID:            send.ID,
+		SendAt:            dispatch.SendAt,
+		Priority:          dispatch.Priority,
+		Locale:            dispatch.Locale,
 		HTML: HTML{
 			BodyContent:    dispatch.Message.HTML.BodyContent,
 			BodyAttributes: dispatch.Message.HTML.BodyAttributes,
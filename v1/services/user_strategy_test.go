@@ -1,11 +1,14 @@
 package services_test
 
 import (
+	"errors"
 	"reflect"
 	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 
 	. "github.com/onsi/ginkgo"
@@ -16,6 +19,7 @@ var _ = Describe("UserStrategy", func() {
 	var (
 		strategy        services.UserStrategy
 		enqueuer        *mocks.Enqueuer
+		sendsRepo       *mocks.SendsRepo
 		conn            *mocks.Connection
 		requestReceived time.Time
 	)
@@ -24,7 +28,9 @@ var _ = Describe("UserStrategy", func() {
 		requestReceived, _ = time.Parse(time.RFC3339Nano, "2015-06-08T14:37:35.181067085-07:00")
 		conn = mocks.NewConnection()
 		enqueuer = mocks.NewEnqueuer()
-		strategy = services.NewUserStrategy(enqueuer)
+		sendsRepo = mocks.NewSendsRepo()
+		sendsRepo.CreateCall.Returns.Send = models.Send{ID: "some-send-id"}
+		strategy = services.NewUserStrategy(enqueuer, sendsRepo)
 	})
 
 	Describe("Dispatch", func() {
@@ -46,6 +52,9 @@ var _ = Describe("UserStrategy", func() {
 				},
 				TemplateID: "some-template-id",
 				UAAHost:    "uaa",
+				SendAt:     requestReceived.Add(time.Hour),
+				Priority:   gobble.PriorityHigh,
+				Locale:     "de-DE",
 				Kind: services.DispatchKind{
 					ID:          "forgot_waterbottle",
 					Description: "Water Bottle Reminder",
@@ -81,6 +90,10 @@ var _ = Describe("UserStrategy", func() {
 					Doctype:        "<html>",
 				},
 				Endorsement: services.UserEndorsement,
+				SendID:      "some-send-id",
+				SendAt:      requestReceived.Add(time.Hour),
+				Priority:    gobble.PriorityHigh,
+				Locale:      "de-DE",
 			}))
 			Expect(enqueuer.EnqueueCall.Receives.Space).To(Equal(cf.CloudControllerSpace{}))
 			Expect(enqueuer.EnqueueCall.Receives.Org).To(Equal(cf.CloudControllerOrganization{}))
@@ -89,6 +102,24 @@ var _ = Describe("UserStrategy", func() {
 			Expect(enqueuer.EnqueueCall.Receives.UAAHost).To(Equal("uaa"))
 			Expect(enqueuer.EnqueueCall.Receives.VCAPRequestID).To(Equal("some-vcap-request-id"))
 			Expect(enqueuer.EnqueueCall.Receives.RequestReceived).To(Equal(requestReceived))
+
+			Expect(sendsRepo.CreateCall.Receives.Send).To(Equal(models.Send{
+				ClientID:      "mister-client",
+				KindID:        "forgot_waterbottle",
+				Audience:      services.AudienceUser,
+				VCAPRequestID: "some-vcap-request-id",
+				Resolved:      true,
+			}))
+		})
+
+		Context("when the send cannot be recorded", func() {
+			It("returns the error without enqueuing anything", func() {
+				sendsRepo.CreateCall.Returns.Error = errors.New("database is down")
+
+				_, err := strategy.Dispatch(services.Dispatch{GUID: "user-123", Connection: conn})
+				Expect(err).To(MatchError(errors.New("database is down")))
+				Expect(enqueuer.EnqueueCall.WasCalled).To(BeFalse())
+			})
 		})
 	})
 })
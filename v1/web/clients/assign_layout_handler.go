@@ -0,0 +1,52 @@
+package clients
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type assignsLayouts interface {
+	AssignLayoutToClient(connection collections.ConnectionInterface, clientID, layout string) error
+}
+
+type AssignLayoutHandler struct {
+	layoutAssigner assignsLayouts
+	errorWriter    errorWriter
+}
+
+func NewAssignLayoutHandler(assigner assignsLayouts, errWriter errorWriter) AssignLayoutHandler {
+	return AssignLayoutHandler{
+		layoutAssigner: assigner,
+		errorWriter:    errWriter,
+	}
+}
+
+type LayoutAssignment struct {
+	Layout string `json:"layout"`
+}
+
+func (h AssignLayoutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	routeRegex := regexp.MustCompile("/clients/(.*)/layout")
+	clientID := routeRegex.FindStringSubmatch(req.URL.Path)[1]
+
+	var layoutAssignment LayoutAssignment
+	err := json.NewDecoder(req.Body).Decode(&layoutAssignment)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+	err = h.layoutAssigner.AssignLayoutToClient(database.Connection(), clientID, layoutAssignment.Layout)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
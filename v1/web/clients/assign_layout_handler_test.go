@@ -0,0 +1,84 @@
+package clients_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/clients"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AssignLayoutHandler", func() {
+	var (
+		handler        clients.AssignLayoutHandler
+		layoutAssigner *mocks.LayoutAssigner
+		errorWriter    *mocks.ErrorWriter
+		context        stack.Context
+		database       *mocks.Database
+		connection     *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		layoutAssigner = mocks.NewLayoutAssigner()
+		errorWriter = mocks.NewErrorWriter()
+		connection = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = clients.NewAssignLayoutHandler(layoutAssigner, errorWriter)
+	})
+
+	It("assigns a layout to a client", func() {
+		body, err := json.Marshal(map[string]string{
+			"layout": "my-layout",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		w := httptest.NewRecorder()
+		request, err := http.NewRequest("PUT", "/clients/my-client/layout", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(w, request, context)
+
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(layoutAssigner.AssignLayoutToClientCall.Receives.Connection).To(Equal(connection))
+		Expect(layoutAssigner.AssignLayoutToClientCall.Receives.ClientID).To(Equal("my-client"))
+		Expect(layoutAssigner.AssignLayoutToClientCall.Receives.Layout).To(Equal("my-layout"))
+	})
+
+	It("delegates to the error writer when the assigner errors", func() {
+		layoutAssigner.AssignLayoutToClientCall.Returns.Error = errors.New("banana")
+		body, err := json.Marshal(map[string]string{
+			"layout": "my-layout",
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		w := httptest.NewRecorder()
+		request, err := http.NewRequest("PUT", "/clients/my-client/layout", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(w, request, context)
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("banana")))
+	})
+
+	It("writes a ParseError to the error writer when request body is invalid", func() {
+		body := []byte(`{ "this is" : not-valid-json }`)
+
+		w := httptest.NewRecorder()
+		request, err := http.NewRequest("PUT", "/clients/my-client/layout", bytes.NewBuffer(body))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(w, request, context)
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ParseError{}))
+	})
+})
@@ -14,8 +14,10 @@ type Routes struct {
 
 	ErrorWriter      errorWriter
 	TemplateAssigner assignsTemplates
+	LayoutAssigner   assignsLayouts
 }
 
 func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/clients/{client_id}/template", NewAssignTemplateHandler(r.TemplateAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/clients/{client_id}/layout", NewAssignLayoutHandler(r.LayoutAssigner, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 }
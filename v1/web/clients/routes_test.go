@@ -27,6 +27,7 @@ var _ = Describe("Routes", func() {
 
 			ErrorWriter:      mocks.NewErrorWriter(),
 			TemplateAssigner: mocks.NewTemplateAssigner(),
+			LayoutAssigner:   mocks.NewLayoutAssigner(),
 		}.Register(muxer)
 	})
 
@@ -41,4 +42,16 @@ var _ = Describe("Routes", func() {
 		authenticator := s.Middleware[2].(middleware.Authenticator)
 		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
 	})
+
+	It("routes PUT /clients/{client_id}/layout", func() {
+		request, err := http.NewRequest("PUT", "/clients/some-client-id/layout", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(clients.AssignLayoutHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(Equal([]string{"notifications.manage"}))
+	})
 })
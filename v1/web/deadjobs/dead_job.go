@@ -0,0 +1,59 @@
+package deadjobs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+)
+
+type deadJobDocument struct {
+	ID              int       `json:"id"`
+	JobID           int       `json:"job_id"`
+	Attempts        int       `json:"attempts"`
+	Priority        int       `json:"priority"`
+	LastError       string    `json:"last_error"`
+	LastAttemptedAt time.Time `json:"last_attempted_at"`
+	FailedAt        time.Time `json:"failed_at"`
+	Payload         string    `json:"payload,omitempty"`
+}
+
+func newDeadJobDocument(deadJob gobble.DeadJob) deadJobDocument {
+	return deadJobDocument{
+		ID:              deadJob.ID,
+		JobID:           deadJob.JobID,
+		Attempts:        deadJob.Attempts,
+		Priority:        deadJob.Priority,
+		LastError:       deadJob.LastError,
+		LastAttemptedAt: deadJob.LastAttemptedAt,
+		FailedAt:        deadJob.FailedAt,
+		Payload:         deadJob.Payload,
+	}
+}
+
+func parseDeadJobID(path string) (int, error) {
+	id := strings.TrimPrefix(path, "/dead_jobs/")
+	id = strings.TrimSuffix(id, "/replay")
+
+	deadJobID, err := strconv.Atoi(id)
+	if err != nil {
+		return 0, webutil.ValidationError{Err: errors.New("Dead job ID must be an integer")}
+	}
+
+	return deadJobID, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
@@ -0,0 +1,35 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type GetHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewGetHandler(queue deadJobsQueue, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadJobID, err := parseDeadJobID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	deadJob, err := h.queue.FindDeadJob(deadJobID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newDeadJobDocument(deadJob))
+}
@@ -0,0 +1,83 @@
+package deadjobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     deadjobs.GetHandler
+		queue       *mocks.DeadJobsQueue
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewDeadJobsQueue()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		handler = deadjobs.NewGetHandler(queue, errorWriter)
+	})
+
+	It("returns the dead job including its payload", func() {
+		queue.FindDeadJobCall.Returns.DeadJob = gobble.DeadJob{
+			ID:              42,
+			JobID:           12,
+			Payload:         `{"MessageID":"some-message-id"}`,
+			Attempts:        11,
+			Priority:        gobble.PriorityHigh,
+			LastError:       "smtp is down",
+			LastAttemptedAt: time.Date(2015, 6, 8, 14, 0, 0, 0, time.UTC),
+			FailedAt:        time.Date(2015, 6, 8, 15, 0, 0, 0, time.UTC),
+		}
+
+		request, err := http.NewRequest("GET", "/dead_jobs/42", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(queue.FindDeadJobCall.Receives.ID).To(Equal(42))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"id": 42,
+			"job_id": 12,
+			"payload": "{\"MessageID\":\"some-message-id\"}",
+			"attempts": 11,
+			"priority": 10,
+			"last_error": "smtp is down",
+			"last_attempted_at": "2015-06-08T14:00:00Z",
+			"failed_at": "2015-06-08T15:00:00Z"
+		}`))
+	})
+
+	It("delegates not found errors to the error writer", func() {
+		queue.FindDeadJobCall.Returns.Error = gobble.DeadJobNotFoundError{ID: 42}
+
+		request, err := http.NewRequest("GET", "/dead_jobs/42", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(gobble.DeadJobNotFoundError{ID: 42}))
+	})
+
+	It("returns a validation error when the ID is not an integer", func() {
+		request, err := http.NewRequest("GET", "/dead_jobs/banana", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError("Dead job ID must be an integer"))
+	})
+})
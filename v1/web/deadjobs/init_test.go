@@ -0,0 +1,13 @@
+package deadjobs_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebV1DeadJobsSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "v1/web/deadjobs")
+}
@@ -0,0 +1,36 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type ListHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewListHandler(queue deadJobsQueue, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadJobs, err := h.queue.DeadJobs()
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	documents := []deadJobDocument{}
+	for _, deadJob := range deadJobs {
+		documents = append(documents, newDeadJobDocument(deadJob))
+	}
+
+	writeJSON(w, http.StatusOK, map[string][]deadJobDocument{
+		"dead_jobs": documents,
+	})
+}
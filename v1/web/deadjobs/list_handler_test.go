@@ -0,0 +1,84 @@
+package deadjobs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler     deadjobs.ListHandler
+		queue       *mocks.DeadJobsQueue
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		queue = mocks.NewDeadJobsQueue()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("GET", "/dead_jobs", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = deadjobs.NewListHandler(queue, errorWriter)
+	})
+
+	It("lists the dead jobs", func() {
+		queue.DeadJobsCall.Returns.DeadJobs = []gobble.DeadJob{
+			{
+				ID:              1,
+				JobID:           12,
+				Attempts:        11,
+				Priority:        gobble.PriorityHigh,
+				LastError:       "smtp is down",
+				LastAttemptedAt: time.Date(2015, 6, 8, 14, 0, 0, 0, time.UTC),
+				FailedAt:        time.Date(2015, 6, 8, 15, 0, 0, 0, time.UTC),
+			},
+		}
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"dead_jobs": [
+				{
+					"id": 1,
+					"job_id": 12,
+					"attempts": 11,
+					"priority": 10,
+					"last_error": "smtp is down",
+					"last_attempted_at": "2015-06-08T14:00:00Z",
+					"failed_at": "2015-06-08T15:00:00Z"
+				}
+			]
+		}`))
+	})
+
+	It("returns an empty list when there are no dead jobs", func() {
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"dead_jobs": []}`))
+	})
+
+	It("delegates errors to the error writer", func() {
+		queue.DeadJobsCall.Returns.Error = errors.New("db is down")
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db is down")))
+	})
+})
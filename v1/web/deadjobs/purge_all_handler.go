@@ -0,0 +1,31 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type PurgeAllHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewPurgeAllHandler(queue deadJobsQueue, errWriter errorWriter) PurgeAllHandler {
+	return PurgeAllHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h PurgeAllHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	count, err := h.queue.PurgeAll()
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"purged": count,
+	})
+}
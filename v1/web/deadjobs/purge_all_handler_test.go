@@ -0,0 +1,53 @@
+package deadjobs_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PurgeAllHandler", func() {
+	var (
+		handler     deadjobs.PurgeAllHandler
+		queue       *mocks.DeadJobsQueue
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		queue = mocks.NewDeadJobsQueue()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		request, err = http.NewRequest("DELETE", "/dead_jobs", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = deadjobs.NewPurgeAllHandler(queue, errorWriter)
+	})
+
+	It("purges every dead job and returns the count", func() {
+		queue.PurgeAllCall.Returns.Count = 3
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"purged": 3}`))
+	})
+
+	It("delegates errors to the error writer", func() {
+		queue.PurgeAllCall.Returns.Error = errors.New("db is down")
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db is down")))
+	})
+})
@@ -0,0 +1,35 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type PurgeHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewPurgeHandler(queue deadJobsQueue, errWriter errorWriter) PurgeHandler {
+	return PurgeHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h PurgeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadJobID, err := parseDeadJobID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	err = h.queue.Purge(deadJobID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
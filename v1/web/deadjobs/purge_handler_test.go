@@ -0,0 +1,60 @@
+package deadjobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PurgeHandler", func() {
+	var (
+		handler     deadjobs.PurgeHandler
+		queue       *mocks.DeadJobsQueue
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewDeadJobsQueue()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		handler = deadjobs.NewPurgeHandler(queue, errorWriter)
+	})
+
+	It("purges the dead job", func() {
+		request, err := http.NewRequest("DELETE", "/dead_jobs/42", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(queue.PurgeCall.Receives.ID).To(Equal(42))
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+	})
+
+	It("delegates errors to the error writer", func() {
+		queue.PurgeCall.Returns.Error = gobble.DeadJobNotFoundError{ID: 42}
+
+		request, err := http.NewRequest("DELETE", "/dead_jobs/42", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(gobble.DeadJobNotFoundError{ID: 42}))
+	})
+
+	It("returns a validation error when the ID is not an integer", func() {
+		request, err := http.NewRequest("DELETE", "/dead_jobs/banana", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+	})
+})
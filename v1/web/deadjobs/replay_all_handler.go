@@ -0,0 +1,31 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type ReplayAllHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewReplayAllHandler(queue deadJobsQueue, errWriter errorWriter) ReplayAllHandler {
+	return ReplayAllHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ReplayAllHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	count, err := h.queue.ReplayAll()
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"replayed": count,
+	})
+}
@@ -0,0 +1,37 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/ryanmoran/stack"
+)
+
+type ReplayHandler struct {
+	queue       deadJobsQueue
+	errorWriter errorWriter
+}
+
+func NewReplayHandler(queue deadJobsQueue, errWriter errorWriter) ReplayHandler {
+	return ReplayHandler{
+		queue:       queue,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ReplayHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	deadJobID, err := parseDeadJobID(req.URL.Path)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	job, err := h.queue.Replay(deadJobID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"job_id": job.ID,
+	})
+}
@@ -0,0 +1,63 @@
+package deadjobs_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ReplayHandler", func() {
+	var (
+		handler     deadjobs.ReplayHandler
+		queue       *mocks.DeadJobsQueue
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		queue = mocks.NewDeadJobsQueue()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		handler = deadjobs.NewReplayHandler(queue, errorWriter)
+	})
+
+	It("re-enqueues the dead job and returns the new job ID", func() {
+		queue.ReplayCall.Returns.Job = &gobble.Job{ID: 99}
+
+		request, err := http.NewRequest("POST", "/dead_jobs/42/replay", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+
+		Expect(queue.ReplayCall.Receives.ID).To(Equal(42))
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"job_id": 99}`))
+	})
+
+	It("delegates errors to the error writer", func() {
+		queue.ReplayCall.Returns.Error = gobble.DeadJobNotFoundError{ID: 42}
+
+		request, err := http.NewRequest("POST", "/dead_jobs/42/replay", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(gobble.DeadJobNotFoundError{ID: 42}))
+	})
+
+	It("returns a validation error when the ID is not an integer", func() {
+		request, err := http.NewRequest("POST", "/dead_jobs/banana/replay", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, stack.NewContext())
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+	})
+})
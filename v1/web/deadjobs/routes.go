@@ -0,0 +1,43 @@
+package deadjobs
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type deadJobsQueue interface {
+	DeadJobs() ([]gobble.DeadJob, error)
+	FindDeadJob(id int) (gobble.DeadJob, error)
+	Replay(id int) (*gobble.Job, error)
+	ReplayAll() (int, error)
+	Purge(id int) error
+	PurgeAll() (int, error)
+}
+
+type Routes struct {
+	RequestCounter                  stack.Middleware
+	RequestLogging                  stack.Middleware
+	NotificationsAdminAuthenticator stack.Middleware
+
+	ErrorWriter errorWriter
+	Queue       deadJobsQueue
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/dead_jobs", NewListHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("DELETE", "/dead_jobs", NewPurgeAllHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("POST", "/dead_jobs/replay", NewReplayAllHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("GET", "/dead_jobs/{dead_job_id}", NewGetHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("DELETE", "/dead_jobs/{dead_job_id}", NewPurgeHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+	m.Handle("POST", "/dead_jobs/{dead_job_id}/replay", NewReplayHandler(r.Queue, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator)
+}
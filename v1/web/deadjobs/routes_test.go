@@ -0,0 +1,107 @@
+package deadjobs_test
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var muxer web.Muxer
+
+	BeforeEach(func() {
+		muxer = web.NewMuxer()
+		deadjobs.Routes{
+			RequestCounter:                  middleware.RequestCounter{},
+			RequestLogging:                  middleware.RequestLogging{},
+			NotificationsAdminAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.admin"}},
+
+			ErrorWriter: mocks.NewErrorWriter(),
+			Queue:       mocks.NewDeadJobsQueue(),
+		}.Register(muxer)
+	})
+
+	Describe("/dead_jobs", func() {
+		It("routes GET /dead_jobs", func() {
+			request, err := http.NewRequest("GET", "/dead_jobs", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(deadjobs.ListHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+		})
+
+		It("routes DELETE /dead_jobs", func() {
+			request, err := http.NewRequest("DELETE", "/dead_jobs", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(deadjobs.PurgeAllHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+		})
+
+		It("routes POST /dead_jobs/replay", func() {
+			request, err := http.NewRequest("POST", "/dead_jobs/replay", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(deadjobs.ReplayAllHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+		})
+	})
+
+	Describe("/dead_jobs/{dead_job_id}", func() {
+		It("routes GET /dead_jobs/{dead_job_id}", func() {
+			request, err := http.NewRequest("GET", "/dead_jobs/42", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(deadjobs.GetHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+		})
+
+		It("routes DELETE /dead_jobs/{dead_job_id}", func() {
+			request, err := http.NewRequest("DELETE", "/dead_jobs/42", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(deadjobs.PurgeHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+		})
+
+		It("routes POST /dead_jobs/{dead_job_id}/replay", func() {
+			request, err := http.NewRequest("POST", "/dead_jobs/42/replay", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(deadjobs.ReplayHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notifications.admin"}))
+		})
+	})
+})
@@ -0,0 +1,41 @@
+package feedbackreports
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type CreateHandler struct {
+	recorder    feedbackRecorder
+	errorWriter errorWriter
+}
+
+func NewCreateHandler(recorder feedbackRecorder, errWriter errorWriter) CreateHandler {
+	return CreateHandler{
+		recorder:    recorder,
+		errorWriter: errWriter,
+	}
+}
+
+// ServeHTTP records a bounce or complaint report posted as a raw
+// multipart/report email, as forwarded by a mail provider's webhook.
+func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	report, err := feedback.Parse(req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		return
+	}
+
+	database := context.Get("database").(DatabaseInterface)
+
+	err = h.recorder.Record(database.Connection(), report)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
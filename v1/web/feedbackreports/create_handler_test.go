@@ -0,0 +1,97 @@
+package feedbackreports_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/feedbackreports"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var complaintReport = strings.Replace(`Content-Type: multipart/report; report-type=feedback-report; boundary="boundary"
+
+--boundary
+Content-Type: message/feedback-report
+
+Feedback-Type: abuse
+
+--boundary
+Content-Type: text/rfc822-headers
+
+To: someone@example.com
+X-CF-Notification-ID: some-message-id
+
+--boundary--
+`, "\n", "\r\n", -1)
+
+var _ = Describe("CreateHandler", func() {
+	var (
+		handler     feedbackreports.CreateHandler
+		recorder    *mocks.FeedbackRecorder
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		conn        *mocks.Connection
+		context     stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		recorder = mocks.NewFeedbackRecorder()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		conn = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("POST", "/feedback_reports", strings.NewReader(complaintReport))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Content-Type", "message/rfc822")
+
+		handler = feedbackreports.NewCreateHandler(recorder, errorWriter)
+	})
+
+	It("records the report", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(recorder.RecordCall.Receives.Connection).To(Equal(conn))
+		Expect(recorder.RecordCall.Receives.Report).To(Equal(feedback.Report{
+			Type:           feedback.Complaint,
+			NotificationID: "some-message-id",
+			Recipient:      "someone@example.com",
+			FeedbackType:   "abuse",
+		}))
+	})
+
+	Context("failure cases", func() {
+		It("writes a validation error when the body is not a report", func() {
+			request, _ = http.NewRequest("POST", "/feedback_reports", strings.NewReader("Subject: hello\r\n\r\nhello\r\n"))
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+			Expect(recorder.RecordCall.WasCalled).To(BeFalse())
+		})
+
+		It("writes errors from the recorder", func() {
+			recorder.RecordCall.Returns.Error = errors.New("db is down")
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db is down")))
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package feedbackreports_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWebV1FeedbackReportsSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "v1/web/feedbackreports")
+}
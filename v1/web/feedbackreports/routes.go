@@ -0,0 +1,35 @@
+package feedbackreports
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/feedback"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type feedbackRecorder interface {
+	Record(conn services.ConnectionInterface, report feedback.Report) error
+}
+
+type Routes struct {
+	RequestCounter                  stack.Middleware
+	RequestLogging                  stack.Middleware
+	DatabaseAllocator               stack.Middleware
+	NotificationsAdminAuthenticator stack.Middleware
+
+	ErrorWriter      errorWriter
+	FeedbackRecorder feedbackRecorder
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("POST", "/feedback_reports", NewCreateHandler(r.FeedbackRecorder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsAdminAuthenticator, r.DatabaseAllocator)
+}
@@ -0,0 +1,44 @@
+package feedbackreports_test
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/feedbackreports"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var muxer web.Muxer
+
+	BeforeEach(func() {
+		muxer = web.NewMuxer()
+		feedbackreports.Routes{
+			RequestCounter:                  middleware.RequestCounter{},
+			RequestLogging:                  middleware.RequestLogging{},
+			DatabaseAllocator:               middleware.DatabaseAllocator{},
+			NotificationsAdminAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.admin"}},
+
+			ErrorWriter:      mocks.NewErrorWriter(),
+			FeedbackRecorder: mocks.NewFeedbackRecorder(),
+		}.Register(muxer)
+	})
+
+	It("routes POST /feedback_reports", func() {
+		request, err := http.NewRequest("POST", "/feedback_reports", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(feedbackreports.CreateHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.admin"}))
+	})
+})
@@ -0,0 +1,42 @@
+package messages
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type DeleteHandler struct {
+	canceller   messageCanceller
+	errorWriter errorWriter
+}
+
+type messageCanceller interface {
+	CancelMessage(database services.DatabaseInterface, messageID, clientID string, admin bool) error
+}
+
+func NewDeleteHandler(canceller messageCanceller, errWriter errorWriter) DeleteHandler {
+	return DeleteHandler{
+		canceller:   canceller,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	messageID := strings.Split(req.URL.Path, "/messages/")[1]
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	err := h.canceller.CancelMessage(context.Get("database").(DatabaseInterface), messageID, clientID, webutil.HasAdminScope(token.Claims["scope"]))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,93 @@
+package messages_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler     messages.DeleteHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		canceller   *mocks.Canceller
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	setToken := func(scopes ...string) {
+		rawToken := helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "some-client",
+			"iss":       "http://uaa.example.com/oauth/token",
+			"exp":       int64(3404281214),
+			"scope":     scopes,
+		})
+
+		token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+			return []byte(helpers.UAAPublicKey), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		context.Set("token", token)
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		errorWriter = mocks.NewErrorWriter()
+		canceller = mocks.NewCanceller()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		setToken("notifications.write")
+
+		request, err = http.NewRequest("DELETE", "/messages/message-123", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = messages.NewDeleteHandler(canceller, errorWriter)
+	})
+
+	It("cancels the message on behalf of the client", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(writer.Body.String()).To(BeEmpty())
+
+		Expect(canceller.CancelMessageCall.Receives.Database).To(Equal(database))
+		Expect(canceller.CancelMessageCall.Receives.MessageID).To(Equal("message-123"))
+		Expect(canceller.CancelMessageCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(canceller.CancelMessageCall.Receives.Admin).To(BeFalse())
+	})
+
+	It("cancels the message as an admin when the token has the notifications.admin scope", func() {
+		setToken("notifications.admin")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(canceller.CancelMessageCall.Receives.Admin).To(BeTrue())
+	})
+
+	It("delegates errors to the error writer", func() {
+		canceller.CancelMessageCall.Returns.Error = services.MessageNotCancellableError{Err: errors.New("already delivered")}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(services.MessageNotCancellableError{Err: errors.New("already delivered")}))
+	})
+})
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -19,7 +22,7 @@ type errorWriter interface {
 }
 
 type messageFinder interface {
-	Find(services.DatabaseInterface, string) (services.Message, error)
+	Find(database services.DatabaseInterface, messageID, clientID string, admin bool) (services.Message, error)
 }
 
 func NewGetHandler(finder messageFinder, errWriter errorWriter) GetHandler {
@@ -32,20 +35,62 @@ func NewGetHandler(finder messageFinder, errWriter errorWriter) GetHandler {
 func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
 	messageID := strings.Split(req.URL.Path, "/messages/")[1]
 
-	message, err := h.finder.Find(context.Get("database").(DatabaseInterface), messageID)
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	message, err := h.finder.Find(context.Get("database").(DatabaseInterface), messageID, clientID, webutil.HasAdminScope(token.Claims["scope"]))
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
-	var document struct {
-		Status string `json:"status"`
+	document := messageDocument{
+		Status:          message.Status,
+		Recipient:       message.Recipient,
+		ClientID:        message.ClientID,
+		KindID:          message.KindID,
+		VCAPRequestID:   message.VCAPRequestID,
+		TemplateID:      message.TemplateID,
+		TemplateVersion: message.TemplateVersion,
+		QueuedAt:        message.QueuedAt,
+		UpdatedAt:       message.UpdatedAt,
+		Events:          []messageEventDocument{},
+	}
+
+	for _, event := range message.Events {
+		document.Events = append(document.Events, messageEventDocument{
+			Type:        event.Type,
+			Attempt:     event.Attempt,
+			SMTPCode:    event.SMTPCode,
+			Description: event.Description,
+			CreatedAt:   event.CreatedAt,
+		})
 	}
-	document.Status = message.Status
 
 	writeJSON(w, http.StatusOK, document)
 }
 
+type messageDocument struct {
+	Status          string                 `json:"status"`
+	Recipient       string                 `json:"recipient"`
+	ClientID        string                 `json:"client_id"`
+	KindID          string                 `json:"kind_id"`
+	VCAPRequestID   string                 `json:"vcap_request_id"`
+	TemplateID      string                 `json:"template_id,omitempty"`
+	TemplateVersion int                    `json:"template_version,omitempty"`
+	QueuedAt        time.Time              `json:"queued_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	Events          []messageEventDocument `json:"events"`
+}
+
+type messageEventDocument struct {
+	Type        string    `json:"type"`
+	Attempt     int       `json:"attempt,omitempty"`
+	SMTPCode    int       `json:"smtp_code,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 func writeJSON(w http.ResponseWriter, status int, object interface{}) {
 	output, err := json.Marshal(object)
 	if err != nil {
@@ -1,13 +1,18 @@
 package messages_test
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo"
@@ -27,6 +32,24 @@ var _ = Describe("GetHandler", func() {
 		context       stack.Context
 	)
 
+	setToken := func(scopes ...string) {
+		rawToken := helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "some-client",
+			"iss":       "http://uaa.example.com/oauth/token",
+			"exp":       int64(3404281214),
+			"scope":     scopes,
+		})
+
+		token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+			return []byte(helpers.UAAPublicKey), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		context.Set("token", token)
+	}
+
 	BeforeEach(func() {
 		errorWriter = mocks.NewErrorWriter()
 		messageFinder = mocks.NewMessageFinder()
@@ -35,6 +58,7 @@ var _ = Describe("GetHandler", func() {
 		database = mocks.NewDatabase()
 		context = stack.NewContext()
 		context.Set("database", database)
+		setToken("notifications.write")
 
 		request, err = http.NewRequest("GET", "/messages/"+messageID, nil)
 		if err != nil {
@@ -54,11 +78,109 @@ var _ = Describe("GetHandler", func() {
 
 			Expect(writer.Code).To(Equal(http.StatusOK))
 			Expect(writer.Body.Bytes()).To(MatchJSON(`{
-				"status": "The generic status returned"
+				"status": "The generic status returned",
+				"recipient": "",
+				"client_id": "",
+				"kind_id": "",
+				"vcap_request_id": "",
+				"queued_at": "0001-01-01T00:00:00Z",
+				"updated_at": "0001-01-01T00:00:00Z",
+				"events": []
 			}`))
 
 			Expect(messageFinder.FindCall.Receives.Database).To(Equal(database))
 			Expect(messageFinder.FindCall.Receives.MessageID).To(Equal(messageID))
+			Expect(messageFinder.FindCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(messageFinder.FindCall.Receives.Admin).To(BeFalse())
+		})
+
+		It("writes not found for a message sent by another client", func() {
+			notFound := models.NotFoundError{Err: errors.New(`Message with ID "message-123" could not be found`)}
+			messageFinder.FindCall.Returns.Error = notFound
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(messageFinder.FindCall.Receives.ClientID).To(Equal("some-client"))
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notFound))
+		})
+
+		It("lets an admin find a message sent by another client", func() {
+			setToken("notifications.admin")
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status:   "delivered",
+				ClientID: "other-client",
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(messageFinder.FindCall.Receives.Admin).To(BeTrue())
+		})
+
+		It("includes the template version that rendered the message", func() {
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status:          "delivered",
+				TemplateID:      "some-template",
+				TemplateVersion: 4,
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+
+			var document map[string]interface{}
+			Expect(json.Unmarshal(writer.Body.Bytes(), &document)).To(Succeed())
+			Expect(document["template_id"]).To(Equal("some-template"))
+			Expect(document["template_version"]).To(Equal(float64(4)))
+		})
+
+		It("returns the delivery history of the given message", func() {
+			queuedAt := time.Date(2015, time.June, 8, 14, 40, 12, 0, time.UTC)
+			messageFinder.FindCall.Returns.Message = services.Message{
+				Status:        "failed",
+				Recipient:     "user@example.com",
+				ClientID:      "some-client",
+				KindID:        "some-kind",
+				VCAPRequestID: "some-request-id",
+				QueuedAt:      queuedAt,
+				UpdatedAt:     queuedAt.Add(time.Minute),
+				Events: []services.MessageEvent{
+					{Type: "queued", CreatedAt: queuedAt},
+					{Type: "attempted", Attempt: 1, CreatedAt: queuedAt.Add(time.Minute)},
+					{Type: "failed", Attempt: 1, SMTPCode: 451, Description: "451 try again later", CreatedAt: queuedAt.Add(time.Minute)},
+				},
+			}
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(writer.Code).To(Equal(http.StatusOK))
+			Expect(writer.Body.Bytes()).To(MatchJSON(`{
+				"status": "failed",
+				"recipient": "user@example.com",
+				"client_id": "some-client",
+				"kind_id": "some-kind",
+				"vcap_request_id": "some-request-id",
+				"queued_at": "2015-06-08T14:40:12Z",
+				"updated_at": "2015-06-08T14:41:12Z",
+				"events": [
+					{
+						"type": "queued",
+						"created_at": "2015-06-08T14:40:12Z"
+					},
+					{
+						"type": "attempted",
+						"attempt": 1,
+						"created_at": "2015-06-08T14:41:12Z"
+					},
+					{
+						"type": "failed",
+						"attempt": 1,
+						"smtp_code": 451,
+						"description": "451 try again later",
+						"created_at": "2015-06-08T14:41:12Z"
+					}
+				]
+			}`))
 		})
 
 		Context("When the finder errors", func() {
@@ -7,15 +7,17 @@ type muxer interface {
 }
 
 type Routes struct {
-	RequestCounter                               stack.Middleware
-	RequestLogging                               stack.Middleware
-	NotificationsWriteOrEmailsWriteAuthenticator stack.Middleware
-	DatabaseAllocator                            stack.Middleware
+	RequestCounter                                      stack.Middleware
+	RequestLogging                                      stack.Middleware
+	NotificationsWriteOrEmailsWriteOrAdminAuthenticator stack.Middleware
+	DatabaseAllocator                                   stack.Middleware
 
-	MessageFinder messageFinder
-	ErrorWriter   errorWriter
+	MessageFinder    messageFinder
+	MessageCanceller messageCanceller
+	ErrorWriter      errorWriter
 }
 
 func (r Routes) Register(m muxer) {
-	m.Handle("GET", "/messages/{message_id}", NewGetHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/messages/{message_id}", NewGetHandler(r.MessageFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteOrAdminAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/messages/{message_id}", NewDeleteHandler(r.MessageCanceller, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteOrAdminAuthenticator, r.DatabaseAllocator)
 }
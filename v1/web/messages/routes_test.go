@@ -20,13 +20,14 @@ var _ = Describe("Routes", func() {
 	BeforeEach(func() {
 		muxer = web.NewMuxer()
 		messages.Routes{
-			RequestCounter:                               middleware.RequestCounter{},
-			RequestLogging:                               middleware.RequestLogging{},
-			DatabaseAllocator:                            middleware.DatabaseAllocator{},
-			NotificationsWriteOrEmailsWriteAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.write", "emails.write"}},
-
-			ErrorWriter:   mocks.NewErrorWriter(),
-			MessageFinder: mocks.NewMessageFinder(),
+			RequestCounter:    middleware.RequestCounter{},
+			RequestLogging:    middleware.RequestLogging{},
+			DatabaseAllocator: middleware.DatabaseAllocator{},
+			NotificationsWriteOrEmailsWriteOrAdminAuthenticator: middleware.Authenticator{Scopes: []string{"notifications.write", "emails.write", "notifications.admin"}},
+
+			ErrorWriter:      mocks.NewErrorWriter(),
+			MessageFinder:    mocks.NewMessageFinder(),
+			MessageCanceller: mocks.NewCanceller(),
 		}.Register(muxer)
 	})
 
@@ -39,6 +40,18 @@ var _ = Describe("Routes", func() {
 		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
 
 		authenticator := s.Middleware[2].(middleware.Authenticator)
-		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write"}))
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write", "notifications.admin"}))
+	})
+
+	It("routes DELETE /messages/{message_id}", func() {
+		request, err := http.NewRequest("DELETE", "/messages/some-message-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(messages.DeleteHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+		authenticator := s.Middleware[2].(middleware.Authenticator)
+		Expect(authenticator.Scopes).To(ConsistOf([]string{"notifications.write", "emails.write", "notifications.admin"}))
 	})
 })
@@ -8,6 +8,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 )
 
@@ -18,8 +19,49 @@ type ClientRegistrationParams struct {
 
 type NotificationStruct struct {
 	ID          string
-	Description string `json:"description"`
-	Critical    bool   `json:"critical"`
+	Description string             `json:"description"`
+	Critical    bool               `json:"critical"`
+	RetryPolicy *RetryPolicyParams `json:"retry_policy"`
+}
+
+type RetryPolicyParams struct {
+	MaxAttempts int     `json:"max_attempts"`
+	BaseDelay   int     `json:"base_delay"`
+	MaxDelay    int     `json:"max_delay"`
+	Jitter      float64 `json:"jitter"`
+	GiveUpAfter int     `json:"give_up_after"`
+}
+
+func (params *RetryPolicyParams) ToModel() models.RetryPolicy {
+	if params == nil {
+		return models.RetryPolicy{}
+	}
+
+	return models.RetryPolicy{
+		MaxAttempts: params.MaxAttempts,
+		BaseDelay:   params.BaseDelay,
+		MaxDelay:    params.MaxDelay,
+		Jitter:      params.Jitter,
+		GiveUpAfter: params.GiveUpAfter,
+	}
+}
+
+func (params RetryPolicyParams) validate(id string) []string {
+	var errs []string
+
+	if params.MaxAttempts < 0 || params.BaseDelay < 0 || params.MaxDelay < 0 || params.GiveUpAfter < 0 {
+		errs = append(errs, fmt.Sprintf(`notification "%+v" retry policy values must not be negative`, id))
+	}
+
+	if params.Jitter < 0 || params.Jitter > 1 {
+		errs = append(errs, fmt.Sprintf(`notification "%+v" retry policy "jitter" must be between 0 and 1`, id))
+	}
+
+	if params.MaxDelay > 0 && params.MaxDelay < params.BaseDelay {
+		errs = append(errs, fmt.Sprintf(`notification "%+v" retry policy "max_delay" must not be less than "base_delay"`, id))
+	}
+
+	return errs
 }
 
 func NewClientRegistrationParams(body io.Reader) (ClientRegistrationParams, error) {
@@ -66,9 +108,14 @@ func strictValidateJSON(bytes []byte) error {
 					return webutil.SchemaError{Err: errors.New("notification must not be null")}
 				}
 				notificationMap := notificationData.(map[string]interface{})
-				for propertyName := range notificationMap {
+				for propertyName, propertyValue := range notificationMap {
 					if propertyName == "description" || propertyName == "critical" {
 						continue
+					} else if propertyName == "retry_policy" {
+						err := strictValidateRetryPolicy(propertyValue)
+						if err != nil {
+							return err
+						}
 					} else {
 						return webutil.SchemaError{Err: fmt.Errorf("%q is not a valid property", propertyName)}
 					}
@@ -81,6 +128,24 @@ func strictValidateJSON(bytes []byte) error {
 	return nil
 }
 
+func strictValidateRetryPolicy(retryPolicy interface{}) error {
+	retryPolicyMap, ok := retryPolicy.(map[string]interface{})
+	if !ok {
+		return webutil.SchemaError{Err: errors.New("\"retry_policy\" must be an object")}
+	}
+
+	for propertyName := range retryPolicyMap {
+		switch propertyName {
+		case "max_attempts", "base_delay", "max_delay", "jitter", "give_up_after":
+			continue
+		default:
+			return webutil.SchemaError{Err: fmt.Errorf("%q is not a valid retry policy property", propertyName)}
+		}
+	}
+
+	return nil
+}
+
 func (clientRegistration ClientRegistrationParams) Validate() error {
 	var errs []string
 	if clientRegistration.SourceName == "" {
@@ -97,6 +162,9 @@ func (clientRegistration ClientRegistrationParams) Validate() error {
 		if value.Description == "" {
 			errs = append(errs, fmt.Sprintf(`notification "%+v" is missing required field "Description"`, id))
 		}
+		if value.RetryPolicy != nil {
+			errs = append(errs, value.RetryPolicy.validate(id)...)
+		}
 	}
 
 	if len(errs) > 0 {
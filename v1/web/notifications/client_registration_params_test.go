@@ -47,6 +47,21 @@ var _ = Describe("ClientRegistrationParams", func() {
 			}))
 		})
 
+		It("constructs the retry policy of a notification", func() {
+			body := `{ "source_name": "Raptor", "notifications": { "perimeter_breach": { "description": "Perimeter Breach", "retry_policy": { "max_attempts": 20, "base_delay": 10, "max_delay": 600, "jitter": 0.5, "give_up_after": 86400 } } } }`
+
+			parameters, err := notifications.NewClientRegistrationParams(strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(parameters.Notifications["perimeter_breach"].RetryPolicy).To(Equal(&notifications.RetryPolicyParams{
+				MaxAttempts: 20,
+				BaseDelay:   10,
+				MaxDelay:    600,
+				Jitter:      0.5,
+				GiveUpAfter: 86400,
+			}))
+		})
+
 		Context("error cases", func() {
 			It("returns an error when the parameters are invalid JSON", func() {
 				_, err := notifications.NewClientRegistrationParams(strings.NewReader("this is not valid JSON"))
@@ -72,6 +87,20 @@ var _ = Describe("ClientRegistrationParams", func() {
 					_, err := notifications.NewClientRegistrationParams(strings.NewReader(someJson))
 					Expect(err).To(MatchError(webutil.SchemaError{Err: errors.New("\"invalid_property\" is not a valid property")}))
 				})
+
+				It("returns an error for invalid retry policy keys", func() {
+					someJson := `{ "source_name" : "Raptor", "notifications": { "some_id": {"description" : "ok", "retry_policy" : { "invalid_property" : 5 } } } }`
+
+					_, err := notifications.NewClientRegistrationParams(strings.NewReader(someJson))
+					Expect(err).To(MatchError(webutil.SchemaError{Err: errors.New("\"invalid_property\" is not a valid retry policy property")}))
+				})
+
+				It("returns an error when the retry policy is null", func() {
+					someJson := `{ "source_name" : "Raptor", "notifications": { "some_id": {"description" : "ok", "retry_policy" : null } } }`
+
+					_, err := notifications.NewClientRegistrationParams(strings.NewReader(someJson))
+					Expect(err).To(MatchError(webutil.SchemaError{Err: errors.New("\"retry_policy\" must be an object")}))
+				})
 			})
 
 			Context("when the JSON contains null values", func() {
@@ -118,5 +147,28 @@ var _ = Describe("ClientRegistrationParams", func() {
 				Err: errors.New("notification \"perimeter_breach\" is missing required field \"ID\", notification \"perimeter_breach\" is missing required field \"Description\""),
 			}))
 		})
+
+		It("returns an error if the retry policy is invalid", func() {
+			cr := notifications.ClientRegistrationParams{
+				SourceName: "jurassic_park",
+				Notifications: map[string](*notifications.NotificationStruct){
+					"perimeter_breach": {
+						ID:          "perimeter_breach",
+						Description: "Perimeter Breach",
+						RetryPolicy: &notifications.RetryPolicyParams{
+							MaxAttempts: -1,
+							BaseDelay:   60,
+							MaxDelay:    30,
+							Jitter:      1.5,
+						},
+					},
+				},
+			}
+
+			err := cr.Validate()
+			Expect(err).To(MatchError(webutil.ValidationError{
+				Err: errors.New("notification \"perimeter_breach\" retry policy values must not be negative, notification \"perimeter_breach\" retry policy \"jitter\" must be between 0 and 1, notification \"perimeter_breach\" retry policy \"max_delay\" must not be less than \"base_delay\""),
+			}))
+		})
 	})
 })
@@ -47,10 +47,12 @@ func (h PutHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 	generatedKinds := []models.Kind{}
 	for _, notification := range parameters.Notifications {
 		generatedKinds = append(generatedKinds, models.Kind{
-			ID:          notification.ID,
-			Description: notification.Description,
-			Critical:    notification.Critical,
-			TemplateID:  models.DoNotSetTemplateID,
+			ID:             notification.ID,
+			Description:    notification.Description,
+			Critical:       notification.Critical,
+			TemplateID:     models.DoNotSetTemplateID,
+			RetryPolicy:    notification.RetryPolicy.ToModel(),
+			HasRetryPolicy: notification.RetryPolicy != nil,
 		})
 	}
 
@@ -148,6 +148,93 @@ var _ = Describe("PutHandler", func() {
 			Expect(transaction.RollbackCall.WasCalled).To(BeFalse())
 		})
 
+		It("passes the retry policy of each notification to Register", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"source_name": "Raptor Containment Unit",
+				"notifications": map[string]interface{}{
+					"perimeter_breach": map[string]interface{}{
+						"description": "Perimeter Breach",
+						"critical":    true,
+						"retry_policy": map[string]interface{}{
+							"max_attempts":  20,
+							"base_delay":    10,
+							"max_delay":     600,
+							"jitter":        0.5,
+							"give_up_after": 86400,
+						},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = ioutil.NopCloser(bytes.NewBuffer(requestBody))
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf(models.Kind{
+				ID:          "perimeter_breach",
+				Description: "Perimeter Breach",
+				Critical:    true,
+				ClientID:    client.ID,
+				RetryPolicy: models.RetryPolicy{
+					MaxAttempts: 20,
+					BaseDelay:   10,
+					MaxDelay:    600,
+					Jitter:      0.5,
+					GiveUpAfter: 86400,
+				},
+				HasRetryPolicy: true,
+			}))
+		})
+
+		It("resets the retry policy of a notification given an empty retry policy", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"source_name": "Raptor Containment Unit",
+				"notifications": map[string]interface{}{
+					"perimeter_breach": map[string]interface{}{
+						"description":  "Perimeter Breach",
+						"retry_policy": map[string]interface{}{},
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = ioutil.NopCloser(bytes.NewBuffer(requestBody))
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf(models.Kind{
+				ID:             "perimeter_breach",
+				Description:    "Perimeter Breach",
+				ClientID:       client.ID,
+				TemplateID:     models.DoNotSetTemplateID,
+				HasRetryPolicy: true,
+			}))
+		})
+
+		It("keeps the retry policy of a notification that is registered without one", func() {
+			requestBody, err := json.Marshal(map[string]interface{}{
+				"source_name": "Raptor Containment Unit",
+				"notifications": map[string]interface{}{
+					"perimeter_breach": map[string]interface{}{
+						"description": "Perimeter Breach",
+					},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			request.Body = ioutil.NopCloser(bytes.NewBuffer(requestBody))
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf(models.Kind{
+				ID:          "perimeter_breach",
+				Description: "Perimeter Breach",
+				ClientID:    client.ID,
+				TemplateID:  models.DoNotSetTemplateID,
+			}))
+		})
+
 		Context("failure cases", func() {
 			It("rejects entire request and returns 404 error if notification is critical without scope", func() {
 				requestBody, err := json.Marshal(map[string]interface{}{
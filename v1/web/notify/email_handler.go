@@ -16,6 +16,10 @@ type notifyExecutor interface {
 	Execute(conn ConnectionInterface, req *http.Request, context stack.Context, guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string) (response []byte, err error)
 }
 
+type fanOutExecutor interface {
+	ExecuteFanOut(conn ConnectionInterface, req *http.Request, context stack.Context, guid string, strategy FanOutDispatcher, validator ValidatorInterface, vcapRequestID string) (response []byte, err error)
+}
+
 type errorWriter interface {
 	Write(writer http.ResponseWriter, err error)
 }
@@ -24,6 +28,10 @@ type Dispatcher interface {
 	Dispatch(dispatch services.Dispatch) ([]services.Response, error)
 }
 
+type FanOutDispatcher interface {
+	Dispatch(dispatch services.Dispatch) (services.SendResponse, error)
+}
+
 type EmailHandler struct {
 	errorWriter errorWriter
 	notify      notifyExecutor
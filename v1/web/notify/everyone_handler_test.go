@@ -24,14 +24,14 @@ var _ = Describe("EveryoneHandler", func() {
 			notifyObj   *mocks.Notify
 			context     stack.Context
 			connection  *mocks.Connection
-			strategy    *mocks.Strategy
+			strategy    *mocks.FanOutStrategy
 		)
 
 		BeforeEach(func() {
 			errorWriter = mocks.NewErrorWriter()
 			writer = httptest.NewRecorder()
 			request = &http.Request{}
-			strategy = mocks.NewStrategy()
+			strategy = mocks.NewFanOutStrategy()
 
 			connection = mocks.NewConnection()
 			database := mocks.NewDatabase()
@@ -45,35 +45,35 @@ var _ = Describe("EveryoneHandler", func() {
 			handler = notify.NewEveryoneHandler(notifyObj, errorWriter, strategy)
 		})
 
-		Context("when notifyObj.Execute returns a successful response", func() {
+		Context("when notifyObj.ExecuteFanOut returns a successful response", func() {
 			It("returns the JSON representation of the response", func() {
-				notifyObj.ExecuteCall.Returns.Response = []byte("hello")
+				notifyObj.ExecuteFanOutCall.Returns.Response = []byte("hello")
 
 				handler.ServeHTTP(writer, request, context)
 
-				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Code).To(Equal(http.StatusAccepted))
 				Expect(writer.Body.String()).To(Equal("hello"))
 			})
 
 			It("delegates to the notifyObj object with the correct arguments", func() {
 				handler.ServeHTTP(writer, request, context)
 
-				Expect(reflect.ValueOf(notifyObj.ExecuteCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
-				Expect(notifyObj.ExecuteCall.Receives.Request).To(Equal(request))
-				Expect(notifyObj.ExecuteCall.Receives.Context).To(Equal(context))
-				Expect(notifyObj.ExecuteCall.Receives.GUID).To(Equal(""))
-				Expect(notifyObj.ExecuteCall.Receives.Strategy).To(Equal(strategy))
-				Expect(notifyObj.ExecuteCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
-				Expect(notifyObj.ExecuteCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+				Expect(reflect.ValueOf(notifyObj.ExecuteFanOutCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Request).To(Equal(request))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Context).To(Equal(context))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.GUID).To(Equal(""))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Strategy).To(Equal(strategy))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
 			})
 		})
 
-		Context("when notifyObj.Execute returns an error", func() {
+		Context("when notifyObj.ExecuteFanOut returns an error", func() {
 			It("propagates the error", func() {
-				notifyObj.ExecuteCall.Returns.Error = errors.New("BOOM!")
+				notifyObj.ExecuteFanOutCall.Returns.Error = errors.New("BOOM!")
 
 				handler.ServeHTTP(writer, request, context)
-				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteCall.Returns.Error))
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteFanOutCall.Returns.Error))
 			})
 		})
 	})
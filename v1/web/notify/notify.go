@@ -1,20 +1,28 @@
 package notify
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/pivotal-golang/lager"
 	"github.com/ryanmoran/stack"
 )
 
+// IdempotencyKeyHeader names the header that makes retrying a notify request
+// safe; see Notify.idempotent.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 type clientAndKindFinder interface {
 	ClientAndKind(database services.DatabaseInterface, clientID, kindID string) (models.Client, models.Kind, error)
 }
@@ -24,15 +32,25 @@ type registrar interface {
 	Prune(services.ConnectionInterface, models.Client, []models.Kind) error
 }
 
+type idempotencyKeeper interface {
+	Reserve(conn services.ConnectionInterface, clientID, key, path string, body []byte) ([]byte, bool, error)
+	Complete(conn services.ConnectionInterface, clientID, key string, response []byte) error
+	Release(conn services.ConnectionInterface, clientID, key string) error
+}
+
 type Notify struct {
-	finder    clientAndKindFinder
-	registrar registrar
+	finder            clientAndKindFinder
+	registrar         registrar
+	idempotencyKeeper idempotencyKeeper
+	maxSendAtHorizon  time.Duration
 }
 
-func NewNotify(finder clientAndKindFinder, registrar registrar) Notify {
+func NewNotify(finder clientAndKindFinder, registrar registrar, idempotencyKeeper idempotencyKeeper, maxSendAtHorizon time.Duration) Notify {
 	return Notify{
-		finder:    finder,
-		registrar: registrar,
+		finder:            finder,
+		registrar:         registrar,
+		idempotencyKeeper: idempotencyKeeper,
+		maxSendAtHorizon:  maxSendAtHorizon,
 	}
 }
 
@@ -43,45 +61,150 @@ type ValidatorInterface interface {
 func (h Notify) Execute(connection ConnectionInterface, req *http.Request, context stack.Context,
 	guid string, strategy Dispatcher, validator ValidatorInterface, vcapRequestID string) ([]byte, error) {
 
-	parameters, err := NewNotifyParams(req.Body)
+	return h.idempotent(connection, req, context, func() ([]byte, error) {
+		dispatch, err := h.dispatch(connection, req, context, guid, validator, vcapRequestID)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		responses, err := strategy.Dispatch(dispatch)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		output, err := json.Marshal(responses)
+		if err != nil {
+			panic(err)
+		}
+
+		return output, nil
+	})
+}
+
+// ExecuteFanOut accepts a send whose recipients are resolved in the
+// background and responds with the ID of the send.
+func (h Notify) ExecuteFanOut(connection ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, strategy FanOutDispatcher, validator ValidatorInterface, vcapRequestID string) ([]byte, error) {
+
+	return h.idempotent(connection, req, context, func() ([]byte, error) {
+		dispatch, err := h.dispatch(connection, req, context, guid, validator, vcapRequestID)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		response, err := strategy.Dispatch(dispatch)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		output, err := json.Marshal(response)
+		if err != nil {
+			panic(err)
+		}
+
+		return output, nil
+	})
+}
+
+// idempotent runs the request once per Idempotency-Key header. A retry of a
+// request with the same key and body gets the original response without
+// anything being sent again.
+func (h Notify) idempotent(connection ConnectionInterface, req *http.Request, context stack.Context, execute func() ([]byte, error)) ([]byte, error) {
+	key := req.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return execute()
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return []byte{}, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	output, found, err := h.idempotencyKeeper.Reserve(connection, clientID, key, req.URL.Path, body)
 	if err != nil {
 		return []byte{}, err
 	}
 
-	if !validator.Validate(&parameters) {
-		return []byte{}, webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
+	if found {
+		return output, nil
+	}
+
+	logger := context.Get("logger").(lager.Logger).WithData(lager.Data{
+		"idempotency_key": key,
+	})
+
+	output, err = execute()
+	if err != nil {
+		if releaseErr := h.idempotencyKeeper.Release(connection, clientID, key); releaseErr != nil {
+			logger.Error("failed-idempotency-key-release", releaseErr)
+		}
+		return []byte{}, err
+	}
+
+	// The notification has been accepted at this point, so a failure to store
+	// the response must not fail the request. Retries are then rejected as
+	// still being processed until the key expires.
+	if err := h.idempotencyKeeper.Complete(connection, clientID, key, output); err != nil {
+		logger.Error("failed-idempotency-key-completion", err)
+	}
+
+	return output, nil
+}
+
+func (h Notify) dispatch(connection ConnectionInterface, req *http.Request, context stack.Context,
+	guid string, validator ValidatorInterface, vcapRequestID string) (services.Dispatch, error) {
+
+	parameters, err := NewNotifyParams(req.Body)
+	if err != nil {
+		return services.Dispatch{}, err
 	}
 
 	requestReceivedTime, ok := context.Get(RequestReceivedTime).(time.Time)
 	if !ok {
 		panic("programmer error: missing RequestReceivedTime in http context")
 	}
+
+	valid := validator.Validate(&parameters)
+	sendAtValidator := SendAtValidator{Now: requestReceivedTime, MaxHorizon: h.maxSendAtHorizon}
+	validSendAt := sendAtValidator.Validate(&parameters)
+	validPriority := PriorityValidator{}.Validate(&parameters)
+	validLocale := LocaleValidator{}.Validate(&parameters)
+	if !valid || !validSendAt || !validPriority || !validLocale {
+		return services.Dispatch{}, webutil.ValidationError{Err: errors.New(strings.Join(parameters.Errors, ","))}
+	}
 	token := context.Get("token").(*jwt.Token) // TODO: (rm) get rid of the context object, just pass in the token
 	clientID := token.Claims["client_id"].(string)
 
 	tokenIssuerURL, err := url.Parse(token.Claims["iss"].(string))
 	if err != nil {
-		return []byte{}, errors.New("Token issuer URL invalid")
+		return services.Dispatch{}, errors.New("Token issuer URL invalid")
 	}
 	uaaHost := tokenIssuerURL.Scheme + "://" + tokenIssuerURL.Host
 
 	client, kind, err := h.finder.ClientAndKind(context.Get("database").(DatabaseInterface), clientID, parameters.KindID)
 	if err != nil {
-		return []byte{}, err
+		return services.Dispatch{}, err
 	}
 
 	if kind.Critical && !h.hasCriticalNotificationsWriteScope(token.Claims["scope"]) {
-		return []byte{}, webutil.NewCriticalNotificationError(kind.ID)
+		return services.Dispatch{}, webutil.NewCriticalNotificationError(kind.ID)
 	}
 
 	err = h.registrar.Register(connection, client, []models.Kind{kind})
 	if err != nil {
-		return []byte{}, err
+		return services.Dispatch{}, err
 	}
 
-	var responses []services.Response
+	priority := parameters.Priority
+	if kind.Critical {
+		priority = gobble.PriorityHigh
+	}
 
-	responses, err = strategy.Dispatch(services.Dispatch{
+	return services.Dispatch{
 		GUID:       guid,
 		Connection: connection,
 		Role:       parameters.Role,
@@ -93,7 +216,10 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 			ID:          parameters.KindID,
 			Description: kind.Description,
 		},
-		UAAHost: uaaHost,
+		UAAHost:  uaaHost,
+		SendAt:   parameters.ParsedSendAt,
+		Priority: priority,
+		Locale:   parameters.Locale,
 		VCAPRequest: services.DispatchVCAPRequest{
 			ID:          vcapRequestID,
 			ReceiptTime: requestReceivedTime,
@@ -110,17 +236,7 @@ func (h Notify) Execute(connection ConnectionInterface, req *http.Request, conte
 				Doctype:        parameters.ParsedHTML.Doctype,
 			},
 		},
-	})
-	if err != nil {
-		return []byte{}, err
-	}
-
-	output, err := json.Marshal(responses)
-	if err != nil {
-		panic(err)
-	}
-
-	return output, nil
+	}, nil
 }
 
 func (h Notify) hasCriticalNotificationsWriteScope(elements interface{}) bool {
@@ -6,6 +6,7 @@ import (
 	"io"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
@@ -15,19 +16,24 @@ const InvalidEmail = "<>InvalidEmail<>"
 
 var (
 	validOrganizationRoles = []string{"OrgManager", "OrgAuditor", "BillingManager"}
+	validSpaceRoles        = []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor"}
 	emailRegexp            = regexp.MustCompile("[^<]*<([^@]*@[^@]*)>|([^<][^@]*@[^@]*)")
 )
 
 type NotifyParams struct {
-	ReplyTo string `json:"reply_to"`
-	Subject string `json:"subject"`
-	Text    string `json:"text"`
-	RawHTML string `json:"html"`
-	KindID  string `json:"kind_id"`
-	To      string `json:"to"`
-	Role    string `json:"role"`
+	ReplyTo  string `json:"reply_to"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	RawHTML  string `json:"html"`
+	KindID   string `json:"kind_id"`
+	To       string `json:"to"`
+	Role     string `json:"role"`
+	SendAt   string `json:"send_at"`
+	Priority int    `json:"priority"`
+	Locale   string `json:"locale"`
 
 	ParsedHTML        HTML
+	ParsedSendAt      time.Time
 	KindDescription   string
 	SourceDescription string
 	Errors            []string
@@ -1,6 +1,14 @@
 package notify
 
-import "regexp"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+)
 
 var kindIDFormat = regexp.MustCompile(`^[0-9a-zA-Z_\-.]+$`)
 
@@ -24,7 +32,12 @@ func (validator EmailValidator) Validate(notify *NotifyParams) bool {
 	return len(notify.Errors) == 0
 }
 
-type GUIDValidator struct{}
+// GUIDValidator checks the params of a send to a user, space, organization,
+// scope or everyone. Roles lists the values accepted for the "role" field and
+// defaults to the organization roles.
+type GUIDValidator struct {
+	Roles []string
+}
 
 func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 	notify.Errors = []string{}
@@ -36,7 +49,7 @@ func (validator GUIDValidator) Validate(notify *NotifyParams) bool {
 	}
 
 	if validator.invalidRoleField(notify.Role) {
-		notify.Errors = append(notify.Errors, `"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`)
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"role" must be "%s" or unset`, strings.Join(validator.roles(), `", "`)))
 	}
 
 	return len(notify.Errors) == 0
@@ -51,7 +64,7 @@ func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 		return false
 	}
 
-	for _, role := range validOrganizationRoles {
+	for _, role := range validator.roles() {
 		if roleName == role {
 			return false
 		}
@@ -59,6 +72,14 @@ func (validator GUIDValidator) invalidRoleField(roleName string) bool {
 	return true
 }
 
+func (validator GUIDValidator) roles() []string {
+	if validator.Roles == nil {
+		return validOrganizationRoles
+	}
+
+	return validator.Roles
+}
+
 func (validator GUIDValidator) checkKindIDField(notify *NotifyParams) {
 	if notify.KindID == "" {
 		notify.Errors = append(notify.Errors, `"kind_id" is a required field`)
@@ -68,3 +89,68 @@ func (validator GUIDValidator) checkKindIDField(notify *NotifyParams) {
 		}
 	}
 }
+
+// SendAtValidator checks the optional "send_at" field and parses it into
+// ParsedSendAt. Unlike the other validators it adds to the errors already on
+// the params, so it runs after them. A zero MaxHorizon removes the limit on
+// how far ahead a send can be scheduled.
+type SendAtValidator struct {
+	Now        time.Time
+	MaxHorizon time.Duration
+}
+
+func (validator SendAtValidator) Validate(notify *NotifyParams) bool {
+	if notify.SendAt == "" {
+		return true
+	}
+
+	sendAt, err := time.Parse(time.RFC3339, notify.SendAt)
+	if err != nil {
+		notify.Errors = append(notify.Errors, `"send_at" must be an RFC 3339 timestamp`)
+		return false
+	}
+
+	if validator.MaxHorizon > 0 && sendAt.Sub(validator.Now) > validator.MaxHorizon {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"send_at" must not be more than %d hours in the future`, int(validator.MaxHorizon.Hours())))
+		return false
+	}
+
+	notify.ParsedSendAt = sendAt.UTC()
+	return true
+}
+
+// PriorityValidator checks the optional "priority" field. Clients may ask
+// for any priority below the one reserved for critical notifications. Like
+// SendAtValidator it adds to the errors already on the params.
+type PriorityValidator struct{}
+
+func (validator PriorityValidator) Validate(notify *NotifyParams) bool {
+	if notify.Priority == 0 {
+		return true
+	}
+
+	if notify.Priority < gobble.PriorityLow || notify.Priority >= gobble.PriorityHigh {
+		notify.Errors = append(notify.Errors, fmt.Sprintf(`"priority" must be between %d and %d`, gobble.PriorityLow, gobble.PriorityHigh-1))
+		return false
+	}
+
+	return true
+}
+
+// LocaleValidator checks the optional "locale" field, which picks the locale
+// variant of the template over the locale of each recipient. Like
+// SendAtValidator it adds to the errors already on the params.
+type LocaleValidator struct{}
+
+func (validator LocaleValidator) Validate(notify *NotifyParams) bool {
+	if notify.Locale == "" {
+		return true
+	}
+
+	if !models.ValidLocale(notify.Locale) {
+		notify.Errors = append(notify.Errors, `"locale" must be a language tag like "de" or "pt-BR"`)
+		return false
+	}
+
+	return true
+}
@@ -1,6 +1,8 @@
 package notify_test
 
 import (
+	"time"
+
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 
 	. "github.com/onsi/ginkgo"
@@ -124,6 +126,149 @@ var _ = Describe("Validator", func() {
 				Expect(len(params.Errors)).To(Equal(1))
 				Expect(params.Errors).To(ContainElement(`"role" must be "OrgManager", "OrgAuditor", "BillingManager" or unset`))
 			})
+
+			It("validates the role against the roles it was given", func() {
+				validator = notify.GUIDValidator{Roles: []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor"}}
+
+				for _, role := range []string{"SpaceDeveloper", "SpaceManager", "SpaceAuditor", ""} {
+					params.Role = role
+					Expect(validator.Validate(params)).To(BeTrue())
+					Expect(len(params.Errors)).To(Equal(0))
+				}
+
+				params.Role = "OrgManager"
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(len(params.Errors)).To(Equal(1))
+				Expect(params.Errors).To(ContainElement(`"role" must be "SpaceDeveloper", "SpaceManager", "SpaceAuditor" or unset`))
+			})
+		})
+	})
+
+	Describe("SendAtValidator", func() {
+		var (
+			params    *notify.NotifyParams
+			validator notify.SendAtValidator
+			now       time.Time
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2015, time.June, 8, 14, 0, 0, 0, time.UTC)
+			params = &notify.NotifyParams{
+				Errors: []string{"some other error"},
+			}
+			validator = notify.SendAtValidator{
+				Now:        now,
+				MaxHorizon: 720 * time.Hour,
+			}
+		})
+
+		Describe("Validate", func() {
+			It("accepts a missing send_at", func() {
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(params.ParsedSendAt.IsZero()).To(BeTrue())
+				Expect(params.Errors).To(Equal([]string{"some other error"}))
+			})
+
+			It("parses an RFC 3339 send_at into UTC", func() {
+				params.SendAt = "2015-06-09T02:00:00-07:00"
+
+				Expect(validator.Validate(params)).To(BeTrue())
+				Expect(params.ParsedSendAt).To(Equal(time.Date(2015, time.June, 9, 9, 0, 0, 0, time.UTC)))
+			})
+
+			It("accepts a send_at in the past", func() {
+				params.SendAt = "2015-06-01T00:00:00Z"
+
+				Expect(validator.Validate(params)).To(BeTrue())
+			})
+
+			It("adds an error when send_at is not an RFC 3339 timestamp", func() {
+				params.SendAt = "tomorrow at 2am"
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(Equal([]string{
+					"some other error",
+					`"send_at" must be an RFC 3339 timestamp`,
+				}))
+			})
+
+			It("adds an error when send_at is beyond the maximum horizon", func() {
+				params.SendAt = now.Add(721 * time.Hour).Format(time.RFC3339)
+
+				Expect(validator.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(ContainElement(`"send_at" must not be more than 720 hours in the future`))
+			})
+
+			It("has no horizon when the maximum horizon is zero", func() {
+				validator.MaxHorizon = 0
+				params.SendAt = now.Add(10000 * time.Hour).Format(time.RFC3339)
+
+				Expect(validator.Validate(params)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("PriorityValidator", func() {
+		var params *notify.NotifyParams
+
+		BeforeEach(func() {
+			params = &notify.NotifyParams{
+				Errors: []string{"some other error"},
+			}
+		})
+
+		Describe("Validate", func() {
+			It("accepts a missing priority", func() {
+				Expect(notify.PriorityValidator{}.Validate(params)).To(BeTrue())
+				Expect(params.Errors).To(Equal([]string{"some other error"}))
+			})
+
+			It("accepts a priority below the one for critical notifications", func() {
+				params.Priority = 9
+
+				Expect(notify.PriorityValidator{}.Validate(params)).To(BeTrue())
+			})
+
+			It("adds an error when the priority is out of range", func() {
+				for _, priority := range []int{-1, 10, 11} {
+					params.Priority = priority
+
+					Expect(notify.PriorityValidator{}.Validate(params)).To(BeFalse())
+					Expect(params.Errors).To(ContainElement(`"priority" must be between 1 and 9`))
+				}
+			})
+		})
+	})
+
+	Describe("LocaleValidator", func() {
+		var params *notify.NotifyParams
+
+		BeforeEach(func() {
+			params = &notify.NotifyParams{
+				Errors: []string{"some other error"},
+			}
+		})
+
+		Describe("Validate", func() {
+			It("accepts a missing locale", func() {
+				Expect(notify.LocaleValidator{}.Validate(params)).To(BeTrue())
+				Expect(params.Errors).To(Equal([]string{"some other error"}))
+			})
+
+			It("accepts a language tag", func() {
+				for _, locale := range []string{"de", "pt-BR", "zh_Hant_TW"} {
+					params.Locale = locale
+
+					Expect(notify.LocaleValidator{}.Validate(params)).To(BeTrue())
+				}
+			})
+
+			It("adds an error when the locale is not a language tag", func() {
+				params.Locale = "klingon!"
+
+				Expect(notify.LocaleValidator{}.Validate(params)).To(BeFalse())
+				Expect(params.Errors).To(Equal([]string{"some other error", `"locale" must be a language tag like "de" or "pt-BR"`}))
+			})
 		})
 	})
 })
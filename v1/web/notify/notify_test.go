@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
 	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -15,6 +16,7 @@ import (
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/pivotal-golang/lager"
 	"github.com/ryanmoran/stack"
 
 	. "github.com/onsi/ginkgo"
@@ -29,6 +31,8 @@ var _ = Describe("Notify", func() {
 				finder          *mocks.NotificationsFinder
 				validator       *mocks.Validator
 				registrar       *mocks.Registrar
+				keeper          *mocks.IdempotencyKeeper
+				body            []byte
 				request         *http.Request
 				rawToken        string
 				client          models.Client
@@ -41,6 +45,7 @@ var _ = Describe("Notify", func() {
 				vcapRequestID   string
 				database        *mocks.Database
 				reqReceivedTime time.Time
+				logWriter       *bytes.Buffer
 			)
 
 			BeforeEach(func() {
@@ -59,8 +64,10 @@ var _ = Describe("Notify", func() {
 				finder.ClientAndKindCall.Returns.Kind = kind
 
 				registrar = mocks.NewRegistrar()
+				keeper = mocks.NewIdempotencyKeeper()
 
-				body, err := json.Marshal(map[string]string{
+				var err error
+				body, err = json.Marshal(map[string]string{
 					"kind_id":  "test_email",
 					"text":     "This is the plain text body of the email",
 					"html":     "<!DOCTYPE html><html><head><script type='javascript'></script></head><body class='hello'><p>This is the HTML Body of the email</p><body></html>",
@@ -101,6 +108,11 @@ var _ = Describe("Notify", func() {
 				context.Set("database", database)
 				context.Set(notify.RequestReceivedTime, reqReceivedTime)
 
+				logWriter = &bytes.Buffer{}
+				logger := lager.NewLogger("notifications")
+				logger.RegisterSink(lager.NewWriterSink(logWriter, lager.DEBUG))
+				context.Set("logger", logger)
+
 				vcapRequestID = "some-request-id"
 
 				conn = mocks.NewConnection()
@@ -108,7 +120,7 @@ var _ = Describe("Notify", func() {
 				validator = mocks.NewValidator()
 				validator.ValidateCall.Returns.Valid = true
 
-				handler = notify.NewNotify(finder, registrar)
+				handler = notify.NewNotify(finder, registrar, keeper, 720*time.Hour)
 			})
 
 			It("delegates to the strategy", func() {
@@ -127,7 +139,8 @@ var _ = Describe("Notify", func() {
 						ID:          "test_email",
 						Description: "Instance Down",
 					},
-					UAAHost: "http://zone-uaa-host",
+					UAAHost:  "http://zone-uaa-host",
+					Priority: gobble.PriorityHigh,
 					VCAPRequest: services.DispatchVCAPRequest{
 						ID:          "some-request-id",
 						ReceiptTime: reqReceivedTime,
@@ -159,6 +172,207 @@ var _ = Describe("Notify", func() {
 				Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf([]models.Kind{kind}))
 			})
 
+			It("schedules the send when send_at is given", func() {
+				body, err := json.Marshal(map[string]string{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"send_at": "2015-06-09T02:00:00-07:00",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.SendAt).To(Equal(time.Date(2015, time.June, 9, 9, 0, 0, 0, time.UTC)))
+			})
+
+			It("dispatches with the locale that is given", func() {
+				body, err := json.Marshal(map[string]string{
+					"kind_id": "test_email",
+					"text":    "This is the plain text body of the email",
+					"locale":  "de-DE",
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(strategy.DispatchCalls[0].Receives.Dispatch.Locale).To(Equal("de-DE"))
+			})
+
+			Context("when the kind is not critical", func() {
+				BeforeEach(func() {
+					kind.Critical = false
+					finder.ClientAndKindCall.Returns.Kind = kind
+				})
+
+				It("dispatches with the priority that is given", func() {
+					body, err := json.Marshal(map[string]interface{}{
+						"kind_id":  "test_email",
+						"text":     "This is the plain text body of the email",
+						"priority": 2,
+					})
+					Expect(err).NotTo(HaveOccurred())
+
+					request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(strategy.DispatchCalls[0].Receives.Dispatch.Priority).To(Equal(2))
+				})
+
+				It("leaves the priority to the queue when none is given", func() {
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(strategy.DispatchCalls[0].Receives.Dispatch.Priority).To(Equal(0))
+				})
+			})
+
+			Context("when the strategy fans out to its recipients in the background", func() {
+				var fanOutStrategy *mocks.FanOutStrategy
+
+				BeforeEach(func() {
+					fanOutStrategy = mocks.NewFanOutStrategy()
+					fanOutStrategy.DispatchCall.Returns.Response = services.SendResponse{
+						SendID:        "some-send-id",
+						Status:        "queued",
+						VCAPRequestID: "some-request-id",
+					}
+				})
+
+				It("responds with the send", func() {
+					output, err := handler.ExecuteFanOut(conn, request, context, "space-001", fanOutStrategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(output).To(MatchJSON(`{
+						"send_id": "some-send-id",
+						"status": "queued",
+						"vcap_request_id": "some-request-id"
+					}`))
+
+					Expect(fanOutStrategy.DispatchCall.Receives.Dispatch.GUID).To(Equal("space-001"))
+					Expect(fanOutStrategy.DispatchCall.Receives.Dispatch.Connection).To(Equal(conn))
+					Expect(fanOutStrategy.DispatchCall.Receives.Dispatch.Client.ID).To(Equal("mister-client"))
+					Expect(fanOutStrategy.DispatchCall.Receives.Dispatch.Kind.ID).To(Equal("test_email"))
+					Expect(registrar.RegisterCall.Receives.Kinds).To(ConsistOf([]models.Kind{kind}))
+				})
+
+				It("returns the error of the strategy", func() {
+					fanOutStrategy.DispatchCall.Returns.Error = errors.New("BOOM!")
+
+					_, err := handler.ExecuteFanOut(conn, request, context, "space-001", fanOutStrategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+				})
+			})
+
+			Context("when the request has an Idempotency-Key", func() {
+				BeforeEach(func() {
+					request.Header.Set("Idempotency-Key", "some-key")
+				})
+
+				It("reserves the key for the client and stores the response", func() {
+					strategy.DispatchCalls = append(strategy.DispatchCalls, mocks.NewStrategyDispatchCall([]services.Response{
+						{Status: "queued", NotificationID: "some-message-id"},
+					}, nil))
+
+					output, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(strategy.DispatchCallsCount).To(Equal(1))
+
+					Expect(keeper.ReserveCall.Receives.Connection).To(Equal(conn))
+					Expect(keeper.ReserveCall.Receives.ClientID).To(Equal("mister-client"))
+					Expect(keeper.ReserveCall.Receives.Key).To(Equal("some-key"))
+					Expect(keeper.ReserveCall.Receives.Path).To(Equal("/spaces/space-001"))
+					Expect(keeper.ReserveCall.Receives.Body).To(Equal(body))
+
+					Expect(keeper.CompleteCall.Receives.ClientID).To(Equal("mister-client"))
+					Expect(keeper.CompleteCall.Receives.Key).To(Equal("some-key"))
+					Expect(keeper.CompleteCall.Receives.Response).To(Equal(output))
+				})
+
+				It("returns the original response without sending anything when the request is repeated", func() {
+					keeper.ReserveCall.Returns.Response = []byte(`[{"status":"queued"}]`)
+					keeper.ReserveCall.Returns.Found = true
+
+					output, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(output).To(MatchJSON(`[{"status":"queued"}]`))
+
+					Expect(strategy.DispatchCallsCount).To(Equal(0))
+					Expect(registrar.RegisterCall.Receives.Kinds).To(BeEmpty())
+					Expect(keeper.CompleteCall.WasCalled).To(BeFalse())
+				})
+
+				It("returns the original response of a send that fans out", func() {
+					fanOutStrategy := mocks.NewFanOutStrategy()
+					keeper.ReserveCall.Returns.Response = []byte(`{"send_id":"some-send-id"}`)
+					keeper.ReserveCall.Returns.Found = true
+
+					output, err := handler.ExecuteFanOut(conn, request, context, "space-001", fanOutStrategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(output).To(MatchJSON(`{"send_id":"some-send-id"}`))
+					Expect(fanOutStrategy.DispatchCall.Receives.Dispatch).To(Equal(services.Dispatch{}))
+				})
+
+				It("returns the error when the key was used for a different request", func() {
+					keeper.ReserveCall.Returns.Error = services.IdempotencyKeyConflictError{Message: "key was already used"}
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(MatchError(services.IdempotencyKeyConflictError{Message: "key was already used"}))
+					Expect(strategy.DispatchCallsCount).To(Equal(0))
+				})
+
+				It("releases the key when the request fails", func() {
+					strategy.DispatchCalls = append(strategy.DispatchCalls, mocks.NewStrategyDispatchCall([]services.Response{}, errors.New("BOOM!")))
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+
+					Expect(keeper.ReleaseCall.Receives.ClientID).To(Equal("mister-client"))
+					Expect(keeper.ReleaseCall.Receives.Key).To(Equal("some-key"))
+					Expect(keeper.CompleteCall.WasCalled).To(BeFalse())
+				})
+
+				It("logs a failure to release the key", func() {
+					strategy.DispatchCalls = append(strategy.DispatchCalls, mocks.NewStrategyDispatchCall([]services.Response{}, errors.New("BOOM!")))
+					keeper.ReleaseCall.Returns.Error = errors.New("release failed")
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).To(Equal(errors.New("BOOM!")))
+
+					Expect(logWriter.String()).To(ContainSubstring("notifications.failed-idempotency-key-release"))
+					Expect(logWriter.String()).To(ContainSubstring("release failed"))
+					Expect(logWriter.String()).To(ContainSubstring(`"idempotency_key":"some-key"`))
+				})
+
+				It("logs a failure to store the response without failing the request", func() {
+					strategy.DispatchCalls = append(strategy.DispatchCalls, mocks.NewStrategyDispatchCall([]services.Response{
+						{Status: "queued", NotificationID: "some-message-id"},
+					}, nil))
+					keeper.CompleteCall.Returns.Error = errors.New("complete failed")
+
+					_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(logWriter.String()).To(ContainSubstring("notifications.failed-idempotency-key-completion"))
+					Expect(logWriter.String()).To(ContainSubstring("complete failed"))
+				})
+			})
+
+			It("does not track requests without an Idempotency-Key", func() {
+				_, err := handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(keeper.ReserveCall.WasCalled).To(BeFalse())
+			})
+
 			Context("failure cases", func() {
 				Context("when validating params", func() {
 					It("returns a error response when params are missing", func() {
@@ -182,6 +396,54 @@ var _ = Describe("Notify", func() {
 						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("boom")}))
 					})
 
+					It("returns a error response when send_at is too far in the future", func() {
+						body, err := json.Marshal(map[string]string{
+							"kind_id": "test_email",
+							"text":    "This is the plain text body of the email",
+							"send_at": "2016-06-08T14:32:11-07:00",
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+						Expect(err).NotTo(HaveOccurred())
+
+						_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"send_at" must not be more than 720 hours in the future`)}))
+						Expect(strategy.DispatchCallsCount).To(Equal(0))
+					})
+
+					It("returns a error response when the priority is out of range", func() {
+						body, err := json.Marshal(map[string]interface{}{
+							"kind_id":  "test_email",
+							"text":     "This is the plain text body of the email",
+							"priority": 10,
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+						Expect(err).NotTo(HaveOccurred())
+
+						_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"priority" must be between 1 and 9`)}))
+						Expect(strategy.DispatchCallsCount).To(Equal(0))
+					})
+
+					It("returns a error response when the locale is not a language tag", func() {
+						body, err := json.Marshal(map[string]interface{}{
+							"kind_id": "test_email",
+							"text":    "This is the plain text body of the email",
+							"locale":  "klingon!",
+						})
+						Expect(err).NotTo(HaveOccurred())
+
+						request, err = http.NewRequest("POST", "/spaces/space-001", bytes.NewBuffer(body))
+						Expect(err).NotTo(HaveOccurred())
+
+						_, err = handler.Execute(conn, request, context, "space-001", strategy, validator, vcapRequestID)
+						Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`"locale" must be a language tag like "de" or "pt-BR"`)}))
+						Expect(strategy.DispatchCallsCount).To(Equal(0))
+					})
+
 					It("returns a error response when params cannot be parsed", func() {
 						request, err := http.NewRequest("POST", "/spaces/space-001", strings.NewReader("this is not JSON"))
 						Expect(err).NotTo(HaveOccurred())
@@ -6,6 +6,11 @@ type muxer interface {
 	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
 }
 
+type executor interface {
+	notifyExecutor
+	fanOutExecutor
+}
+
 type Routes struct {
 	RequestCounter                  stack.Middleware
 	RequestLogging                  stack.Middleware
@@ -13,13 +18,13 @@ type Routes struct {
 	NotificationsWriteAuthenticator stack.Middleware
 	EmailsWriteAuthenticator        stack.Middleware
 
-	Notify               notifyExecutor
+	Notify               executor
 	ErrorWriter          errorWriter
 	UserStrategy         Dispatcher
-	SpaceStrategy        Dispatcher
-	OrganizationStrategy Dispatcher
-	EveryoneStrategy     Dispatcher
-	UAAScopeStrategy     Dispatcher
+	SpaceStrategy        FanOutDispatcher
+	OrganizationStrategy FanOutDispatcher
+	EveryoneStrategy     FanOutDispatcher
+	UAAScopeStrategy     FanOutDispatcher
 	EmailStrategy        Dispatcher
 }
 
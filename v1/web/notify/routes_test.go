@@ -23,10 +23,10 @@ var _ = Describe("Routes", func() {
 			Notify:               mocks.NewNotify(),
 			ErrorWriter:          mocks.NewErrorWriter(),
 			UserStrategy:         mocks.NewStrategy(),
-			SpaceStrategy:        mocks.NewStrategy(),
-			OrganizationStrategy: mocks.NewStrategy(),
-			EveryoneStrategy:     mocks.NewStrategy(),
-			UAAScopeStrategy:     mocks.NewStrategy(),
+			SpaceStrategy:        mocks.NewFanOutStrategy(),
+			OrganizationStrategy: mocks.NewFanOutStrategy(),
+			EveryoneStrategy:     mocks.NewFanOutStrategy(),
+			UAAScopeStrategy:     mocks.NewFanOutStrategy(),
 			EmailStrategy:        mocks.NewStrategy(),
 
 			RequestCounter:                  middleware.RequestCounter{},
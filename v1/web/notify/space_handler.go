@@ -9,11 +9,11 @@ import (
 
 type SpaceHandler struct {
 	errorWriter errorWriter
-	notify      notifyExecutor
-	strategy    Dispatcher
+	notify      fanOutExecutor
+	strategy    FanOutDispatcher
 }
 
-func NewSpaceHandler(notify notifyExecutor, errWriter errorWriter, strategy Dispatcher) SpaceHandler {
+func NewSpaceHandler(notify fanOutExecutor, errWriter errorWriter, strategy FanOutDispatcher) SpaceHandler {
 	return SpaceHandler{
 		errorWriter: errWriter,
 		notify:      notify,
@@ -26,12 +26,12 @@ func (h SpaceHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, contex
 	spaceGUID := strings.TrimPrefix(req.URL.Path, "/spaces/")
 	vcapRequestID := context.Get(VCAPRequestIDKey).(string)
 
-	output, err := h.notify.Execute(conn, req, context, spaceGUID, h.strategy, GUIDValidator{}, vcapRequestID)
+	output, err := h.notify.ExecuteFanOut(conn, req, context, spaceGUID, h.strategy, GUIDValidator{Roles: validSpaceRoles}, vcapRequestID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(http.StatusAccepted)
 	w.Write(output)
 }
@@ -25,13 +25,13 @@ var _ = Describe("UAAScopeHandler", func() {
 			context     stack.Context
 			connection  *mocks.Connection
 			errorWriter *mocks.ErrorWriter
-			strategy    *mocks.Strategy
+			strategy    *mocks.FanOutStrategy
 		)
 
 		BeforeEach(func() {
 			writer = httptest.NewRecorder()
 			request = &http.Request{URL: &url.URL{Path: "/uaa_scopes/great.scope"}}
-			strategy = mocks.NewStrategy()
+			strategy = mocks.NewFanOutStrategy()
 			errorWriter = mocks.NewErrorWriter()
 
 			connection = mocks.NewConnection()
@@ -46,35 +46,35 @@ var _ = Describe("UAAScopeHandler", func() {
 			handler = notify.NewUAAScopeHandler(notifyObj, errorWriter, strategy)
 		})
 
-		Context("when the notifyObj.Execute returns a successful response", func() {
+		Context("when the notifyObj.ExecuteFanOut returns a successful response", func() {
 			It("returns the JSON representation of the response", func() {
-				notifyObj.ExecuteCall.Returns.Response = []byte("whatever")
+				notifyObj.ExecuteFanOutCall.Returns.Response = []byte("whatever")
 
 				handler.ServeHTTP(writer, request, context)
 
-				Expect(writer.Code).To(Equal(http.StatusOK))
+				Expect(writer.Code).To(Equal(http.StatusAccepted))
 				Expect(writer.Body.String()).To(Equal("whatever"))
 			})
 
 			It("delegates to the notifyObj object with the correct arguments", func() {
 				handler.ServeHTTP(writer, request, context)
 
-				Expect(reflect.ValueOf(notifyObj.ExecuteCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
-				Expect(notifyObj.ExecuteCall.Receives.Request).To(Equal(request))
-				Expect(notifyObj.ExecuteCall.Receives.Context).To(Equal(context))
-				Expect(notifyObj.ExecuteCall.Receives.GUID).To(Equal("great.scope"))
-				Expect(notifyObj.ExecuteCall.Receives.Strategy).To(Equal(strategy))
-				Expect(notifyObj.ExecuteCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
-				Expect(notifyObj.ExecuteCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
+				Expect(reflect.ValueOf(notifyObj.ExecuteFanOutCall.Receives.Connection).Pointer()).To(Equal(reflect.ValueOf(connection).Pointer()))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Request).To(Equal(request))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Context).To(Equal(context))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.GUID).To(Equal("great.scope"))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Strategy).To(Equal(strategy))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.Validator).To(BeAssignableToTypeOf(notify.GUIDValidator{}))
+				Expect(notifyObj.ExecuteFanOutCall.Receives.VCAPRequestID).To(Equal("some-request-id"))
 			})
 		})
 
-		Context("when notifyObj.Execute returns an error", func() {
+		Context("when notifyObj.ExecuteFanOut returns an error", func() {
 			It("Propagates the error", func() {
-				notifyObj.ExecuteCall.Returns.Error = errors.New("the error")
+				notifyObj.ExecuteFanOutCall.Returns.Error = errors.New("the error")
 
 				handler.ServeHTTP(writer, request, context)
-				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteCall.Returns.Error))
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(notifyObj.ExecuteFanOutCall.Returns.Error))
 			})
 		})
 	})
@@ -0,0 +1,38 @@
+package partials
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type partialDeleter interface {
+	Delete(connection collections.ConnectionInterface, name string) error
+}
+
+type DeleteHandler struct {
+	deleter     partialDeleter
+	errorWriter errorWriter
+}
+
+func NewDeleteHandler(deleter partialDeleter, errWriter errorWriter) DeleteHandler {
+	return DeleteHandler{
+		deleter:     deleter,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DeleteHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	name := strings.Split(req.URL.Path, "/partials/")[1]
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	err := h.deleter.Delete(connection, name)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DeleteHandler", func() {
+	var (
+		handler     partials.DeleteHandler
+		collection  *mocks.PartialsCollection
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewPartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("DELETE", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = partials.NewDeleteHandler(collection, errorWriter)
+	})
+
+	It("deletes the partial", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusNoContent))
+		Expect(collection.DeleteCall.Receives.Connection).To(Equal(connection))
+		Expect(collection.DeleteCall.Receives.Name).To(Equal("footer"))
+	})
+
+	It("writes the errors from the collection to the error writer", func() {
+		collection.DeleteCall.Returns.Error = collections.PartialInUseError{Err: errors.New("in use")}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(collections.PartialInUseError{Err: errors.New("in use")}))
+	})
+})
@@ -0,0 +1,38 @@
+package partials
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type partialGetter interface {
+	Get(connection collections.ConnectionInterface, name string) (collections.Partial, error)
+}
+
+type GetHandler struct {
+	getter      partialGetter
+	errorWriter errorWriter
+}
+
+func NewGetHandler(getter partialGetter, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		getter:      getter,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	name := strings.Split(req.URL.Path, "/partials/")[1]
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	partial, err := h.getter.Get(connection, name)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewPartialOutput(partial))
+}
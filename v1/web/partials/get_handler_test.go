@@ -0,0 +1,76 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     partials.GetHandler
+		collection  *mocks.PartialsCollection
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewPartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/partials/footer", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = partials.NewGetHandler(collection, errorWriter)
+	})
+
+	It("returns the partial", func() {
+		collection.GetCall.Returns.Partial = collections.Partial{
+			Name:      "footer",
+			Body:      "<p>the footer</p>",
+			UpdatedAt: time.Date(2015, time.March, 1, 12, 0, 0, 0, time.UTC),
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"name": "footer",
+			"body": "<p>the footer</p>",
+			"updated_at": "2015-03-01T12:00:00Z"
+		}`))
+
+		Expect(collection.GetCall.Receives.Connection).To(Equal(connection))
+		Expect(collection.GetCall.Receives.Name).To(Equal("footer"))
+	})
+
+	It("writes the errors from the collection to the error writer", func() {
+		collection.GetCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(models.NotFoundError{Err: errors.New("not found")}))
+	})
+})
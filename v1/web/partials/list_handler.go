@@ -0,0 +1,48 @@
+package partials
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type partialLister interface {
+	List(connection collections.ConnectionInterface) ([]collections.Partial, error)
+}
+
+type PartialsListOutput struct {
+	Partials []PartialOutput `json:"partials"`
+}
+
+type ListHandler struct {
+	lister      partialLister
+	errorWriter errorWriter
+}
+
+func NewListHandler(lister partialLister, errWriter errorWriter) ListHandler {
+	return ListHandler{
+		lister:      lister,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	partials, err := h.lister.List(connection)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output := PartialsListOutput{
+		Partials: []PartialOutput{},
+	}
+
+	for _, partial := range partials {
+		output.Partials = append(output.Partials, NewPartialOutput(partial))
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
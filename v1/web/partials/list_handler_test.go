@@ -0,0 +1,82 @@
+package partials_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/partials"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListHandler", func() {
+	var (
+		handler     partials.ListHandler
+		collection  *mocks.PartialsCollection
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewPartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		var err error
+		request, err = http.NewRequest("GET", "/partials", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = partials.NewListHandler(collection, errorWriter)
+	})
+
+	It("lists the partials", func() {
+		updatedAt := time.Date(2015, time.March, 1, 12, 0, 0, 0, time.UTC)
+		collection.ListCall.Returns.Partials = []collections.Partial{
+			{Name: "footer", Body: "the footer", UpdatedAt: updatedAt},
+			{Name: "header", Body: "the header", UpdatedAt: updatedAt},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"partials": [
+				{"name": "footer", "body": "the footer", "updated_at": "2015-03-01T12:00:00Z"},
+				{"name": "header", "body": "the header", "updated_at": "2015-03-01T12:00:00Z"}
+			]
+		}`))
+
+		Expect(collection.ListCall.Receives.Connection).To(Equal(connection))
+	})
+
+	It("returns an empty list when there are no partials", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"partials": []}`))
+	})
+
+	It("writes the errors from the collection to the error writer", func() {
+		collection.ListCall.Returns.Error = errors.New("boom")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("boom")))
+	})
+})
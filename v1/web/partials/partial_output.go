@@ -0,0 +1,33 @@
+package partials
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+)
+
+type PartialOutput struct {
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewPartialOutput(partial collections.Partial) PartialOutput {
+	return PartialOutput{
+		Name:      partial.Name,
+		Body:      partial.Body,
+		UpdatedAt: partial.UpdatedAt,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
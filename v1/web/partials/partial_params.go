@@ -0,0 +1,44 @@
+package partials
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/template"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/cloudfoundry-incubator/notifications/valiant"
+)
+
+type PartialParams struct {
+	Body string `json:"body" validate-required:"true"`
+}
+
+func NewPartialParams(name string, body io.ReadCloser) (PartialParams, error) {
+	defer body.Close()
+
+	var partial PartialParams
+	validator := valiant.NewValidator(body)
+
+	err := validator.Validate(&partial)
+	if err != nil {
+		switch err.(type) {
+		case valiant.RequiredFieldError:
+			return partial, webutil.ValidationError{Err: err}
+		default:
+			return partial, webutil.ParseError{}
+		}
+	}
+
+	if !models.ValidPartialName(name) {
+		return PartialParams{}, webutil.ValidationError{Err: fmt.Errorf("Partial name %q must be letters and digits joined by dots, hyphens or underscores, and not the name of a message part", name)}
+	}
+
+	_, err = template.New(name).Parse(partial.Body)
+	if err != nil {
+		return PartialParams{}, webutil.ValidationError{Err: errors.New("Body syntax is malformed please check your braces")}
+	}
+
+	return partial, nil
+}
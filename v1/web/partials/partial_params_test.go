@@ -0,0 +1,60 @@
+package partials_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/partials"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PartialParams", func() {
+	Describe("NewPartialParams", func() {
+		It("constructs parameters from a reader", func() {
+			body := ioutil.NopCloser(bytes.NewBufferString(`{"body": "<p>{{.ClientID}}</p>"}`))
+
+			params, err := partials.NewPartialParams("footer", body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(params.Body).To(Equal("<p>{{.ClientID}}</p>"))
+		})
+
+		It("requires a body", func() {
+			body := ioutil.NopCloser(bytes.NewBufferString(`{}`))
+
+			_, err := partials.NewPartialParams("footer", body)
+			Expect(err).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+		})
+
+		It("returns a parse error when the request is not valid JSON", func() {
+			body := ioutil.NopCloser(bytes.NewBufferString(`{"body": `))
+
+			_, err := partials.NewPartialParams("footer", body)
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+
+		It("returns a parse error when the request has unknown fields", func() {
+			body := ioutil.NopCloser(bytes.NewBufferString(`{"body": "the footer", "name": "header"}`))
+
+			_, err := partials.NewPartialParams("footer", body)
+			Expect(err).To(Equal(webutil.ParseError{}))
+		})
+
+		It("rejects names that cannot be given to a partial", func() {
+			body := ioutil.NopCloser(bytes.NewBufferString(`{"body": "the footer"}`))
+
+			_, err := partials.NewPartialParams("html", body)
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`Partial name "html" must be letters and digits joined by dots, hyphens or underscores, and not the name of a message part`)}))
+		})
+
+		It("rejects a body with malformed syntax", func() {
+			body := ioutil.NopCloser(bytes.NewBufferString(`{"body": "{{.ClientID"}`))
+
+			_, err := partials.NewPartialParams("footer", body)
+			Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New("Body syntax is malformed please check your braces")}))
+		})
+	})
+})
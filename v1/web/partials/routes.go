@@ -0,0 +1,32 @@
+package partials
+
+import "github.com/ryanmoran/stack"
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type partialsCollection interface {
+	partialSetter
+	partialGetter
+	partialLister
+	partialDeleter
+}
+
+type Routes struct {
+	RequestCounter                          stack.Middleware
+	RequestLogging                          stack.Middleware
+	DatabaseAllocator                       stack.Middleware
+	NotificationTemplatesReadAuthenticator  stack.Middleware
+	NotificationTemplatesWriteAuthenticator stack.Middleware
+
+	ErrorWriter errorWriter
+	Partials    partialsCollection
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/partials", NewListHandler(r.Partials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/partials/{name}", NewGetHandler(r.Partials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("PUT", "/partials/{name}", NewSetHandler(r.Partials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("DELETE", "/partials/{name}", NewDeleteHandler(r.Partials, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+}
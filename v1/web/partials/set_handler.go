@@ -0,0 +1,52 @@
+package partials
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/ryanmoran/stack"
+)
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type partialSetter interface {
+	Set(connection collections.ConnectionInterface, partial collections.Partial) (collections.Partial, error)
+}
+
+type SetHandler struct {
+	setter      partialSetter
+	errorWriter errorWriter
+}
+
+func NewSetHandler(setter partialSetter, errWriter errorWriter) SetHandler {
+	return SetHandler{
+		setter:      setter,
+		errorWriter: errWriter,
+	}
+}
+
+func (h SetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	name := strings.Split(req.URL.Path, "/partials/")[1]
+
+	params, err := NewPartialParams(name, req.Body)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	connection := context.Get("database").(DatabaseInterface).Connection()
+
+	partial, err := h.setter.Set(connection, collections.Partial{
+		Name: name,
+		Body: params.Body,
+	})
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, NewPartialOutput(partial))
+}
@@ -0,0 +1,90 @@
+package partials_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/partials"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetHandler", func() {
+	var (
+		handler     partials.SetHandler
+		collection  *mocks.PartialsCollection
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		connection  *mocks.Connection
+	)
+
+	BeforeEach(func() {
+		collection = mocks.NewPartialsCollection()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		connection = mocks.NewConnection()
+		database := mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = partials.NewSetHandler(collection, errorWriter)
+	})
+
+	It("stores the partial under the name in the path", func() {
+		collection.SetCall.Returns.Partial = collections.Partial{
+			Name:      "footer",
+			Body:      "<p>the footer</p>",
+			UpdatedAt: time.Date(2015, time.March, 1, 12, 0, 0, 0, time.UTC),
+		}
+
+		request, err := http.NewRequest("PUT", "/partials/footer", bytes.NewBufferString(`{"body": "<p>the footer</p>"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"name": "footer",
+			"body": "<p>the footer</p>",
+			"updated_at": "2015-03-01T12:00:00Z"
+		}`))
+
+		Expect(collection.SetCall.Receives.Connection).To(Equal(connection))
+		Expect(collection.SetCall.Receives.Partial).To(Equal(collections.Partial{
+			Name: "footer",
+			Body: "<p>the footer</p>",
+		}))
+	})
+
+	It("writes the validation errors to the error writer", func() {
+		request, err := http.NewRequest("PUT", "/partials/footer", bytes.NewBufferString(`{"body": "{{.ClientID"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+	})
+
+	It("writes the errors from the collection to the error writer", func() {
+		collection.SetCall.Returns.Error = errors.New("boom")
+
+		request, err := http.NewRequest("PUT", "/partials/footer", bytes.NewBufferString(`{"body": "<p>the footer</p>"}`))
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("boom")))
+	})
+})
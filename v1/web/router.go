@@ -8,19 +8,25 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
 	"github.com/cloudfoundry-incubator/notifications/gobble"
+	"github.com/cloudfoundry-incubator/notifications/postal/common"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
 	"github.com/cloudfoundry-incubator/notifications/util"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/clients"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/deadjobs"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/feedbackreports"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/info"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/messages"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notifications"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/partials"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/preferences"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/sends"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 	"github.com/gorilla/mux"
 	"github.com/pivotal-golang/lager"
@@ -47,6 +53,16 @@ type Config struct {
 	CORSOrigin           string
 	SQLDB                *sql.DB
 	QueueWaitMaxDuration int
+
+	EncryptionKey         []byte
+	UnsubscribeIDLifetime time.Duration
+	SendAtMaxHorizon      time.Duration
+	IdempotencyKeyTTL     time.Duration
+
+	Sender string
+	Domain string
+
+	HTMLEscapingCompatibilityMode bool
 }
 
 func NewRouter(mx muxer, config Config) http.Handler {
@@ -59,43 +75,69 @@ func NewRouter(mx muxer, config Config) http.Handler {
 	preferencesRepo := models.NewPreferencesRepo()
 	unsubscribesRepo := models.NewUnsubscribesRepo()
 	messagesRepo := models.NewMessagesRepo(guidGenerator.Generate)
+	messageEventsRepo := models.NewMessageEventsRepo()
+	sendsRepo := models.NewSendsRepo(guidGenerator.Generate)
+	suppressionsRepo := models.NewSuppressionsRepo()
+	idempotencyKeysRepo := models.NewIdempotencyKeysRepo()
 	templatesRepo := models.NewTemplatesRepo()
+	templateVersionsRepo := models.NewTemplateVersionsRepo()
+	partialsRepo := models.NewPartialsRepo()
 
 	registrar := services.NewRegistrar(clientsRepo, kindsRepo)
 	notificationsFinder := services.NewNotificationsFinder(clientsRepo, kindsRepo)
 	preferencesFinder := services.NewPreferencesFinder(preferencesRepo, globalUnsubscribesRepo)
 	preferenceUpdater := services.NewPreferenceUpdater(globalUnsubscribesRepo, unsubscribesRepo, kindsRepo)
 	notificationsUpdater := services.NewNotificationsUpdater(kindsRepo)
-	messageFinder := services.NewMessageFinder(messagesRepo)
+	messageFinder := services.NewMessageFinder(messagesRepo, messageEventsRepo)
+	sendFinder := services.NewSendFinder(sendsRepo, messagesRepo)
+	feedbackRecorder := services.NewFeedbackRecorder(messagesRepo, messageEventsRepo, suppressionsRepo)
+
+	cloak, err := common.NewAuthenticatedCloak(config.EncryptionKey)
+	if err != nil {
+		panic(err)
+	}
 
-	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo)
+	unsubscriber := services.NewUnsubscriber(cloak, clock, config.UnsubscribeIDLifetime, clientsRepo, kindsRepo, unsubscribesRepo)
+
+	templatesCollection := collections.NewTemplatesCollection(clientsRepo, kindsRepo, templatesRepo, templateVersionsRepo)
+	partialsCollection := collections.NewPartialsCollection(clientsRepo, templatesRepo, partialsRepo)
 
 	templateFinder := services.NewTemplateFinder(templatesRepo)
-	templateUpdater := services.NewTemplateUpdater(templatesRepo)
+	templateUpdater := services.NewTemplateUpdater(templatesRepo, templateVersionsRepo, partialsRepo)
+	templateHistory := services.NewTemplateHistory(templatesRepo, templateVersionsRepo)
 	templateLister := services.NewTemplateLister(templatesRepo)
 
-	notifyObj := notify.NewNotify(notificationsFinder, registrar)
+	// Previews are packed from the template in the request, so the packager
+	// never loads templates itself.
+	templatePreviewer := services.NewTemplatePreviewer(common.NewPackager(nil, cloak, "", config.HTMLEscapingCompatibilityMode, config.Logger), cloak, clock, config.Sender, config.Domain)
+
+	idempotencyKeeper := services.NewIdempotencyKeeper(idempotencyKeysRepo, clock, config.IdempotencyKeyTTL)
+	notifyObj := notify.NewNotify(notificationsFinder, registrar, idempotencyKeeper, config.SendAtMaxHorizon)
 
 	gobbleQueue := gobble.NewQueue(gobble.NewDatabase(config.SQLDB), clock, gobble.Config{
 		WaitMaxDuration: time.Duration(config.QueueWaitMaxDuration) * time.Millisecond,
 	})
 
-	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, gobble.Initializer{})
+	canceller := services.NewCanceller(messagesRepo, messageEventsRepo, sendsRepo, gobbleQueue)
+	v1enqueuer := services.NewEnqueuer(gobbleQueue, messagesRepo, messageEventsRepo, gobble.Initializer{})
 
 	uaaClient := uaa.NewZonedUAAClient(config.UAAClientID, config.UAAClientSecret, config.VerifySSL, config.UAATokenValidator)
 	cloudController := cf.NewCloudController(config.CCHost, !config.VerifySSL)
-	tokenLoader := uaa.NewTokenLoader(uaaClient)
+	tokenLoader := uaa.NewTokenLoader(uaaClient, clock)
 	spaceLoader := services.NewSpaceLoader(cloudController)
 	organizationLoader := services.NewOrganizationLoader(cloudController)
 	findsUserIDs := services.NewFindsUserIDs(cloudController, uaaClient)
 	allUsers := services.NewAllUsers(uaaClient)
 
-	emailStrategy := services.NewEmailStrategy(v1enqueuer)
-	userStrategy := services.NewUserStrategy(v1enqueuer)
-	spaceStrategy := services.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserIDs, v1enqueuer)
-	organizationStrategy := services.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserIDs, v1enqueuer)
-	everyoneStrategy := services.NewEveryoneStrategy(tokenLoader, allUsers, v1enqueuer)
-	uaaScopeStrategy := services.NewUAAScopeStrategy(tokenLoader, findsUserIDs, v1enqueuer, config.DefaultUAAScopes)
+	emailStrategy := services.NewEmailStrategy(v1enqueuer, sendsRepo)
+	userStrategy := services.NewUserStrategy(v1enqueuer, sendsRepo)
+	fanOutStrategy := func(audience string, validator services.AudienceValidator) services.FanOutStrategy {
+		return services.NewFanOutStrategy(audience, validator, gobbleQueue, gobble.Initializer{}, sendsRepo)
+	}
+	spaceStrategy := fanOutStrategy(services.AudienceSpace, services.NewSpaceStrategy(tokenLoader, spaceLoader, organizationLoader, findsUserIDs, v1enqueuer))
+	organizationStrategy := fanOutStrategy(services.AudienceOrganization, services.NewOrganizationStrategy(tokenLoader, organizationLoader, findsUserIDs, v1enqueuer))
+	everyoneStrategy := fanOutStrategy(services.AudienceEveryone, services.NewEveryoneStrategy(tokenLoader, allUsers, v1enqueuer))
+	uaaScopeStrategy := fanOutStrategy(services.AudienceUAAScope, services.NewUAAScopeStrategy(tokenLoader, findsUserIDs, v1enqueuer, config.DefaultUAAScopes))
 
 	errorWriter := webutil.NewErrorWriter()
 
@@ -136,16 +178,38 @@ func NewRouter(mx muxer, config Config) http.Handler {
 
 		ErrorWriter:      errorWriter,
 		TemplateAssigner: templatesCollection,
+		LayoutAssigner:   partialsCollection,
 	}.Register(mx)
 
 	messages.Routes{
-		RequestCounter:                               requestCounter,
-		RequestLogging:                               requestLogging,
-		DatabaseAllocator:                            databaseAllocator,
-		NotificationsWriteOrEmailsWriteAuthenticator: auth("notifications.write", "emails.write"),
+		RequestCounter:                                      requestCounter,
+		RequestLogging:                                      requestLogging,
+		DatabaseAllocator:                                   databaseAllocator,
+		NotificationsWriteOrEmailsWriteOrAdminAuthenticator: auth("notifications.write", "emails.write", "notifications.admin"),
+
+		ErrorWriter:      errorWriter,
+		MessageFinder:    messageFinder,
+		MessageCanceller: canceller,
+	}.Register(mx)
+
+	sends.Routes{
+		RequestCounter:                                      requestCounter,
+		RequestLogging:                                      requestLogging,
+		DatabaseAllocator:                                   databaseAllocator,
+		NotificationsWriteOrEmailsWriteOrAdminAuthenticator: auth("notifications.write", "emails.write", "notifications.admin"),
 
 		ErrorWriter:   errorWriter,
-		MessageFinder: messageFinder,
+		SendFinder:    sendFinder,
+		SendCanceller: canceller,
+	}.Register(mx)
+
+	unsubscribes.Routes{
+		RequestCounter:    requestCounter,
+		RequestLogging:    requestLogging,
+		DatabaseAllocator: databaseAllocator,
+
+		ErrorWriter:  errorWriter,
+		Unsubscriber: unsubscriber,
 	}.Register(mx)
 
 	templates.Routes{
@@ -163,6 +227,21 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateDeleter:           templatesCollection,
 		TemplateLister:            templateLister,
 		TemplateAssociationLister: templatesCollection,
+		TemplatePreviewer:         templatePreviewer,
+		TemplateHistory:           templateHistory,
+		TemplateRollbacker:        templateUpdater,
+		PartialLister:             partialsCollection,
+	}.Register(mx)
+
+	partials.Routes{
+		RequestCounter:                          requestCounter,
+		RequestLogging:                          requestLogging,
+		DatabaseAllocator:                       databaseAllocator,
+		NotificationTemplatesReadAuthenticator:  auth("notification_templates.read"),
+		NotificationTemplatesWriteAuthenticator: auth("notification_templates.write"),
+
+		ErrorWriter: errorWriter,
+		Partials:    partialsCollection,
 	}.Register(mx)
 
 	notifications.Routes{
@@ -179,6 +258,25 @@ func NewRouter(mx muxer, config Config) http.Handler {
 		TemplateAssigner:     templatesCollection,
 	}.Register(mx)
 
+	deadjobs.Routes{
+		RequestCounter:                  requestCounter,
+		RequestLogging:                  requestLogging,
+		NotificationsAdminAuthenticator: auth("notifications.admin"),
+
+		ErrorWriter: errorWriter,
+		Queue:       gobbleQueue,
+	}.Register(mx)
+
+	feedbackreports.Routes{
+		RequestCounter:                  requestCounter,
+		RequestLogging:                  requestLogging,
+		DatabaseAllocator:               databaseAllocator,
+		NotificationsAdminAuthenticator: auth("notifications.admin"),
+
+		ErrorWriter:      errorWriter,
+		FeedbackRecorder: feedbackRecorder,
+	}.Register(mx)
+
 	notify.Routes{
 		RequestCounter:                  requestCounter,
 		RequestLogging:                  requestLogging,
@@ -0,0 +1,44 @@
+package sends
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type CancelHandler struct {
+	canceller   sendCanceller
+	errorWriter errorWriter
+}
+
+type sendCanceller interface {
+	CancelSend(database services.DatabaseInterface, sendID, clientID string, admin bool) (int, error)
+}
+
+func NewCancelHandler(canceller sendCanceller, errWriter errorWriter) CancelHandler {
+	return CancelHandler{
+		canceller:   canceller,
+		errorWriter: errWriter,
+	}
+}
+
+func (h CancelHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	sendID := strings.TrimSuffix(strings.Split(req.URL.Path, "/sends/")[1], "/cancel")
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	count, err := h.canceller.CancelSend(context.Get("database").(DatabaseInterface), sendID, clientID, webutil.HasAdminScope(token.Claims["scope"]))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int{
+		"cancelled": count,
+	})
+}
@@ -0,0 +1,94 @@
+package sends_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/sends"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CancelHandler", func() {
+	var (
+		handler     sends.CancelHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		request     *http.Request
+		canceller   *mocks.Canceller
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	setToken := func(scopes ...string) {
+		rawToken := helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "some-client",
+			"iss":       "http://uaa.example.com/oauth/token",
+			"exp":       int64(3404281214),
+			"scope":     scopes,
+		})
+
+		token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+			return []byte(helpers.UAAPublicKey), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		context.Set("token", token)
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		errorWriter = mocks.NewErrorWriter()
+		canceller = mocks.NewCanceller()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		setToken("emails.write")
+
+		request, err = http.NewRequest("POST", "/sends/some-send-id/cancel", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = sends.NewCancelHandler(canceller, errorWriter)
+	})
+
+	It("cancels the send and returns how many messages were cancelled", func() {
+		canceller.CancelSendCall.Returns.Count = 7
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{"cancelled": 7}`))
+
+		Expect(canceller.CancelSendCall.Receives.Database).To(Equal(database))
+		Expect(canceller.CancelSendCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(canceller.CancelSendCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(canceller.CancelSendCall.Receives.Admin).To(BeFalse())
+	})
+
+	It("cancels the send as an admin when the token has the notifications.admin scope", func() {
+		setToken("notifications.admin")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(canceller.CancelSendCall.Receives.Admin).To(BeTrue())
+	})
+
+	It("delegates errors to the error writer", func() {
+		canceller.CancelSendCall.Returns.Error = services.CancelForbiddenError{}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(services.CancelForbiddenError{}))
+	})
+})
@@ -0,0 +1,7 @@
+package sends
+
+import "github.com/cloudfoundry-incubator/notifications/v1/services"
+
+type DatabaseInterface interface {
+	services.DatabaseInterface
+}
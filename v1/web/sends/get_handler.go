@@ -0,0 +1,159 @@
+package sends
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+const (
+	DefaultPerPage = 50
+	MaxPerPage     = 500
+)
+
+type GetHandler struct {
+	finder      sendFinder
+	errorWriter errorWriter
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type sendFinder interface {
+	Find(database services.DatabaseInterface, sendID, clientID string, admin bool, page, perPage int) (services.Send, error)
+}
+
+func NewGetHandler(finder sendFinder, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		finder:      finder,
+		errorWriter: errWriter,
+	}
+}
+
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	sendID := strings.Split(req.URL.Path, "/sends/")[1]
+
+	query := req.URL.Query()
+	page, err := parsePositiveInt(query.Get("page"), "page", 1, 0)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	perPage, err := parsePositiveInt(query.Get("per_page"), "per_page", DefaultPerPage, MaxPerPage)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	token := context.Get("token").(*jwt.Token)
+	clientID := token.Claims["client_id"].(string)
+
+	send, err := h.finder.Find(context.Get("database").(DatabaseInterface), sendID, clientID, webutil.HasAdminScope(token.Claims["scope"]), page, perPage)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	document := sendDocument{
+		ID:            send.ID,
+		ClientID:      send.ClientID,
+		KindID:        send.KindID,
+		Audience:      send.Audience,
+		VCAPRequestID: send.VCAPRequestID,
+		Counts: sendCountsDocument{
+			Queued:        send.Counts.Queued,
+			Delivered:     send.Counts.Delivered,
+			Failed:        send.Counts.Failed,
+			Undeliverable: send.Counts.Undeliverable,
+			Cancelled:     send.Counts.Cancelled,
+		},
+		StartedAt:  send.StartedAt,
+		FinishedAt: send.FinishedAt,
+		Recipients: sendRecipientsDocument{
+			Total:     send.Counts.Total(),
+			Page:      page,
+			PerPage:   perPage,
+			Resources: []sendRecipientDocument{},
+		},
+	}
+
+	for _, recipient := range send.Recipients {
+		document.Recipients.Resources = append(document.Recipients.Resources, sendRecipientDocument{
+			MessageID: recipient.MessageID,
+			Recipient: recipient.Recipient,
+			Status:    recipient.Status,
+		})
+	}
+
+	writeJSON(w, http.StatusOK, document)
+}
+
+func parsePositiveInt(value, name string, defaultValue, max int) (int, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, webutil.ValidationError{Err: fmt.Errorf("%q must be a positive integer", name)}
+	}
+
+	if max > 0 && number > max {
+		return 0, webutil.ValidationError{Err: fmt.Errorf("%q must not be greater than %d", name, max)}
+	}
+
+	return number, nil
+}
+
+type sendDocument struct {
+	ID            string                 `json:"id"`
+	ClientID      string                 `json:"client_id"`
+	KindID        string                 `json:"kind_id"`
+	Audience      string                 `json:"audience"`
+	VCAPRequestID string                 `json:"vcap_request_id"`
+	Counts        sendCountsDocument     `json:"counts"`
+	StartedAt     time.Time              `json:"started_at"`
+	FinishedAt    *time.Time             `json:"finished_at"`
+	Recipients    sendRecipientsDocument `json:"recipients"`
+}
+
+type sendCountsDocument struct {
+	Queued        int `json:"queued"`
+	Delivered     int `json:"delivered"`
+	Failed        int `json:"failed"`
+	Undeliverable int `json:"undeliverable"`
+	Cancelled     int `json:"cancelled"`
+}
+
+type sendRecipientsDocument struct {
+	Total     int                     `json:"total"`
+	Page      int                     `json:"page"`
+	PerPage   int                     `json:"per_page"`
+	Resources []sendRecipientDocument `json:"resources"`
+}
+
+type sendRecipientDocument struct {
+	MessageID string `json:"message_id"`
+	Recipient string `json:"recipient"`
+	Status    string `json:"status"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
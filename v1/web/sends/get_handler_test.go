@@ -0,0 +1,199 @@
+package sends_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/sends"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler     sends.GetHandler
+		errorWriter *mocks.ErrorWriter
+		writer      *httptest.ResponseRecorder
+		sendFinder  *mocks.SendFinder
+		database    *mocks.Database
+		context     stack.Context
+	)
+
+	setToken := func(scopes ...string) {
+		rawToken := helpers.BuildToken(map[string]interface{}{
+			"alg": "RS256",
+		}, map[string]interface{}{
+			"client_id": "some-client",
+			"iss":       "http://uaa.example.com/oauth/token",
+			"exp":       int64(3404281214),
+			"scope":     scopes,
+		})
+
+		token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+			return []byte(helpers.UAAPublicKey), nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		context.Set("token", token)
+	}
+
+	BeforeEach(func() {
+		errorWriter = mocks.NewErrorWriter()
+		sendFinder = mocks.NewSendFinder()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		setToken("emails.write")
+
+		handler = sends.NewGetHandler(sendFinder, errorWriter)
+	})
+
+	It("returns the status rollup of the send", func() {
+		startedAt := time.Date(2015, time.June, 8, 14, 40, 12, 0, time.UTC)
+		finishedAt := startedAt.Add(time.Minute)
+		sendFinder.FindCall.Returns.Send = services.Send{
+			ID:            "some-send-id",
+			ClientID:      "some-client",
+			KindID:        "some-kind",
+			Audience:      "space",
+			VCAPRequestID: "some-request-id",
+			Counts: services.SendCounts{
+				Delivered: 2,
+				Failed:    1,
+				Cancelled: 1,
+			},
+			StartedAt:  startedAt,
+			FinishedAt: &finishedAt,
+			Recipients: []services.SendRecipient{
+				{MessageID: "message-1", Recipient: "user-1", Status: "delivered"},
+				{MessageID: "message-2", Recipient: "user-2", Status: "failed"},
+			},
+		}
+
+		request, err := http.NewRequest("GET", "/sends/some-send-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.Bytes()).To(MatchJSON(`{
+			"id": "some-send-id",
+			"client_id": "some-client",
+			"kind_id": "some-kind",
+			"audience": "space",
+			"vcap_request_id": "some-request-id",
+			"counts": {
+				"queued": 0,
+				"delivered": 2,
+				"failed": 1,
+				"undeliverable": 0,
+				"cancelled": 1
+			},
+			"started_at": "2015-06-08T14:40:12Z",
+			"finished_at": "2015-06-08T14:41:12Z",
+			"recipients": {
+				"total": 4,
+				"page": 1,
+				"per_page": 50,
+				"resources": [
+					{"message_id": "message-1", "recipient": "user-1", "status": "delivered"},
+					{"message_id": "message-2", "recipient": "user-2", "status": "failed"}
+				]
+			}
+		}`))
+
+		Expect(sendFinder.FindCall.Receives.Database).To(Equal(database))
+		Expect(sendFinder.FindCall.Receives.SendID).To(Equal("some-send-id"))
+		Expect(sendFinder.FindCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(sendFinder.FindCall.Receives.Admin).To(BeFalse())
+		Expect(sendFinder.FindCall.Receives.Page).To(Equal(1))
+		Expect(sendFinder.FindCall.Receives.PerPage).To(Equal(sends.DefaultPerPage))
+	})
+
+	It("lets an admin view sends of other clients", func() {
+		setToken("notifications.admin")
+
+		request, err := http.NewRequest("GET", "/sends/some-send-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(sendFinder.FindCall.Receives.Admin).To(BeTrue())
+	})
+
+	It("reports a send that has not finished", func() {
+		request, err := http.NewRequest("GET", "/sends/some-send-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(ContainSubstring(`"finished_at":null`))
+	})
+
+	It("pages through the recipients", func() {
+		request, err := http.NewRequest("GET", "/sends/some-send-id?page=3&per_page=20", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(sendFinder.FindCall.Receives.Page).To(Equal(3))
+		Expect(sendFinder.FindCall.Receives.PerPage).To(Equal(20))
+	})
+
+	Context("when the pagination is invalid", func() {
+		It("rejects a page that is not a positive integer", func() {
+			for _, query := range []string{"page=first", "page=0"} {
+				request, err := http.NewRequest("GET", "/sends/some-send-id?"+query, nil)
+				Expect(err).NotTo(HaveOccurred())
+
+				handler.ServeHTTP(writer, request, context)
+				Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"page" must be a positive integer`)}))
+			}
+
+			Expect(sendFinder.FindCall.Receives.SendID).To(BeEmpty())
+		})
+
+		It("rejects a per_page that is not a positive integer", func() {
+			request, err := http.NewRequest("GET", "/sends/some-send-id?per_page=-5", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"per_page" must be a positive integer`)}))
+			Expect(sendFinder.FindCall.Receives.SendID).To(BeEmpty())
+		})
+
+		It("rejects a per_page that is too large", func() {
+			request, err := http.NewRequest("GET", "/sends/some-send-id?per_page=501", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"per_page" must not be greater than 500`)}))
+			Expect(sendFinder.FindCall.Receives.SendID).To(BeEmpty())
+		})
+	})
+
+	Context("when the finder errors", func() {
+		It("delegates to the error writer", func() {
+			sendFinder.FindCall.Returns.Error = errors.New("the send could not be found")
+
+			request, err := http.NewRequest("GET", "/sends/some-send-id", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(errors.New("the send could not be found")))
+		})
+	})
+})
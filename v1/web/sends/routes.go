@@ -0,0 +1,23 @@
+package sends
+
+import "github.com/ryanmoran/stack"
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type Routes struct {
+	RequestCounter                                      stack.Middleware
+	RequestLogging                                      stack.Middleware
+	NotificationsWriteOrEmailsWriteOrAdminAuthenticator stack.Middleware
+	DatabaseAllocator                                   stack.Middleware
+
+	SendFinder    sendFinder
+	SendCanceller sendCanceller
+	ErrorWriter   errorWriter
+}
+
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/sends/{send_id}", NewGetHandler(r.SendFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteOrAdminAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/sends/{send_id}/cancel", NewCancelHandler(r.SendCanceller, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsWriteOrEmailsWriteOrAdminAuthenticator, r.DatabaseAllocator)
+}
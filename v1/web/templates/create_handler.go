@@ -6,6 +6,7 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -14,7 +15,7 @@ type errorWriter interface {
 }
 
 type templateCreator interface {
-	Create(connection collections.ConnectionInterface, template collections.Template) (collections.Template, error)
+	Create(connection collections.ConnectionInterface, template collections.Template, clientID string) (collections.Template, error)
 }
 
 type CreateHandler struct {
@@ -37,6 +38,7 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 	}
 
 	connection := context.Get("database").(DatabaseInterface).Connection()
+	clientID := context.Get("token").(*jwt.Token).Claims["client_id"].(string)
 
 	template, err := h.creator.Create(connection, collections.Template{
 		Name:     templateParams.Name,
@@ -44,7 +46,7 @@ func (h CreateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		HTML:     templateParams.HTML,
 		Subject:  templateParams.Subject,
 		Metadata: string(templateParams.Metadata),
-	})
+	}, clientID)
 	if err != nil {
 		h.errorWriter.Write(w, webutil.TemplateCreateError{})
 		return
@@ -59,6 +59,7 @@ var _ = Describe("CreateHandler", func() {
 
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("token", newClientToken("some-client"))
 
 			request, err = http.NewRequest("POST", "/templates", body)
 			Expect(err).NotTo(HaveOccurred())
@@ -77,6 +78,7 @@ var _ = Describe("CreateHandler", func() {
 				Subject:  "Raptor Containment Unit Breached",
 				Metadata: "{}",
 			}))
+			Expect(creator.CreateCall.Receives.ClientID).To(Equal("some-client"))
 
 			Expect(writer.Code).To(Equal(http.StatusCreated))
 			Expect(writer.Body.String()).To(MatchJSON(`{"template_id":"template-guid"}`))
@@ -0,0 +1,65 @@
+package templates
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type DiffVersionsHandler struct {
+	history     templateHistory
+	errorWriter errorWriter
+}
+
+func NewDiffVersionsHandler(history templateHistory, errWriter errorWriter) DiffVersionsHandler {
+	return DiffVersionsHandler{
+		history:     history,
+		errorWriter: errWriter,
+	}
+}
+
+func (h DiffVersionsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := strings.Split(strings.Split(req.URL.Path, "/templates/")[1], "/")[0]
+
+	query := req.URL.Query()
+	from, err := parseVersion(query.Get("from"), "from")
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	to, err := parseVersion(query.Get("to"), "to")
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	diff, err := h.history.Diff(context.Get("database").(DatabaseInterface), templateID, from, to)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		From    int               `json:"from"`
+		To      int               `json:"to"`
+		Changes map[string]string `json:"changes"`
+	}{
+		From:    diff.From,
+		To:      diff.To,
+		Changes: diff.Changes,
+	})
+}
+
+func parseVersion(value, name string) (int, error) {
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, webutil.ValidationError{Err: fmt.Errorf("%q must be a positive integer", name)}
+	}
+
+	return version, nil
+}
@@ -0,0 +1,92 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DiffVersionsHandler", func() {
+	var (
+		handler     templates.DiffVersionsHandler
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		history     *mocks.TemplateHistory
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+	)
+
+	newRequest := func(query string) *http.Request {
+		request, err := http.NewRequest("GET", "/templates/some-template-id/versions/diff"+query, nil)
+		Expect(err).NotTo(HaveOccurred())
+		return request
+	}
+
+	BeforeEach(func() {
+		history = mocks.NewTemplateHistory()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		handler = templates.NewDiffVersionsHandler(history, errorWriter)
+	})
+
+	It("returns the changes between the two versions", func() {
+		history.DiffCall.Returns.Diff = services.TemplateDiff{
+			TemplateID: "some-template-id",
+			From:       1,
+			To:         3,
+			Changes: map[string]string{
+				"subject": "-old subject\n+new subject\n",
+			},
+		}
+
+		handler.ServeHTTP(writer, newRequest("?from=1&to=3"), context)
+
+		Expect(history.DiffCall.Receives.Database).To(Equal(database))
+		Expect(history.DiffCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(history.DiffCall.Receives.From).To(Equal(1))
+		Expect(history.DiffCall.Receives.To).To(Equal(3))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"from": 1,
+			"to": 3,
+			"changes": {
+				"subject": "-old subject\n+new subject\n"
+			}
+		}`))
+	})
+
+	It("requires both versions", func() {
+		handler.ServeHTTP(writer, newRequest("?from=1"), context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"to" must be a positive integer`)}))
+	})
+
+	It("rejects versions that are not positive integers", func() {
+		handler.ServeHTTP(writer, newRequest("?from=first&to=2"), context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"from" must be a positive integer`)}))
+	})
+
+	It("writes errors from the history to the error writer", func() {
+		history.DiffCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		handler.ServeHTTP(writer, newRequest("?from=1&to=9"), context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{Err: errors.New("not found")}))
+	})
+})
@@ -38,12 +38,18 @@ func (h GetDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, c
 		panic(err)
 	}
 
+	locales, err := localesOutput(template.Locales)
+	if err != nil {
+		panic(err)
+	}
+
 	templateOutput := TemplateOutput{
 		Name:     template.Name,
 		Subject:  template.Subject,
 		HTML:     template.HTML,
 		Text:     template.Text,
 		Metadata: metadata,
+		Locales:  locales,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
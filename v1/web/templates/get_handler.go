@@ -5,15 +5,33 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/ryanmoran/stack"
 )
 
 type TemplateOutput struct {
-	Name     string                 `json:"name"`
-	Subject  string                 `json:"subject"`
-	HTML     string                 `json:"html"`
-	Text     string                 `json:"text"`
-	Metadata map[string]interface{} `json:"metadata"`
+	Name     string                            `json:"name"`
+	Subject  string                            `json:"subject"`
+	HTML     string                            `json:"html"`
+	Text     string                            `json:"text"`
+	Metadata map[string]interface{}            `json:"metadata"`
+	Locales  map[string]models.TemplateVariant `json:"locales"`
+}
+
+// localesOutput reads the locale variants stored on a template, keyed by
+// the locales they were saved with.
+func localesOutput(locales string) (map[string]models.TemplateVariant, error) {
+	variants := map[string]models.TemplateVariant{}
+	if locales == "" {
+		return variants, nil
+	}
+
+	err := json.Unmarshal([]byte(locales), &variants)
+	if err != nil {
+		return nil, err
+	}
+
+	return variants, nil
 }
 
 type GetHandler struct {
@@ -44,12 +62,19 @@ func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context
 		return
 	}
 
+	locales, err := localesOutput(template.Locales)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
 	templateOutput := TemplateOutput{
 		Name:     template.Name,
 		Subject:  template.Subject,
 		HTML:     template.HTML,
 		Text:     template.Text,
 		Metadata: metadata,
+		Locales:  locales,
 	}
 
 	writeJSON(w, http.StatusOK, templateOutput)
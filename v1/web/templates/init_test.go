@@ -3,6 +3,9 @@ package templates_test
 import (
 	"testing"
 
+	"github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	"github.com/dgrijalva/jwt-go"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -11,3 +14,21 @@ func TestWebV1TemplatesSuite(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "v1/web/templates")
 }
+
+func newClientToken(clientID string) *jwt.Token {
+	rawToken := helpers.BuildToken(map[string]interface{}{
+		"alg": "RS256",
+	}, map[string]interface{}{
+		"client_id": clientID,
+		"iss":       "http://uaa.example.com/oauth/token",
+		"exp":       int64(3404281214),
+		"scope":     []string{"notification_templates.write"},
+	})
+
+	token, err := jwt.Parse(rawToken, func(*jwt.Token) (interface{}, error) {
+		return []byte(helpers.UAAPublicKey), nil
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	return token
+}
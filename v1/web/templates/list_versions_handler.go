@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type templateHistory interface {
+	List(database services.DatabaseInterface, templateID string) ([]models.TemplateVersion, error)
+	Diff(database services.DatabaseInterface, templateID string, from, to int) (services.TemplateDiff, error)
+}
+
+type TemplateVersionOutput struct {
+	Version   int                               `json:"version"`
+	Name      string                            `json:"name"`
+	Subject   string                            `json:"subject"`
+	HTML      string                            `json:"html"`
+	Text      string                            `json:"text"`
+	Metadata  map[string]interface{}            `json:"metadata"`
+	Locales   map[string]models.TemplateVariant `json:"locales"`
+	ClientID  string                            `json:"client_id"`
+	CreatedAt time.Time                         `json:"created_at"`
+}
+
+func NewTemplateVersionOutput(version models.TemplateVersion) (TemplateVersionOutput, error) {
+	var metadata map[string]interface{}
+	if version.Metadata != "" {
+		err := json.Unmarshal([]byte(version.Metadata), &metadata)
+		if err != nil {
+			return TemplateVersionOutput{}, err
+		}
+	}
+
+	locales, err := localesOutput(version.Locales)
+	if err != nil {
+		return TemplateVersionOutput{}, err
+	}
+
+	return TemplateVersionOutput{
+		Version:   version.Version,
+		Name:      version.Name,
+		Subject:   version.Subject,
+		HTML:      version.HTML,
+		Text:      version.Text,
+		Metadata:  metadata,
+		Locales:   locales,
+		ClientID:  version.ClientID,
+		CreatedAt: version.CreatedAt,
+	}, nil
+}
+
+type ListVersionsHandler struct {
+	history     templateHistory
+	errorWriter errorWriter
+}
+
+func NewListVersionsHandler(history templateHistory, errWriter errorWriter) ListVersionsHandler {
+	return ListVersionsHandler{
+		history:     history,
+		errorWriter: errWriter,
+	}
+}
+
+func (h ListVersionsHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	templateID := strings.Split(strings.Split(req.URL.Path, "/templates/")[1], "/")[0]
+
+	versions, err := h.history.List(context.Get("database").(DatabaseInterface), templateID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output := struct {
+		Versions []TemplateVersionOutput `json:"versions"`
+	}{
+		Versions: []TemplateVersionOutput{},
+	}
+
+	for _, version := range versions {
+		versionOutput, err := NewTemplateVersionOutput(version)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		output.Versions = append(output.Versions, versionOutput)
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
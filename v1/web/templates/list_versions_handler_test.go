@@ -0,0 +1,113 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ListVersionsHandler", func() {
+	var (
+		handler     templates.ListVersionsHandler
+		request     *http.Request
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		history     *mocks.TemplateHistory
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		history = mocks.NewTemplateHistory()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/templates/some-template-id/versions", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = templates.NewListVersionsHandler(history, errorWriter)
+	})
+
+	It("lists the versions of the template", func() {
+		createdAt := time.Date(2015, time.June, 8, 14, 40, 12, 0, time.UTC)
+		history.ListCall.Returns.Versions = []models.TemplateVersion{
+			{
+				TemplateID: "some-template-id",
+				Version:    2,
+				Name:       "some-name",
+				Subject:    "{{.Subject}}",
+				Text:       "new text",
+				HTML:       "<p>new html</p>",
+				Metadata:   `{"hello": "world"}`,
+				Locales:    `{"de": {"subject": "Betreff: {{.Subject}}"}}`,
+				ClientID:   "some-client",
+				CreatedAt:  createdAt.Add(time.Hour),
+			},
+			{
+				TemplateID: "some-template-id",
+				Version:    1,
+				Name:       "some-name",
+				Subject:    "{{.Subject}}",
+				Text:       "old text",
+				HTML:       "<p>old html</p>",
+				Metadata:   "{}",
+				CreatedAt:  createdAt,
+			},
+		}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(history.ListCall.Receives.Database).To(Equal(database))
+		Expect(history.ListCall.Receives.TemplateID).To(Equal("some-template-id"))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"versions": [
+				{
+					"version": 2,
+					"name": "some-name",
+					"subject": "{{.Subject}}",
+					"text": "new text",
+					"html": "<p>new html</p>",
+					"metadata": {"hello": "world"},
+					"locales": {"de": {"subject": "Betreff: {{.Subject}}"}},
+					"client_id": "some-client",
+					"created_at": "2015-06-08T15:40:12Z"
+				},
+				{
+					"version": 1,
+					"name": "some-name",
+					"subject": "{{.Subject}}",
+					"text": "old text",
+					"html": "<p>old html</p>",
+					"metadata": {},
+					"locales": {},
+					"client_id": "",
+					"created_at": "2015-06-08T14:40:12Z"
+				}
+			]
+		}`))
+	})
+
+	It("writes errors to the error writer", func() {
+		history.ListCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{Err: errors.New("not found")}))
+	})
+})
@@ -0,0 +1,180 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/notify"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type templatePreviewer interface {
+	Preview(template services.PreviewTemplate, notification services.PreviewNotification) (services.Preview, error)
+}
+
+type partialLister interface {
+	List(connection collections.ConnectionInterface) ([]collections.Partial, error)
+	LayoutOf(connection collections.ConnectionInterface, clientID string) (string, error)
+}
+
+type PreviewTemplateParams struct {
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+type PreviewNotificationParams struct {
+	To                string `json:"to"`
+	ReplyTo           string `json:"reply_to"`
+	Subject           string `json:"subject"`
+	Text              string `json:"text"`
+	HTML              string `json:"html"`
+	KindID            string `json:"kind_id"`
+	KindDescription   string `json:"kind_description"`
+	SourceDescription string `json:"source_description"`
+	ClientID          string `json:"client_id"`
+	Space             string `json:"space"`
+	Organization      string `json:"organization"`
+	Locale            string `json:"locale"`
+}
+
+type PreviewParams struct {
+	Template     *PreviewTemplateParams    `json:"template"`
+	Notification PreviewNotificationParams `json:"notification"`
+}
+
+type PreviewOutput struct {
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html"`
+	MIME    string   `json:"mime"`
+	Errors  []string `json:"errors"`
+}
+
+// PreviewHandler renders a template with a sample notification. It serves
+// both the preview of a saved template, and the stateless preview of a
+// template given in the request body. Either may include the saved partials,
+// and is wrapped in the layout of the client of the sample notification the
+// way its deliveries are.
+type PreviewHandler struct {
+	finder      templateFinder
+	partials    partialLister
+	previewer   templatePreviewer
+	errorWriter errorWriter
+}
+
+func NewPreviewHandler(templateFinder templateFinder, partials partialLister, previewer templatePreviewer, errWriter errorWriter) PreviewHandler {
+	return PreviewHandler{
+		finder:      templateFinder,
+		partials:    partials,
+		previewer:   previewer,
+		errorWriter: errWriter,
+	}
+}
+
+func (h PreviewHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	var params PreviewParams
+	err := json.NewDecoder(req.Body).Decode(&params)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	var template services.PreviewTemplate
+	database := context.Get("database").(DatabaseInterface)
+
+	templateID := strings.TrimSuffix(strings.Split(req.URL.Path, "/templates/")[1], "/preview")
+	if templateID == "preview" {
+		if params.Template == nil || params.Template.HTML == "" {
+			h.errorWriter.Write(w, webutil.ValidationError{Err: errors.New("Missing required field 'template.html'")})
+			return
+		}
+
+		template = services.PreviewTemplate{
+			Subject: params.Template.Subject,
+			Text:    params.Template.Text,
+			HTML:    params.Template.HTML,
+		}
+	} else {
+		found, err := h.finder.FindByID(database, templateID)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		found, err = found.Localized(params.Notification.Locale)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		template = services.PreviewTemplate{
+			Subject: found.Subject,
+			Text:    found.Text,
+			HTML:    found.HTML,
+		}
+	}
+
+	partials, err := h.partials.List(database.Connection())
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	template.Partials = map[string]string{}
+	for _, partial := range partials {
+		template.Partials[partial.Name] = partial.Body
+	}
+
+	if params.Notification.ClientID != "" {
+		layout, err := h.partials.LayoutOf(database.Connection(), params.Notification.ClientID)
+		if err != nil {
+			h.errorWriter.Write(w, err)
+			return
+		}
+
+		template.Layout = template.Partials[layout]
+	}
+
+	doctype, head, bodyContent, bodyAttributes, err := notify.HTMLExtractor{}.Extract(params.Notification.HTML)
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ParseError{})
+		return
+	}
+
+	preview, err := h.previewer.Preview(template, services.PreviewNotification{
+		To:                params.Notification.To,
+		ReplyTo:           params.Notification.ReplyTo,
+		Subject:           params.Notification.Subject,
+		Text:              params.Notification.Text,
+		KindID:            params.Notification.KindID,
+		KindDescription:   params.Notification.KindDescription,
+		SourceDescription: params.Notification.SourceDescription,
+		ClientID:          params.Notification.ClientID,
+		Space:             params.Notification.Space,
+		Organization:      params.Notification.Organization,
+		HTML: services.HTML{
+			BodyContent:    bodyContent,
+			BodyAttributes: bodyAttributes,
+			Head:           head,
+			Doctype:        doctype,
+		},
+	})
+	if err != nil {
+		h.errorWriter.Write(w, webutil.ValidationError{Err: err})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PreviewOutput{
+		Subject: preview.Subject,
+		Text:    preview.Text,
+		HTML:    preview.HTML,
+		MIME:    preview.MIME,
+		Errors:  preview.Errors,
+	})
+}
@@ -0,0 +1,284 @@
+package templates_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/collections"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PreviewHandler", func() {
+	var (
+		handler      templates.PreviewHandler
+		writer       *httptest.ResponseRecorder
+		context      stack.Context
+		finder       *mocks.TemplateFinder
+		previewer    *mocks.TemplatePreviewer
+		partials     *mocks.PartialsCollection
+		connection   *mocks.Connection
+		errorWriter  *mocks.ErrorWriter
+		database     *mocks.Database
+		notification map[string]interface{}
+	)
+
+	newRequest := func(path string, body map[string]interface{}) *http.Request {
+		requestBody, err := json.Marshal(body)
+		Expect(err).NotTo(HaveOccurred())
+
+		request, err := http.NewRequest("POST", path, bytes.NewBuffer(requestBody))
+		Expect(err).NotTo(HaveOccurred())
+
+		return request
+	}
+
+	BeforeEach(func() {
+		finder = mocks.NewTemplateFinder()
+		finder.FindByIDCall.Returns.Template = models.Template{
+			ID:      "some-template-id",
+			Name:    "Saved Template",
+			Subject: "Saved: {{.Subject}}",
+			Text:    "Saved: {{.Text}}",
+			HTML:    "<h1>Saved</h1>{{.HTML}}",
+		}
+
+		previewer = mocks.NewTemplatePreviewer()
+		previewer.PreviewCall.Returns.Preview = services.Preview{
+			Subject: "compiled subject",
+			Text:    "compiled text",
+			HTML:    "<p>compiled html</p>",
+			MIME:    "Subject: compiled subject\n\ncompiled text",
+			Errors:  []string{`template: text:1:3: executing "text" at <.Missing>`},
+		}
+
+		partials = mocks.NewPartialsCollection()
+		partials.ListCall.Returns.Partials = []collections.Partial{
+			{Name: "footer", Body: "the footer"},
+		}
+
+		writer = httptest.NewRecorder()
+		errorWriter = mocks.NewErrorWriter()
+		connection = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = connection
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		notification = map[string]interface{}{
+			"to":                 "user@example.com",
+			"reply_to":           "reply@example.com",
+			"subject":            "the subject",
+			"text":               "the text",
+			"html":               `<!DOCTYPE html><html><head><title>hi</title></head><body class="sample"><p>the html</p></body></html>`,
+			"kind_id":            "some-kind",
+			"kind_description":   "Some Kind",
+			"source_description": "Some Source",
+			"client_id":          "some-client",
+			"space":              "some-space",
+			"organization":       "some-org",
+		}
+
+		handler = templates.NewPreviewHandler(finder, partials, previewer, errorWriter)
+	})
+
+	It("previews a saved template with the sample notification", func() {
+		handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+			"notification": notification,
+		}), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"subject": "compiled subject",
+			"text": "compiled text",
+			"html": "<p>compiled html</p>",
+			"mime": "Subject: compiled subject\n\ncompiled text",
+			"errors": ["template: text:1:3: executing \"text\" at <.Missing>"]
+		}`))
+
+		Expect(finder.FindByIDCall.Receives.Database).To(Equal(database))
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(Equal("some-template-id"))
+
+		Expect(previewer.PreviewCall.Receives.Template).To(Equal(services.PreviewTemplate{
+			Subject:  "Saved: {{.Subject}}",
+			Text:     "Saved: {{.Text}}",
+			HTML:     "<h1>Saved</h1>{{.HTML}}",
+			Partials: map[string]string{"footer": "the footer"},
+		}))
+		Expect(previewer.PreviewCall.Receives.Notification).To(Equal(services.PreviewNotification{
+			To:                "user@example.com",
+			ReplyTo:           "reply@example.com",
+			Subject:           "the subject",
+			Text:              "the text",
+			KindID:            "some-kind",
+			KindDescription:   "Some Kind",
+			SourceDescription: "Some Source",
+			ClientID:          "some-client",
+			Space:             "some-space",
+			Organization:      "some-org",
+			HTML: services.HTML{
+				BodyContent:    "<p>the html</p>",
+				BodyAttributes: `class="sample"`,
+				Head:           "<title>hi</title>",
+				Doctype:        "<!DOCTYPE html>",
+			},
+		}))
+	})
+
+	It("previews the variant of a saved template for the locale of the sample notification", func() {
+		finder.FindByIDCall.Returns.Template.Locales = `{"de": {"subject": "Gespeichert: {{.Subject}}"}}`
+		notification["locale"] = "de-AT"
+
+		handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+			"notification": notification,
+		}), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(previewer.PreviewCall.Receives.Template).To(Equal(services.PreviewTemplate{
+			Subject:  "Gespeichert: {{.Subject}}",
+			Text:     "Saved: {{.Text}}",
+			HTML:     "<h1>Saved</h1>{{.HTML}}",
+			Partials: map[string]string{"footer": "the footer"},
+		}))
+	})
+
+	It("previews the template given in the request", func() {
+		handler.ServeHTTP(writer, newRequest("/templates/preview", map[string]interface{}{
+			"template": map[string]interface{}{
+				"subject": "Draft: {{.Subject}}",
+				"text":    "Draft: {{.Text}}",
+				"html":    "<h1>Draft</h1>{{.HTML}}",
+			},
+			"notification": notification,
+		}), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(finder.FindByIDCall.Receives.TemplateID).To(BeEmpty())
+		Expect(previewer.PreviewCall.Receives.Template).To(Equal(services.PreviewTemplate{
+			Subject:  "Draft: {{.Subject}}",
+			Text:     "Draft: {{.Text}}",
+			HTML:     "<h1>Draft</h1>{{.HTML}}",
+			Partials: map[string]string{"footer": "the footer"},
+		}))
+	})
+
+	It("lists the partials on the connection of the request", func() {
+		handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+			"notification": notification,
+		}), context)
+
+		Expect(partials.ListCall.Receives.Connection).To(Equal(connection))
+	})
+
+	It("wraps the preview in the layout of the client", func() {
+		partials.ListCall.Returns.Partials = append(partials.ListCall.Returns.Partials, collections.Partial{
+			Name: "my-layout",
+			Body: "<main>{{.HTMLComponents.BodyContent}}</main>",
+		})
+		partials.LayoutOfCall.Returns.Layout = "my-layout"
+
+		handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+			"notification": notification,
+		}), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(partials.LayoutOfCall.Receives.Connection).To(Equal(connection))
+		Expect(partials.LayoutOfCall.Receives.ClientID).To(Equal("some-client"))
+		Expect(previewer.PreviewCall.Receives.Template.Layout).To(Equal("<main>{{.HTMLComponents.BodyContent}}</main>"))
+	})
+
+	It("does not look up a layout without a client", func() {
+		delete(notification, "client_id")
+
+		handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+			"notification": notification,
+		}), context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(partials.LayoutOfCall.WasCalled).To(BeFalse())
+		Expect(previewer.PreviewCall.Receives.Template.Layout).To(BeEmpty())
+	})
+
+	Context("when the layout of the client cannot be found", func() {
+		It("writes the error", func() {
+			partials.LayoutOfCall.Returns.Error = errors.New("db is down")
+
+			handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+				"notification": notification,
+			}), context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("db is down")))
+			Expect(previewer.PreviewCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the partials cannot be listed", func() {
+		It("writes the error", func() {
+			partials.ListCall.Returns.Error = errors.New("boom")
+
+			handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+				"notification": notification,
+			}), context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(MatchError(errors.New("boom")))
+			Expect(previewer.PreviewCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the template is missing from a stateless preview", func() {
+		It("writes a validation error", func() {
+			handler.ServeHTTP(writer, newRequest("/templates/preview", map[string]interface{}{
+				"notification": notification,
+			}), context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(BeAssignableToTypeOf(webutil.ValidationError{}))
+			Expect(previewer.PreviewCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the request body cannot be parsed", func() {
+		It("writes a parse error", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", bytes.NewBufferString("%%"))
+			Expect(err).NotTo(HaveOccurred())
+
+			handler.ServeHTTP(writer, request, context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ParseError{}))
+		})
+	})
+
+	Context("when the saved template cannot be found", func() {
+		It("writes the error", func() {
+			finder.FindByIDCall.Returns.Error = models.NotFoundError{}
+
+			handler.ServeHTTP(writer, newRequest("/templates/missing-template-id/preview", map[string]interface{}{
+				"notification": notification,
+			}), context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{}))
+			Expect(previewer.PreviewCall.WasCalled).To(BeFalse())
+		})
+	})
+
+	Context("when the template cannot be parsed", func() {
+		It("writes a validation error", func() {
+			previewer.PreviewCall.Returns.Error = errors.New("template: text:1: unclosed action")
+
+			handler.ServeHTTP(writer, newRequest("/templates/some-template-id/preview", map[string]interface{}{
+				"notification": notification,
+			}), context)
+
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New("template: text:1: unclosed action")}))
+		})
+	})
+})
@@ -0,0 +1,54 @@
+package templates
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/ryanmoran/stack"
+)
+
+type templateRollbacker interface {
+	Rollback(database services.DatabaseInterface, templateID string, version int, clientID string) (models.Template, error)
+}
+
+type RollbackHandler struct {
+	rollbacker  templateRollbacker
+	errorWriter errorWriter
+}
+
+func NewRollbackHandler(rollbacker templateRollbacker, errWriter errorWriter) RollbackHandler {
+	return RollbackHandler{
+		rollbacker:  rollbacker,
+		errorWriter: errWriter,
+	}
+}
+
+func (h RollbackHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	segments := strings.Split(strings.Split(req.URL.Path, "/templates/")[1], "/")
+	templateID := segments[0]
+
+	version, err := parseVersion(segments[2], "version")
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	clientID := context.Get("token").(*jwt.Token).Claims["client_id"].(string)
+
+	template, err := h.rollbacker.Rollback(context.Get("database").(DatabaseInterface), templateID, version, clientID)
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	output, err := NewTemplateVersionOutput(models.NewTemplateVersion(template, clientID))
+	if err != nil {
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, output)
+}
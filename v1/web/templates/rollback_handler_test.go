@@ -0,0 +1,93 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RollbackHandler", func() {
+	var (
+		handler     templates.RollbackHandler
+		writer      *httptest.ResponseRecorder
+		context     stack.Context
+		updater     *mocks.TemplateUpdater
+		errorWriter *mocks.ErrorWriter
+		database    *mocks.Database
+	)
+
+	newRequest := func(version string) *http.Request {
+		request, err := http.NewRequest("POST", "/templates/some-template-id/versions/"+version+"/rollback", nil)
+		Expect(err).NotTo(HaveOccurred())
+		return request
+	}
+
+	BeforeEach(func() {
+		updater = mocks.NewTemplateUpdater()
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+		database = mocks.NewDatabase()
+		context = stack.NewContext()
+		context.Set("database", database)
+		context.Set("token", newClientToken("some-client"))
+
+		handler = templates.NewRollbackHandler(updater, errorWriter)
+	})
+
+	It("restores the version and returns the new version", func() {
+		updater.RollbackCall.Returns.Template = models.Template{
+			ID:        "some-template-id",
+			Name:      "some-name",
+			Subject:   "{{.Subject}}",
+			Text:      "old text",
+			HTML:      "<p>old html</p>",
+			Metadata:  "{}",
+			UpdatedAt: time.Date(2015, time.June, 8, 14, 40, 12, 0, time.UTC),
+			Version:   4,
+		}
+
+		handler.ServeHTTP(writer, newRequest("2"), context)
+
+		Expect(updater.RollbackCall.Receives.Database).To(Equal(database))
+		Expect(updater.RollbackCall.Receives.TemplateID).To(Equal("some-template-id"))
+		Expect(updater.RollbackCall.Receives.Version).To(Equal(2))
+		Expect(updater.RollbackCall.Receives.ClientID).To(Equal("some-client"))
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"version": 4,
+			"name": "some-name",
+			"subject": "{{.Subject}}",
+			"text": "old text",
+			"html": "<p>old html</p>",
+			"metadata": {},
+			"locales": {},
+			"client_id": "some-client",
+			"created_at": "2015-06-08T14:40:12Z"
+		}`))
+	})
+
+	It("rejects versions that are not positive integers", func() {
+		handler.ServeHTTP(writer, newRequest("latest"), context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: errors.New(`"version" must be a positive integer`)}))
+	})
+
+	It("writes errors from the updater to the error writer", func() {
+		updater.RollbackCall.Returns.Error = models.NotFoundError{Err: errors.New("not found")}
+
+		handler.ServeHTTP(writer, newRequest("9"), context)
+
+		Expect(errorWriter.WriteCall.Receives.Error).To(Equal(models.NotFoundError{Err: errors.New("not found")}))
+	})
+})
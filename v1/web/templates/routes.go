@@ -21,6 +21,10 @@ type Routes struct {
 	TemplateCreator           templateCreator
 	TemplateDeleter           templateDeleter
 	TemplateAssociationLister templateAssociationLister
+	TemplatePreviewer         templatePreviewer
+	TemplateHistory           templateHistory
+	TemplateRollbacker        templateRollbacker
+	PartialLister             partialLister
 }
 
 func (r Routes) Register(m muxer) {
@@ -28,8 +32,13 @@ func (r Routes) Register(m muxer) {
 	m.Handle("PUT", "/default_template", NewUpdateDefaultHandler(r.TemplateUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates", NewListHandler(r.TemplateLister, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
 	m.Handle("POST", "/templates", NewCreateHandler(r.TemplateCreator, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/preview", NewPreviewHandler(r.TemplateFinder, r.PartialLister, r.TemplatePreviewer, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}", NewGetHandler(r.TemplateFinder, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
 	m.Handle("PUT", "/templates/{template_id}", NewUpdateHandler(r.TemplateUpdater, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("DELETE", "/templates/{template_id}", NewDeleteHandler(r.TemplateDeleter, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/preview", NewPreviewHandler(r.TemplateFinder, r.PartialLister, r.TemplatePreviewer, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions", NewListVersionsHandler(r.TemplateHistory, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("GET", "/templates/{template_id}/versions/diff", NewDiffVersionsHandler(r.TemplateHistory, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesReadAuthenticator, r.DatabaseAllocator)
+	m.Handle("POST", "/templates/{template_id}/versions/{version}/rollback", NewRollbackHandler(r.TemplateRollbacker, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationTemplatesWriteAuthenticator, r.DatabaseAllocator)
 	m.Handle("GET", "/templates/{template_id}/associations", NewListAssociationsHandler(r.TemplateAssociationLister, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.NotificationsManageAuthenticator, r.DatabaseAllocator)
 }
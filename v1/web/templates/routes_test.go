@@ -27,6 +27,10 @@ var _ = Describe("Routes", func() {
 			TemplateDeleter:           mocks.NewTemplateDeleter(),
 			TemplateLister:            mocks.NewTemplateLister(),
 			TemplateAssociationLister: mocks.NewTemplateAssociationLister(),
+			TemplatePreviewer:         mocks.NewTemplatePreviewer(),
+			TemplateHistory:           mocks.NewTemplateHistory(),
+			TemplateRollbacker:        mocks.NewTemplateUpdater(),
+			PartialLister:             mocks.NewPartialsCollection(),
 
 			RequestCounter:                          middleware.RequestCounter{},
 			RequestLogging:                          middleware.RequestLogging{},
@@ -63,6 +67,32 @@ var _ = Describe("Routes", func() {
 		})
 	})
 
+	Describe("/templates/preview", func() {
+		It("routes POST /templates/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes POST /templates/{template_id}/preview", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/preview", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.PreviewHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+	})
+
 	Describe("/templates/{template_id}", func() {
 		It("routes GET /templates/{template_id}", func() {
 			request, err := http.NewRequest("GET", "/templates/{template_id}", nil)
@@ -113,6 +143,44 @@ var _ = Describe("Routes", func() {
 		})
 	})
 
+	Describe("/templates/{template_id}/versions", func() {
+		It("routes GET /templates/{template_id}/versions", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.ListVersionsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes GET /templates/{template_id}/versions/diff", func() {
+			request, err := http.NewRequest("GET", "/templates/{template_id}/versions/diff", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.DiffVersionsHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.read"}))
+		})
+
+		It("routes POST /templates/{template_id}/versions/{version}/rollback", func() {
+			request, err := http.NewRequest("POST", "/templates/{template_id}/versions/{version}/rollback", nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			s := muxer.Match(request).(stack.Stack)
+			Expect(s.Handler).To(BeAssignableToTypeOf(templates.RollbackHandler{}))
+			ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.Authenticator{}, middleware.DatabaseAllocator{})
+
+			authenticator := s.Middleware[2].(middleware.Authenticator)
+			Expect(authenticator.Scopes).To(Equal([]string{"notification_templates.write"}))
+		})
+	})
+
 	Describe("/default_template", func() {
 		It("routes GET /default_template", func() {
 			request, err := http.NewRequest("GET", "/default_template", nil)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"text/template"
 
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
@@ -12,11 +13,12 @@ import (
 )
 
 type TemplateParams struct {
-	Name     string          `json:"name" validate-required:"true"`
-	Text     string          `json:"text"`
-	HTML     string          `json:"html" validate-required:"true"`
-	Subject  string          `json:"subject"`
-	Metadata json.RawMessage `json:"metadata"`
+	Name     string                            `json:"name" validate-required:"true"`
+	Text     string                            `json:"text"`
+	HTML     string                            `json:"html" validate-required:"true"`
+	Subject  string                            `json:"subject"`
+	Metadata json.RawMessage                   `json:"metadata"`
+	Locales  map[string]models.TemplateVariant `json:"locales"`
 }
 
 func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
@@ -44,6 +46,11 @@ func NewTemplateParams(body io.ReadCloser) (TemplateParams, error) {
 		return TemplateParams{}, err
 	}
 
+	err = template.validateLocales()
+	if err != nil {
+		return TemplateParams{}, err
+	}
+
 	template.setDefaults()
 
 	return template, nil
@@ -66,13 +73,65 @@ func (t TemplateParams) validateSyntax() error {
 	return nil
 }
 
+// validateLocales checks that the variants are keyed by distinct language
+// tags and that their templates parse.
+func (t TemplateParams) validateLocales() error {
+	var locales []string
+	for locale := range t.Locales {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	normalized := map[string]string{}
+	for _, locale := range locales {
+		if !models.ValidLocale(locale) {
+			return webutil.ValidationError{Err: fmt.Errorf("Locale %q is not a language tag like \"de\" or \"pt-BR\"", locale)}
+		}
+
+		if other, ok := normalized[models.NormalizeLocale(locale)]; ok {
+			return webutil.ValidationError{Err: fmt.Errorf("Locales %q and %q name the same locale", other, locale)}
+		}
+		normalized[models.NormalizeLocale(locale)] = locale
+
+		variant := t.Locales[locale]
+		toValidate := []struct {
+			field    string
+			contents string
+		}{
+			{"Subject", variant.Subject},
+			{"Text", variant.Text},
+			{"HTML", variant.HTML},
+		}
+
+		for _, part := range toValidate {
+			_, err := template.New("test").Parse(part.contents)
+			if err != nil {
+				return webutil.ValidationError{Err: fmt.Errorf("%s syntax of locale %q is malformed please check your braces", part.field, locale)}
+			}
+		}
+	}
+
+	return nil
+}
+
 func (t TemplateParams) ToModel() models.Template {
+	locales := t.Locales
+	if locales == nil {
+		locales = map[string]models.TemplateVariant{}
+	}
+
+	encodedLocales, err := json.Marshal(locales)
+	if err != nil {
+		panic(err)
+	}
+
 	return models.Template{
 		Name:     t.Name,
 		Text:     t.Text,
 		HTML:     t.HTML,
 		Subject:  t.Subject,
 		Metadata: string(t.Metadata),
+		Locales:  string(encodedLocales),
 	}
 }
 
@@ -7,6 +7,7 @@ import (
 	"io"
 	"io/ioutil"
 
+	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/templates"
 	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
 
@@ -62,6 +63,64 @@ var _ = Describe("TemplateParams", func() {
 				Expect(parameters.Metadata).To(Equal(json.RawMessage("{}")))
 			})
 
+			It("constructs the locale variants", func() {
+				body, err := json.Marshal(map[string]interface{}{
+					"name": "Foo Bar Baz",
+					"html": "<p>its foobar</p>",
+					"locales": map[string]interface{}{
+						"de":    map[string]string{"subject": "Sachen und Dinge", "html": "<p>es ist foobar</p>"},
+						"pt_BR": map[string]string{"text": "é foobar"},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				parameters, err := templates.NewTemplateParams(ioutil.NopCloser(bytes.NewBuffer(body)))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parameters.Locales).To(Equal(map[string]models.TemplateVariant{
+					"de":    {Subject: "Sachen und Dinge", HTML: "<p>es ist foobar</p>"},
+					"pt_BR": {Text: "é foobar"},
+				}))
+			})
+
+			Context("when the locales are not valid", func() {
+				It("returns a validation error when a locale is not a language tag", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name: "Template name",
+						HTML: "HTML template",
+						Locales: map[string]models.TemplateVariant{
+							"german": {Subject: "Betreff"},
+						},
+					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`Locale "german" is not a language tag like "de" or "pt-BR"`)}))
+				})
+
+				It("returns a validation error when two locales are the same", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name: "Template name",
+						HTML: "HTML template",
+						Locales: map[string]models.TemplateVariant{
+							"pt-BR": {Subject: "Assunto"},
+							"pt_br": {Subject: "Assunto"},
+						},
+					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`Locales "pt-BR" and "pt_br" name the same locale`)}))
+				})
+
+				It("returns a validation error when a variant has invalid syntax", func() {
+					body := buildTemplateRequestBody(templates.TemplateParams{
+						Name: "Template name",
+						HTML: "HTML template",
+						Locales: map[string]models.TemplateVariant{
+							"de": {HTML: "{{.bad}"},
+						},
+					})
+					_, err := templates.NewTemplateParams(ioutil.NopCloser(body))
+					Expect(err).To(MatchError(webutil.ValidationError{Err: errors.New(`HTML syntax of locale "de" is malformed please check your braces`)}))
+				})
+			})
+
 			Context("when the template has invalid syntax", func() {
 				Context("when subject template has invalid syntax", func() {
 					It("returns a validation error", func() {
@@ -121,8 +180,21 @@ var _ = Describe("TemplateParams", func() {
 			Expect(templateModel.HTML).To(Equal("<p>its foobar</p>"))
 			Expect(templateModel.Subject).To(Equal("Foobar Yah"))
 			Expect(templateModel.Metadata).To(MatchJSON(`{"some_property": "some_value"}`))
+			Expect(templateModel.Locales).To(MatchJSON(`{}`))
 			Expect(templateModel.CreatedAt).To(BeZero())
 			Expect(templateModel.UpdatedAt).To(BeZero())
 		})
+
+		It("encodes the locale variants", func() {
+			templateParams := templates.TemplateParams{
+				Name: "The Foo to the Bar",
+				HTML: "<p>its foobar</p>",
+				Locales: map[string]models.TemplateVariant{
+					"de": {Subject: "Foobar ja"},
+				},
+			}
+
+			Expect(templateParams.ToModel().Locales).To(MatchJSON(`{"de": {"subject": "Foobar ja"}}`))
+		})
 	})
 })
@@ -5,11 +5,12 @@ import (
 
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
 type templateUpdater interface {
-	Update(database services.DatabaseInterface, templateID string, template models.Template) error
+	Update(database services.DatabaseInterface, templateID string, template models.Template, clientID string) error
 }
 
 type UpdateDefaultHandler struct {
@@ -31,7 +32,9 @@ func (h UpdateDefaultHandler) ServeHTTP(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel())
+	clientID := context.Get("token").(*jwt.Token).Claims["client_id"].(string)
+
+	err = h.updater.Update(context.Get("database").(DatabaseInterface), models.DefaultTemplateID, template.ToModel(), clientID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 	}
@@ -45,6 +45,7 @@ var _ = Describe("UpdateDefaultHandler", func() {
 		database = mocks.NewDatabase()
 		context = stack.NewContext()
 		context.Set("database", database)
+		context.Set("token", newClientToken("some-client"))
 
 		handler = templates.NewUpdateDefaultHandler(updater, errorWriter)
 	})
@@ -61,7 +62,9 @@ var _ = Describe("UpdateDefaultHandler", func() {
 			HTML:     "<p>something</p>",
 			Text:     "something",
 			Metadata: `{"hello": true}`,
+			Locales:  "{}",
 		}))
+		Expect(updater.UpdateCall.Receives.ClientID).To(Equal("some-client"))
 	})
 
 	Context("when the request is not valid", func() {
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/ryanmoran/stack"
 )
 
@@ -28,7 +29,9 @@ func (h UpdateHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, conte
 		return
 	}
 
-	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel())
+	clientID := context.Get("token").(*jwt.Token).Claims["client_id"].(string)
+
+	err = h.updater.Update(context.Get("database").(DatabaseInterface), templateID, templateParams.ToModel(), clientID)
 	if err != nil {
 		h.errorWriter.Write(w, err)
 		return
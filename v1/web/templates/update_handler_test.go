@@ -34,13 +34,14 @@ var _ = Describe("UpdateHandler", func() {
 			updater = mocks.NewTemplateUpdater()
 			errorWriter = mocks.NewErrorWriter()
 			writer = httptest.NewRecorder()
-			body := []byte(`{"name":"An Interesting Template", "subject":"very interesting subject", "text":"Here's the msg {{.Text}}", "html":"<p>turkey gobble</p>"}`)
+			body := []byte(`{"name":"An Interesting Template", "subject":"very interesting subject", "text":"Here's the msg {{.Text}}", "html":"<p>turkey gobble</p>", "locales": {"de": {"subject": "sehr interessanter Betreff"}}}`)
 			request, err = http.NewRequest("PUT", "/templates/a-template-id", bytes.NewBuffer(body))
 			Expect(err).NotTo(HaveOccurred())
 
 			database = mocks.NewDatabase()
 			context = stack.NewContext()
 			context.Set("database", database)
+			context.Set("token", newClientToken("some-client"))
 
 			handler = templates.NewUpdateHandler(updater, errorWriter)
 		})
@@ -57,7 +58,9 @@ var _ = Describe("UpdateHandler", func() {
 				Text:     "Here's the msg {{.Text}}",
 				HTML:     "<p>turkey gobble</p>",
 				Metadata: "{}",
+				Locales:  `{"de":{"subject":"sehr interessanter Betreff"}}`,
 			}))
+			Expect(updater.UpdateCall.Receives.ClientID).To(Equal("some-client"))
 		})
 
 		It("can update a template without a subject field", func() {
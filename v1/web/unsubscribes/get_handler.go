@@ -0,0 +1,48 @@
+package unsubscribes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type GetHandler struct {
+	unsubscriber unsubscriber
+	errorWriter  errorWriter
+}
+
+func NewGetHandler(unsubscriber unsubscriber, errWriter errorWriter) GetHandler {
+	return GetHandler{
+		unsubscriber: unsubscriber,
+		errorWriter:  errWriter,
+	}
+}
+
+// ServeHTTP only describes the unsubscription. Mail scanners follow links in
+// messages, so the preference is not changed until the form is POSTed.
+func (h GetHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	unsubscribeID := strings.TrimPrefix(req.URL.Path, "/unsubscribe/")
+	database := context.Get("database").(DatabaseInterface)
+
+	unsubscription, err := h.unsubscriber.Find(database.Connection(), unsubscribeID)
+	if err != nil {
+		if _, ok := err.(services.CriticalKindError); ok {
+			err = webutil.ValidationError{Err: err}
+		}
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if acceptsHTML(req) {
+		writeHTML(w, http.StatusOK, confirmationPage, struct {
+			services.Unsubscription
+			Action string
+		}{unsubscription, req.URL.Path})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUnsubscriptionDocument(unsubscription, false))
+}
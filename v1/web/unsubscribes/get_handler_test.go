@@ -0,0 +1,103 @@
+package unsubscribes_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GetHandler", func() {
+	var (
+		handler      unsubscribes.GetHandler
+		unsubscriber *mocks.Unsubscriber
+		errorWriter  *mocks.ErrorWriter
+		writer       *httptest.ResponseRecorder
+		request      *http.Request
+		database     *mocks.Database
+		conn         *mocks.Connection
+		context      stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		unsubscriber = mocks.NewUnsubscriber()
+		unsubscriber.FindCall.Returns.Unsubscription = services.Unsubscription{
+			UserGUID:          "some-user",
+			ClientID:          "some-client",
+			KindID:            "some-kind",
+			KindDescription:   "Some <Kind>",
+			SourceDescription: "Some Client",
+		}
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("GET", "/unsubscribe/some-unsubscribe-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		handler = unsubscribes.NewGetHandler(unsubscriber, errorWriter)
+	})
+
+	It("describes the unsubscription as JSON without unsubscribing", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"user_guid": "some-user",
+			"client_id": "some-client",
+			"kind_id": "some-kind",
+			"kind_description": "Some <Kind>",
+			"source_description": "Some Client",
+			"unsubscribed": false
+		}`))
+
+		Expect(unsubscriber.FindCall.Receives.Connection).To(Equal(conn))
+		Expect(unsubscriber.FindCall.Receives.UnsubscribeID).To(Equal("some-unsubscribe-id"))
+		Expect(unsubscriber.UnsubscribeCall.CallCount).To(Equal(0))
+	})
+
+	It("renders a confirmation page when HTML is accepted", func() {
+		request.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.HeaderMap.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(writer.Body.String()).To(ContainSubstring(`Some &lt;Kind&gt;`))
+		Expect(writer.Body.String()).To(ContainSubstring(`<form method="post" action="/unsubscribe/some-unsubscribe-id">`))
+		Expect(writer.Body.String()).To(ContainSubstring(`name="List-Unsubscribe" value="One-Click"`))
+		Expect(unsubscriber.UnsubscribeCall.CallCount).To(Equal(0))
+	})
+
+	Context("when the unsubscriber errors", func() {
+		It("delegates to the error writer", func() {
+			unsubscriber.FindCall.Returns.Error = services.ExpiredUnsubscribeIDError{}
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(services.ExpiredUnsubscribeIDError{}))
+		})
+
+		It("treats critical kinds as a validation error", func() {
+			criticalErr := services.CriticalKindError{Err: errors.New("critical")}
+			unsubscriber.FindCall.Returns.Error = criticalErr
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: criticalErr}))
+		})
+	})
+})
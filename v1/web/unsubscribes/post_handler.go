@@ -0,0 +1,46 @@
+package unsubscribes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+)
+
+type PostHandler struct {
+	unsubscriber unsubscriber
+	errorWriter  errorWriter
+}
+
+func NewPostHandler(unsubscriber unsubscriber, errWriter errorWriter) PostHandler {
+	return PostHandler{
+		unsubscriber: unsubscriber,
+		errorWriter:  errWriter,
+	}
+}
+
+// ServeHTTP performs the unsubscription. It handles both the confirmation
+// form and RFC 8058 one-click requests, which POST a body of
+// "List-Unsubscribe=One-Click" with no credentials.
+func (h PostHandler) ServeHTTP(w http.ResponseWriter, req *http.Request, context stack.Context) {
+	unsubscribeID := strings.TrimPrefix(req.URL.Path, "/unsubscribe/")
+	database := context.Get("database").(DatabaseInterface)
+
+	unsubscription, err := h.unsubscriber.Unsubscribe(database.Connection(), unsubscribeID)
+	if err != nil {
+		if _, ok := err.(services.CriticalKindError); ok {
+			err = webutil.ValidationError{Err: err}
+		}
+		h.errorWriter.Write(w, err)
+		return
+	}
+
+	if acceptsHTML(req) {
+		writeHTML(w, http.StatusOK, unsubscribedPage, unsubscription)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newUnsubscriptionDocument(unsubscription, true))
+}
@@ -0,0 +1,102 @@
+package unsubscribes_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostHandler", func() {
+	var (
+		handler      unsubscribes.PostHandler
+		unsubscriber *mocks.Unsubscriber
+		errorWriter  *mocks.ErrorWriter
+		writer       *httptest.ResponseRecorder
+		request      *http.Request
+		database     *mocks.Database
+		conn         *mocks.Connection
+		context      stack.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		unsubscriber = mocks.NewUnsubscriber()
+		unsubscriber.UnsubscribeCall.Returns.Unsubscription = services.Unsubscription{
+			UserGUID:          "some-user",
+			ClientID:          "some-client",
+			KindID:            "some-kind",
+			KindDescription:   "Some Kind",
+			SourceDescription: "Some Client",
+		}
+		errorWriter = mocks.NewErrorWriter()
+		writer = httptest.NewRecorder()
+
+		conn = mocks.NewConnection()
+		database = mocks.NewDatabase()
+		database.ConnectionCall.Returns.Connection = conn
+		context = stack.NewContext()
+		context.Set("database", database)
+
+		request, err = http.NewRequest("POST", "/unsubscribe/some-unsubscribe-id", strings.NewReader("List-Unsubscribe=One-Click"))
+		Expect(err).NotTo(HaveOccurred())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		handler = unsubscribes.NewPostHandler(unsubscriber, errorWriter)
+	})
+
+	It("unsubscribes the user and responds with JSON", func() {
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.Body.String()).To(MatchJSON(`{
+			"user_guid": "some-user",
+			"client_id": "some-client",
+			"kind_id": "some-kind",
+			"kind_description": "Some Kind",
+			"source_description": "Some Client",
+			"unsubscribed": true
+		}`))
+
+		Expect(unsubscriber.UnsubscribeCall.Receives.Connection).To(Equal(conn))
+		Expect(unsubscriber.UnsubscribeCall.Receives.UnsubscribeID).To(Equal("some-unsubscribe-id"))
+	})
+
+	It("renders a confirmation page when HTML is accepted", func() {
+		request.Header.Set("Accept", "text/html")
+
+		handler.ServeHTTP(writer, request, context)
+
+		Expect(writer.Code).To(Equal(http.StatusOK))
+		Expect(writer.HeaderMap.Get("Content-Type")).To(Equal("text/html; charset=utf-8"))
+		Expect(writer.Body.String()).To(ContainSubstring(`You will no longer receive "Some Kind" notifications from Some Client.`))
+	})
+
+	Context("when the unsubscriber errors", func() {
+		It("delegates to the error writer", func() {
+			invalidErr := services.InvalidUnsubscribeIDError{Err: errors.New("malformed")}
+			unsubscriber.UnsubscribeCall.Returns.Error = invalidErr
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(invalidErr))
+		})
+
+		It("treats critical kinds as a validation error", func() {
+			criticalErr := services.CriticalKindError{Err: errors.New("critical")}
+			unsubscriber.UnsubscribeCall.Returns.Error = criticalErr
+
+			handler.ServeHTTP(writer, request, context)
+			Expect(errorWriter.WriteCall.Receives.Error).To(Equal(webutil.ValidationError{Err: criticalErr}))
+		})
+	})
+})
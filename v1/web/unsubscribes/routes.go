@@ -0,0 +1,37 @@
+package unsubscribes
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+	"github.com/ryanmoran/stack"
+)
+
+type muxer interface {
+	Handle(method, path string, handler stack.Handler, middleware ...stack.Middleware)
+}
+
+type errorWriter interface {
+	Write(writer http.ResponseWriter, err error)
+}
+
+type unsubscriber interface {
+	Find(conn services.ConnectionInterface, unsubscribeID string) (services.Unsubscription, error)
+	Unsubscribe(conn services.ConnectionInterface, unsubscribeID string) (services.Unsubscription, error)
+}
+
+type Routes struct {
+	RequestCounter    stack.Middleware
+	RequestLogging    stack.Middleware
+	DatabaseAllocator stack.Middleware
+
+	ErrorWriter  errorWriter
+	Unsubscriber unsubscriber
+}
+
+// Register mounts the unsubscribe routes. They are deliberately left
+// unauthenticated: possession of a valid unsubscribe ID is the credential.
+func (r Routes) Register(m muxer) {
+	m.Handle("GET", "/unsubscribe/{unsubscribe_id}", NewGetHandler(r.Unsubscriber, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.DatabaseAllocator)
+	m.Handle("POST", "/unsubscribe/{unsubscribe_id}", NewPostHandler(r.Unsubscriber, r.ErrorWriter), r.RequestLogging, r.RequestCounter, r.DatabaseAllocator)
+}
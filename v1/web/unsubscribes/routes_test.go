@@ -0,0 +1,49 @@
+package unsubscribes_test
+
+import (
+	"net/http"
+
+	"github.com/cloudfoundry-incubator/notifications/testing/mocks"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/middleware"
+	"github.com/cloudfoundry-incubator/notifications/v1/web/unsubscribes"
+	"github.com/cloudfoundry-incubator/notifications/web"
+	"github.com/ryanmoran/stack"
+
+	. "github.com/cloudfoundry-incubator/notifications/testing/helpers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Routes", func() {
+	var muxer web.Muxer
+
+	BeforeEach(func() {
+		muxer = web.NewMuxer()
+		unsubscribes.Routes{
+			RequestCounter:    middleware.RequestCounter{},
+			RequestLogging:    middleware.RequestLogging{},
+			DatabaseAllocator: middleware.DatabaseAllocator{},
+
+			ErrorWriter:  mocks.NewErrorWriter(),
+			Unsubscriber: mocks.NewUnsubscriber(),
+		}.Register(muxer)
+	})
+
+	It("routes GET /unsubscribe/{unsubscribe_id}", func() {
+		request, err := http.NewRequest("GET", "/unsubscribe/some-unsubscribe-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(unsubscribes.GetHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.DatabaseAllocator{})
+	})
+
+	It("routes POST /unsubscribe/{unsubscribe_id}", func() {
+		request, err := http.NewRequest("POST", "/unsubscribe/some-unsubscribe-id", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		s := muxer.Match(request).(stack.Stack)
+		Expect(s.Handler).To(BeAssignableToTypeOf(unsubscribes.PostHandler{}))
+		ExpectToContainMiddlewareStack(s.Middleware, middleware.RequestLogging{}, middleware.RequestCounter{}, middleware.DatabaseAllocator{})
+	})
+})
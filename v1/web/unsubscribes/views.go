@@ -0,0 +1,82 @@
+package unsubscribes
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/cloudfoundry-incubator/notifications/v1/services"
+)
+
+var confirmationPage = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<title>Unsubscribe</title>
+	</head>
+	<body>
+		<p>Do you want to stop receiving "{{.KindDescription}}" notifications from {{.SourceDescription}}?</p>
+		<form method="post" action="{{.Action}}">
+			<input type="hidden" name="List-Unsubscribe" value="One-Click">
+			<button type="submit">Unsubscribe</button>
+		</form>
+	</body>
+</html>
+`))
+
+var unsubscribedPage = template.Must(template.New("unsubscribed").Parse(`<!DOCTYPE html>
+<html>
+	<head>
+		<meta charset="utf-8">
+		<title>Unsubscribed</title>
+	</head>
+	<body>
+		<p>You will no longer receive "{{.KindDescription}}" notifications from {{.SourceDescription}}.</p>
+	</body>
+</html>
+`))
+
+type unsubscriptionDocument struct {
+	UserGUID          string `json:"user_guid"`
+	ClientID          string `json:"client_id"`
+	KindID            string `json:"kind_id"`
+	KindDescription   string `json:"kind_description"`
+	SourceDescription string `json:"source_description"`
+	Unsubscribed      bool   `json:"unsubscribed"`
+}
+
+func newUnsubscriptionDocument(unsubscription services.Unsubscription, unsubscribed bool) unsubscriptionDocument {
+	return unsubscriptionDocument{
+		UserGUID:          unsubscription.UserGUID,
+		ClientID:          unsubscription.ClientID,
+		KindID:            unsubscription.KindID,
+		KindDescription:   unsubscription.KindDescription,
+		SourceDescription: unsubscription.SourceDescription,
+		Unsubscribed:      unsubscribed,
+	}
+}
+
+func acceptsHTML(req *http.Request) bool {
+	return strings.Contains(req.Header.Get("Accept"), "text/html")
+}
+
+func writeHTML(w http.ResponseWriter, status int, page *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := page.Execute(w, data)
+	if err != nil {
+		panic(err) // The pages are static templates and should always render
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, object interface{}) {
+	output, err := json.Marshal(object)
+	if err != nil {
+		panic(err) // No JSON we write into a response should ever panic
+	}
+
+	w.WriteHeader(status)
+	w.Write(output)
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -18,18 +19,22 @@ func NewErrorWriter() ErrorWriter {
 
 func (writer ErrorWriter) Write(w http.ResponseWriter, err error) {
 	switch err.(type) {
-	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, MissingUserTokenError, ValidationError:
+	case UAAScopesError, CriticalNotificationError, collections.TemplateAssignmentError, collections.LayoutAssignmentError, services.TemplateRollbackError, MissingUserTokenError, ValidationError:
 		w.WriteHeader(422)
 	case services.CCDownError:
 		w.WriteHeader(http.StatusBadGateway)
-	case services.CCNotFoundError, models.NotFoundError, cf.NotFoundError:
+	case services.CCNotFoundError, models.NotFoundError, cf.NotFoundError, services.InvalidUnsubscribeIDError, gobble.DeadJobNotFoundError:
 		w.WriteHeader(http.StatusNotFound)
 	case ParseError, SchemaError:
 		w.WriteHeader(http.StatusBadRequest)
-	case models.DuplicateError:
+	case models.DuplicateError, services.MessageNotCancellableError, services.IdempotencyKeyConflictError, collections.PartialInUseError:
 		w.WriteHeader(http.StatusConflict)
+	case services.CancelForbiddenError, services.SendForbiddenError:
+		w.WriteHeader(http.StatusForbidden)
 	case services.DefaultScopeError:
 		w.WriteHeader(http.StatusNotAcceptable)
+	case services.ExpiredUnsubscribeIDError:
+		w.WriteHeader(http.StatusGone)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
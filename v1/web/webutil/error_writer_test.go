@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 
 	"github.com/cloudfoundry-incubator/notifications/cf"
+	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/v1/collections"
 	"github.com/cloudfoundry-incubator/notifications/v1/models"
 	"github.com/cloudfoundry-incubator/notifications/v1/services"
@@ -106,6 +107,46 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 409 when a partial that is in use is deleted", func() {
+		writer.Write(recorder, collections.PartialInUseError{Err: errors.New("partial in use")})
+		Expect(recorder.Code).To(Equal(409))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["partial in use"]
+		}`))
+	})
+
+	It("returns a 409 when a message can no longer be cancelled", func() {
+		writer.Write(recorder, services.MessageNotCancellableError{Err: errors.New("already delivered")})
+		Expect(recorder.Code).To(Equal(409))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["already delivered"]
+		}`))
+	})
+
+	It("returns a 409 when an idempotency key was used for a different request", func() {
+		writer.Write(recorder, services.IdempotencyKeyConflictError{Message: "key was already used"})
+		Expect(recorder.Code).To(Equal(409))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["key was already used"]
+		}`))
+	})
+
+	It("returns a 403 when the client may not cancel a notification", func() {
+		writer.Write(recorder, services.CancelForbiddenError{})
+		Expect(recorder.Code).To(Equal(403))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["Only the client that sent the notification, or a holder of notifications.admin, may cancel it"]
+		}`))
+	})
+
+	It("returns a 403 when the client may not view a send", func() {
+		writer.Write(recorder, services.SendForbiddenError{})
+		Expect(recorder.Code).To(Equal(403))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["Only the client that sent the notification, or a holder of notifications.admin, may view its send"]
+		}`))
+	})
+
 	It("returns a 404 when a record cannot be found", func() {
 		writer.Write(recorder, models.NotFoundError{Err: errors.New("not found")})
 		Expect(recorder.Code).To(Equal(404))
@@ -122,6 +163,30 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 404 when an unsubscribe ID is invalid", func() {
+		writer.Write(recorder, services.InvalidUnsubscribeIDError{Err: errors.New("unsubscribe ID is malformed")})
+		Expect(recorder.Code).To(Equal(404))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["The unsubscribe ID is invalid: unsubscribe ID is malformed"]
+		}`))
+	})
+
+	It("returns a 404 when a dead job cannot be found", func() {
+		writer.Write(recorder, gobble.DeadJobNotFoundError{ID: 42})
+		Expect(recorder.Code).To(Equal(404))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["Dead job with id 42 could not be found"]
+		}`))
+	})
+
+	It("returns a 410 when an unsubscribe ID has expired", func() {
+		writer.Write(recorder, services.ExpiredUnsubscribeIDError{})
+		Expect(recorder.Code).To(Equal(410))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["The unsubscribe ID has expired"]
+		}`))
+	})
+
 	It("returns a 422 when a template cannot be assigned", func() {
 		writer.Write(recorder, collections.TemplateAssignmentError{Err: errors.New("The template could not be assigned")})
 		Expect(recorder.Code).To(Equal(422))
@@ -130,6 +195,22 @@ var _ = Describe("ErrorWriter", func() {
 		}`))
 	})
 
+	It("returns a 422 when a layout cannot be assigned", func() {
+		writer.Write(recorder, collections.LayoutAssignmentError{Err: errors.New("No partial named \"missing\"")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["No partial named \"missing\""]
+		}`))
+	})
+
+	It("returns a 422 when a template version cannot be restored", func() {
+		writer.Write(recorder, services.TemplateRollbackError{Err: errors.New("includes a deleted partial")})
+		Expect(recorder.Code).To(Equal(422))
+		Expect(recorder.Body).To(MatchJSON(`{
+			"errors": ["includes a deleted partial"]
+		}`))
+	})
+
 	It("returns a 422 when a user token was expected but is not present", func() {
 		writer.Write(recorder, webutil.MissingUserTokenError{Err: errors.New("Missing user_id from token claims.")})
 		Expect(recorder.Code).To(Equal(422))
@@ -0,0 +1,14 @@
+package webutil
+
+// HasAdminScope reports whether the "scope" claim of a token grants
+// notifications.admin, which lets a client act on the notifications of every
+// other client.
+func HasAdminScope(elements interface{}) bool {
+	scopes, _ := elements.([]interface{})
+	for _, elem := range scopes {
+		if scope, ok := elem.(string); ok && scope == "notifications.admin" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package webutil_test
+
+import (
+	"github.com/cloudfoundry-incubator/notifications/v1/web/webutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HasAdminScope", func() {
+	It("is true when the scopes include notifications.admin", func() {
+		Expect(webutil.HasAdminScope([]interface{}{"emails.write", "notifications.admin"})).To(BeTrue())
+	})
+
+	It("is false when the scopes do not include notifications.admin", func() {
+		Expect(webutil.HasAdminScope([]interface{}{"emails.write", "notifications.write"})).To(BeFalse())
+	})
+
+	It("is false when the scope claim is missing or malformed", func() {
+		Expect(webutil.HasAdminScope(nil)).To(BeFalse())
+		Expect(webutil.HasAdminScope("notifications.admin")).To(BeFalse())
+		Expect(webutil.HasAdminScope([]interface{}{42})).To(BeFalse())
+	})
+})
@@ -18,6 +18,16 @@ func NewRouter(config Config) http.Handler {
 		CCHost:            config.CCHost,
 		CORSOrigin:        config.CORSOrigin,
 		SQLDB:             config.SQLDB,
+
+		EncryptionKey:         config.EncryptionKey,
+		UnsubscribeIDLifetime: config.UnsubscribeIDLifetime,
+		SendAtMaxHorizon:      config.SendAtMaxHorizon,
+		IdempotencyKeyTTL:     config.IdempotencyKeyTTL,
+
+		Sender: config.Sender,
+		Domain: config.Domain,
+
+		HTMLEscapingCompatibilityMode: config.HTMLEscapingCompatibilityMode,
 	})
 
 	return VersionRouter{
@@ -1,10 +1,12 @@
 package web
 
 import (
+	"context"
 	"database/sql"
 	"net/http"
 
 	"fmt"
+	"time"
 
 	"github.com/cloudfoundry-incubator/notifications/gobble"
 	"github.com/cloudfoundry-incubator/notifications/uaa"
@@ -27,18 +29,50 @@ type Config struct {
 	UAAClientSecret   string
 	DefaultUAAScopes  []string
 	CCHost            string
+
+	EncryptionKey         []byte
+	UnsubscribeIDLifetime time.Duration
+	SendAtMaxHorizon      time.Duration
+	IdempotencyKeyTTL     time.Duration
+
+	Sender string
+	Domain string
+
+	HTMLEscapingCompatibilityMode bool
 }
 
-type Server struct{}
+type Server struct {
+	httpServer *http.Server
+}
 
 func NewServer() Server {
-	return Server{}
+	return Server{
+		httpServer: &http.Server{},
+	}
 }
 
-func (s Server) Run(config Config) {
+// Run serves requests until the server is shut down, when it returns nil.
+func (s Server) Run(config Config) error {
 	config.Logger.Info("listen-and-serve", lager.Data{
 		"port": config.Port,
 	})
 
-	http.ListenAndServe(fmt.Sprintf(":%d", config.Port), NewRouter(config))
+	s.httpServer.Addr = fmt.Sprintf(":%d", config.Port)
+	s.httpServer.Handler = NewRouter(config)
+
+	err := s.httpServer.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown stops the server from accepting connections and waits until the
+// deadline for the requests it is handling to finish.
+func (s Server) Shutdown(deadline time.Time) error {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	return s.httpServer.Shutdown(ctx)
 }